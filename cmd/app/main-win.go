@@ -17,7 +17,9 @@ func Run(args []string) error {
 		args = args[1:]
 		cmd.SetArgs(args)
 	}
-	return cmd.Execute()
+	ctx, cancel := signalContext()
+	defer cancel()
+	return cmd.ExecuteContext(ctx)
 }
 
 const (
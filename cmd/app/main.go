@@ -13,5 +13,7 @@ func Run(args []string) error {
 		args = args[1:]
 		rootCmd.SetArgs(args)
 	}
-	return rootCmd.Execute()
+	ctx, cancel := signalContext()
+	defer cancel()
+	return rootCmd.ExecuteContext(ctx)
 }
@@ -1,6 +1,8 @@
 module github.com/jenkins-x-plugins/jx-changelog
 
 require (
+	github.com/Masterminds/semver/v3 v3.1.1
+	github.com/Masterminds/sprig/v3 v3.2.2
 	github.com/andygrunwald/go-jira v1.13.0
 	github.com/antham/chyle v1.11.0
 	github.com/cpuguy83/go-md2man v1.0.10
@@ -16,8 +18,11 @@ require (
 	github.com/spf13/cobra v1.2.0
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/crypto v0.0.0-20210220033148-5ea612d1eb83
 	gopkg.in/src-d/go-git.v4 v4.13.1
+	k8s.io/api v0.21.0
 	k8s.io/apimachinery v0.21.0
+	k8s.io/client-go v11.0.0+incompatible
 )
 
 replace (
@@ -0,0 +1,320 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/pkg/errors"
+)
+
+const (
+	// GitKind the git kind of Azure DevOps repositories which go-scm does not support yet
+	GitKind = "azure"
+
+	// APIVersion the version of the Azure DevOps REST API
+	APIVersion = "7.0"
+
+	// WorkItemPrefix the prefix of work item references such as 'AB#123' in commit messages
+	WorkItemPrefix = "AB#"
+)
+
+// Repository an Azure DevOps git repository
+type Repository struct {
+	// ServerURL the URL of the organization such as https://dev.azure.com/myorg
+	ServerURL    string
+	Organization string
+	Project      string
+	Name         string
+}
+
+// ParseURL parses the git URL of an Azure DevOps repository such as https://dev.azure.com/myorg/myproject/_git/myrepo
+// or git@ssh.dev.azure.com:v3/myorg/myproject/myrepo. Returns false if the URL is not an Azure DevOps URL
+func ParseURL(gitURL string) (*Repository, bool) {
+	gitURL = strings.TrimSuffix(strings.TrimSpace(gitURL), "/")
+	if strings.HasPrefix(gitURL, "git@ssh.dev.azure.com:v3/") || strings.Contains(gitURL, "@vs-ssh.visualstudio.com:v3/") {
+		parts := strings.Split(gitURL[strings.Index(gitURL, ":v3/")+4:], "/")
+		if len(parts) != 3 {
+			return nil, false
+		}
+		return newRepository("https://dev.azure.com/"+parts[0], parts[0], parts[1], parts[2]), true
+	}
+	u, err := url.Parse(gitURL)
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case strings.EqualFold(u.Host, "dev.azure.com"):
+		// https://dev.azure.com/{organization}/{project}/_git/{repository}
+		if len(parts) != 4 || parts[2] != "_git" {
+			return nil, false
+		}
+		return newRepository("https://dev.azure.com/"+parts[0], parts[0], parts[1], parts[3]), true
+	case strings.HasSuffix(strings.ToLower(u.Host), ".visualstudio.com"):
+		// https://{organization}.visualstudio.com/[DefaultCollection/]{project}/_git/{repository}
+		if len(parts) > 0 && parts[0] == "DefaultCollection" {
+			parts = parts[1:]
+		}
+		if len(parts) != 3 || parts[1] != "_git" {
+			return nil, false
+		}
+		org := strings.TrimSuffix(strings.ToLower(u.Host), ".visualstudio.com")
+		return newRepository("https://"+u.Host, org, parts[0], parts[2]), true
+	}
+	return nil, false
+}
+
+func newRepository(serverURL, org, project, name string) *Repository {
+	return &Repository{
+		ServerURL:    serverURL,
+		Organization: org,
+		Project:      project,
+		Name:         strings.TrimSuffix(name, ".git"),
+	}
+}
+
+// HTMLURL returns the URL of the repository in the web UI
+func (r *Repository) HTMLURL() string {
+	return r.projectURL() + "/_git/" + url.PathEscape(r.Name)
+}
+
+// WorkItemURL returns the URL of the work item in the web UI
+func (r *Repository) WorkItemURL(id int) string {
+	return r.projectURL() + "/_workitems/edit/" + strconv.Itoa(id)
+}
+
+// PullRequestURL returns the URL of the pull request in the web UI
+func (r *Repository) PullRequestURL(id int) string {
+	return r.HTMLURL() + "/pullrequest/" + strconv.Itoa(id)
+}
+
+// GitRepository returns the repository details used to link commits and users. The host includes the project so that
+// the https URL of the repository is the URL of the repository in the web UI
+func (r *Repository) GitRepository() *giturl.GitRepository {
+	return &giturl.GitRepository{
+		URL:          r.HTMLURL(),
+		Host:         r.projectURL(),
+		Organisation: "_git",
+		Name:         r.Name,
+	}
+}
+
+func (r *Repository) projectURL() string {
+	return r.ServerURL + "/" + url.PathEscape(r.Project)
+}
+
+// Client a client of the Azure DevOps REST API of a repository as there is no go-scm driver for Azure DevOps
+type Client struct {
+	Repository *Repository
+	// Token the personal access token
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client of the Azure DevOps REST API for the repository using the personal access token
+func NewClient(repository *Repository, token string) *Client {
+	return &Client{
+		Repository: repository,
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// doJSON invokes the method on the path relative to the project API marshalling the body to JSON and unmarshalling
+// the JSON response into the result if it is not nil. A 404 response returns scm.ErrNotFound
+func (c *Client) doJSON(ctx context.Context, method, path string, header http.Header, body, result interface{}) (*scm.Response, error) {
+	u := c.Repository.projectURL() + "/_apis/" + path
+	if strings.Contains(u, "?") {
+		u += "&api-version=" + APIVersion
+	} else {
+		u += "?api-version=" + APIVersion
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal request body for %s %s", method, path)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create request %s %s", method, u)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.SetBasicAuth("", c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to invoke %s %s", method, u)
+	}
+	defer resp.Body.Close()
+
+	res := &scm.Response{Status: resp.StatusCode, Header: resp.Header}
+	if resp.StatusCode == http.StatusNotFound {
+		return res, scm.ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return res, errors.Errorf("%s %s returned status %d: %s", method, u, resp.StatusCode, string(data))
+	}
+	if result == nil {
+		return res, nil
+	}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil && err != io.EOF {
+		return res, errors.Wrapf(err, "failed to parse JSON response of %s %s", method, u)
+	}
+	return res, nil
+}
+
+type identity struct {
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+	ImageURL    string `json:"imageUrl"`
+}
+
+func (i *identity) toUser() scm.User {
+	return scm.User{
+		Login:  i.UniqueName,
+		Name:   i.DisplayName,
+		Avatar: i.ImageURL,
+	}
+}
+
+type pullRequest struct {
+	PullRequestID int       `json:"pullRequestId"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	Status        string    `json:"status"`
+	CreationDate  time.Time `json:"creationDate"`
+	CreatedBy     identity  `json:"createdBy"`
+	Labels        []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// ListCommitPullRequests lists the pull requests which contain the commit
+func (c *Client) ListCommitPullRequests(ctx context.Context, sha string) ([]*scm.PullRequest, *scm.Response, error) {
+	body := map[string]interface{}{
+		"queries": []map[string]interface{}{
+			{
+				"type":  "commit",
+				"items": []string{sha},
+			},
+		},
+	}
+	result := struct {
+		Results []map[string][]pullRequest `json:"results"`
+	}{}
+	path := fmt.Sprintf("git/repositories/%s/pullrequestquery", url.PathEscape(c.Repository.Name))
+	res, err := c.doJSON(ctx, http.MethodPost, path, nil, body, &result)
+	if err != nil {
+		return nil, res, err
+	}
+	var answer []*scm.PullRequest
+	for _, r := range result.Results {
+		for i := range r[sha] {
+			pr := &r[sha][i]
+			p := &scm.PullRequest{
+				Number:  pr.PullRequestID,
+				Title:   pr.Title,
+				Body:    pr.Description,
+				State:   pr.Status,
+				Closed:  pr.Status != "active",
+				Merged:  pr.Status == "completed",
+				Link:    c.Repository.PullRequestURL(pr.PullRequestID),
+				Created: pr.CreationDate,
+				Author:  pr.CreatedBy.toUser(),
+			}
+			for _, l := range pr.Labels {
+				p.Labels = append(p.Labels, &scm.Label{Name: l.Name})
+			}
+			answer = append(answer, p)
+		}
+	}
+	return answer, res, nil
+}
+
+type workItem struct {
+	ID     int `json:"id"`
+	Fields struct {
+		Title       string    `json:"System.Title"`
+		State       string    `json:"System.State"`
+		Description string    `json:"System.Description"`
+		Tags        string    `json:"System.Tags"`
+		CreatedDate time.Time `json:"System.CreatedDate"`
+		CreatedBy   identity  `json:"System.CreatedBy"`
+		AssignedTo  *identity `json:"System.AssignedTo"`
+	} `json:"fields"`
+}
+
+// GetWorkItem returns the work item of the Azure Boards project as an issue
+func (c *Client) GetWorkItem(ctx context.Context, id int) (*scm.Issue, *scm.Response, error) {
+	item := &workItem{}
+	res, err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("wit/workitems/%d", id), nil, nil, item)
+	if err != nil {
+		return nil, res, err
+	}
+	f := &item.Fields
+	issue := &scm.Issue{
+		Number:  item.ID,
+		Title:   f.Title,
+		Body:    f.Description,
+		State:   strings.ToLower(f.State),
+		Closed:  f.State == "Closed" || f.State == "Done" || f.State == "Resolved",
+		Link:    c.Repository.WorkItemURL(item.ID),
+		Created: f.CreatedDate,
+		Author:  f.CreatedBy.toUser(),
+	}
+	for _, tag := range strings.Split(f.Tags, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			issue.Labels = append(issue.Labels, tag)
+		}
+	}
+	if f.AssignedTo != nil {
+		issue.Assignees = append(issue.Assignees, f.AssignedTo.toUser())
+	}
+	return issue, res, nil
+}
+
+// PutWikiPage creates or replaces the content of the page at the given path of the wiki returning the URL of the page
+func (c *Client) PutWikiPage(ctx context.Context, wiki, pagePath, content string) (string, *scm.Response, error) {
+	path := fmt.Sprintf("wiki/wikis/%s/pages?path=%s", url.PathEscape(wiki), url.QueryEscape(pagePath))
+	header := http.Header{}
+	res, err := c.doJSON(ctx, http.MethodGet, path, nil, nil, nil)
+	switch {
+	case err == scm.ErrNotFound:
+	case err != nil:
+		return "", res, errors.Wrapf(err, "failed to find wiki page %s", pagePath)
+	default:
+		// the version of an existing page must be specified to replace it
+		header.Set("If-Match", res.Header.Get("ETag"))
+	}
+	page := struct {
+		RemoteURL string `json:"remoteUrl"`
+	}{}
+	res, err = c.doJSON(ctx, http.MethodPut, path, header, map[string]string{"content": content}, &page)
+	if err != nil {
+		return "", res, errors.Wrapf(err, "failed to save wiki page %s", pagePath)
+	}
+	return page.RemoteURL, res, nil
+}
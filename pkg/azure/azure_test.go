@@ -0,0 +1,104 @@
+// +build unit
+
+package azure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/azure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	t.Parallel()
+	for _, gitURL := range []string{
+		"https://dev.azure.com/myorg/myproject/_git/myrepo",
+		"https://myorg@dev.azure.com/myorg/myproject/_git/myrepo",
+		"git@ssh.dev.azure.com:v3/myorg/myproject/myrepo",
+	} {
+		repo, ok := azure.ParseURL(gitURL)
+		require.True(t, ok, "should parse %s", gitURL)
+		assert.Equal(t, "https://dev.azure.com/myorg", repo.ServerURL, "server of %s", gitURL)
+		assert.Equal(t, "myproject", repo.Project, "project of %s", gitURL)
+		assert.Equal(t, "myrepo", repo.Name, "name of %s", gitURL)
+		assert.Equal(t, "https://dev.azure.com/myorg/myproject/_git/myrepo", repo.GitRepository().HttpsURL())
+	}
+
+	repo, ok := azure.ParseURL("https://myorg.visualstudio.com/DefaultCollection/myproject/_git/myrepo")
+	require.True(t, ok)
+	assert.Equal(t, "https://myorg.visualstudio.com", repo.ServerURL)
+	assert.Equal(t, "myorg", repo.Organization)
+	assert.Equal(t, "https://myorg.visualstudio.com/myproject/_workitems/edit/12", repo.WorkItemURL(12))
+
+	_, ok = azure.ParseURL("https://github.com/jstrachan/foo.git")
+	assert.False(t, ok)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	wiki := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, password, _ := r.BasicAuth()
+		if password != "mytoken" || r.URL.Query().Get("api-version") != azure.APIVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "POST /myorg/myproject/_apis/git/repositories/myrepo/pullrequestquery":
+			_, _ = w.Write([]byte(`{"results": [{"abc123": [
+  {"pullRequestId": 7, "title": "fix: the cork", "status": "completed", "createdBy": {"displayName": "James Strachan", "uniqueName": "james@example.com"}}
+]}]}`))
+		case "GET /myorg/myproject/_apis/wit/workitems/12":
+			_, _ = w.Write([]byte(`{"id": 12, "fields": {"System.Title": "Cheese", "System.State": "Done", "System.Tags": "food; dairy"}}`))
+		case "GET /myorg/myproject/_apis/wiki/wikis/docs/pages":
+			content, ok := wiki[r.URL.Query().Get("path")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", `"`+content+`"`)
+		case "PUT /myorg/myproject/_apis/wiki/wikis/docs/pages":
+			page := r.URL.Query().Get("path")
+			if content, ok := wiki[page]; ok && r.Header.Get("If-Match") != `"`+content+`"` {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			body := map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			wiki[page] = body["content"]
+			_, _ = w.Write([]byte(`{"remoteUrl": "https://dev.azure.com/myorg/myproject/_wiki/wikis/docs/1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo := &azure.Repository{ServerURL: server.URL + "/myorg", Organization: "myorg", Project: "myproject", Name: "myrepo"}
+	client := azure.NewClient(repo, "mytoken")
+	ctx := context.Background()
+
+	prs, _, err := client.ListCommitPullRequests(ctx, "abc123")
+	require.NoError(t, err, "failed to list the pull requests of the commit")
+	require.Len(t, prs, 1)
+	assert.Equal(t, 7, prs[0].Number)
+	assert.True(t, prs[0].Merged)
+	assert.Equal(t, server.URL+"/myorg/myproject/_git/myrepo/pullrequest/7", prs[0].Link)
+
+	issue, _, err := client.GetWorkItem(ctx, 12)
+	require.NoError(t, err, "failed to get the work item")
+	assert.Equal(t, "Cheese", issue.Title)
+	assert.True(t, issue.Closed)
+	assert.Equal(t, []string{"food", "dairy"}, issue.Labels)
+
+	for _, content := range []string{"v1", "v2"} {
+		url, _, err := client.PutWikiPage(ctx, "docs", "/Release Notes/v1.0.0", content)
+		require.NoError(t, err, "failed to save the wiki page")
+		assert.Equal(t, "https://dev.azure.com/myorg/myproject/_wiki/wikis/docs/1", url)
+		assert.Equal(t, content, wiki["/Release Notes/v1.0.0"])
+	}
+}
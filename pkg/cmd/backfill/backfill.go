@@ -0,0 +1,188 @@
+package backfill
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/create"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// DefaultChangelogFile the default changelog file which is regenerated
+const DefaultChangelogFile = gits.DefaultChangelogFile
+
+var (
+	cmdLong = templates.LongDesc(`
+		Regenerates a complete changelog file from scratch with a section for every tag of the repository
+
+		The changes between each pair of consecutive tags are generated with the same options as 'jx-changelog create' so that projects adopting jx-changelog late can bootstrap a consistent historical changelog. No tags, releases or comments are created or updated on the git provider. The changelog file is only replaced once every section has been generated.
+`)
+
+	cmdExample = templates.Examples(`
+		# regenerate the CHANGELOG.md from all the tags
+		jx-changelog backfill
+
+		# regenerate the changelog of the tags starting with 'v' including the unreleased changes
+		jx-changelog backfill --tag-prefix v --unreleased
+`)
+
+	// ignoredFlags the flags of the create command which are set for each pair of tags
+	ignoredFlags = []string{"previous-rev", "previous-date", "rev", "version", "update-release", "crd", "generate-yaml", "push-changelog", "create-tag", "tag-description", "dry-run"}
+)
+
+// Options the options for backfilling the changelog file
+type Options struct {
+	create.Options
+
+	TagPrefix string
+}
+
+// NewCmdBackfill creates the command and options
+func NewCmdBackfill() (*cobra.Command, *Options) {
+	o := &Options{}
+	cmd := &cobra.Command{
+		Use:     "backfill",
+		Short:   "Regenerates a complete changelog file from all the tags of the repository",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Context = cmd.Context()
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	o.Options.AddFlags(cmd)
+	for _, name := range ignoredFlags {
+		_ = cmd.Flags().MarkHidden(name)
+	}
+	cmd.Flags().StringVarP(&o.TagPrefix, "tag-prefix", "", "", "Only uses the tags starting with this prefix such as 'v'")
+	return cmd, o
+}
+
+// Run implements the command
+func (o *Options) Run() error {
+	if o.ChangelogFile == "" {
+		o.ChangelogFile = DefaultChangelogFile
+	}
+	unreleased := o.Unreleased
+	o.Unreleased = false
+	o.UpdateRelease = false
+	o.GenerateCRD = false
+	o.GenerateReleaseYaml = false
+	o.NoKubernetes = true
+	o.PreviousDate = ""
+	// lets never change the git provider or the tags
+	o.DryRun = true
+	o.CreateTag = false
+	o.TagDescription = false
+	o.PushChangelog = ""
+	var outputs []string
+	for _, output := range o.Outputs {
+		if output != create.OutputRelease {
+			outputs = append(outputs, output)
+		}
+	}
+	o.Outputs = outputs
+
+	dir := o.ScmFactory.Dir
+	g := o.Git()
+	tags, err := gits.ListTags(g, dir, o.TagPrefix)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the tags in %s", dir)
+	}
+	if len(tags) == 0 && !unreleased {
+		return errors.Errorf("no tags starting with '%s' could be found in dir %s", o.TagPrefix, dir)
+	}
+
+	// lets generate the changelog into a temporary file so that the existing file is kept if anything fails
+	changelogFile := o.ChangelogFile
+	tmpDir, err := ioutil.TempDir(filepath.Dir(changelogFile), ".backfill-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create a temporary directory for %s", changelogFile)
+	}
+	defer os.RemoveAll(tmpDir)
+	o.ChangelogFile = filepath.Join(tmpDir, filepath.Base(changelogFile))
+	defer func() {
+		o.ChangelogFile = changelogFile
+	}()
+
+	previousRev, previousTag := "", ""
+	for _, tag := range tags {
+		rev, err := g.Command(dir, "rev-list", "-n", "1", tag)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the commit of tag %s", tag)
+		}
+		if previousRev == "" {
+			previousRev, err = gits.GetFirstCommitSha(g, dir)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find the first commit in %s", dir)
+			}
+			if previousRev == rev {
+				// lets skip a tag of the first commit as there are no changes
+				previousTag = tag
+				continue
+			}
+		}
+		dateText, err := g.Command(dir, "log", "-1", "--format=%cI", rev)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the date of tag %s", tag)
+		}
+		o.ReleaseDate, err = time.Parse(time.RFC3339, strings.TrimSpace(dateText))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse the date %s of tag %s", dateText, tag)
+		}
+		log.Logger().Infof("generating the changelog of %s", termcolor.ColorInfo(tag))
+		err = o.generate(previousRev, previousTag, rev, tag, strings.TrimPrefix(strings.TrimPrefix(tag, o.TagPrefix), "v"))
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate the changelog of %s", tag)
+		}
+		previousRev, previousTag = rev, tag
+	}
+	if unreleased {
+		o.Unreleased = true
+		o.ReleaseDate = time.Time{}
+		err = o.generate(previousRev, previousTag, "", "", "")
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate the unreleased changes")
+		}
+	}
+	exists, err := files.FileExists(o.ChangelogFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", o.ChangelogFile)
+	}
+	if exists {
+		err = os.Rename(o.ChangelogFile, changelogFile)
+	} else {
+		// lets remove the changelog as there are no changes
+		err = os.Remove(changelogFile)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to replace %s", changelogFile)
+	}
+	log.Logger().Infof("regenerated %s from %d tags", termcolor.ColorInfo(changelogFile), len(tags))
+	return nil
+}
+
+// generate inserts the section of the changes between the revisions into the changelog file
+func (o *Options) generate(previousRev, previousTag, currentRev, currentTag, version string) error {
+	o.PreviousRevision = previousRev
+	o.PreviousTag = previousTag
+	o.CurrentRevision = currentRev
+	o.CurrentTag = currentTag
+	o.Version = version
+	o.State = create.State{}
+	return o.Options.Run()
+}
@@ -0,0 +1,119 @@
+// +build unit
+
+package backfill_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/backfill"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestBackfill(t *testing.T) {
+	t.Parallel()
+	dir := createRepository(t)
+
+	scmClient, _ := scmfake.NewDefault()
+	changelogFile := filepath.Join(dir, "CHANGELOG.md")
+	_, o := backfill.NewCmdBackfill()
+	o.ScmFactory.Dir = dir
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.Owner = "jstrachan"
+	o.ScmFactory.Repository = "foo"
+	o.Offline = true
+	o.CompareLink = false
+	o.Unreleased = true
+	o.ChangelogFile = changelogFile
+	err := o.Run()
+	require.NoError(t, err, "failed to backfill the changelog")
+
+	data, err := ioutil.ReadFile(changelogFile)
+	require.NoError(t, err, "failed to read %s", changelogFile)
+	expected := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### New Features
+
+* wine (James)
+
+## [1.1.0] - 2021-04-02
+
+### Bug Fixes
+
+* the cork (James)
+
+## [1.0.0] - 2021-04-01
+
+### New Features
+
+* cheese (James)
+`
+	assert.Equal(t, expected, string(data))
+}
+
+func TestBackfillKeepsChangelogOnFailure(t *testing.T) {
+	t.Parallel()
+	dir := createRepository(t)
+	changelogFile := filepath.Join(dir, "CHANGELOG.md")
+	existing := "# Changelog\n\n## [1.0.0]\n\n* handwritten notes\n"
+	err := ioutil.WriteFile(changelogFile, []byte(existing), 0600)
+	require.NoError(t, err)
+
+	scmClient, _ := scmfake.NewDefault()
+	_, o := backfill.NewCmdBackfill()
+	o.ScmFactory.Dir = dir
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.Owner = "jstrachan"
+	o.ScmFactory.Repository = "foo"
+	o.Offline = true
+	o.ChangelogFile = changelogFile
+	o.TemplateFile = filepath.Join(dir, "missing.tmpl")
+	err = o.Run()
+	require.Error(t, err, "should fail to render the missing template")
+
+	data, err := ioutil.ReadFile(changelogFile)
+	require.NoError(t, err, "failed to read %s", changelogFile)
+	assert.Equal(t, existing, string(data), "the changelog file should be kept")
+	fileInfos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, fi := range fileInfos {
+		assert.NotContains(t, fi.Name(), "backfill", "the temporary files should be removed")
+	}
+}
+
+// createRepository creates a git repository with the tags v1.0.0 and v1.1.0 and an unreleased commit
+func createRepository(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/jstrachan/foo.git"}})
+	require.NoError(t, err, "failed to add the remote")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	released := time.Date(2021, time.March, 31, 10, 0, 0, 0, time.UTC)
+	tags := map[int]string{1: "v1.0.0", 2: "v1.1.0"}
+	for i, message := range []string{"initial commit", "feat: cheese", "fix: the cork", "feat: wine"} {
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: released.Add(time.Duration(i) * 24 * time.Hour)}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		if tag := tags[i]; tag != "" {
+			_, err = repo.CreateTag(tag, hash, nil)
+			require.NoError(t, err, "failed to tag %s", hash.String())
+		}
+	}
+	return dir
+}
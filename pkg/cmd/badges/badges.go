@@ -0,0 +1,225 @@
+package badges
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/gitdiscovery"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// OutputFormatMarkdown writes the badges as markdown images
+	OutputFormatMarkdown = "markdown"
+
+	// OutputFormatJSON writes the badges as JSON
+	OutputFormatJSON = "json"
+
+	// StartMarker the comment after which the badges are written in the README file
+	StartMarker = "<!-- badges:start -->"
+
+	// EndMarker the comment before which the badges are written in the README file
+	EndMarker = "<!-- badges:end -->"
+)
+
+// OutputFormats the supported output formats
+var OutputFormats = []string{OutputFormatMarkdown, OutputFormatJSON}
+
+var (
+	cmdLong = templates.LongDesc(`
+		Generates the badge markdown of the latest release with its version, its date and the number of commits since the release
+
+		The badges are static shields.io images so they work with any git provider. Use '--readme' to replace the badges between the '` + StartMarker + `' and '` + EndMarker + `' comments of a README file so that it is kept in sync by the release pipeline.
+`)
+
+	cmdExample = templates.Examples(`
+		# print the badges of the latest release
+		jx-changelog badges
+
+		# update the badges in the README
+		jx-changelog badges --readme README.md
+`)
+)
+
+// Options the options for generating the release badges
+type Options struct {
+	Dir             string
+	Tag             string
+	CurrentRevision string
+	GitKind         string
+	Color           string
+	Output          string
+	OutputFile      string
+	Readme          string
+	GitClient       gitclient.Interface
+	CommandRunner   cmdrunner.CommandRunner
+
+	// Badges the badges of the last run
+	Badges []*gits.Badge
+}
+
+// NewCmdBadges creates the command and options
+func NewCmdBadges() (*cobra.Command, *Options) {
+	o := &Options{}
+	cmd := &cobra.Command{
+		Use:     "badges",
+		Short:   "Generates the badge markdown of the latest release",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory of the git repository")
+	cmd.Flags().StringVarP(&o.Tag, "tag", "t", "", "the tag of the release. Defaults to the latest tag")
+	cmd.Flags().StringVarP(&o.CurrentRevision, "rev", "", "HEAD", "the revision the commits since the release are counted up to")
+	cmd.Flags().StringVarP(&o.GitKind, "git-kind", "", "", "the kind of git provider used to link the badges. Defaults to the kind detected from the git URL")
+	cmd.Flags().StringVarP(&o.Color, "color", "", gits.DefaultBadgeColor, "the color of the badges")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", OutputFormatMarkdown, fmt.Sprintf("The output format. Supported values: %s", strings.Join(OutputFormats, ", ")))
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "The file to write the badges to. Defaults to the standard output")
+	cmd.Flags().StringVarP(&o.Readme, "readme", "", "", "The README file whose badges between the '"+StartMarker+"' and '"+EndMarker+"' comments are replaced")
+	return cmd, o
+}
+
+// Run implements the command
+func (o *Options) Run() error {
+	if stringhelpers.StringArrayIndex(OutputFormats, o.Output) < 0 {
+		return errors.Errorf("unsupported output format %s. Supported values: %s", o.Output, strings.Join(OutputFormats, ", "))
+	}
+	g := o.Git()
+	tag := o.Tag
+	var err error
+	if tag == "" {
+		_, tag, err = gits.GetCommitPointedToByLatestTag(g, o.Dir)
+		if err != nil {
+			return err
+		}
+		if tag == "" {
+			return errors.Errorf("no tags could be found in dir %s", o.Dir)
+		}
+	}
+	sha, err := g.Command(o.Dir, "rev-list", "-n", "1", tag)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the commit of tag %s", tag)
+	}
+	dateText, err := g.Command(o.Dir, "log", "-1", "--format=%cI", sha)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the date of tag %s", tag)
+	}
+	date, err := time.Parse(time.RFC3339, strings.TrimSpace(dateText))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the date %s of tag %s", dateText, tag)
+	}
+	countText, err := g.Command(o.Dir, "rev-list", "--count", sha+".."+o.CurrentRevision)
+	if err != nil {
+		return errors.Wrapf(err, "failed to count the commits since tag %s", tag)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countText))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the number of commits %s", countText)
+	}
+
+	rev := o.CurrentRevision
+	if rev == "HEAD" {
+		branch, err := gitclient.Branch(g, o.Dir)
+		if err == nil && branch != "" {
+			rev = branch
+		}
+	}
+	gitInfo, err := gitdiscovery.FindGitInfoFromDir(o.Dir)
+	if err != nil {
+		log.Logger().Warnf("failed to find the git URL of dir %s so the badges are not linked: %s", o.Dir, err.Error())
+		gitInfo = &giturl.GitRepository{}
+	}
+	o.Badges = gits.ReleaseBadges(gitInfo, o.GitKind, tag, rev, date, count, o.Color)
+
+	data, err := o.marshalBadges()
+	if err != nil {
+		return err
+	}
+	if o.Readme != "" {
+		return o.updateReadme(gits.BadgesMarkdown(o.Badges))
+	}
+	if o.OutputFile == "" {
+		fmt.Println(data)
+		return nil
+	}
+	err = ioutil.WriteFile(o.OutputFile, []byte(data), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", o.OutputFile)
+	}
+	log.Logger().Infof("saved the badges to %s", termcolor.ColorInfo(o.OutputFile))
+	return nil
+}
+
+// Git returns the git client
+func (o *Options) Git() gitclient.Interface {
+	if o.GitClient == nil {
+		o.GitClient = cli.NewCLIClient("", o.CommandRunner)
+	}
+	return o.GitClient
+}
+
+func (o *Options) marshalBadges() (string, error) {
+	if o.Output == OutputFormatJSON {
+		data, err := json.MarshalIndent(o.Badges, "", "  ")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to marshal the badges")
+		}
+		return string(data), nil
+	}
+	return gits.BadgesMarkdown(o.Badges), nil
+}
+
+// updateReadme replaces the badges between the markers of the README file
+func (o *Options) updateReadme(markdown string) error {
+	data, err := ioutil.ReadFile(o.Readme)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file %s", o.Readme)
+	}
+	text, err := ReplaceBadges(string(data), markdown)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update file %s", o.Readme)
+	}
+	if text == string(data) {
+		log.Logger().Infof("the badges in %s are up to date", termcolor.ColorInfo(o.Readme))
+		return nil
+	}
+	err = ioutil.WriteFile(o.Readme, []byte(text), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", o.Readme)
+	}
+	log.Logger().Infof("updated the badges in %s", termcolor.ColorInfo(o.Readme))
+	return nil
+}
+
+// ReplaceBadges replaces the text between the StartMarker and EndMarker with the badge markdown
+func ReplaceBadges(text, markdown string) (string, error) {
+	start := strings.Index(text, StartMarker)
+	if start < 0 {
+		return "", errors.Errorf("missing the %s comment", StartMarker)
+	}
+	start += len(StartMarker)
+	end := strings.Index(text[start:], EndMarker)
+	if end < 0 {
+		return "", errors.Errorf("missing the %s comment after the %s comment", EndMarker, StartMarker)
+	}
+	return text[:start] + "\n" + markdown + "\n" + text[start+end:], nil
+}
@@ -0,0 +1,79 @@
+// +build unit
+
+package badges_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/badges"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestBadges(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	released := time.Date(2021, time.March, 31, 10, 0, 0, 0, time.UTC)
+	for i, message := range []string{"initial commit", "feat: cheese", "fix: the cork", "feat: wine"} {
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: released.Add(time.Duration(i-1) * time.Hour)}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		if i == 1 {
+			_, err = repo.CreateTag("v1.2.3", hash, nil)
+			require.NoError(t, err, "failed to tag %s", hash.String())
+		}
+	}
+
+	readme := filepath.Join(dir, "README.md")
+	err = ioutil.WriteFile(readme, []byte("# Cheese\n\n"+badges.StartMarker+"\nold badges\n"+badges.EndMarker+"\n\nSome docs\n"), 0600)
+	require.NoError(t, err, "failed to save %s", readme)
+
+	_, o := badges.NewCmdBadges()
+	o.Dir = dir
+	o.Readme = readme
+	err = o.Run()
+	require.NoError(t, err, "failed to generate the badges")
+
+	require.Len(t, o.Badges, 3)
+	assert.Equal(t, "v1.2.3", o.Badges[0].Message)
+	assert.Equal(t, "2021-03-31", o.Badges[1].Message)
+	assert.Equal(t, "2", o.Badges[2].Message)
+
+	data, err := ioutil.ReadFile(readme)
+	require.NoError(t, err, "failed to read %s", readme)
+	expected := "# Cheese\n\n" + badges.StartMarker + "\n" +
+		"![release](https://img.shields.io/badge/release-v1.2.3-blue) " +
+		"![release date](https://img.shields.io/badge/release%20date-2021--03--31-blue) " +
+		"![commits since v1.2.3](https://img.shields.io/badge/commits%20since%20v1.2.3-2-blue)\n" +
+		badges.EndMarker + "\n\nSome docs\n"
+	assert.Equal(t, expected, string(data))
+
+	_, err = badges.ReplaceBadges("# Cheese\n", "")
+	assert.Error(t, err, "should fail without the markers")
+}
+
+func TestReleaseBadges(t *testing.T) {
+	t.Parallel()
+	info := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+	released := time.Date(2021, time.March, 31, 10, 0, 0, 0, time.UTC)
+
+	markdown := gits.BadgesMarkdown(gits.ReleaseBadges(info, "", "v1.2.3", "main", released, 5, "green"))
+	expected := "[![release](https://img.shields.io/badge/release-v1.2.3-green)](https://github.com/jstrachan/foo/releases/tag/v1.2.3) " +
+		"[![release date](https://img.shields.io/badge/release%20date-2021--03--31-green)](https://github.com/jstrachan/foo/releases/tag/v1.2.3) " +
+		"[![commits since v1.2.3](https://img.shields.io/badge/commits%20since%20v1.2.3-5-green)](https://github.com/jstrachan/foo/compare/v1.2.3...main)"
+	assert.Equal(t, expected, markdown)
+}
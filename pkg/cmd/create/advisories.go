@@ -0,0 +1,46 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// securityAdvisories returns the published security advisories of the repository which are fixed by the release
+func (o *Options) securityAdvisories(scmClient *scm.Client, release *v1.Release, version string) []gits.SecurityAdvisory {
+	if scmClient == nil || o.AzureClient != nil {
+		return nil
+	}
+	fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	var advisories []*scmapi.SecurityAdvisory
+	err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+		var res *scm.Response
+		var err error
+		advisories, res, err = scmapi.ListSecurityAdvisories(o.Context, scmClient, fullName)
+		return res, err
+	})
+	if err != nil {
+		log.Logger().Warnf("failed to list the security advisories: %s", err.Error())
+		return nil
+	}
+	if version == gits.UnreleasedVersion {
+		version = ""
+	}
+	var answer []gits.SecurityAdvisory
+	for _, a := range advisories {
+		if !gits.AdvisoryFixedByRelease(&release.Spec, version, a.Description, a.PatchedVersions) {
+			continue
+		}
+		answer = append(answer, gits.SecurityAdvisory{
+			ID:       a.ID,
+			CVE:      a.CVE,
+			Summary:  a.Summary,
+			Severity: a.Severity,
+			URL:      a.Link,
+		})
+	}
+	log.Logger().Debugf("found %d of %d security advisories fixed by the release", len(answer), len(advisories))
+	return answer
+}
@@ -0,0 +1,54 @@
+// +build unit
+
+package create_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/create"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestCreateFailsWithMissingAliasesFile(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/jstrachan/foo.git"}})
+	require.NoError(t, err, "failed to add the remote")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+	for i, message := range []string{"initial commit", "feat: cheese"} {
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now()}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		if i == 0 {
+			_, err = repo.CreateTag("v1.0.0", hash, nil)
+			require.NoError(t, err, "failed to tag %s", hash.String())
+		}
+	}
+
+	scmClient, _ := scmfake.NewDefault()
+	_, o := create.NewCmdChangelogCreate()
+	o.ScmFactory.Dir = dir
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.Owner = "jstrachan"
+	o.ScmFactory.Repository = "foo"
+	o.Offline = true
+	o.Unreleased = true
+	o.NoKubernetes = true
+	o.OutputMarkdownFile = filepath.Join(dir, "changelog.md")
+	o.AliasesFile = filepath.Join(dir, "missing-aliases.yaml")
+	err = o.Run()
+	require.Error(t, err, "should fail if the explicit aliases file does not exist")
+	assert.Contains(t, err.Error(), "--aliases-file")
+}
@@ -0,0 +1,140 @@
+package create
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CoAuthorsAnnotation the annotation on the Release listing the co-authors of the commits
+	CoAuthorsAnnotation = "changelog.jenkins-x.io/co-authors"
+
+	// ReviewersAnnotation the annotation on the Release listing the reviewers of the pull requests
+	ReviewersAnnotation = "changelog.jenkins-x.io/reviewers"
+
+	// ApproversAnnotation the annotation on the Release listing the approvers of the pull requests
+	ApproversAnnotation = "changelog.jenkins-x.io/approvers"
+
+	// FirstTimeContributorsAnnotation the annotation on the Release listing the contributors making their first contribution
+	FirstTimeContributorsAnnotation = "changelog.jenkins-x.io/first-time-contributors"
+
+	// BreakingChangesAnnotation the annotation on the Release listing the breaking changes one per line if there are any
+	BreakingChangesAnnotation = "changelog.jenkins-x.io/breaking-changes"
+
+	// ConventionalCommitsAnnotation the annotation on the Release containing the JSON of the parsed conventional commits
+	ConventionalCommitsAnnotation = "changelog.jenkins-x.io/conventional-commits"
+
+	// MaxConventionalCommitsAnnotationSize the maximum size of the conventional commits annotation so that the
+	// annotations of the Release stay well below the 256KB limit of Kubernetes
+	MaxConventionalCommitsAnnotationSize = 64 * 1024
+)
+
+// addCoAuthorsAnnotation records the co-authors of the commits on the release
+func (o *Options) addCoAuthorsAnnotation(release *v1.Release) {
+	var coAuthors []v1.UserDetails
+	for _, commit := range release.Spec.Commits {
+		coAuthors = append(coAuthors, o.State.CoAuthors[commit.SHA]...)
+	}
+	setUsersAnnotation(release, CoAuthorsAnnotation, coAuthors)
+}
+
+// addReviewers resolves the reviewers of the pull requests in the release
+func (o *Options) addReviewers(release *v1.Release) error {
+	var numbers []int
+	for _, pr := range release.Spec.PullRequests {
+		n, err := strconv.Atoi(pr.ID)
+		if err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	reviewers, err := o.State.Resolver.PullRequestReviewers(o.Context, fullName, numbers)
+	o.State.Reviewers = reviewers
+	if err != nil {
+		return errors.Wrapf(err, "failed to find reviewers")
+	}
+	return nil
+}
+
+// addReviewersAnnotations records the reviewers and approvers of the pull requests on the release
+func (o *Options) addReviewersAnnotations(release *v1.Release) {
+	reviewers := o.State.Reviewers
+	if reviewers == nil {
+		return
+	}
+	setUsersAnnotation(release, ReviewersAnnotation, reviewers.Reviewers)
+	setUsersAnnotation(release, ApproversAnnotation, reviewers.Approvers)
+}
+
+// addConventionalCommitsAnnotation records the type, scope, breaking flag and footers of the conventional commits on the release
+func addConventionalCommitsAnnotation(release *v1.Release) error {
+	var summaries []conventional.Commit
+	for _, c := range conventionalCommits(release.Spec.Commits) {
+		summaries = append(summaries, conventional.Commit{
+			SHA:          c.SHA,
+			Conventional: c.Conventional,
+			Type:         c.Type,
+			Scope:        c.Scope,
+			Breaking:     c.Breaking,
+			Footers:      c.Footers,
+		})
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(summaries)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the conventional commits")
+	}
+	if len(data) > MaxConventionalCommitsAnnotationSize {
+		log.Logger().Warnf("not adding the %s annotation as the %d commits are too large at %d bytes", ConventionalCommitsAnnotation, len(summaries), len(data))
+		return nil
+	}
+	if release.Annotations == nil {
+		release.Annotations = map[string]string{}
+	}
+	release.Annotations[ConventionalCommitsAnnotation] = string(data)
+	return nil
+}
+
+// addBreakingChangesAnnotation flags the release as containing breaking changes
+func (o *Options) addBreakingChangesAnnotation(release *v1.Release) {
+	breakingChanges := gits.BreakingChanges(release.Spec.Commits, o.State.Config.CommitConvention())
+	if len(breakingChanges) == 0 {
+		return
+	}
+	if release.Annotations == nil {
+		release.Annotations = map[string]string{}
+	}
+	release.Annotations[BreakingChangesAnnotation] = strings.Join(breakingChanges, "\n")
+}
+
+// setUsersAnnotation sets the annotation to the comma separated unique logins (or names) of the users
+func setUsersAnnotation(release *v1.Release, key string, users []v1.UserDetails) {
+	var names []string
+	for _, u := range users {
+		name := u.Login
+		if name == "" {
+			name = u.Name
+		}
+		if name != "" && stringhelpers.StringArrayIndex(names, name) < 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	if release.Annotations == nil {
+		release.Annotations = map[string]string{}
+	}
+	release.Annotations[key] = strings.Join(names, ",")
+}
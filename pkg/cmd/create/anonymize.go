@@ -0,0 +1,59 @@
+package create
+
+import (
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// anonymizeRelease removes the personal data of the users in the release, the co-authors and the reviewers
+func (o *Options) anonymizeRelease(release *v1.Release) {
+	a := o.State.Anonymizer
+	spec := &release.Spec
+	for i := range spec.Commits {
+		commit := &spec.Commits[i]
+		commit.Author = a.Anonymize(commit.Author)
+		commit.Committer = a.Anonymize(commit.Committer)
+		commit.Message = a.AnonymizeText(commit.Message)
+	}
+	anonymizeIssues := func(issues []v1.IssueSummary) {
+		for i := range issues {
+			issue := &issues[i]
+			issue.User = a.Anonymize(issue.User)
+			issue.ClosedBy = a.Anonymize(issue.ClosedBy)
+			issue.Assignees = a.AnonymizeSlice(issue.Assignees)
+		}
+	}
+	anonymizeIssues(spec.Issues)
+	anonymizeIssues(spec.PullRequests)
+
+	for sha, coAuthors := range o.State.CoAuthors {
+		o.State.CoAuthors[sha] = a.AnonymizeSlice(coAuthors)
+	}
+	o.State.FirstTimeContributors = a.AnonymizeSlice(o.State.FirstTimeContributors)
+	for _, s := range o.State.ContributorSummary {
+		s.User = *a.Anonymize(&s.User)
+	}
+	if o.State.Reviewers != nil {
+		o.State.Reviewers.Reviewers = a.AnonymizeSlice(o.State.Reviewers.Reviewers)
+		o.State.Reviewers.Approvers = a.AnonymizeSlice(o.State.Reviewers.Approvers)
+	}
+}
+
+// withoutUserDetails returns a copy of the release without any user details so they are not stored in the Release resource
+func withoutUserDetails(release *v1.Release) *v1.Release {
+	answer := release.DeepCopy()
+	spec := &answer.Spec
+	for i := range spec.Commits {
+		spec.Commits[i].Author = nil
+		spec.Commits[i].Committer = nil
+	}
+	removeIssueUsers := func(issues []v1.IssueSummary) {
+		for i := range issues {
+			issues[i].User = nil
+			issues[i].ClosedBy = nil
+			issues[i].Assignees = nil
+		}
+	}
+	removeIssueUsers(spec.Issues)
+	removeIssueUsers(spec.PullRequests)
+	return answer
+}
@@ -0,0 +1,70 @@
+package create
+
+import (
+	"os"
+	"path"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/azure"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/gitdiscovery"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// discoverAzureRepository returns the Azure DevOps repository of the source URL or the git clone or nil if the
+// repository is not hosted on Azure DevOps
+func (o *Options) discoverAzureRepository() *azure.Repository {
+	if o.AzureClient != nil {
+		return o.AzureClient.Repository
+	}
+	sourceURL := o.ScmFactory.SourceURL
+	if sourceURL == "" {
+		sourceURL, _ = gitdiscovery.FindGitURLFromDir(o.ScmFactory.Dir, o.ScmFactory.PreferUpstream)
+	}
+	repo, ok := azure.ParseURL(sourceURL)
+	if !ok {
+		if o.ScmFactory.GitKind == azure.GitKind {
+			log.Logger().Warnf("could not parse the Azure DevOps git URL %s", sourceURL)
+		}
+		return nil
+	}
+	o.ScmFactory.SourceURL = sourceURL
+	return repo
+}
+
+// useAzureRepository uses the Azure DevOps REST API for the repository instead of a go-scm client
+func (o *Options) useAzureRepository(repo *azure.Repository) {
+	f := &o.ScmFactory
+	f.GitKind = azure.GitKind
+	f.GitURL = repo.GitRepository()
+	f.GitServerURL = repo.ServerURL
+	f.Owner = repo.Project
+	f.Repository = repo.Name
+	f.FullRepositoryName = scm.Join(repo.Project, repo.Name)
+	if o.AzureClient == nil {
+		token := stringhelpers.FirstNotEmptyString(f.GitToken, os.Getenv("AZURE_DEVOPS_EXT_PAT"), os.Getenv("GIT_TOKEN"))
+		o.AzureClient = azure.NewClient(repo, token)
+	}
+	log.Logger().Debugf("using the Azure DevOps repository %s", repo.HTMLURL())
+}
+
+// publishAzureRelease publishes the release notes on Azure DevOps which has no releases as a page of the --azure-wiki
+// or, if no wiki is configured, by annotating the tag
+func (o *Options) publishAzureRelease(dir, tagName, description string, release *v1.Release) error {
+	if o.AzureWiki == "" {
+		return o.publishTagRelease("Azure DevOps", dir, tagName, description)
+	}
+	if len(o.Assets) > 0 {
+		log.Logger().Warnf("the Azure DevOps git provider does not support release assets so they are not uploaded")
+	}
+	page := path.Join("/", o.AzureWikiPath, tagName)
+	url, _, err := o.AzureClient.PutWikiPage(o.Context, o.AzureWiki, page, description)
+	if err != nil {
+		log.Logger().Warnf("Failed to publish the release notes to wiki %s page %s: %s", o.AzureWiki, page, err)
+		return nil
+	}
+	release.Spec.ReleaseNotesURL = url
+	log.Logger().Infof("updated the release notes at %s", info(stringhelpers.FirstNotEmptyString(url, page)))
+	return nil
+}
@@ -0,0 +1,44 @@
+package create
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// updateChangelogFile inserts the section of the release into the changelog file replacing any existing section
+// for the same version. The Unreleased section is replaced when using --unreleased
+func (o *Options) updateChangelogFile(version string, date time.Time, markdown string) error {
+	if version == "" || version == SpecVersion {
+		log.Logger().Warnf("cannot update the changelog file %s without a --version", info(o.ChangelogFile))
+		return nil
+	}
+	existing := ""
+	exists, err := files.FileExists(o.ChangelogFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", o.ChangelogFile)
+	}
+	if exists {
+		data, err := ioutil.ReadFile(o.ChangelogFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", o.ChangelogFile)
+		}
+		existing = string(data)
+	}
+	heading := fmt.Sprintf("[%s] - %s", version, o.State.Config.FormatDate(date))
+	if version == gits.UnreleasedVersion {
+		heading = "[" + gits.UnreleasedVersion + "]"
+	}
+	text := gits.InsertChangelogFileSection(existing, version, gits.ChangelogFileSection(heading, markdown))
+	err = ioutil.WriteFile(o.ChangelogFile, []byte(text), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save %s", o.ChangelogFile)
+	}
+	log.Logger().Infof("added the release %s to %s", info(version), info(o.ChangelogFile))
+	return nil
+}
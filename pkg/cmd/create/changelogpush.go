@@ -0,0 +1,138 @@
+package create
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PushChangelogDirect commits the changelog file and pushes it to the branch of the release
+	PushChangelogDirect = "push"
+
+	// PushChangelogPullRequest commits the changelog file to a new branch and creates a pull request into the branch
+	// of the release so that it can be merged into protected branches
+	PushChangelogPullRequest = "pull-request"
+
+	// PushChangelogAuto pushes the changelog file to the branch of the release and falls back to a pull request if
+	// the push is rejected such as by a protected branch
+	PushChangelogAuto = "auto"
+)
+
+// PushChangelogModes the supported ways of pushing the updated changelog file
+var PushChangelogModes = []string{PushChangelogDirect, PushChangelogPullRequest, PushChangelogAuto}
+
+// pushChangelogFile commits the updated changelog file and pushes it to the branch of the release or, if the branch
+// is protected, creates a pull request with the change
+func (o *Options) pushChangelogFile(scmClient *scm.Client, dir, version string) error {
+	if o.PushChangelog == "" || version == "" || version == SpecVersion {
+		return nil
+	}
+	base, err := o.ScmFactory.GetBranch()
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the branch of the release")
+	}
+	if base == "" || base == "HEAD" {
+		return errors.Errorf("cannot push the changelog file %s from a detached HEAD without a --branch", o.ChangelogFile)
+	}
+	message := fmt.Sprintf("chore: add %s to %s", version, filepath.Base(o.ChangelogFile))
+	if o.DryRun {
+		log.Logger().Infof("would commit %s and push it via %s to %s", info(o.ChangelogFile), o.PushChangelog, info(base))
+		return nil
+	}
+	// lets use an absolute path as the changelog file is relative to the working directory rather than the repository
+	file, err := filepath.Abs(o.ChangelogFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the absolute path of %s", o.ChangelogFile)
+	}
+	committed, err := gits.CommitFile(o.Git(), dir, file, message)
+	if err != nil {
+		return err
+	}
+	if !committed {
+		log.Logger().Infof("the changelog file %s has no changes to push", info(o.ChangelogFile))
+		return nil
+	}
+
+	if o.PushChangelog != PushChangelogPullRequest {
+		err = gits.PushBranch(o.Git(), dir, "origin", "HEAD", base, false)
+		if err == nil {
+			log.Logger().Infof("pushed %s to %s", info(o.ChangelogFile), info(base))
+			return nil
+		}
+		if o.PushChangelog == PushChangelogDirect {
+			return err
+		}
+		log.Logger().Warnf("failed to push %s to %s so creating a pull request instead: %s", o.ChangelogFile, base, err.Error())
+	}
+	return o.createChangelogPullRequest(scmClient, dir, version, base, message)
+}
+
+// createChangelogPullRequest pushes the changelog commit to the --changelog-branch and creates a pull request into the
+// branch of the release labelled with the --changelog-pr-label labels such as one which enables auto merging
+func (o *Options) createChangelogPullRequest(scmClient *scm.Client, dir, version, base, message string) error {
+	if scmClient == nil || o.AzureClient != nil {
+		return errors.Errorf("cannot create a pull request for the changelog file %s without a git provider client", o.ChangelogFile)
+	}
+	branch := o.pullRequestBranch(version)
+	// lets replace the branch of a previous run of the same release
+	err := gits.PushBranch(o.Git(), dir, "origin", "HEAD", branch, true)
+	if err != nil {
+		return err
+	}
+	fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	input := &scm.PullRequestInput{
+		Title: message,
+		Head:  branch,
+		Base:  base,
+		Body:  fmt.Sprintf("Adds the release %s to %s.", version, filepath.Base(o.ChangelogFile)),
+	}
+	var pr *scm.PullRequest
+	var created bool
+	err = o.backoff().Do(o.Context, func() (*scm.Response, error) {
+		var res *scm.Response
+		var err error
+		pr, created, res, err = scmapi.CreatePullRequestOnce(o.Context, scmClient, fullName, input)
+		return res, err
+	})
+	if err != nil {
+		return err
+	}
+	for _, label := range o.ChangelogPRLabels {
+		err = o.backoff().Do(o.Context, func() (*scm.Response, error) {
+			return scmClient.PullRequests.AddLabel(o.Context, fullName, pr.Number, label)
+		})
+		if err != nil {
+			log.Logger().Warnf("failed to add the label %s to pull request %d: %s", label, pr.Number, err.Error())
+		}
+	}
+	if created {
+		log.Logger().Infof("created pull request %s to add %s to %s", info(pr.Link), info(o.ChangelogFile), info(base))
+	} else {
+		log.Logger().Infof("updated pull request %s to add %s to %s", info(pr.Link), info(o.ChangelogFile), info(base))
+	}
+	return nil
+}
+
+// pullRequestBranch returns the branch of the pull request created by --push-changelog
+func (o *Options) pullRequestBranch(version string) string {
+	if o.ChangelogBranch != "" {
+		return o.ChangelogBranch
+	}
+	return "changelog-" + strings.TrimPrefix(version, "v")
+}
+
+// changelogBranch returns the branch the changelog file is pushed to by --push-changelog. If the push falls back to
+// a pull request this is still the branch of the release which the pull request is merged into
+func (o *Options) changelogBranch(version string) (string, error) {
+	if o.PushChangelog == PushChangelogPullRequest {
+		return o.pullRequestBranch(version), nil
+	}
+	return o.ScmFactory.GetBranch()
+}
@@ -0,0 +1,26 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// previousChannelTag returns the commit and name of the previous tag of the same channel as the current or latest tag
+func (o *Options) previousChannelTag(dir string) (string, string, error) {
+	tag := o.CurrentTag
+	if tag == "" {
+		_, latest, err := gits.GetCommitPointedToByLatestTag(o.Git(), dir)
+		if err != nil {
+			return "", "", err
+		}
+		tag = latest
+	}
+	rev, previousTag, err := gits.GetCommitPointedToByPreviousChannelTag(o.Git(), dir, tag)
+	if err != nil {
+		return "", "", err
+	}
+	if previousTag != "" {
+		log.Logger().Debugf("comparing tag %s to the previous tag %s of the same channel", tag, previousTag)
+	}
+	return rev, previousTag, nil
+}
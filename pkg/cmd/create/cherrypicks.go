@@ -0,0 +1,186 @@
+package create
+
+import (
+	"strings"
+
+	chgit "github.com/antham/chyle/chyle/git"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// cherryPicks the cherry picked commits of a release
+type cherryPicks struct {
+	repo *git.Repository
+	// sources the SHAs of the commits which were cherry picked indexed by the SHA of the cherry pick
+	sources map[string]string
+	// shipped the SHAs of the source commits which are in a release tagged before this release
+	shipped map[string]bool
+}
+
+// findCherryPicks finds the source of the cherry picked commits of the release using the trailer added by
+// 'git cherry-pick -x' or, if a cherry pick ref is configured, by comparing the patch IDs of the commits with the
+// commits on the ref
+func (o *Options) findCherryPicks(gitDir, previousRev, currentRev string, commits []object.Commit) (*cherryPicks, error) {
+	repo, err := git.PlainOpen(gitDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open git repository %s", gitDir)
+	}
+	answer := &cherryPicks{
+		repo:    repo,
+		sources: map[string]string{},
+		shipped: map[string]bool{},
+	}
+	var patchIDs map[string]string
+	if o.CherryPickRef != "" {
+		patchIDs, err = refPatchIDs(gitDir, previousRev, o.CherryPickRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range commits {
+		commit := &commits[i]
+		sha := commit.Hash.String()
+		source := gits.CherryPickSource(commit.Message)
+		if source == "" && len(patchIDs) > 0 {
+			id, err := gits.PatchID(commit)
+			if err != nil {
+				log.Logger().Warnf("failed to find the patch ID of commit %s: %s", sha, err.Error())
+				continue
+			}
+			source = patchIDs[id]
+		}
+		if source != "" && source != sha {
+			answer.sources[sha] = source
+		}
+	}
+
+	if o.CherryPicks == gits.CherryPickModeSkip && len(answer.sources) > 0 {
+		err = answer.findShipped(currentRev, commits)
+		if err != nil {
+			return nil, err
+		}
+	}
+	log.Logger().Debugf("found %d cherry picked commits of which %d already shipped", len(answer.sources), len(answer.shipped))
+	return answer, nil
+}
+
+// refPatchIDs returns the SHAs of the commits on the ref since the previous revision indexed by their patch ID
+func refPatchIDs(gitDir, previousRev, ref string) (map[string]string, error) {
+	commits, err := chgit.FetchCommits(gitDir, previousRev, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the commits of %s", ref)
+	}
+	answer := map[string]string{}
+	if commits == nil {
+		return answer, nil
+	}
+	for i := range *commits {
+		commit := &(*commits)[i]
+		id, err := gits.PatchID(commit)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the patch ID of commit %s", commit.Hash.String())
+		}
+		if id != "" {
+			answer[id] = commit.Hash.String()
+		}
+	}
+	return answer, nil
+}
+
+// findShipped finds the sources which are reachable from the tags of prior releases. Tags of the current revision
+// or of the commits in this release are ignored
+func (c *cherryPicks) findShipped(currentRev string, commits []object.Commit) error {
+	ignore := map[plumbing.Hash]bool{}
+	for i := range commits {
+		ignore[commits[i].Hash] = true
+	}
+	current, err := c.repo.ResolveRevision(plumbing.Revision(currentRev))
+	if err == nil {
+		ignore[*current] = true
+	}
+
+	refs, err := c.repo.Tags()
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the tags")
+	}
+	var tags []plumbing.Hash
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		hash, err := c.repo.ResolveRevision(plumbing.Revision(ref.Name().String()))
+		if err != nil {
+			log.Logger().Debugf("ignoring tag %s as it could not be resolved: %s", ref.Name().Short(), err.Error())
+			return nil
+		}
+		if !ignore[*hash] {
+			tags = append(tags, *hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to iterate the tags")
+	}
+
+	sources := map[string]bool{}
+	for _, source := range c.sources {
+		sources[source] = true
+	}
+	visited := map[plumbing.Hash]bool{}
+	stack := tags
+	for len(stack) > 0 && len(c.shipped) < len(sources) {
+		hash := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[hash] {
+			continue
+		}
+		visited[hash] = true
+		sha := hash.String()
+		for source := range sources {
+			if strings.HasPrefix(sha, source) {
+				c.shipped[source] = true
+			}
+		}
+		commit, err := c.repo.CommitObject(hash)
+		if err != nil {
+			if err == plumbing.ErrObjectNotFound {
+				// lets ignore the missing history of shallow clones
+				continue
+			}
+			return errors.Wrapf(err, "failed to find commit %s", sha)
+		}
+		stack = append(stack, commit.ParentHashes...)
+	}
+	return nil
+}
+
+// isShipped returns true if the commit is a cherry pick of a commit which shipped in a prior release
+func (c *cherryPicks) isShipped(sha string) bool {
+	if c == nil {
+		return false
+	}
+	source := c.sources[sha]
+	return source != "" && c.shipped[source]
+}
+
+// backportedFrom returns the pull request or commit the commit was cherry picked from or an empty string
+// if it is not a cherry pick
+func (c *cherryPicks) backportedFrom(sha string) string {
+	if c == nil {
+		return ""
+	}
+	source := c.sources[sha]
+	if source == "" {
+		return ""
+	}
+	var sourceCommit *object.Commit
+	hash, err := c.repo.ResolveRevision(plumbing.Revision(source))
+	if err == nil {
+		sourceCommit, err = c.repo.CommitObject(*hash)
+	}
+	if err != nil {
+		log.Logger().Debugf("failed to find the source commit %s of cherry pick %s: %s", source, sha, err.Error())
+	}
+	return gits.BackportedFrom(source, sourceCommit)
+}
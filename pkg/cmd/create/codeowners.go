@@ -0,0 +1,65 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/codeowners"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// OwnerCommits the commits in a release which touch the paths owned by an owner in the CODEOWNERS file
+type OwnerCommits struct {
+	Owner   string
+	Commits []v1.CommitSummary
+}
+
+// addCommitOwners finds the owners of the paths touched by each commit using the CODEOWNERS file
+func (o *Options) addCommitOwners(commits []*object.Commit, gitDir string) error {
+	var owners *codeowners.CodeOwners
+	var err error
+	if o.CodeOwnersFile != "" {
+		owners, err = codeowners.LoadFile(o.CodeOwnersFile)
+	} else {
+		owners, err = codeowners.Load(gitDir)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to load CODEOWNERS")
+	}
+	if owners == nil {
+		log.Logger().Infof("no CODEOWNERS file found in %s", gitDir)
+		return nil
+	}
+
+	o.State.CommitOwners = map[string][]string{}
+	for _, commit := range commits {
+		stats, err := commit.Stats()
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the files changed by commit %s", commit.Hash.String())
+		}
+		var paths []string
+		for _, s := range stats {
+			paths = append(paths, s.Name)
+		}
+		o.State.CommitOwners[commit.Hash.String()] = owners.PathsOwners(paths)
+	}
+	return nil
+}
+
+// groupCommitsByOwner groups the commits by the owners of the paths they touch in the order the owners are found
+func groupCommitsByOwner(commits []v1.CommitSummary, commitOwners map[string][]string) []*OwnerCommits {
+	var answer []*OwnerCommits
+	groups := map[string]*OwnerCommits{}
+	for _, commit := range commits {
+		for _, owner := range commitOwners[commit.SHA] {
+			group := groups[owner]
+			if group == nil {
+				group = &OwnerCommits{Owner: owner}
+				groups[owner] = group
+				answer = append(answer, group)
+			}
+			group.Commits = append(group.Commits, commit)
+		}
+	}
+	return answer
+}
@@ -0,0 +1,43 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/pkg/errors"
+)
+
+// addFirstTimeContributors finds the commit authors in the release who have not contributed to the repository before
+func (o *Options) addFirstTimeContributors(release *v1.Release, gitDir, previousRev string) error {
+	resolver := o.State.Resolver
+	detector := &contributors.FirstTimeDetector{
+		GitProvider: resolver.GitProvider,
+		Repository:  scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository),
+		Mailmap:     resolver.Mailmap,
+		Backoff:     resolver.Backoff,
+		Offline:     resolver.Offline,
+	}
+	err := detector.LoadHistory(gitDir, previousRev)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the contributors before %s", previousRev)
+	}
+
+	for _, c := range contributors.FromCommits(release.Spec.Commits) {
+		var identities [][2]string
+		for _, u := range c.Identities {
+			key := u.Login
+			if key == "" {
+				key = u.Name
+			}
+			identities = append(identities, o.State.AuthorIdentities[key]...)
+		}
+		firstTime, err := detector.IsFirstTime(o.Context, &c.User, identities)
+		if err != nil {
+			return err
+		}
+		if firstTime {
+			o.State.FirstTimeContributors = append(o.State.FirstTimeContributors, c.User)
+		}
+	}
+	return nil
+}
@@ -14,17 +14,21 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/azure"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/config"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gitprovider"
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/helmhelpers"
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/issues"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/postpublish"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
 	"github.com/jenkins-x/go-scm/scm"
 	jxc "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/builds"
-	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
-	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/activities"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxclient"
@@ -56,37 +60,142 @@ import (
 type Options struct {
 	options.BaseOptions
 
-	ScmFactory    scmhelpers.Options
-	GitClient     gitclient.Interface
-	CommandRunner cmdrunner.CommandRunner
-	JXClient      jxc.Interface
-
-	Namespace           string
-	BuildNumber         string
-	PreviousRevision    string
-	PreviousDate        string
-	CurrentRevision     string
-	TemplatesDir        string
-	ReleaseYamlFile     string
-	CrdYamlFile         string
-	Version             string
-	Build               string
-	Header              string
-	HeaderFile          string
-	Footer              string
-	FooterFile          string
-	OutputMarkdownFile  string
-	OverwriteCRD        bool
-	GenerateCRD         bool
-	GenerateReleaseYaml bool
-	ConditionalRelease  bool
-	UpdateRelease       bool
-	NoReleaseInDev      bool
-	IncludeMergeCommits bool
-	FailIfFindCommits   bool
-	Draft               bool
-	Prerelease          bool
-	State               State
+	gitprovider.Options
+	AzureClient *azure.Client
+	JXClient    jxc.Interface
+	PostPublish postpublish.Options
+	// Context cancels any calls to the git provider when done. Defaults to a background context
+	Context context.Context
+
+	Namespace             string
+	BuildNumber           string
+	PreviousRevision      string
+	PreviousDate          string
+	CurrentRevision       string
+	FromSHA               string
+	ToSHA                 string
+	Since                 string
+	Until                 string
+	TemplatesDir          string
+	ReleaseYamlFile       string
+	CrdYamlFile           string
+	Version               string
+	Build                 string
+	Header                string
+	HeaderFile            string
+	Footer                string
+	FooterFile            string
+	OutputMarkdownFile    string
+	OutputFormat          string
+	OutputFile            string
+	Outputs               []string
+	FrontMatter           bool
+	FrontMatterTags       []string
+	ChangelogFile         string
+	PushChangelog         string
+	ChangelogBranch       string
+	ChangelogPRLabels     []string
+	HTMLTemplateFile      string
+	MarkdownFlavor        string
+	Locale                string
+	StringsFile           string
+	TemplateFile          string
+	SectionEmoji          bool
+	NoEmoji               bool
+	CompareLink           bool
+	CompareLinkPosition   string
+	EntryLinks            string
+	FeedFile              string
+	FeedFormat            string
+	FeedTitle             string
+	OverwriteCRD          bool
+	GenerateCRD           bool
+	GenerateReleaseYaml   bool
+	CommitsAnnotation     bool
+	ConditionalRelease    bool
+	UpdateRelease         bool
+	Unreleased            bool
+	NoReleaseInDev        bool
+	IncludeMergeCommits   bool
+	MergeStrategy         string
+	CherryPicks           string
+	CherryPickRef         string
+	IncludePaths          []string
+	ExcludePaths          []string
+	FailIfFindCommits     bool
+	Draft                 bool
+	Prerelease            bool
+	SameChannel           bool
+	ContributorAvatars    bool
+	Reviewers             bool
+	OrgMembers            bool
+	SigningKeys           bool
+	EmailLookup           bool
+	FirstTimeContributors bool
+	ContributorSummary    bool
+	MentionContributors   bool
+	ListReverted          bool
+	ExpandSquashCommits   bool
+	CommitBody            bool
+	GroupDependencies     bool
+	RollUpDependencies    bool
+	ClassifyByKeywords    bool
+	LinkPullRequests      bool
+	FixedIssues           bool
+	PullRequestTitles     bool
+	DCO                   bool
+	DCOSection            bool
+	DCOFail               bool
+	GroupByScope          bool
+	Deduplicate           string
+	DiffStats             bool
+	SortByImpact          bool
+	SortBy                string
+	CollapseSections      []string
+	CommitBodyMaxLength   int
+	MaxReleaseBody        int
+	ReleaseAssetLinks     []string
+	Milestones            []string
+	MilestoneFromVersion  bool
+	MilestoneProgress     bool
+	SecurityAdvisories    bool
+	DryRun                bool
+	Assets                []string
+	IfExists              string
+	ProviderNotes         bool
+	MakeLatest            string
+	CreateTag             bool
+	CreateTagViaAPI       bool
+	SignTag               bool
+	TagSigningKey         string
+	TagDescription        bool
+	AzureWiki             string
+	AzureWikiPath         string
+	CodeOwners            bool
+	CodeOwnersFile        string
+	LogResolutionStats    bool
+	Anonymize             string
+	AnonymizeSalt         string
+	ExcludeBots           bool
+	Bots                  []string
+	BotSuffixes           []string
+	AliasesFile           string
+	ConfigFile            string
+	UserStore             string
+	UserCacheFile         string
+	UserCacheTTL          time.Duration
+	Offline               bool
+	ResolveWorkers        int
+	RateLimitMaxWait      time.Duration
+	RateLimitLowBudget    int
+	GraphQLBatchSize      int
+	NoKubernetes          bool
+	// PreviousTag and CurrentTag the tags of the revisions used for the compare link and the title
+	PreviousTag string
+	CurrentTag  string
+	// ReleaseDate the date of the release which defaults to the current time
+	ReleaseDate time.Time
+	State       State
 }
 
 type State struct {
@@ -94,6 +203,43 @@ type State struct {
 	FoundIssueNames map[string]bool
 	LoggedIssueKind bool
 	Release         *v1.Release
+	Resolver        *users.GitUserResolver
+	CoAuthors       map[string][]v1.UserDetails
+	Reviewers       *users.Reviewers
+	Anonymizer      *users.Anonymizer
+	// AuthorIdentities the git names and emails of the commit authors indexed by the resolved login or name
+	AuthorIdentities      map[string][][2]string
+	FirstTimeContributors []v1.UserDetails
+	ContributorSummary    []*users.ContributorStats
+	// CommitOwners the owners of the paths touched by each commit indexed by the commit SHA
+	CommitOwners    map[string][]string
+	ResolutionStats users.ResolutionStats
+	Config          *config.Config
+	// RevertedCommits the commits reverted within the release which are removed from the changes
+	RevertedCommits []gits.RevertedCommit
+	// DCO the sign off compliance of the commits
+	DCO *gits.DCOReport
+	// DiffStats the lazily computed diff statistics of the commits shared by the markdown and the templates
+	DiffStats *gits.DiffStatsCache
+	// Compare the link to the comparison with the previous release
+	Compare *gits.Compare
+	// Outputs the file outputs parsed from the --output flags
+	Outputs []*OutputSpec
+	// Tag the git tag of the release if known
+	Tag string
+	// Title the title of the release rendered from the configured title template
+	Title string
+	// PreviousTag the tag of the previous release if known
+	PreviousTag string
+	// NewTag the tag of the --version created by --create-tag
+	NewTag string
+	// MissingCommits is true if the commits of the release could not be read from the git clone such as a shallow
+	// clone without the previous tag
+	MissingCommits bool
+	// Backoff retries rate limited git provider calls and tracks the remaining rate limit
+	Backoff *scmapi.Backoff
+	// Milestone the milestone of the release if found
+	Milestone *scmapi.Milestone
 }
 
 const (
@@ -140,7 +286,7 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 		Creates a Changelog for the latest tag
 
 		This command will generate a Changelog as markdown for the git commit range given. 
-		If you are using GitHub it will also update the GitHub Release with the changelog. You can disable that by passing'--update-release=false'
+		If you are using GitHub, GitLab or Gitea it will also update the release of the tag with the changelog. You can disable that by passing'--update-release=false'
 
 		If you have just created a git tag this command will try default to the changes between the last tag and the previous one. You can always specify the exact Git references (tag/sha) directly via '--previous-rev' and '--rev'
 
@@ -166,8 +312,11 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 
 `)
 
-	GitHubIssueRegex = regexp.MustCompile(`(\#\d+)`)
-	JIRAIssueRegex   = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
+	GitHubIssueRegex      = regexp.MustCompile(`(\#\d+)`)
+	JIRAIssueRegex        = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
+	AzureBoardsIssueRegex = regexp.MustCompile(`\bAB#\d+`)
+
+	numericIssueRegex = regexp.MustCompile(`^\d+$`)
 
 	conditionalReleaseYAML = `{{- if and (.Capabilities.APIVersions.Has "jenkins.io/v1/Release") (hasKey .Values.jx "releaseCRD") (.Values.jx.releaseCRD)}}
 %s 
@@ -185,40 +334,145 @@ func NewCmdChangelogCreate() (*cobra.Command, *Options) {
 		Long:    cmdLong,
 		Example: cmdExample,
 		Run: func(cmd *cobra.Command, args []string) {
+			o.Context = cmd.Context()
 			err := o.Run()
 			helper.CheckErr(err)
 		},
 	}
+	o.AddFlags(cmd)
+	return cmd, o
+}
+
+// AddFlags adds the flags of the create command bound to the options to the command
+func (o *Options) AddFlags(cmd *cobra.Command) {
 	o.ScmFactory.DiscoverFromGit = true
 
 	cmd.Flags().StringVarP(&o.PreviousRevision, "previous-rev", "p", "", "the previous tag revision")
 	cmd.Flags().StringVarP(&o.PreviousDate, "previous-date", "", "", "the previous date to find a revision in format 'MonthName dayNumber year'")
 	cmd.Flags().StringVarP(&o.CurrentRevision, "rev", "", "", "the current tag revision")
+	cmd.Flags().StringVarP(&o.FromSHA, "from-sha", "", "", "the commit SHA after which changes are included instead of the previous tag such as for hotfix branches or environments tracking SHAs")
+	cmd.Flags().StringVarP(&o.ToSHA, "to-sha", "", "", "the commit SHA of the last change included instead of the current tag")
+	cmd.Flags().StringVarP(&o.Since, "since", "", "", "includes the changes committed since the date regardless of tags such as for weekly release notes. Supports any date git understands such as '2021-05-01' or '1 week ago'")
+	cmd.Flags().StringVarP(&o.Until, "until", "", "", "includes the changes committed until the date instead of the current tag. Supports any date git understands such as '2021-05-08'")
 	cmd.Flags().StringVarP(&o.TemplatesDir, "templates-dir", "t", "", "the directory containing the helm chart templates to generate the resources")
 	cmd.Flags().StringVarP(&o.ReleaseYamlFile, "release-yaml-file", "", "release.yaml", "the name of the file to generate the Release YAML")
 	cmd.Flags().StringVarP(&o.CrdYamlFile, "crd-yaml-file", "", "release-crd.yaml", "the name of the file to generate the Release CustomResourceDefinition YAML")
 	cmd.Flags().StringVarP(&o.Version, "version", "v", "", "The version to release")
+	cmd.Flags().BoolVarP(&o.Unreleased, "unreleased", "", false, "Generates an Unreleased section of the changes from the latest tag to HEAD without creating a release such as for nightly builds, pull request previews or the Unreleased section of --changelog-file")
 	cmd.Flags().StringVarP(&o.Build, "build", "", "", "The Build number which is used to update the PipelineActivity. If not specified its defaulted from  the '$BUILD_NUMBER' environment variable")
-	cmd.Flags().StringVarP(&o.OutputMarkdownFile, "output-markdown", "", "", "The file to generate for the changelog output if not updating a Git provider release")
+	cmd.Flags().StringVarP(&o.OutputMarkdownFile, "output-markdown", "", "", "The file to generate for the changelog output if not updating a Git provider release. Deprecated: use --output-file instead")
+	cmd.Flags().BoolVarP(&o.SectionEmoji, "section-emoji", "", false, "Renders the default emoji such as '🚀' or '🐛' before the titles of the sections which have no emoji configured")
+	cmd.Flags().BoolVarP(&o.CompareLink, "compare-link", "", false, "Renders a link to the comparison of the previous and current tags on the git provider in the changelog. Header and footer templates can use {{ .Compare.URL }}")
+	cmd.Flags().StringVarP(&o.CompareLinkPosition, "compare-link-position", "", gits.CompareLinkFooter, fmt.Sprintf("Where the --compare-link is rendered in the changelog. Supported values: %s", strings.Join(gits.CompareLinkPositions, ", ")))
+	cmd.Flags().StringVarP(&o.EntryLinks, "entry-links", "", "", fmt.Sprintf("Links each entry of the changelog to its commit or to its pull request including the pull request number, falling back to the commit if it has no pull request. Supported values: %s", strings.Join(gits.EntryLinks, ", ")))
+	cmd.Flags().BoolVarP(&o.NoEmoji, "no-emoji", "", false, "Renders no emoji before the section titles in any output format even if they are configured")
+	cmd.Flags().StringVarP(&o.TemplateFile, "template", "", "", "The text/template file used to render the changelog instead of the built-in layout. The template is executed with the parsed changelog and can use the sprig functions together with shortSHA, linkify, formatDate and escapeMarkdown")
+	cmd.Flags().StringVarP(&o.MarkdownFlavor, "markdown-flavor", "", "", fmt.Sprintf("Adjusts the user mentions, pull request references and collapsible blocks of the markdown to the platform rendering it. Users are linked rather than mentioned by default. Supported values: %s", strings.Join(gits.MarkdownFlavors, ", ")))
+	cmd.Flags().StringVarP(&o.Locale, "locale", "", "", fmt.Sprintf("The locale of the section titles and standard phrases of the changelog such as 'Full Changelog'. Defaults to English. Built-in locales: %s", strings.Join(gits.Locales(), ", ")))
+	cmd.Flags().StringVarP(&o.StringsFile, "strings-file", "", "", "The YAML file mapping the English section titles and standard phrases of the changelog to their translations. Overrides the translations of the --locale")
+	cmd.Flags().StringArrayVarP(&o.Outputs, "output", "", nil, fmt.Sprintf("The outputs to write in a single run such as 'release', 'changelog=CHANGELOG.md', 'json=changelog.json' or 'slack=slack.json'. Each output is a format with an optional file defaulting to the console. Only the listed outputs are written and the release is only updated if 'release' is listed. Supported formats: %s", strings.Join(append([]string{OutputRelease, OutputChangelogFile}, OutputFormats...), ", ")))
+	cmd.Flags().BoolVarP(&o.FrontMatter, "front-matter", "", false, "Prepends YAML front matter with the title, date and version of the release to the markdown --output-file so it can be added to the content of a docs site such as Hugo or Jekyll. Enabled if the changelog configuration has front matter")
+	cmd.Flags().StringArrayVarP(&o.FrontMatterTags, "front-matter-tag", "", nil, "The tags to add to the front matter of the markdown --output-file. Implies --front-matter")
+	cmd.Flags().StringVarP(&o.PushChangelog, "push-changelog", "", "", fmt.Sprintf("Commits the updated --changelog-file and pushes it. 'push' pushes it to the branch of the release, 'pull-request' pushes it to the --changelog-branch and creates a pull request for protected branches and 'auto' creates the pull request if the push is rejected. Supported values: %s", strings.Join(PushChangelogModes, ", ")))
+	cmd.Flags().StringVarP(&o.ChangelogBranch, "changelog-branch", "", "", "The branch of the pull request created by --push-changelog. Defaults to 'changelog-' and the version")
+	cmd.Flags().StringArrayVarP(&o.ChangelogPRLabels, "changelog-pr-label", "", nil, "The labels to add to the pull request created by --push-changelog such as one which enables auto merging")
+	cmd.Flags().StringVarP(&o.ChangelogFile, "changelog-file", "", "", "The changelog file such as CHANGELOG.md to insert the release section into below the header and the Unreleased section. An existing section for the same version is replaced")
+	cmd.Flags().StringVarP(&o.HTMLTemplateFile, "html-template", "", "", "The html/template file used to render the changelog with the html output format instead of the built-in page. The template is executed with the parsed changelog")
+	cmd.Flags().StringVarP(&o.FeedFile, "feed-file", "", "", "The RSS or Atom feed file to add the release to so that users can subscribe to the releases. The file is created if it does not exist")
+	cmd.Flags().StringVarP(&o.FeedFormat, "feed-format", "", gits.FeedFormatAtom, fmt.Sprintf("The format of the feed file. Supported values: %s", strings.Join(gits.FeedFormats, ", ")))
+	cmd.Flags().StringVarP(&o.FeedTitle, "feed-title", "", "", "The title of the feed if it is created. Defaults to the repository name followed by 'releases'")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "The file to generate for the changelog output in the output format. If updating a Git provider release the full markdown changelog is written to it even if the release notes are truncated")
+	cmd.Flags().StringVarP(&o.OutputFormat, "output-format", "", OutputFormatMarkdown, fmt.Sprintf("The format of the changelog output if not updating a Git provider release. The json and yaml formats contain the sections, entries and contributors of the changelog for use by other tools such as GitOps repositories or Helm values. The slack format is a Block Kit message which can be posted to Slack. Supported values: %s", strings.Join(OutputFormats, ", ")))
 	cmd.Flags().BoolVarP(&o.OverwriteCRD, "overwrite", "o", false, "overwrites the Release CRD YAML file if it exists")
 	cmd.Flags().BoolVarP(&o.GenerateCRD, "crd", "c", false, "Generate the CRD in the chart")
 	cmd.Flags().BoolVarP(&o.GenerateReleaseYaml, "generate-yaml", "y", false, "Generate the Release YAML in the local helm chart")
+	cmd.Flags().BoolVarP(&o.CommitsAnnotation, "conventional-commits-annotation", "", false, fmt.Sprintf("Records the JSON of the parsed conventional commits in the %s annotation of the Release unless it is larger than %dKB", ConventionalCommitsAnnotation, MaxConventionalCommitsAnnotationSize/1024))
 	cmd.Flags().BoolVarP(&o.ConditionalRelease, "conditional-release", "", true, "Wrap the Release YAML in the helm Capabilities.APIVersions.Has if statement")
 	cmd.Flags().BoolVarP(&o.UpdateRelease, "update-release", "", true, "Should we update the release on the Git repository with the changelog")
+	cmd.Flags().IntVarP(&o.MaxReleaseBody, "max-release-body", "", 0, "The maximum number of characters of the release notes. Longer changelogs are truncated at a section boundary with a link to the full changelog which is still written to --output-file. Defaults to the limit of the git provider. Use -1 for no limit")
+	cmd.Flags().StringArrayVarP(&o.ReleaseAssetLinks, "release-asset-link", "", nil, "A link to attach to the release as an asset in the form 'name=url' or 'name=url=type' where the type is one of other, runbook, image or package. Only supported on GitLab")
+	cmd.Flags().StringArrayVarP(&o.Assets, "asset", "", nil, "A file to upload to the release in the form 'path' or 'path:label' such as a binary, checksum or SBOM. The path may be a glob such as 'dist/*.tar.gz'. Supported on GitHub, GitLab and Gitea")
+	cmd.Flags().StringVarP(&o.IfExists, "if-exists", "", IfExistsReplace, fmt.Sprintf("How a release which already exists for the tag, such as one created by CI or a human, is handled. Supported values: %s", strings.Join(IfExistsPolicies, ", ")))
+	cmd.Flags().StringVarP(&o.MakeLatest, "make-latest", "", "", fmt.Sprintf("Whether the release becomes the latest release of the repository so that hotfix releases of older versions do not replace the latest release. Supported values: %s. Defaults to the behaviour of the git provider. Only supported on GitHub", strings.Join(scmapi.MakeLatestValues, ", ")))
+	cmd.Flags().BoolVarP(&o.CreateTag, "create-tag", "", false, "Creates the annotated tag of the --version at the current revision if it does not exist yet using the changelog as its message and pushes it to the origin remote")
+	cmd.Flags().BoolVarP(&o.CreateTagViaAPI, "create-tag-via-api", "", false, "Creates the tag of --create-tag via the git provider API instead of pushing it so that no push access is required. Supported on GitHub, GitLab and Gitea. The tag is not signed")
+	cmd.Flags().BoolVarP(&o.SignTag, "sign-tag", "", false, "Signs the tag of --create-tag with GPG using the signing key of the git configuration")
+	cmd.Flags().StringVarP(&o.TagSigningKey, "tag-signing-key", "", "", "The ID of the GPG key the tag of --create-tag is signed with")
+	cmd.Flags().BoolVarP(&o.ProviderNotes, "provider-notes", "", true, "Falls back to the release notes generated by the git provider when the commits of the release are unavailable such as in a shallow clone without the previous tag. Supported on GitHub and GitLab")
+	cmd.Flags().StringArrayVarP(&o.Milestones, "milestone", "", nil, "The title of the milestone of the release. GitLab releases are associated with the milestone while other git providers link to it in the release notes")
+	cmd.Flags().BoolVarP(&o.MilestoneFromVersion, "milestone-from-version", "", false, "Uses the milestone whose title is the version with or without the 'v' prefix if no --milestone is specified")
+	cmd.Flags().BoolVarP(&o.MilestoneProgress, "milestone-progress", "", true, "Links to the milestone of the release in the release notes including the number of its closed issues")
+	cmd.Flags().BoolVarP(&o.SecurityAdvisories, "security-advisories", "", false, "Lists the published security advisories of the repository which reference the commits or pull requests of the release or are patched in its version in a Security section. Supported on GitHub")
+	o.PostPublish.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Logs the tags, releases, milestones and comments which would be created or updated on the git provider without changing them")
+	cmd.Flags().BoolVarP(&o.TagDescription, "tag-description", "", false, "If the git provider does not support releases such as Bitbucket then annotate the lightweight tag with the release notes and push it. Annotated or signed tags are never changed. The release is also inserted into the --changelog-file which defaults to "+gits.DefaultChangelogFile)
+	cmd.Flags().StringVarP(&o.AzureWiki, "azure-wiki", "", "", "The name or ID of the Azure DevOps wiki to publish the release notes to as a page instead of annotating the tag")
+	cmd.Flags().StringVarP(&o.AzureWikiPath, "azure-wiki-path", "", "/Release Notes", "The path of the parent page of the release note pages in the --azure-wiki")
 	cmd.Flags().BoolVarP(&o.NoReleaseInDev, "no-dev-release", "", false, "Disables the generation of Release CRDs in the development namespace to track releases being performed")
 	cmd.Flags().BoolVarP(&o.IncludeMergeCommits, "include-merge-commits", "", false, "Include merge commits when generating the changelog")
+	cmd.Flags().StringVarP(&o.MergeStrategy, "merge-strategy", "", "", fmt.Sprintf("How merge commits and the commits of merged branches are included. Supported values: %s. Defaults to '%s' or '%s' if using --include-merge-commits", strings.Join(gits.MergeStrategies, ", "), gits.MergeStrategyNoMerges, gits.MergeStrategyAll))
+	cmd.Flags().StringVarP(&o.CherryPicks, "cherry-picks", "", "", fmt.Sprintf("How cherry picked commits are handled. Supported values: %s. Use '%s' to mark them with the pull request or commit they were backported from and '%s' to also remove those whose source already shipped in a prior release", strings.Join(gits.CherryPickModes, ", "), gits.CherryPickModeAnnotate, gits.CherryPickModeSkip))
+	cmd.Flags().StringVarP(&o.CherryPickRef, "cherry-pick-ref", "", "", "The branch commits are cherry picked from such as 'origin/main'. Used to detect cherry picks without the '(cherry picked from commit ...)' trailer by comparing patch IDs")
+	cmd.Flags().StringArrayVarP(&o.IncludePaths, "include-path", "", nil, "Only includes the commits which touch paths matching the gitignore style glob such as 'charts/' or 'pkg/**/*.go'. Useful for generating the changelog of a component of a monorepo")
+	cmd.Flags().StringArrayVarP(&o.ExcludePaths, "exclude-path", "", nil, "Ignores the changes to paths matching the gitignore style glob. Commits which only touch excluded paths are not included")
 	cmd.Flags().BoolVarP(&o.FailIfFindCommits, "fail-if-no-commits", "", false, "Do we want to fail the build if we don't find any commits to generate the changelog")
-	cmd.Flags().BoolVarP(&o.Draft, "draft", "", false, "The git provider release is marked as draft")
-	cmd.Flags().BoolVarP(&o.Prerelease, "prerelease", "", false, "The git provider release is marked as a pre-release")
+	cmd.Flags().BoolVarP(&o.Draft, "draft", "", false, "The git provider release is marked as draft so the release notes can be reviewed before they are published via 'jx-changelog publish-draft'")
+	cmd.Flags().BoolVarP(&o.Prerelease, "prerelease", "", false, "The git provider release is marked as a pre-release. Releases of pre-release tags such as v1.2.0-rc.1 or v1.2.0-beta are always marked as pre-releases")
+	cmd.Flags().BoolVarP(&o.SameChannel, "same-channel", "", false, "Compares the release to the previous tag of the same channel so that a stable release includes the changes since the previous stable release rather than the previous release candidate and a pre-release such as v1.2.0-rc.2 the changes since the previous pre-release of its channel or stable release")
+	cmd.Flags().BoolVarP(&o.ContributorAvatars, "contributor-avatars", "", false, "Renders the avatars of the contributors in the changelog")
+	cmd.Flags().BoolVarP(&o.Reviewers, "reviewers", "", false, "Resolves the reviewers and approvers of the pull requests included in the release")
+	cmd.Flags().BoolVarP(&o.LogResolutionStats, "log-resolution-stats", "", false, "Logs the number of user cache hits, git provider calls and errors when resolving users at the end of the run")
+	cmd.Flags().BoolVarP(&o.CodeOwners, "codeowners", "", false, "Finds the owners of the paths touched by each commit using the CODEOWNERS file so templates can group changes by owning team")
+	cmd.Flags().StringVarP(&o.CodeOwnersFile, "codeowners-file", "", "", "The CODEOWNERS file to use. Defaults to the CODEOWNERS file in the root, .github, .gitlab or docs directory of the repository")
+	cmd.Flags().BoolVarP(&o.GroupDependencies, "group-dependency-updates", "", false, "Renders the dependency update commits of bots such as Dependabot and Renovate or with the 'deps' scope in a collapsed Dependency Updates section")
+	cmd.Flags().BoolVarP(&o.RollUpDependencies, "roll-up-dependency-updates", "", false, "Renders multiple updates of the same dependency as a single line from the old to the new version when using --group-dependency-updates")
+	cmd.Flags().BoolVarP(&o.FixedIssues, "fixed-issues", "", false, "Renders an Issues Fixed section of the issues closed by the Fixes, Closes or Resolves trailers of the commits")
+	cmd.Flags().BoolVarP(&o.LinkPullRequests, "link-pull-requests", "", false, "Associates each commit with the pull request which merged it using the pull request number in the commit message or the git provider and links to it in the changelog. The labels of the pull requests are used by the section rules")
+	cmd.Flags().BoolVarP(&o.PullRequestTitles, "pull-request-titles", "", false, "Renders the title of the pull request of each commit instead of the commit subject. Implies --link-pull-requests")
+	cmd.Flags().BoolVarP(&o.DCO, "dco", "", false, "Checks that all the commits have a 'Signed-off-by' trailer of their author as required by the Developer Certificate of Origin and logs a compliance summary")
+	cmd.Flags().BoolVarP(&o.DCOSection, "dco-section", "", false, "Renders the sign off compliance of the commits in the changelog. Implies --dco")
+	cmd.Flags().BoolVarP(&o.DCOFail, "dco-fail", "", false, "Fails if any commit is not signed off by its author. Implies --dco")
+	cmd.Flags().BoolVarP(&o.GroupByScope, "group-by-scope", "", false, "Renders the commits of each section under a subheading for each conventional commit scope. The order of the scopes can be configured in the changelog configuration file")
+	cmd.Flags().StringVarP(&o.Deduplicate, "deduplicate", "", "", fmt.Sprintf("Collapses the commits with the same subject into a single entry with links to each commit. Supported values: %s", strings.Join(gits.DeduplicateModes, ", ")))
+	cmd.Flags().BoolVarP(&o.DiffStats, "diff-stats", "", false, "Renders the number of inserted and deleted lines of each commit such as '(+4,200/−3,800)'")
+	cmd.Flags().BoolVarP(&o.SortByImpact, "sort-by-impact", "", false, "Sorts the commits of each section by the number of changed lines with the largest changes first. Deprecated: use --sort-by impact instead")
+	cmd.Flags().StringArrayVarP(&o.CollapseSections, "collapse-section", "", nil, "The titles of the sections such as 'Chores' or 'Tests' whose commits are rendered inside a collapsible '<details>' block. Sections can also be collapsed in the changelog configuration")
+	cmd.Flags().StringVarP(&o.SortBy, "sort-by", "", "", fmt.Sprintf("Sorts the commits of each section instead of using the git log order. Supported values: %s", strings.Join(gits.SortStrategies, ", ")))
+	cmd.Flags().BoolVarP(&o.ClassifyByKeywords, "classify-by-keywords", "", false, "Groups the commits which do not follow the convention using keywords in their subject such as 'fix' or 'add'. Enabled if the changelog configuration file has keyword rules")
+	cmd.Flags().BoolVarP(&o.CommitBody, "commit-body", "", false, "Renders the body of the commit messages under each commit in the changelog")
+	cmd.Flags().IntVarP(&o.CommitBodyMaxLength, "commit-body-max-length", "", 0, "The maximum number of characters of each commit body rendered when using --commit-body. Use 0 for no limit")
+	cmd.Flags().BoolVarP(&o.ExpandSquashCommits, "expand-squash-commits", "", false, "Replaces squash merged pull requests by the individual commits of the pull request, or by the bulleted lines of the squash commit message if they cannot be listed")
+	cmd.Flags().BoolVarP(&o.ListReverted, "list-reverted", "", false, "Lists the commits reverted within the release in a Reverted section rather than omitting them and their reverts from the changelog")
+	cmd.Flags().BoolVarP(&o.ContributorSummary, "contributor-summary", "", false, "Renders a table of the number of commits and the first and last commit dates of each contributor")
+	cmd.Flags().BoolVarP(&o.MentionContributors, "mention-contributors", "", false, "Mentions the authors and co-authors of the commits at the end of the changelog sorted by their number of commits so that they are notified. Bots are excluded")
+	cmd.Flags().BoolVarP(&o.FirstTimeContributors, "first-time-contributors", "", false, "Detects the contributors making their first contribution to the repository and lists them in the changelog")
+	cmd.Flags().BoolVarP(&o.SigningKeys, "signing-keys", "", false, "Resolves the authors of signed commits using the git provider account which owns the GPG or SSH signing key")
+	cmd.Flags().BoolVarP(&o.EmailLookup, "email-lookup", "", false, "Searches the git provider for the accounts of commit authors by their email. The user search APIs are heavily rate limited so this can slow down large changelogs")
+	cmd.Flags().StringVarP(&o.Anonymize, "anonymize", "", "", fmt.Sprintf("Removes emails and real names from the changelog and omits the user details from the Release resource. Supported values: %s", strings.Join(users.AnonymizeModes, ", ")))
+	cmd.Flags().StringVarP(&o.AnonymizeSalt, "anonymize-salt", "", "", "The secret the identities are hashed with by --anonymize hash which is required so the hashes cannot be reversed by hashing known emails or logins. Defaults to $"+users.AnonymizeSaltEnv)
+	cmd.Flags().BoolVarP(&o.OrgMembers, "org-members", "", false, "Checks whether each contributor is a member of the repository owner organisation so templates can separate team and community contributions")
+	cmd.Flags().BoolVarP(&o.ExcludeBots, "exclude-bots", "", false, "Excludes bot accounts from the authors and contributors of the changelog")
+	cmd.Flags().StringArrayVarP(&o.Bots, "bot", "", nil, "The login, name or email of a bot account to exclude from the authors when using --exclude-bots")
+	cmd.Flags().StringArrayVarP(&o.BotSuffixes, "bot-suffix", "", nil, "The login or name suffixes which indicate a bot account when using --exclude-bots. Defaults to '[bot]' and '-bot'")
+	cmd.Flags().StringVarP(&o.ConfigFile, "config-file", "", "", "The YAML file configuring the commit message convention and the changelog sections of the commit types. Defaults to '"+config.FileName+"' in the root of the repository")
+	cmd.Flags().StringVarP(&o.AliasesFile, "aliases-file", "", "", "The YAML file mapping git emails and names to git provider logins. Defaults to '"+users.AliasesFileName+"' in the root of the repository if it exists")
+	cmd.Flags().StringVarP(&o.UserStore, "user-store", "", "", fmt.Sprintf("The kind of store used to cache resolved git users. Supported values: %s. Defaults to 'file' if --user-cache-file is specified otherwise 'memory'", strings.Join(users.UserStoreKinds, ", ")))
+	cmd.Flags().StringVarP(&o.UserCacheFile, "user-cache-file", "", "", "The JSON file used to cache the resolved git users across runs. If not specified users are only cached in memory")
+	cmd.Flags().DurationVarP(&o.UserCacheTTL, "user-cache-ttl", "", users.DefaultUserCacheTTL, "How long users in the --user-cache-file are valid before they are resolved again. Use 0 to never expire")
+	cmd.Flags().IntVarP(&o.ResolveWorkers, "resolve-workers", "", users.DefaultWorkers, "The maximum number of git users resolved concurrently via the git provider")
+	cmd.Flags().DurationVarP(&o.RateLimitMaxWait, "rate-limit-max-wait", "", scmapi.DefaultMaxWait, "The maximum total time spent waiting for the git provider rate limit across all calls. Once it is used up rate limited calls fail fast and user resolution falls back to the git signatures")
+	cmd.Flags().IntVarP(&o.RateLimitLowBudget, "rate-limit-warn-remaining", "", scmapi.DefaultWarnRemaining, "Warns when the remaining calls of the git provider rate limit fall to this number. Use 0 to disable the warning")
+	cmd.Flags().IntVarP(&o.GraphQLBatchSize, "graphql-batch-size", "", scmapi.DefaultGraphQLBatchSize, "The number of commits whose pull requests are found in one GitHub GraphQL query. Use 0 to query each commit via the REST API")
+	cmd.Flags().BoolVarP(&o.NoKubernetes, "no-kubernetes", "", false, "Runs without a kubernetes cluster so that no PipelineActivity is updated. Defaults to true if $JX_NO_KUBERNETES is true")
+	cmd.Flags().BoolVarP(&o.Offline, "offline", "", false, "Resolves the commit authors purely from the git signatures and the user cache without calling the git provider")
 
 	cmd.Flags().StringVarP(&o.Header, "header", "", "", "The changelog header in markdown for the changelog. Can use go template expressions on the ReleaseSpec object: https://golang.org/pkg/text/template/")
 	cmd.Flags().StringVarP(&o.HeaderFile, "header-file", "", "", "The file name of the changelog header in markdown for the changelog. Can use go template expressions on the ReleaseSpec object: https://golang.org/pkg/text/template/")
-	cmd.Flags().StringVarP(&o.Footer, "footer", "", "", "The changelog footer in markdown for the changelog. Can use go template expressions on the ReleaseSpec object: https://golang.org/pkg/text/template/")
-	cmd.Flags().StringVarP(&o.FooterFile, "footer-file", "", "", "The file name of the changelog footer in markdown for the changelog. Can use go template expressions on the ReleaseSpec object: https://golang.org/pkg/text/template/")
+	cmd.Flags().StringVarP(&o.Footer, "footer", "", "", "The changelog footer in markdown for the changelog. Can use go template expressions on the ReleaseSpec object and the parsed .Changelog with the sprig functions: https://golang.org/pkg/text/template/. Defaults to the footer of the changelog configuration")
+	cmd.Flags().StringVarP(&o.FooterFile, "footer-file", "", "", "The file name of the changelog footer in markdown for the changelog. Can use go template expressions on the ReleaseSpec object and the parsed .Changelog with the sprig functions: https://golang.org/pkg/text/template/. Defaults to the footerFile of the changelog configuration")
 
-	o.ScmFactory.AddFlags(cmd)
+	o.Options.AddFlags(cmd)
 	o.BaseOptions.AddBaseFlags(cmd)
-	return cmd, o
 }
 
 func (o *Options) Validate() error {
@@ -226,25 +480,118 @@ func (o *Options) Validate() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to validate base options")
 	}
-
-	err = o.ScmFactory.Validate()
+	err = o.Options.Prepare()
 	if err != nil {
-		return errors.Wrapf(err, "failed to discover git repository")
+		return err
+	}
+
+	azureRepo := o.discoverAzureRepository()
+	if azureRepo != nil {
+		o.useAzureRepository(azureRepo)
+	} else {
+		err = o.Connect()
+		if err != nil {
+			return err
+		}
 	}
 
-	o.JXClient, o.Namespace, err = jxclient.LazyCreateJXClientAndNamespace(o.JXClient, o.Namespace)
+	if o.MergeStrategy == "" {
+		o.MergeStrategy = gits.MergeStrategyNoMerges
+		if o.IncludeMergeCommits {
+			o.MergeStrategy = gits.MergeStrategyAll
+		}
+	}
+	err = o.validateSHARange()
 	if err != nil {
-		return errors.Wrapf(err, "failed to create jx client")
+		return err
+	}
+	err = o.validateDateWindow()
+	if err != nil {
+		return err
+	}
+	if stringhelpers.StringArrayIndex(gits.MergeStrategies, o.MergeStrategy) < 0 {
+		return errors.Errorf("unsupported --merge-strategy %s. Supported values: %s", o.MergeStrategy, strings.Join(gits.MergeStrategies, ", "))
+	}
+	if o.OutputFormat == "" {
+		o.OutputFormat = OutputFormatMarkdown
+	}
+	if o.OutputFile == "" {
+		o.OutputFile = o.OutputMarkdownFile
+	}
+	err = o.parseOutputs()
+	if err != nil {
+		return err
+	}
+	if o.Unreleased {
+		o.UpdateRelease = false
+		o.GenerateCRD = false
+		o.GenerateReleaseYaml = false
+	}
+	if o.FeedFormat == "" {
+		o.FeedFormat = gits.FeedFormatAtom
+	}
+	if stringhelpers.StringArrayIndex(gits.FeedFormats, o.FeedFormat) < 0 {
+		return errors.Errorf("unsupported --feed-format %s. Supported values: %s", o.FeedFormat, strings.Join(gits.FeedFormats, ", "))
+	}
+	if stringhelpers.StringArrayIndex(OutputFormats, o.OutputFormat) < 0 {
+		return errors.Errorf("unsupported --output-format %s. Supported values: %s", o.OutputFormat, strings.Join(OutputFormats, ", "))
+	}
+	if o.SortBy != "" && stringhelpers.StringArrayIndex(gits.SortStrategies, o.SortBy) < 0 {
+		return errors.Errorf("unsupported --sort-by %s. Supported values: %s", o.SortBy, strings.Join(gits.SortStrategies, ", "))
+	}
+	if o.CompareLinkPosition != "" && stringhelpers.StringArrayIndex(gits.CompareLinkPositions, o.CompareLinkPosition) < 0 {
+		return errors.Errorf("unsupported --compare-link-position %s. Supported values: %s", o.CompareLinkPosition, strings.Join(gits.CompareLinkPositions, ", "))
+	}
+	if o.EntryLinks != "" && stringhelpers.StringArrayIndex(gits.EntryLinks, o.EntryLinks) < 0 {
+		return errors.Errorf("unsupported --entry-links %s. Supported values: %s", o.EntryLinks, strings.Join(gits.EntryLinks, ", "))
+	}
+	if o.Deduplicate != "" && stringhelpers.StringArrayIndex(gits.DeduplicateModes, o.Deduplicate) < 0 {
+		return errors.Errorf("unsupported --deduplicate %s. Supported values: %s", o.Deduplicate, strings.Join(gits.DeduplicateModes, ", "))
+	}
+	if o.MakeLatest != "" && stringhelpers.StringArrayIndex(scmapi.MakeLatestValues, o.MakeLatest) < 0 {
+		return errors.Errorf("unsupported --make-latest %s. Supported values: %s", o.MakeLatest, strings.Join(scmapi.MakeLatestValues, ", "))
+	}
+	if o.IfExists != "" && stringhelpers.StringArrayIndex(IfExistsPolicies, o.IfExists) < 0 {
+		return errors.Errorf("unsupported --if-exists %s. Supported values: %s", o.IfExists, strings.Join(IfExistsPolicies, ", "))
+	}
+	if o.PushChangelog != "" && stringhelpers.StringArrayIndex(PushChangelogModes, o.PushChangelog) < 0 {
+		return errors.Errorf("unsupported --push-changelog %s. Supported values: %s", o.PushChangelog, strings.Join(PushChangelogModes, ", "))
+	}
+	err = o.PostPublish.Validate()
+	if err != nil {
+		return err
+	}
+	if o.CherryPicks != "" && stringhelpers.StringArrayIndex(gits.CherryPickModes, o.CherryPicks) < 0 {
+		return errors.Errorf("unsupported --cherry-picks %s. Supported values: %s", o.CherryPicks, strings.Join(gits.CherryPickModes, ", "))
+	}
+
+	if kube.IsNoKubernetes() {
+		o.NoKubernetes = true
+	}
+	if !o.NoKubernetes {
+		o.JXClient, o.Namespace, err = jxclient.LazyCreateJXClientAndNamespace(o.JXClient, o.Namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create jx client")
+		}
 	}
 
 	return nil
 }
 
 func (o *Options) Run() error {
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
 	err := o.Validate()
 	if err != nil {
 		return errors.Wrapf(err, "failed to validate")
 	}
+	if o.LogResolutionStats {
+		defer func() {
+			log.Logger().Infof("user resolution %s", o.State.ResolutionStats.String())
+		}()
+	}
+	defer o.reportRateLimit()
 
 	// lets enable batch mode if we detect we are inside a pipeline
 	if !o.BatchMode && builds.GetBuildNumber() != "" {
@@ -254,7 +601,16 @@ func (o *Options) Run() error {
 
 	dir := o.ScmFactory.Dir
 
-	previousRev := o.PreviousRevision
+	fromSHA, toSHA, err := o.resolveSHARange(dir)
+	if err != nil {
+		return err
+	}
+	sinceRev, untilRev, err := o.resolveDateWindow(dir)
+	if err != nil {
+		return err
+	}
+	previousRev := stringhelpers.FirstNotEmptyString(fromSHA, sinceRev, o.PreviousRevision)
+	previousTag := o.PreviousTag
 	if previousRev == "" {
 		previousDate := o.PreviousDate
 		if previousDate != "" {
@@ -264,13 +620,25 @@ func (o *Options) Run() error {
 			}
 		}
 	}
+	o.State.NewTag, err = o.missingVersionTag(dir)
+	if err != nil {
+		return err
+	}
 	if previousRev == "" {
-		previousRev, _, err = gits.GetCommitPointedToByPreviousTag(o.Git(), dir)
+		switch {
+		case o.Unreleased || o.State.NewTag != "":
+			previousRev, previousTag, err = gits.GetCommitPointedToByLatestTag(o.Git(), dir)
+		case o.SameChannel:
+			previousRev, previousTag, err = o.previousChannelTag(dir)
+		default:
+			previousRev, previousTag, err = gits.GetCommitPointedToByPreviousTag(o.Git(), dir)
+		}
 		if err != nil {
 			return err
 		}
 		if previousRev == "" {
-			// lets assume we are the first release
+			// lets assume we are the first release unless the history is incomplete
+			o.State.MissingCommits = gits.IsShallowClone(o.Git(), dir)
 			previousRev, err = gits.GetFirstCommitSha(o.Git(), dir)
 			if err != nil {
 				return errors.Wrap(err, "failed to find first commit after we found no previous releaes")
@@ -281,9 +649,24 @@ func (o *Options) Run() error {
 			}
 		}
 	}
-	currentRev := o.CurrentRevision
+	currentRev := stringhelpers.FirstNotEmptyString(toSHA, untilRev, o.CurrentRevision)
+	currentTag := o.CurrentTag
+	if o.State.NewTag != "" {
+		// lets resolve the revision the tag is created for
+		currentRev, err = o.Git().Command(dir, "rev-parse", stringhelpers.FirstNotEmptyString(currentRev, "HEAD")+"^{commit}")
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the commit to tag in %s", dir)
+		}
+		currentTag = o.State.NewTag
+	}
+	if currentRev == "" && o.Unreleased {
+		currentRev, err = o.Git().Command(dir, "rev-parse", "HEAD")
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the HEAD commit in %s", dir)
+		}
+	}
 	if currentRev == "" {
-		currentRev, _, err = gits.GetCommitPointedToByLatestTag(o.Git(), dir)
+		currentRev, currentTag, err = gits.GetCommitPointedToByLatestTag(o.Git(), dir)
 		if err != nil {
 			return err
 		}
@@ -291,7 +674,7 @@ func (o *Options) Run() error {
 
 	templatesDir := o.TemplatesDir
 	dir = o.ScmFactory.Dir
-	if templatesDir == "" {
+	if templatesDir == "" && !o.Unreleased {
 		chartFile, err := helmhelpers.FindChart(dir)
 		if err != nil {
 			return errors.Wrap(err, "could not find helm chart")
@@ -342,7 +725,10 @@ func (o *Options) Run() error {
 	o.State.Tracker = tracker
 
 	o.State.FoundIssueNames = map[string]bool{}
+	o.State.CoAuthors = map[string][]v1.UserDetails{}
+	o.State.AuthorIdentities = map[string][][2]string{}
 
+	o.State.PreviousTag = previousTag
 	commits, err := chgit.FetchCommits(gitDir, previousRev, currentRev)
 	if err != nil {
 		if o.FailIfFindCommits {
@@ -350,6 +736,9 @@ func (o *Options) Run() error {
 		}
 		log.Logger().Warnf("failed to find git commits between revision %s and %s due to: %s", previousRev, currentRev, err.Error())
 	}
+	if commits == nil || len(*commits) == 0 {
+		o.State.MissingCommits = true
+	}
 	if commits != nil {
 		commitSlice := *commits
 		if len(commitSlice) > 0 {
@@ -369,7 +758,14 @@ func (o *Options) Run() error {
 			}
 		}
 	}
+	releaseDate := o.ReleaseDate
+	if releaseDate.IsZero() {
+		releaseDate = time.Now()
+	}
 	version := o.Version
+	if o.Unreleased {
+		version = gits.UnreleasedVersion
+	}
 	if version == "" {
 		version = SpecVersion
 	}
@@ -382,7 +778,7 @@ func (o *Options) Run() error {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: ReleaseName,
 			CreationTimestamp: metav1.Time{
-				Time: time.Now(),
+				Time: releaseDate,
 			},
 			//ResourceVersion:   "1",
 			DeletionTimestamp: &metav1.Time{},
@@ -400,38 +796,287 @@ func (o *Options) Run() error {
 		},
 	}
 
-	scmClient := o.ScmFactory.ScmClient
-	resolver := users.GitUserResolver{
-		GitProvider: scmClient,
+	configFile := o.ConfigFile
+	if configFile == "" {
+		configFile = filepath.Join(gitDir, config.FileName)
+	}
+	o.State.Config, err = config.LoadConfig(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the changelog configuration")
 	}
+
+	resolver, err := o.createUserResolver(gitDir)
+	if err != nil {
+		return err
+	}
+	o.State.Resolver = resolver
+	o.State.DiffStats = gits.NewDiffStatsCache(gitDir)
+	if o.CompareLink {
+		o.State.Compare = gits.NewCompare(gitInfo, o.ScmFactory.GitKind, stringhelpers.FirstNotEmptyString(previousTag, previousRev), stringhelpers.FirstNotEmptyString(currentTag, currentRev))
+	}
+
+	o.State.Tag = currentTag
+	if o.Unreleased {
+		o.State.Title = gits.UnreleasedVersion
+	} else {
+		o.State.Title, err = o.State.Config.ReleaseTitle(version, currentTag, release.CreationTimestamp.Time)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.State.Anonymizer, err = users.NewAnonymizer(o.Anonymize, stringhelpers.FirstNotEmptyString(o.AnonymizeSalt, os.Getenv(users.AnonymizeSaltEnv)))
+	if err != nil {
+		return errors.Wrapf(err, "invalid --anonymize option")
+	}
+
+	pathFilter, err := gits.NewPathFilter(o.IncludePaths, o.ExcludePaths)
+	if err != nil {
+		return err
+	}
+
+	flavor, err := gits.GetMarkdownFlavor(o.MarkdownFlavor)
+	if err != nil {
+		return err
+	}
+
+	translations, err := gits.LoadTranslations(o.Locale, o.StringsFile)
+	if err != nil {
+		return err
+	}
+
+	var picks *cherryPicks
+	if o.CherryPicks != "" && commits != nil {
+		picks, err = o.findCherryPicks(gitDir, previousRev, currentRev, *commits)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the cherry picked commits")
+		}
+	}
+
+	scmClient := o.ScmFactory.ScmClient
+	var releaseCommits []*object.Commit
+	skipped := 0
 	if commits != nil {
-		for _, commit := range *commits {
+		for _, commit := range gits.FilterCommitsByMergeStrategy(*commits, o.MergeStrategy) {
+			if err := o.Context.Err(); err != nil {
+				return errors.Wrapf(err, "changelog generation cancelled")
+			}
 			c := commit
-			if o.IncludeMergeCommits || len(commit.ParentHashes) <= 1 {
-				o.addCommit(&release.Spec, &c, &resolver)
+			if o.State.Config.IsExcluded(commit.Message) {
+				log.Logger().Debugf("excluding commit %s from the changelog as it matches an exclude pattern", commit.Hash.String())
+				continue
+			}
+			if o.isExcludedAuthor(&c) {
+				log.Logger().Debugf("excluding commit %s from the changelog as its author matches an exclude pattern", commit.Hash.String())
+				continue
+			}
+			matches, err := pathFilter.MatchesCommit(&c)
+			if err != nil {
+				return errors.Wrapf(err, "failed to filter commits by path")
+			}
+			if !matches {
+				log.Logger().Debugf("excluding commit %s from the changelog as it does not touch the included paths", commit.Hash.String())
+				continue
+			}
+			if gits.HasSkipMarker(commit.Message) {
+				log.Logger().Debugf("skipping commit %s as it opts out of the changelog", commit.Hash.String())
+				skipped++
+				continue
+			}
+			if picks.isShipped(commit.Hash.String()) {
+				log.Logger().Debugf("skipping commit %s as it is a cherry pick of a commit which already shipped", commit.Hash.String())
+				skipped++
+				continue
 			}
+			releaseCommits = append(releaseCommits, &c)
 		}
 	}
+	commitUsers := o.resolveCommitUsers(releaseCommits, resolver)
+	if err := o.Context.Err(); err != nil {
+		return errors.Wrapf(err, "changelog generation cancelled")
+	}
+	for _, c := range releaseCommits {
+		o.addCommit(&release.Spec, c, resolver, commitUsers)
+		if from := picks.backportedFrom(c.Hash.String()); from != "" {
+			last := &release.Spec.Commits[len(release.Spec.Commits)-1]
+			last.Message = gits.AnnotateBackport(last.Message, from)
+		}
+	}
+
+	if o.LinkPullRequests || o.PullRequestTitles {
+		o.linkPullRequests(&release.Spec)
+	}
+
+	count := gits.RemoveSkippedPullRequests(&release.Spec, o.State.Config.PullRequestSkipLabels())
+	if count > 0 {
+		skipped += count
+		releaseCommits = retainCommits(releaseCommits, release.Spec.Commits)
+	}
+	if skipped > 0 {
+		log.Logger().Debugf("skipped %d commits which opted out of the changelog", skipped)
+	}
+
+	if o.ExpandSquashCommits {
+		release.Spec.Commits = o.expandSquashCommits(release.Spec.Commits)
+	}
+	release.Spec.Commits, o.State.RevertedCommits = gits.RemoveRevertedCommits(release.Spec.Commits)
+	releaseCommits = withoutRevertedCommits(releaseCommits, o.State.RevertedCommits)
 
 	release.Spec.DependencyUpdates = CollapseDependencyUpdates(release.Spec.DependencyUpdates)
 
+	if o.DCO || o.DCOSection || o.DCOFail {
+		err = o.checkDCO(releaseCommits)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.Reviewers {
+		err = o.addReviewers(release)
+		if err != nil {
+			log.Logger().Warnf("failed to resolve the reviewers of the pull requests: %s", err.Error())
+		}
+	}
+
+	if o.CodeOwners {
+		err = o.addCommitOwners(releaseCommits, gitDir)
+		if err != nil {
+			log.Logger().Warnf("failed to find the owners of the commits: %s", err.Error())
+		}
+	}
+
+	if o.ContributorSummary {
+		o.State.ContributorSummary, err = resolver.ContributorSummary(o.Context, releaseCommits)
+		if err != nil {
+			log.Logger().Warnf("failed to create the contributor summary: %s", err.Error())
+		}
+	}
+
+	if o.FirstTimeContributors {
+		err = o.addFirstTimeContributors(release, gitDir, previousRev)
+		if err != nil {
+			log.Logger().Warnf("failed to detect first time contributors: %s", err.Error())
+		}
+	}
+
+	if o.State.Anonymizer != nil {
+		o.anonymizeRelease(release)
+	} else {
+		o.addCoAuthorsAnnotation(release)
+		o.addReviewersAnnotations(release)
+		setUsersAnnotation(release, FirstTimeContributorsAnnotation, o.State.FirstTimeContributors)
+	}
+	o.addBreakingChangesAnnotation(release)
+	if o.CommitsAnnotation {
+		err = addConventionalCommitsAnnotation(release)
+		if err != nil {
+			return err
+		}
+	}
+
 	// lets try to update the release
-	markdown, err := gits.GenerateMarkdown(&release.Spec, gitInfo)
+	markdownOptions := &gits.MarkdownOptions{
+		CoAuthors: o.State.CoAuthors,
+		Avatars:   o.ContributorAvatars,
+
+		FirstTimeContributors: o.State.FirstTimeContributors,
+		ContributorSummary:    o.State.ContributorSummary,
+		Groups:                o.State.Config.CommitGroups(),
+		Convention:            o.State.Config.CommitConvention(),
+		LabelTypes:            o.State.Config.LabelTypes(),
+		CommitBody:            o.CommitBody,
+		CommitBodyMaxLength:   o.CommitBodyMaxLength,
+
+		GroupDependencyUpdates:  o.GroupDependencies,
+		RollUpDependencyUpdates: o.RollUpDependencies,
+		Keywords:                o.State.Config.KeywordClassifier(o.ClassifyByKeywords),
+		LinkPullRequests:        o.LinkPullRequests || o.PullRequestTitles,
+		PullRequestTitles:       o.PullRequestTitles,
+		FixedIssues:             o.FixedIssues,
+		DCO:                     o.dcoSection(),
+		GroupByScope:            o.GroupByScope,
+		Deduplicate:             o.Deduplicate,
+		DiffStats:               o.State.DiffStats,
+		ShowDiffStats:           o.DiffStats,
+		SortByImpact:            o.SortByImpact,
+		SortBy:                  o.SortBy,
+		CollapsedSections:       o.CollapseSections,
+		CommitDates:             commitDates(releaseCommits),
+		Flavor:                  flavor,
+		DefaultEmoji:            o.SectionEmoji,
+		DisableEmoji:            o.NoEmoji,
+		Compare:                 o.State.Compare,
+		ComparePosition:         o.CompareLinkPosition,
+		EntryLinks:              o.EntryLinks,
+		MentionContributors:     o.MentionContributors,
+		Translations:            translations,
+		Bots:                    users.NewBotFilter(o.BotSuffixes, o.Bots),
+	}
+	if cfg := o.State.Config; cfg != nil {
+		markdownOptions.GroupByScope = markdownOptions.GroupByScope || cfg.GroupByScope
+		markdownOptions.ScopeOrder = cfg.ScopeOrder
+		markdownOptions.UnscopedTitle = cfg.UnscopedTitle
+		markdownOptions.DefaultEmoji = markdownOptions.DefaultEmoji || cfg.DefaultEmoji
+		markdownOptions.DisableEmoji = markdownOptions.DisableEmoji || cfg.DisableEmoji
+	}
+	if o.State.Reviewers != nil {
+		markdownOptions.Reviewers = o.State.Reviewers.Reviewers
+		markdownOptions.Approvers = o.State.Reviewers.Approvers
+	}
+	if o.ListReverted {
+		markdownOptions.Reverted = o.State.RevertedCommits
+	}
+	if o.SecurityAdvisories {
+		markdownOptions.SecurityAdvisories = o.securityAdvisories(scmClient, release, version)
+	}
+	partials, err := gits.LoadPartials(filepath.Join(gitDir, gits.PartialsDir))
 	if err != nil {
 		return err
 	}
-	header, err := o.getTemplateResult(&release.Spec, "header", o.Header, o.HeaderFile)
+	changelog := o.createChangelog(release, gitInfo, markdownOptions)
+	var markdown string
+	if o.TemplateFile != "" {
+		markdown, err = o.renderTemplate(changelog, partials)
+	} else {
+		markdownOptions.Partials = partials
+		markdownOptions.Changelog = changelog
+		markdown, err = gits.GenerateMarkdownWithOptions(&release.Spec, gitInfo, markdownOptions)
+	}
 	if err != nil {
 		return err
 	}
-	footer, err := o.getTemplateResult(&release.Spec, "footer", o.Footer, o.FooterFile)
+	templateData := o.createTemplateData(&release.Spec, changelog)
+	header, err := o.getTemplateResult(templateData, "header", o.Header, o.HeaderFile)
 	if err != nil {
 		return err
 	}
+	footerText, footerFile := o.Footer, o.FooterFile
+	if footerText == "" && footerFile == "" && o.State.Config != nil {
+		footerText = o.State.Config.Footer
+		if o.State.Config.FooterFile != "" {
+			footerFile = filepath.Join(gitDir, o.State.Config.FooterFile)
+		}
+	}
+	footer, err := o.getTemplateResult(templateData, "footer", footerText, footerFile)
+	if err != nil {
+		return err
+	}
+	body := markdown
 	markdown = header + markdown + footer
 
 	log.Logger().Debugf("Generated release notes:\n\n%s\n", markdown)
 
+	if o.State.NewTag != "" {
+		if o.DryRun {
+			log.Logger().Infof("would create the tag %s", info(o.State.NewTag))
+		} else {
+			err = o.createTag(dir, o.State.NewTag, currentRev, body)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if version != "" && o.UpdateRelease {
 		tags, err := gits.FilterTags(o.Git(), dir, version)
 		if err != nil {
@@ -461,23 +1106,93 @@ func (o *Options) Run() error {
 		if foundVTag && !foundTag {
 			tagName = vVersion
 		}
+		if o.State.NewTag != "" {
+			tagName = o.State.NewTag
+		}
+		if o.State.MissingCommits && o.ProviderNotes && scmClient != nil && o.AzureClient == nil {
+			markdown = o.mergeProviderNotes(scmClient, tagName, currentRev, markdown)
+		}
+		if scmClient != nil && o.AzureClient == nil {
+			o.State.Milestone = o.findMilestone(scmClient, version)
+			markdown = o.milestoneNotes(markdown)
+		}
+		description := o.releaseBody(markdown, gitInfo, gitDir, version)
+		if o.OutputFile != "" && o.OutputFormat == OutputFormatMarkdown {
+			err = ioutil.WriteFile(o.OutputFile, []byte(markdown), files.DefaultFileWritePermissions)
+			if err != nil {
+				return errors.Wrapf(err, "failed to save the full changelog to %s", o.OutputFile)
+			}
+			log.Logger().Infof("saved the full changelog to %s", info(o.OutputFile))
+		}
 		releaseInfo := &scm.ReleaseInput{
-			Title:       version,
+			Title:       o.State.Title,
 			Tag:         tagName,
-			Description: markdown,
+			Description: description,
 			Draft:       o.Draft,
-			Prerelease:  o.Prerelease,
+			Prerelease:  o.Prerelease || gits.IsPrerelease(tagName),
+		}
+		if releaseInfo.Prerelease && !o.Prerelease {
+			log.Logger().Infof("marking the release of tag %s as a pre-release", info(tagName))
+		}
+
+		releaseOptions, err := o.releaseOptions()
+		if err != nil {
+			return err
 		}
 
-		ctx := context.Background()
+		ctx := o.Context
 		fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
 
 		// lets try find a release for the tag
-		if scmClient.Releases == nil {
-			log.Logger().Warnf("scm provider does not support Releases so cannot find releases")
+		if o.DryRun {
+			log.Logger().Infof("would publish the release %s of tag %s", info(releaseInfo.Title), info(tagName))
+		} else if o.AzureClient != nil {
+			err = o.publishAzureRelease(dir, tagName, description, release)
+			if err != nil {
+				return err
+			}
+		} else if scmClient.Driver == scm.DriverGitlab || scmClient.Driver == scm.DriverGitea {
+			existing, err := o.findExistingRelease(scmClient, fullName, tagName)
+			if err != nil {
+				return err
+			}
+			update, err := o.applyIfExists(existing, releaseInfo)
+			if err != nil {
+				return err
+			}
+			rel := existing
+			if update {
+				err = o.uploadGitLabAssets(scmClient, fullName, releaseOptions)
+				if err != nil {
+					return err
+				}
+				// lets warn that --make-latest is not supported
+				o.githubMakeLatest(scmClient)
+				rel, err = o.publishRelease(scmClient, fullName, releaseInfo, releaseOptions)
+				if err != nil {
+					log.Logger().Warnf("Failed to publish the release for %s: %s", fullName, err)
+					return nil
+				}
+				err = o.uploadReleaseAssets(scmClient, fullName, rel, releaseOptions.Assets)
+				if err != nil {
+					return err
+				}
+			}
+			release.Spec.ReleaseNotesURL = o.releaseURL(rel, gitInfo, tagName)
+			log.Logger().Infof("updated the release information at %s", info(release.Spec.ReleaseNotesURL))
+			log.Logger().Debugf("added description: %s", description)
+		} else if scmClient.Releases == nil {
+			err = o.publishTagRelease(scmClient.Driver.String(), dir, tagName, description)
+			if err != nil {
+				return err
+			}
 		} else {
-			rel, _, err := scmClient.Releases.FindByTag(ctx, fullName, tagName)
-
+			var rel *scm.Release
+			err = o.backoff().Do(ctx, func() (*scm.Response, error) {
+				var res *scm.Response
+				rel, res, err = scmapi.FindReleaseByTag(ctx, scmClient, fullName, tagName)
+				return res, err
+			})
 			if isReleaseNotFound(err, o.ScmFactory.GitKind) {
 				err = nil
 				rel = nil
@@ -486,18 +1201,38 @@ func (o *Options) Run() error {
 				return errors.Wrapf(err, "failed to query release on repo %s for tag %s", fullName, tagName)
 			}
 
+			update, err := o.applyIfExists(rel, releaseInfo)
+			if err != nil {
+				return err
+			}
+			makeLatest := o.githubMakeLatest(scmClient)
 			if rel == nil {
-				rel, _, err = scmClient.Releases.Create(ctx, fullName, releaseInfo)
+				err = o.backoff().Do(ctx, func() (*scm.Response, error) {
+					var res *scm.Response
+					if makeLatest {
+						rel, res, err = scmapi.PublishGitHubRelease(ctx, scmClient, fullName, nil, releaseInfo, o.MakeLatest)
+					} else {
+						rel, res, err = scmClient.Releases.Create(ctx, fullName, releaseInfo)
+					}
+					return res, err
+				})
 				if err != nil {
 					log.Logger().Warnf("Failed to create the release for %s: %s", fullName, err)
 					return nil
 				}
-			} else {
-				if rel.ID != 0 {
-					rel, _, err = scmClient.Releases.Update(ctx, fullName, rel.ID, releaseInfo)
-				} else {
-					rel, _, err = scmClient.Releases.UpdateByTag(ctx, fullName, rel.Tag, releaseInfo)
-				}
+			} else if update {
+				existing := rel
+				err = o.backoff().Do(ctx, func() (*scm.Response, error) {
+					var res *scm.Response
+					if makeLatest {
+						rel, res, err = scmapi.PublishGitHubRelease(ctx, scmClient, fullName, existing, releaseInfo, o.MakeLatest)
+					} else if existing.ID != 0 {
+						rel, res, err = scmClient.Releases.Update(ctx, fullName, existing.ID, releaseInfo)
+					} else {
+						rel, res, err = scmClient.Releases.UpdateByTag(ctx, fullName, existing.Tag, releaseInfo)
+					}
+					return res, err
+				})
 				if err != nil {
 					id := -1
 					if rel != nil {
@@ -508,31 +1243,68 @@ func (o *Options) Run() error {
 				}
 			}
 
-			url := ""
-			if rel != nil {
-				url = rel.Link
+			if len(releaseOptions.AssetLinks) > 0 {
+				log.Logger().Warnf("the %s git provider does not support release asset links so they are ignored", scmClient.Driver.String())
 			}
-			if url == "" {
-				url = stringhelpers.UrlJoin(gitInfo.HttpsURL(), "releases/tag", tagName)
+			if update {
+				err = o.uploadReleaseAssets(scmClient, fullName, rel, releaseOptions.Assets)
+				if err != nil {
+					return err
+				}
 			}
-			release.Spec.ReleaseNotesURL = url
-			log.Logger().Infof("updated the release information at %s", info(url))
-			log.Logger().Debugf("added description: %s", markdown)
+			release.Spec.ReleaseNotesURL = o.releaseURL(rel, gitInfo, tagName)
+			log.Logger().Infof("updated the release information at %s", info(release.Spec.ReleaseNotesURL))
+			log.Logger().Debugf("added description: %s", description)
 		}
-	} else if o.OutputMarkdownFile != "" {
-		err := ioutil.WriteFile(o.OutputMarkdownFile, []byte(markdown), files.DefaultFileWritePermissions)
+		// draft releases are not published yet so 'jx-changelog publish-draft' runs these steps
+		if o.AzureClient == nil && !o.Draft {
+			err = o.PostPublish.Run(o.publisher(scmClient), o.publishedRelease(release, dir, currentRev, version, tagName))
+			if err != nil {
+				return err
+			}
+		}
+	} else if len(o.Outputs) == 0 && (o.OutputFile != "" || o.OutputFormat != OutputFormatMarkdown || o.ChangelogFile == "") {
+		err = o.writeOutput(&OutputSpec{Format: o.OutputFormat, File: o.OutputFile}, markdown, o.createChangelog(release, gitInfo, markdownOptions))
+		if err != nil {
+			return err
+		}
+	}
+	if len(o.State.Outputs) > 0 {
+		// lets include the URL of the updated release
+		changelog = o.createChangelog(release, gitInfo, markdownOptions)
+		for _, spec := range o.State.Outputs {
+			err = o.writeOutput(spec, markdown, changelog)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.ChangelogFile != "" {
+		err = o.updateChangelogFile(version, release.CreationTimestamp.Time, body)
+		if err != nil {
+			return err
+		}
+		err = o.pushChangelogFile(scmClient, gitDir, version)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.FeedFile != "" {
+		err = o.updateFeed(release, gitInfo, o.createChangelog(release, gitInfo, markdownOptions))
 		if err != nil {
 			return err
 		}
-		log.Logger().Infof("\nGenerated Changelog: %s", info(o.OutputMarkdownFile))
-	} else {
-		log.Logger().Infof("\nGenerated Changelog:")
-		log.Logger().Infof("%s\n", markdown)
 	}
 
 	o.State.Release = release
 	// now lets marshal the release YAML
-	data, err := yaml.Marshal(release)
+	releaseResource := release
+	if o.State.Anonymizer != nil {
+		releaseResource = withoutUserDetails(release)
+	}
+	data, err := yaml.Marshal(releaseResource)
 	if o.ConditionalRelease {
 		data = []byte(fmt.Sprintf(conditionalReleaseYAML, string(data)))
 	}
@@ -587,6 +1359,9 @@ func (o *Options) Run() error {
 		appName = release.Spec.GitRepository
 	}
 	releaseNotesURL := release.Spec.ReleaseNotesURL
+	if o.Unreleased {
+		return nil
+	}
 
 	// lets modify the PipelineActivity
 	err = o.updatePipelineActivity(func(pa *v1.PipelineActivity) (bool, error) {
@@ -619,6 +1394,10 @@ func (o *Options) Run() error {
 }
 
 func (o *Options) updatePipelineActivity(fn func(activity *v1.PipelineActivity) (bool, error)) error {
+	if o.NoKubernetes {
+		log.Logger().Debugf("not updating the PipelineActivity as running without kubernetes")
+		return nil
+	}
 	if o.BuildNumber == "" {
 		o.BuildNumber = os.Getenv("BUILD_NUMBER")
 		if o.BuildNumber == "" {
@@ -627,7 +1406,10 @@ func (o *Options) updatePipelineActivity(fn func(activity *v1.PipelineActivity)
 	}
 	pipeline := fmt.Sprintf("%s/%s/%s", o.ScmFactory.Owner, o.ScmFactory.Repository, o.ScmFactory.Branch)
 
-	ctx := context.Background()
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	build := o.BuildNumber
 	if pipeline != "" && build != "" {
 		ns := o.Namespace
@@ -682,6 +1464,9 @@ func (o *Options) updatePipelineActivity(fn func(activity *v1.PipelineActivity)
 
 // CreateIssueProvider creates the issue provider
 func (o *Options) CreateIssueProvider() (issues.IssueProvider, error) {
+	if o.AzureClient != nil {
+		return issues.CreateAzureBoardsIssueProvider(o.AzureClient)
+	}
 	return issues.CreateGitIssueProvider(o.ScmFactory.ScmClient, o.ScmFactory.Owner, o.ScmFactory.Repository)
 	/*
 		// TODO find kind from a configuration file inside the repository....
@@ -690,14 +1475,87 @@ func (o *Options) CreateIssueProvider() (issues.IssueProvider, error) {
 	*/
 }
 
-func (o *Options) Git() gitclient.Interface {
-	if o.GitClient == nil {
-		o.GitClient = cli.NewCLIClient("", o.CommandRunner)
+// createUserResolver creates the resolver of git users for the repository in the given directory
+func (o *Options) createUserResolver(gitDir string) (*users.GitUserResolver, error) {
+	mailmap, err := users.LoadMailmap(gitDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load mailmap")
+	}
+	aliasesFile := o.AliasesFile
+	if aliasesFile == "" {
+		aliasesFile = filepath.Join(gitDir, users.AliasesFileName)
+	} else {
+		exists, err := files.FileExists(aliasesFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if the aliases file %s exists", aliasesFile)
+		}
+		if !exists {
+			return nil, errors.Errorf("the --aliases-file %s does not exist", aliasesFile)
+		}
+	}
+	aliases, err := users.LoadAliases(aliasesFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load user aliases")
+	}
+	resolver := &users.GitUserResolver{
+		GitProvider:  o.ScmFactory.ScmClient,
+		Mailmap:      mailmap,
+		Aliases:      aliases,
+		Offline:      o.Offline,
+		Workers:      o.ResolveWorkers,
+		Backoff:      o.backoff(),
+		SigningKeys:  o.SigningKeys,
+		EmailLookup:  o.EmailLookup,
+		Repository:   scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository),
+		GitServerURL: o.ScmFactory.GitServerURL,
+		Metrics:      &o.State.ResolutionStats,
+	}
+	if o.OrgMembers {
+		resolver.Organization = o.ScmFactory.Owner
+	}
+	resolver.Cache, err = users.NewUserDetailService(o.UserStore, o.UserCacheFile, o.UserCacheTTL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the user store")
+	}
+	if o.ExcludeBots {
+		resolver.Bots = users.NewBotFilter(o.BotSuffixes, o.Bots)
+	}
+	return resolver, nil
+}
+
+// resolveCommitUsers resolves the unique authors and committers of the commits concurrently via the worker pool of the
+// resolver. Returns the users indexed by their signature with nil users for those which could not be resolved
+func (o *Options) resolveCommitUsers(commits []*object.Commit, resolver *users.GitUserResolver) map[string]*v1.UserDetails {
+	var keys []string
+	var gitUsers []scm.User
+	for _, commit := range commits {
+		for _, signature := range []*object.Signature{&commit.Author, &commit.Committer} {
+			if signature.Email == "" || signature.Name == "" {
+				continue
+			}
+			name, email := resolver.Mailmap.Map(signature.Name, signature.Email)
+			keys = append(keys, signatureKey(signature))
+			gitUsers = append(gitUsers, scm.User{Name: name, Email: email})
+		}
+	}
+	resolved, err := resolver.ResolveAll(o.Context, gitUsers)
+	if err != nil {
+		// lets keep the users which were resolved so only those which failed are missing
+		log.Logger().Warnf("failed to resolve all the authors of the commits: %s", err.Error())
 	}
-	return o.GitClient
+	answer := map[string]*v1.UserDetails{}
+	for i, key := range keys {
+		answer[key] = resolved[i]
+	}
+	return answer
 }
 
-func (o *Options) addCommit(spec *v1.ReleaseSpec, commit *object.Commit, resolver *users.GitUserResolver) {
+// signatureKey returns the key of the git signature in the resolved commit users
+func signatureKey(signature *object.Signature) string {
+	return signature.Name + " <" + strings.ToLower(signature.Email) + ">"
+}
+
+func (o *Options) addCommit(spec *v1.ReleaseSpec, commit *object.Commit, resolver *users.GitUserResolver, commitUsers map[string]*v1.UserDetails) {
 	// TODO
 	url := ""
 	branch := "master"
@@ -705,18 +1563,36 @@ func (o *Options) addCommit(spec *v1.ReleaseSpec, commit *object.Commit, resolve
 	var author, committer *v1.UserDetails
 	var err error
 	sha := commit.Hash.String()
-	if commit.Author.Email != "" && commit.Author.Name != "" {
-		author, err = resolver.GitSignatureAsUser(&commit.Author)
+	if u, ok := commitUsers[signatureKey(&commit.Author)]; ok && !resolver.SigningKeys {
+		author = u
+	} else if commit.Author.Email != "" && commit.Author.Name != "" {
+		author, err = resolver.CommitAuthorAsUser(o.Context, scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository), commit)
 		if err != nil {
 			log.Logger().Warnf("failed to enrich commit with issues, error getting git signature for git author %s: %v", commit.Author, err)
 		}
 	}
-	if commit.Committer.Email != "" && commit.Committer.Name != "" {
-		committer, err = resolver.GitSignatureAsUser(&commit.Committer)
+	if u, ok := commitUsers[signatureKey(&commit.Committer)]; ok {
+		committer = u
+	} else if commit.Committer.Email != "" && commit.Committer.Name != "" {
+		committer, err = resolver.GitSignatureAsUser(o.Context, &commit.Committer)
 		if err != nil {
 			log.Logger().Warnf("failed to enrich commit with issues, error getting git signature for git committer %s: %v", commit.Committer, err)
 		}
 	}
+	if author != nil {
+		key := author.Login
+		if key == "" {
+			key = author.Name
+		}
+		o.State.AuthorIdentities[key] = append(o.State.AuthorIdentities[key], [2]string{commit.Author.Name, commit.Author.Email})
+	}
+	coAuthors, err := resolver.CoAuthorsAsUsers(o.Context, commit.Message)
+	if err != nil {
+		log.Logger().Warnf("failed to resolve co-authors of commit %s: %v", sha, err)
+	}
+	if len(coAuthors) > 0 {
+		o.State.CoAuthors[sha] = coAuthors
+	}
 	commitSummary := v1.CommitSummary{
 		Message:   commit.Message,
 		URL:       url,
@@ -743,85 +1619,118 @@ func (o *Options) addIssuesAndPullRequests(spec *v1.ReleaseSpec, commit *v1.Comm
 		o.State.LoggedIssueKind = true
 		log.Logger().Infof("Finding issues in commit messages using %s format", issueKind)
 	}
-	if issueKind == issues.Jira {
+	switch issueKind {
+	case issues.Jira:
 		regex = JIRAIssueRegex
+	case issues.AzureBoards:
+		regex = AzureBoardsIssueRegex
 	}
 	message := fullCommitMessageText(rawCommit)
 
+	// lets add the keys of the configured issue trackers first so they are not looked up in the git provider
+	for _, key := range o.State.Config.FindIssueKeys(message) {
+		o.addIssueKey(spec, commit, key)
+	}
+
 	matches := regex.FindAllStringSubmatch(message, -1)
 
-	resolver := users.GitUserResolver{
-		GitProvider: o.ScmFactory.ScmClient,
-	}
 	for _, match := range matches {
 		for _, result := range match {
-			result = strings.TrimPrefix(result, "#")
-			if _, ok := o.State.FoundIssueNames[result]; !ok {
-				o.State.FoundIssueNames[result] = true
-				issue, err := tracker.GetIssue(result)
-				if err != nil {
-					log.Logger().Warnf("Failed to lookup issue %s in issue tracker %s due to %s", result, tracker.HomeURL(), err)
-					continue
-				}
-				if issue == nil {
-					log.Logger().Warnf("Failed to find issue %s for repository %s", result, tracker.HomeURL())
-					continue
-				}
+			o.addIssue(spec, commit, strings.TrimPrefix(result, "#"))
+		}
+	}
 
-				user, err := resolver.Resolve(&issue.Author)
-				if err != nil {
-					log.Logger().Warnf("Failed to resolve user %v for issue %s repository %s", issue.Author, result, tracker.HomeURL())
-				}
+	// lets add the issues referenced by trailers such as 'Fixes: 123' which the regex does not match
+	for _, id := range conventional.ParseTrailers(rawCommit.Message).IssueIDs() {
+		if issueKind == issues.Jira || numericIssueRegex.MatchString(id) {
+			o.addIssue(spec, commit, id)
+		}
+	}
+	return nil
+}
 
-				var closedBy *v1.UserDetails
-				if issue.ClosedBy == nil {
-					log.Logger().Warnf("Failed to find closedBy user for issue %s repository %s", result, tracker.HomeURL())
-				} else {
-					u, err := resolver.Resolve(issue.ClosedBy)
-					if err != nil {
-						log.Logger().Warnf("Failed to resolve closedBy user %v for issue %s repository %s", issue.Author, result, tracker.HomeURL())
-					} else if u != nil {
-						closedBy = u
-					}
-				}
+// addIssue looks up the issue in the issue tracker and adds it to the issues or pull requests of the release
+// unless it has already been added
+func (o *Options) addIssue(spec *v1.ReleaseSpec, commit *v1.CommitSummary, result string) {
+	tracker := o.State.Tracker
+	resolver := o.State.Resolver
+	if _, ok := o.State.FoundIssueNames[result]; ok {
+		return
+	}
+	o.State.FoundIssueNames[result] = true
+	issue, err := tracker.GetIssue(result)
+	if err != nil {
+		log.Logger().Warnf("Failed to lookup issue %s in issue tracker %s due to %s", result, tracker.HomeURL(), err)
+		return
+	}
+	if issue == nil {
+		log.Logger().Warnf("Failed to find issue %s for repository %s", result, tracker.HomeURL())
+		return
+	}
 
-				var assignees []v1.UserDetails
-				if issue.Assignees == nil {
-					log.Logger().Warnf("Failed to find assignees for issue %s repository %s", result, tracker.HomeURL())
-				} else {
-					u, err := resolver.GitUserSliceAsUserDetailsSlice(issue.Assignees)
-					if err != nil {
-						log.Logger().Warnf("Failed to resolve Assignees %v for issue %s repository %s", issue.Assignees, result, tracker.HomeURL())
-					}
-					assignees = u
-				}
+	user, err := resolver.Resolve(o.Context, &issue.Author)
+	if err != nil {
+		log.Logger().Warnf("Failed to resolve user %v for issue %s repository %s", issue.Author, result, tracker.HomeURL())
+	}
 
-				labels := toV1Labels(issue.Labels)
-				commit.IssueIDs = append(commit.IssueIDs, result)
-				issueSummary := v1.IssueSummary{
-					ID:                result,
-					URL:               issue.Link,
-					Title:             issue.Title,
-					Body:              issue.Body,
-					User:              user,
-					CreationTimestamp: kube.ToMetaTime(&issue.Created),
-					ClosedBy:          closedBy,
-					Assignees:         assignees,
-					Labels:            labels,
-				}
-				state := issue.State
-				if state != "" {
-					issueSummary.State = state
-				}
-				if issue.PullRequest {
-					spec.PullRequests = append(spec.PullRequests, issueSummary)
-				} else {
-					spec.Issues = append(spec.Issues, issueSummary)
-				}
-			}
+	var closedBy *v1.UserDetails
+	if issue.ClosedBy == nil {
+		log.Logger().Warnf("Failed to find closedBy user for issue %s repository %s", result, tracker.HomeURL())
+	} else {
+		u, err := resolver.Resolve(o.Context, issue.ClosedBy)
+		if err != nil {
+			log.Logger().Warnf("Failed to resolve closedBy user %v for issue %s repository %s", issue.Author, result, tracker.HomeURL())
+		} else if u != nil {
+			closedBy = u
 		}
 	}
-	return nil
+
+	var assignees []v1.UserDetails
+	if issue.Assignees == nil {
+		log.Logger().Warnf("Failed to find assignees for issue %s repository %s", result, tracker.HomeURL())
+	} else {
+		u, err := resolver.GitUserSliceAsUserDetailsSlice(o.Context, issue.Assignees)
+		if err != nil {
+			log.Logger().Warnf("Failed to resolve Assignees %v for issue %s repository %s", issue.Assignees, result, tracker.HomeURL())
+		}
+		assignees = u
+	}
+
+	labels := toV1Labels(issue.Labels)
+	commit.IssueIDs = append(commit.IssueIDs, result)
+	issueSummary := v1.IssueSummary{
+		ID:                result,
+		URL:               issue.Link,
+		Title:             issue.Title,
+		Body:              issue.Body,
+		User:              user,
+		CreationTimestamp: kube.ToMetaTime(&issue.Created),
+		ClosedBy:          closedBy,
+		Assignees:         assignees,
+		Labels:            labels,
+	}
+	state := issue.State
+	if state != "" {
+		issueSummary.State = state
+	}
+	if issue.PullRequest {
+		spec.PullRequests = append(spec.PullRequests, issueSummary)
+	} else {
+		spec.Issues = append(spec.Issues, issueSummary)
+	}
+}
+
+// addIssueKey adds the issue of a configured issue tracker to the issues of the release unless it has already been added
+func (o *Options) addIssueKey(spec *v1.ReleaseSpec, commit *v1.CommitSummary, key config.IssueKey) {
+	if _, ok := o.State.FoundIssueNames[key.Key]; ok {
+		return
+	}
+	o.State.FoundIssueNames[key.Key] = true
+	commit.IssueIDs = append(commit.IssueIDs, key.Key)
+	spec.Issues = append(spec.Issues, v1.IssueSummary{
+		ID:  key.Key,
+		URL: key.URL,
+	})
 }
 
 // toV1Labels converts git labels to IssueLabel
@@ -857,7 +1766,7 @@ func fullCommitMessageText(commit *object.Commit) string {
 
 }
 
-func (o *Options) getTemplateResult(releaseSpec *v1.ReleaseSpec, templateName string, templateText string, templateFile string) (string, error) {
+func (o *Options) getTemplateResult(templateData *TemplateData, templateName string, templateText string, templateFile string) (string, error) {
 	if templateText == "" {
 		if templateFile == "" {
 			return "", nil
@@ -871,13 +1780,13 @@ func (o *Options) getTemplateResult(releaseSpec *v1.ReleaseSpec, templateName st
 	if templateText == "" {
 		return "", nil
 	}
-	tmpl, err := template.New(templateName).Parse(templateText)
+	tmpl, err := template.New(templateName).Funcs(gits.TemplateFuncs(templateData.Changelog)).Parse(templateText)
 	if err != nil {
 		return "", err
 	}
 	var buffer bytes.Buffer
 	writer := bufio.NewWriter(&buffer)
-	err = tmpl.Execute(writer, releaseSpec)
+	err = tmpl.Execute(writer, templateData)
 	writer.Flush()
 	return buffer.String(), err
 }
@@ -936,3 +1845,12 @@ func CollapseDependencyUpdates(dependencyUpdates []v1.DependencyUpdate) []v1.Dep
 func isReleaseNotFound(err error, gitKind string) bool {
 	return scmhelpers.IsScmNotFound(err)
 }
+
+// commitDates returns the committer dates of the commits indexed by their SHA
+func commitDates(commits []*object.Commit) map[string]time.Time {
+	answer := map[string]time.Time{}
+	for _, c := range commits {
+		answer[c.Hash.String()] = c.Committer.When
+	}
+	return answer
+}
@@ -0,0 +1,35 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// checkDCO checks the commits of the release are signed off by their authors logging a compliance summary
+func (o *Options) checkDCO(commits []*object.Commit) error {
+	report := gits.CheckDCO(commits)
+	o.State.DCO = report
+	if report.Compliant() {
+		log.Logger().Infof("all %d commits are signed off", report.Total)
+		return nil
+	}
+	for _, v := range report.Violations {
+		log.Logger().Warnf("commit %s %s by %s: %s", termcolor.ColorWarning(v.SHA), v.Subject, v.Author, v.Reason)
+	}
+	log.Logger().Warnf("%d of %d commits are not signed off", len(report.Violations), report.Total)
+	if o.DCOFail {
+		return errors.Errorf("%d of %d commits are not signed off by their author", len(report.Violations), report.Total)
+	}
+	return nil
+}
+
+// dcoSection returns the sign off compliance to render in the changelog or nil if it should not be rendered
+func (o *Options) dcoSection() *gits.DCOReport {
+	if !o.DCOSection {
+		return nil
+	}
+	return o.State.DCO
+}
@@ -0,0 +1,57 @@
+package create
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// updateFeed adds the release to the feed file creating the file if it does not exist
+func (o *Options) updateFeed(release *v1.Release, gitInfo *giturl.GitRepository, changelog *gits.Changelog) error {
+	content, err := gits.RenderHTMLChanges(changelog)
+	if err != nil {
+		return err
+	}
+	title := stringhelpers.FirstNotEmptyString(changelog.Title, changelog.Version)
+	if title == "" {
+		title = "Unreleased"
+	}
+	link := release.Spec.ReleaseNotesURL
+	if link == "" && changelog.Version != "" {
+		link = stringhelpers.UrlJoin(gitInfo.HttpsURL(), "releases/tag", changelog.Version)
+	}
+	feed := &gits.Feed{
+		Title: o.FeedTitle,
+		Link:  stringhelpers.UrlJoin(gitInfo.HttpsURL(), "releases"),
+	}
+	if feed.Title == "" {
+		feed.Title = gitInfo.Name + " releases"
+	}
+
+	data, err := ioutil.ReadFile(o.FeedFile)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to read the feed file %s", o.FeedFile)
+	}
+	data, err = gits.AddFeedItem(data, o.FeedFormat, feed, &gits.FeedItem{
+		Title:   title,
+		Link:    link,
+		Date:    changelog.Date,
+		Content: content,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the feed file %s", o.FeedFile)
+	}
+	err = ioutil.WriteFile(o.FeedFile, data, files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", o.FeedFile)
+	}
+	log.Logger().Infof("added the release to the feed %s", info(o.FeedFile))
+	return nil
+}
@@ -0,0 +1,30 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/postpublish"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+// findMilestone finds the milestone of the release returning nil if there is none
+func (o *Options) findMilestone(scmClient *scm.Client, version string) *scmapi.Milestone {
+	titles := postpublish.MilestoneTitles(o.Milestones, o.MilestoneFromVersion, version)
+	return postpublish.FindMilestone(o.Context, scmClient, o.backoff(), o.FullName(), titles)
+}
+
+// milestoneNotes appends the link to the milestone of the release and the number of its closed issues to the markdown
+func (o *Options) milestoneNotes(markdown string) string {
+	m := o.State.Milestone
+	if m == nil || !o.MilestoneProgress {
+		return markdown
+	}
+	title := m.Title
+	if m.Link != "" {
+		title = fmt.Sprintf("[%s](%s)", m.Title, m.Link)
+	}
+	total := m.OpenIssues + m.ClosedIssues
+	return strings.TrimRight(markdown, "\n") + fmt.Sprintf("\n\n**Milestone:** %s (%d of %d issues closed)\n", title, m.ClosedIssues, total)
+}
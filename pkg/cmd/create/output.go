@@ -0,0 +1,206 @@
+package create
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// OutputFormatMarkdown outputs the changelog as markdown
+	OutputFormatMarkdown = "markdown"
+
+	// OutputFormatJSON outputs the parsed changelog as JSON
+	OutputFormatJSON = "json"
+
+	// OutputFormatYAML outputs the parsed changelog as YAML
+	OutputFormatYAML = "yaml"
+
+	// OutputFormatHTML outputs the changelog as a standalone HTML page
+	OutputFormatHTML = "html"
+
+	// OutputFormatSlack outputs the changelog as a Slack Block Kit message
+	OutputFormatSlack = "slack"
+)
+
+const (
+	// OutputRelease the --output which updates the release on the git provider
+	OutputRelease = "release"
+
+	// OutputChangelogFile the --output which inserts the release section into a changelog file such as CHANGELOG.md
+	OutputChangelogFile = "changelog"
+)
+
+// OutputFormats the supported output formats
+var OutputFormats = []string{OutputFormatMarkdown, OutputFormatJSON, OutputFormatYAML, OutputFormatHTML, OutputFormatSlack}
+
+// OutputSpec an output of the changelog such as 'json=changelog.json' from the --output flag
+type OutputSpec struct {
+	// Format the output format, release or changelog
+	Format string
+	// File the file to write to or empty for the console
+	File string
+}
+
+// ParseOutputSpec parses an output such as 'release', 'changelog=CHANGELOG.md' or 'json=changelog.json'
+func ParseOutputSpec(text string) (*OutputSpec, error) {
+	parts := strings.SplitN(text, "=", 2)
+	spec := &OutputSpec{Format: strings.TrimSpace(parts[0])}
+	if len(parts) > 1 {
+		spec.File = strings.TrimSpace(parts[1])
+	}
+	switch spec.Format {
+	case OutputRelease:
+		if spec.File != "" {
+			return nil, errors.Errorf("the %s output does not support a file", OutputRelease)
+		}
+	case OutputChangelogFile:
+		if spec.File == "" {
+			spec.File = "CHANGELOG.md"
+		}
+	default:
+		if stringhelpers.StringArrayIndex(OutputFormats, spec.Format) < 0 {
+			return nil, errors.Errorf("unsupported output %s. Supported values: %s", spec.Format, strings.Join(append([]string{OutputRelease, OutputChangelogFile}, OutputFormats...), ", "))
+		}
+	}
+	return spec, nil
+}
+
+// parseOutputs parses the --output flags into the release, changelog file and file outputs
+func (o *Options) parseOutputs() error {
+	o.State.Outputs = nil
+	if len(o.Outputs) == 0 {
+		return nil
+	}
+	o.UpdateRelease = false
+	for _, text := range o.Outputs {
+		spec, err := ParseOutputSpec(text)
+		if err != nil {
+			return errors.Wrapf(err, "invalid --output %s", text)
+		}
+		switch spec.Format {
+		case OutputRelease:
+			o.UpdateRelease = true
+		case OutputChangelogFile:
+			o.ChangelogFile = spec.File
+		default:
+			o.State.Outputs = append(o.State.Outputs, spec)
+		}
+	}
+	return nil
+}
+
+// createChangelog creates the parsed changelog of the release
+func (o *Options) createChangelog(release *v1.Release, gitInfo *giturl.GitRepository, markdownOptions *gits.MarkdownOptions) *gits.Changelog {
+	changelog := gits.CreateChangelog(&release.Spec, gitInfo, release.CreationTimestamp.Time, markdownOptions)
+	changelog.Title = o.State.Title
+	if changelog.Version == SpecVersion {
+		// the version is only known when the chart is released
+		changelog.Version = ""
+		changelog.Title = ""
+	}
+	return changelog
+}
+
+// writeOutput writes the markdown or the parsed changelog in the output format to the file or the console
+func (o *Options) writeOutput(spec *OutputSpec, markdown string, changelog *gits.Changelog) error {
+	if spec.Format == OutputFormatMarkdown {
+		if spec.File == "" {
+			log.Logger().Infof("\nGenerated Changelog:")
+			log.Logger().Infof("%s\n", markdown)
+			return nil
+		}
+		frontMatter, err := o.frontMatter(changelog)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(spec.File, []byte(frontMatter+markdown), files.DefaultFileWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save file %s", spec.File)
+		}
+		log.Logger().Infof("\nGenerated Changelog: %s", info(spec.File))
+		return nil
+	}
+	data, err := o.marshalChangelog(changelog, spec.Format)
+	if err != nil {
+		return err
+	}
+	if spec.File == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	err = ioutil.WriteFile(spec.File, data, files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", spec.File)
+	}
+	log.Logger().Infof("\nGenerated Changelog: %s", info(spec.File))
+	return nil
+}
+
+// frontMatter returns the YAML front matter of the markdown changelog file or an empty string if it is disabled
+func (o *Options) frontMatter(changelog *gits.Changelog) (string, error) {
+	if !o.FrontMatter && len(o.FrontMatterTags) == 0 && !o.State.Config.HasFrontMatter() {
+		return "", nil
+	}
+	return o.State.Config.RenderFrontMatter(changelog.Title, changelog.Version, o.State.Tag, changelog.Date, o.FrontMatterTags)
+}
+
+// marshalChangelog marshals the parsed changelog in the output format
+func (o *Options) marshalChangelog(changelog *gits.Changelog, format string) ([]byte, error) {
+	var data []byte
+	var err error
+	switch format {
+	case OutputFormatJSON:
+		data, err = json.MarshalIndent(changelog, "", "  ")
+	case OutputFormatYAML:
+		data, err = yaml.Marshal(changelog)
+	case OutputFormatHTML:
+		return o.renderHTML(changelog)
+	case OutputFormatSlack:
+		data, err = json.MarshalIndent(gits.CreateSlackMessage(changelog, ""), "", "  ")
+	default:
+		return nil, errors.Errorf("unsupported output format %s", format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the changelog as %s", format)
+	}
+	return data, nil
+}
+
+// renderHTML renders the changelog using the HTML template file or the default template with its built-in stylesheet
+func (o *Options) renderHTML(changelog *gits.Changelog) ([]byte, error) {
+	templateText := ""
+	if o.HTMLTemplateFile != "" {
+		data, err := ioutil.ReadFile(o.HTMLTemplateFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the HTML template file %s", o.HTMLTemplateFile)
+		}
+		templateText = string(data)
+	}
+	html, err := gits.RenderHTML(changelog, templateText)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(html), nil
+}
+
+// renderTemplate renders the changelog using the template file together with the partial templates overriding its
+// blocks
+func (o *Options) renderTemplate(changelog *gits.Changelog, partials map[string]string) (string, error) {
+	data, err := ioutil.ReadFile(o.TemplateFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the template file %s", o.TemplateFile)
+	}
+	return gits.RenderTemplate(changelog, filepath.Base(o.TemplateFile), string(data), partials)
+}
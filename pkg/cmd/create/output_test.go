@@ -0,0 +1,36 @@
+// +build unit
+
+package create_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/create"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputSpec(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		text     string
+		expected *create.OutputSpec
+	}{
+		{text: "release", expected: &create.OutputSpec{Format: create.OutputRelease}},
+		{text: "changelog", expected: &create.OutputSpec{Format: create.OutputChangelogFile, File: "CHANGELOG.md"}},
+		{text: "changelog=docs/CHANGES.md", expected: &create.OutputSpec{Format: create.OutputChangelogFile, File: "docs/CHANGES.md"}},
+		{text: "json=changelog.json", expected: &create.OutputSpec{Format: create.OutputFormatJSON, File: "changelog.json"}},
+		{text: "slack", expected: &create.OutputSpec{Format: create.OutputFormatSlack}},
+		{text: "release=notes.md"},
+		{text: "pdf=changelog.pdf"},
+	}
+	for _, tc := range testCases {
+		spec, err := create.ParseOutputSpec(tc.text)
+		if tc.expected == nil {
+			assert.Error(t, err, "should fail to parse %s", tc.text)
+			continue
+		}
+		require.NoError(t, err, "failed to parse %s", tc.text)
+		assert.Equal(t, tc.expected, spec, "parsing %s", tc.text)
+	}
+}
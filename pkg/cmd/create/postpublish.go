@@ -0,0 +1,59 @@
+package create
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/postpublish"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// publisher returns the publisher of the post publish steps sharing the rate limit of the other git provider calls
+func (o *Options) publisher(scmClient *scm.Client) *postpublish.Publisher {
+	return &postpublish.Publisher{
+		Context:   o.Context,
+		ScmClient: scmClient,
+		Backoff:   o.backoff(),
+		DryRun:    o.DryRun,
+	}
+}
+
+// publishedRelease returns the published release the post publish steps run for
+func (o *Options) publishedRelease(release *v1.Release, dir, rev, version, tagName string) *postpublish.Release {
+	answer := &postpublish.Release{
+		Repository: o.FullName(),
+		Version:    version,
+		Tag:        tagName,
+		Title:      o.State.Title,
+		URL:        release.Spec.ReleaseNotesURL,
+		Milestone:  o.State.Milestone,
+	}
+	for i := range release.Spec.PullRequests {
+		answer.PullRequests = appendNumber(answer.PullRequests, release.Spec.PullRequests[i].ID)
+	}
+	for i := range release.Spec.Issues {
+		answer.Issues = appendNumber(answer.Issues, release.Spec.Issues[i].ID)
+	}
+	if o.PostPublish.CommitStatus != "" {
+		text, err := o.Git().Command(dir, "rev-parse", stringhelpers.FirstNotEmptyString(rev, "HEAD")+"^{commit}")
+		if err != nil {
+			log.Logger().Warnf("failed to find the released commit %s: %s", rev, err.Error())
+		} else {
+			answer.SHA = strings.TrimSpace(text)
+		}
+	}
+	return answer
+}
+
+// appendNumber appends the number of the git provider issue or pull request ID ignoring the issues of other trackers
+// such as Jira which do not have numbers
+func appendNumber(numbers []int, id string) []int {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		return numbers
+	}
+	return append(numbers, n)
+}
@@ -0,0 +1,112 @@
+// +build unit
+
+package create_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/create"
+	"github.com/jenkins-x/go-scm/scm"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestCreatePostPublish(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		draft     bool
+		published bool
+	}{
+		{name: "draft", draft: true},
+		{name: "published", published: true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir, err := ioutil.TempDir("", "")
+			require.NoError(t, err, "could not create temp dir")
+			repo, err := git.PlainInit(dir, false)
+			require.NoError(t, err, "failed to init git repository")
+			_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/jstrachan/foo.git"}})
+			require.NoError(t, err, "failed to add the remote")
+			wt, err := repo.Worktree()
+			require.NoError(t, err, "failed to get worktree")
+			for i, message := range []string{"initial commit", "feat: cheese (#1)"} {
+				sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now()}
+				hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+				require.NoError(t, err, "failed to commit %s", message)
+				if i == 0 {
+					_, err = repo.CreateTag("v1.0.0", hash, nil)
+					require.NoError(t, err, "failed to tag %s", hash.String())
+				}
+			}
+
+			// the milestones are not supported by the fake git provider so they are served over HTTP
+			var lock sync.Mutex
+			var milestoneChanges []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/repos/jstrachan/foo/milestones":
+					_, _ = w.Write([]byte(`[{"number": 2, "title": "1.1.0", "state": "open", "closed_issues": 1}]`))
+				case r.URL.Path == "/repos/jstrachan/foo/milestones/2":
+					lock.Lock()
+					milestoneChanges = append(milestoneChanges, r.Method)
+					lock.Unlock()
+					_, _ = w.Write([]byte(`{}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			scmClient, fakeData := scmfake.NewDefault()
+			scmClient.BaseURL, err = url.Parse(server.URL + "/")
+			require.NoError(t, err, "failed to parse the server URL")
+			scmClient.Driver = scm.DriverGithub
+			fakeData.Issues[1] = []*scm.Issue{{Number: 1, Title: "feat: cheese", Link: "https://github.com/jstrachan/foo/pull/1", PullRequest: true, Closed: true}}
+
+			_, o := create.NewCmdChangelogCreate()
+			o.ScmFactory.Dir = dir
+			o.ScmFactory.ScmClient = scmClient
+			o.ScmFactory.Owner = "jstrachan"
+			o.ScmFactory.Repository = "foo"
+			o.Offline = true
+			o.NoKubernetes = true
+			o.Version = "1.1.0"
+			o.PreviousRevision = "v1.0.0"
+			o.CurrentRevision = "HEAD"
+			o.OutputMarkdownFile = filepath.Join(dir, "changelog.md")
+			o.Draft = tc.draft
+			o.Milestones = []string{"1.1.0"}
+			o.PostPublish.CommentReleased = true
+			o.PostPublish.CloseMilestone = true
+			o.PostPublish.CommitStatus = "status"
+			err = o.Run()
+			require.NoError(t, err, "failed to create the changelog")
+
+			if !tc.published {
+				assert.Empty(t, fakeData.PullRequestComments, "should not comment on the pull requests of a draft release")
+				assert.Empty(t, fakeData.IssueComments, "should not comment on the issues of a draft release")
+				assert.Empty(t, fakeData.Statuses, "should not report the commit status of a draft release")
+				assert.Empty(t, milestoneChanges, "should not close the milestone of a draft release")
+				return
+			}
+			assert.Len(t, fakeData.PullRequestComments[1], 1, "should comment on the pull request of the release")
+			assert.Len(t, fakeData.Statuses, 1, "should report the commit status of the release")
+			assert.Equal(t, []string{http.MethodPatch}, milestoneChanges, "should close the milestone of the release")
+		})
+	}
+}
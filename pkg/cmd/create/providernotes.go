@@ -0,0 +1,36 @@
+package create
+
+import (
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// mergeProviderNotes appends the release notes generated by the git provider to the markdown when the commits of the
+// release are unavailable so that the release still describes its changes
+func (o *Options) mergeProviderNotes(scmClient *scm.Client, tagName, currentRev, markdown string) string {
+	fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	var notes string
+	err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+		var res *scm.Response
+		var err error
+		notes, res, err = scmapi.GenerateReleaseNotes(o.Context, scmClient, fullName, tagName, o.State.PreviousTag, currentRev)
+		return res, err
+	})
+	if err != nil {
+		log.Logger().Warnf("the commits of the release are unavailable and the git provider could not generate the release notes: %s", err)
+		return markdown
+	}
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		return markdown
+	}
+	log.Logger().Infof("the commits of the release are unavailable so using the release notes generated by the git provider")
+	markdown = strings.TrimSpace(markdown)
+	if markdown == "" {
+		return notes + "\n"
+	}
+	return markdown + "\n\n" + notes + "\n"
+}
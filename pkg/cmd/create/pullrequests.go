@@ -0,0 +1,212 @@
+package create
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// mergePullRequestRegex matches the subject GitHub and Bitbucket Server generate for the merge commits of pull requests
+var mergePullRequestRegex = regexp.MustCompile(`^Merge pull request #(\d+) `)
+
+// bitbucketMergeRegex matches the subject Bitbucket Cloud generates for the merge commits of pull requests
+var bitbucketMergeRegex = regexp.MustCompile(`^Merged in \S+ \(pull request #(\d+)\)`)
+
+// linkPullRequests associates each commit with the pull request which merged it using the pull request number in the
+// commit message or, if the git provider supports it, by querying the pull requests which contain the commit
+func (o *Options) linkPullRequests(spec *v1.ReleaseSpec) {
+	prIDs := map[string]bool{}
+	for i := range spec.PullRequests {
+		prIDs[spec.PullRequests[i].ID] = true
+	}
+	query := !o.Offline && (o.ScmFactory.ScmClient != nil || o.AzureClient != nil)
+	var batched map[string][]*scm.PullRequest
+	if query {
+		batched = o.batchCommitPullRequests(spec.Commits, prIDs)
+	}
+	linked := 0
+	for i := range spec.Commits {
+		commit := &spec.Commits[i]
+		if hasPullRequest(commit, prIDs) {
+			continue
+		}
+		if number := pullRequestNumber(commit.Message); number > 0 {
+			id := strconv.Itoa(number)
+			o.linkIssue(spec, commit, id)
+			prIDs[id] = true
+			linked++
+			continue
+		}
+		if !query {
+			continue
+		}
+		var pr *scm.PullRequest
+		var err error
+		if prs, ok := batched[commit.SHA]; ok {
+			pr = mergedPullRequest(prs)
+		} else {
+			pr, err = o.commitPullRequest(commit.SHA)
+		}
+		if err != nil {
+			log.Logger().Warnf("failed to find the pull request of commit %s so no longer querying the git provider: %s", commit.SHA, err.Error())
+			query = false
+			continue
+		}
+		if pr != nil {
+			o.addPullRequest(spec, commit, pr)
+			prIDs[strconv.Itoa(pr.Number)] = true
+			linked++
+		}
+	}
+	log.Logger().Debugf("linked %d commits to their pull requests", linked)
+}
+
+// commitPullRequest returns the merged pull request containing the commit or the first pull request if none are merged
+func (o *Options) commitPullRequest(sha string) (*scm.PullRequest, error) {
+	fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	var prs []*scm.PullRequest
+	err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+		var res *scm.Response
+		var err error
+		if o.AzureClient != nil {
+			prs, res, err = o.AzureClient.ListCommitPullRequests(o.Context, sha)
+		} else {
+			prs, res, err = scmapi.ListCommitPullRequests(o.Context, o.ScmFactory.ScmClient, fullName, sha)
+		}
+		return res, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mergedPullRequest(prs), nil
+}
+
+// batchCommitPullRequests finds the pull requests of the commits without a pull request number using GitHub GraphQL
+// queries of batches of commits. Returns nil if the git provider is not GitHub or the queries fail so that each
+// commit is queried via the REST API instead
+func (o *Options) batchCommitPullRequests(commits []v1.CommitSummary, prIDs map[string]bool) map[string][]*scm.PullRequest {
+	scmClient := o.ScmFactory.ScmClient
+	if o.GraphQLBatchSize <= 0 || o.AzureClient != nil || scmClient == nil || scmClient.Driver != scm.DriverGithub {
+		return nil
+	}
+	var shas []string
+	for i := range commits {
+		commit := &commits[i]
+		if !hasPullRequest(commit, prIDs) && pullRequestNumber(commit.Message) == 0 {
+			shas = append(shas, commit.SHA)
+		}
+	}
+	fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	answer := map[string][]*scm.PullRequest{}
+	for i := 0; i < len(shas); i += o.GraphQLBatchSize {
+		batch := shas[i:]
+		if len(batch) > o.GraphQLBatchSize {
+			batch = batch[:o.GraphQLBatchSize]
+		}
+		var prs map[string][]*scm.PullRequest
+		err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+			var res *scm.Response
+			var err error
+			prs, res, err = scmapi.ListCommitsPullRequests(o.Context, scmClient, fullName, batch)
+			return res, err
+		})
+		if err != nil {
+			log.Logger().Warnf("failed to find the pull requests of the commits via GraphQL so querying each commit instead: %s", err.Error())
+			return nil
+		}
+		for sha, p := range prs {
+			answer[sha] = p
+		}
+	}
+	log.Logger().Debugf("found the pull requests of %d commits via GraphQL", len(answer))
+	return answer
+}
+
+// mergedPullRequest returns the merged pull request or the first pull request if none are merged
+func mergedPullRequest(prs []*scm.PullRequest) *scm.PullRequest {
+	for _, pr := range prs {
+		if pr.Merged {
+			return pr
+		}
+	}
+	if len(prs) > 0 {
+		return prs[0]
+	}
+	return nil
+}
+
+// linkIssue links the commit to the issue or pull request looking it up in the issue tracker if it has not been found yet
+func (o *Options) linkIssue(spec *v1.ReleaseSpec, commit *v1.CommitSummary, id string) {
+	if !o.State.FoundIssueNames[id] {
+		o.addIssue(spec, commit, id)
+		return
+	}
+	if stringhelpers.StringArrayIndex(commit.IssueIDs, id) < 0 {
+		commit.IssueIDs = append(commit.IssueIDs, id)
+	}
+}
+
+// addPullRequest links the commit to the pull request found via the git provider adding it to the release if required
+func (o *Options) addPullRequest(spec *v1.ReleaseSpec, commit *v1.CommitSummary, pr *scm.PullRequest) {
+	id := strconv.Itoa(pr.Number)
+	if stringhelpers.StringArrayIndex(commit.IssueIDs, id) < 0 {
+		commit.IssueIDs = append(commit.IssueIDs, id)
+	}
+	if o.State.FoundIssueNames[id] {
+		return
+	}
+	o.State.FoundIssueNames[id] = true
+
+	user, err := o.State.Resolver.Resolve(o.Context, &pr.Author)
+	if err != nil {
+		log.Logger().Warnf("Failed to resolve user %v for pull request %s: %s", pr.Author, id, err.Error())
+	}
+	var labels []string
+	for _, l := range pr.Labels {
+		labels = append(labels, l.Name)
+	}
+	spec.PullRequests = append(spec.PullRequests, v1.IssueSummary{
+		ID:                id,
+		URL:               pr.Link,
+		Title:             pr.Title,
+		Body:              pr.Body,
+		User:              user,
+		State:             pr.State,
+		CreationTimestamp: kube.ToMetaTime(&pr.Created),
+		Labels:            toV1Labels(labels),
+	})
+}
+
+// hasPullRequest returns true if the commit is already linked to a pull request
+func hasPullRequest(commit *v1.CommitSummary, prIDs map[string]bool) bool {
+	for _, id := range commit.IssueIDs {
+		if prIDs[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// pullRequestNumber returns the number of the pull request which merged the commit using the merge commit subject,
+// the '(#123)' suffix of squash merges or the merge request reference of GitLab. Returns 0 if there is no number
+func pullRequestNumber(message string) int {
+	subject := strings.TrimSpace(strings.SplitN(strings.TrimSpace(message), "\n", 2)[0])
+	m := mergePullRequestRegex.FindStringSubmatch(subject)
+	if m == nil {
+		m = bitbucketMergeRegex.FindStringSubmatch(subject)
+	}
+	if m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err == nil {
+			return n
+		}
+	}
+	return squashPullRequestNumber(message)
+}
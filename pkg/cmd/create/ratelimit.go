@@ -0,0 +1,27 @@
+package create
+
+import (
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// backoff returns the backoff shared by all git provider calls so that the remaining rate limit is tracked across
+// pull request lookups, user resolution and publishing the release
+func (o *Options) backoff() *scmapi.Backoff {
+	if o.State.Backoff == nil {
+		o.State.Backoff = scmapi.NewBackoff(o.RateLimitMaxWait)
+		o.State.Backoff.WarnRemaining = o.RateLimitLowBudget
+	}
+	return o.State.Backoff
+}
+
+// reportRateLimit logs the remaining rate limit of the git provider if any calls were made
+func (o *Options) reportRateLimit() {
+	rate, ok := o.State.Backoff.Budget()
+	if !ok {
+		return
+	}
+	log.Logger().Debugf("%d of %d git provider calls remain until the rate limit resets at %s", rate.Remaining, rate.Limit, time.Unix(rate.Reset, 0).Format(time.RFC3339))
+}
@@ -0,0 +1,283 @@
+package create
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// IfExistsFail fails if the release of the tag already exists
+	IfExistsFail = "fail"
+
+	// IfExistsReplace replaces the description of an existing release with the changelog
+	IfExistsReplace = "replace"
+
+	// IfExistsAppend appends the changelog to the description of an existing release
+	IfExistsAppend = "append"
+
+	// IfExistsSkip leaves an existing release unchanged
+	IfExistsSkip = "skip"
+)
+
+// IfExistsPolicies the supported ways of handling a release which already exists for the tag
+var IfExistsPolicies = []string{IfExistsFail, IfExistsReplace, IfExistsAppend, IfExistsSkip}
+
+// releaseOptions returns the asset links and milestones to associate with the release
+func (o *Options) releaseOptions() (*scmapi.ReleaseOptions, error) {
+	answer := &scmapi.ReleaseOptions{}
+	for _, text := range o.ReleaseAssetLinks {
+		link, err := ParseReleaseAssetLink(text)
+		if err != nil {
+			return nil, err
+		}
+		answer.AssetLinks = append(answer.AssetLinks, *link)
+	}
+	for _, m := range o.Milestones {
+		m = strings.TrimSpace(m)
+		if m != "" {
+			answer.Milestones = append(answer.Milestones, m)
+		}
+	}
+	if m := o.State.Milestone; m != nil && stringhelpers.StringArrayIndex(answer.Milestones, m.Title) < 0 {
+		answer.Milestones = append(answer.Milestones, m.Title)
+	}
+	for _, text := range o.Assets {
+		assets, err := ParseReleaseAssets(text)
+		if err != nil {
+			return nil, err
+		}
+		answer.Assets = append(answer.Assets, assets...)
+	}
+	return answer, nil
+}
+
+// ParseReleaseAssets parses a release asset of the form 'path' or 'path:label' returning the files matching the path
+// glob. The label is only used if the glob matches a single file as the labels of assets should be unique
+func ParseReleaseAssets(text string) ([]*scmapi.ReleaseAsset, error) {
+	pattern := text
+	label := ""
+	if idx := strings.Index(text, ":"); idx > 0 {
+		pattern = text[:idx]
+		label = strings.TrimSpace(text[idx+1:])
+	}
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid release asset glob %q", pattern)
+	}
+	var answer []*scmapi.ReleaseAsset
+	for _, path := range paths {
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if release asset %s exists", path)
+		}
+		if exists {
+			answer = append(answer, &scmapi.ReleaseAsset{Path: path})
+		}
+	}
+	if len(answer) == 0 {
+		return nil, errors.Errorf("no files match the release asset %q", pattern)
+	}
+	if len(answer) == 1 {
+		answer[0].Label = label
+	} else if label != "" {
+		log.Logger().Warnf("ignoring the label %s of release asset %s as it matches %d files", label, pattern, len(answer))
+	}
+	return answer, nil
+}
+
+// uploadReleaseAssets uploads the files to the release
+func (o *Options) uploadReleaseAssets(scmClient *scm.Client, fullName string, rel *scm.Release, assets []*scmapi.ReleaseAsset) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	if rel == nil {
+		log.Logger().Warnf("cannot upload the release assets as the git provider did not return the release")
+		return nil
+	}
+	for _, asset := range assets {
+		_, err := scmapi.UploadReleaseAsset(o.Context, scmClient, fullName, rel, asset)
+		if err != nil {
+			return err
+		}
+		log.Logger().Infof("uploaded the release asset %s", info(asset.Path))
+	}
+	return nil
+}
+
+// ParseReleaseAssetLink parses a release asset link of the form 'name=url' or 'name=url=type'
+func ParseReleaseAssetLink(text string) (*scmapi.ReleaseAssetLink, error) {
+	idx := strings.Index(text, "=")
+	if idx <= 0 {
+		return nil, errors.Errorf("invalid release asset link %q should be of the form 'name=url'", text)
+	}
+	link := &scmapi.ReleaseAssetLink{
+		Name: strings.TrimSpace(text[:idx]),
+		URL:  strings.TrimSpace(text[idx+1:]),
+	}
+	// the URL may contain '=' in its query so only treat a known link type suffix as the type
+	if i := strings.LastIndex(link.URL, "="); i > 0 {
+		switch suffix := link.URL[i+1:]; suffix {
+		case "other", "runbook", "image", "package":
+			link.URL = link.URL[:i]
+			link.LinkType = suffix
+		}
+	}
+	if link.URL == "" {
+		return nil, errors.Errorf("missing URL in release asset link %q", text)
+	}
+	return link, nil
+}
+
+// publishRelease creates or updates the release using the releases API of git providers whose go-scm driver lacks
+// the required features
+func (o *Options) publishRelease(scmClient *scm.Client, fullName string, input *scm.ReleaseInput, opts *scmapi.ReleaseOptions) (*scm.Release, error) {
+	if scmClient.Driver == scm.DriverGitea {
+		if len(opts.AssetLinks) > 0 {
+			log.Logger().Warnf("the gitea git provider does not support release asset links so they are ignored")
+		}
+	} else if input.Draft {
+		log.Logger().Warnf("the gitlab git provider does not support draft releases so the release is published")
+	}
+	var rel *scm.Release
+	err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+		var res *scm.Response
+		var err error
+		if scmClient.Driver == scm.DriverGitea {
+			rel, res, err = scmapi.PublishGiteaRelease(o.Context, scmClient, fullName, input)
+		} else {
+			rel, res, err = scmapi.PublishGitLabRelease(o.Context, scmClient, fullName, input, opts)
+		}
+		return res, err
+	})
+	return rel, err
+}
+
+// uploadGitLabAssets uploads the asset files to the GitLab project and replaces them with asset links as GitLab
+// releases only link to assets
+func (o *Options) uploadGitLabAssets(scmClient *scm.Client, fullName string, opts *scmapi.ReleaseOptions) error {
+	if scmClient.Driver != scm.DriverGitlab {
+		return nil
+	}
+	for _, asset := range opts.Assets {
+		link, _, err := scmapi.UploadGitLabFile(o.Context, scmClient, fullName, asset)
+		if err != nil {
+			return err
+		}
+		log.Logger().Infof("uploaded the release asset %s", info(asset.Path))
+		opts.AssetLinks = append(opts.AssetLinks, *link)
+	}
+	opts.Assets = nil
+	return nil
+}
+
+// applyIfExists applies the --if-exists policy to the input of a release whose tag already has a release created
+// such as by CI or a human. Returns false if the existing release should be left unchanged
+func (o *Options) applyIfExists(existing *scm.Release, input *scm.ReleaseInput) (bool, error) {
+	if existing == nil {
+		return true, nil
+	}
+	switch o.IfExists {
+	case IfExistsFail:
+		return false, errors.Errorf("the release of tag %s already exists. Use --if-exists to replace, append to or skip it", input.Tag)
+	case IfExistsSkip:
+		log.Logger().Infof("leaving the existing release of tag %s unchanged", info(input.Tag))
+		return false, nil
+	case IfExistsAppend:
+		body := strings.TrimSpace(existing.Description)
+		if strings.Contains(body, strings.TrimSpace(input.Description)) {
+			// lets not append the changelog again when re-running a pipeline
+			input.Description = existing.Description
+		} else if body != "" {
+			input.Description = body + "\n\n" + input.Description
+		}
+		if existing.Title != "" {
+			input.Title = existing.Title
+		}
+	}
+	return true, nil
+}
+
+// findExistingRelease returns the release of the tag if the --if-exists policy needs it or nil if there is none
+func (o *Options) findExistingRelease(scmClient *scm.Client, fullName, tagName string) (*scm.Release, error) {
+	if o.IfExists == "" || o.IfExists == IfExistsReplace {
+		return nil, nil
+	}
+	var rel *scm.Release
+	err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+		var res *scm.Response
+		var err error
+		rel, res, err = scmapi.FindReleaseByTag(o.Context, scmClient, fullName, tagName)
+		return res, err
+	})
+	if isReleaseNotFound(err, o.ScmFactory.GitKind) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query release on repo %s for tag %s", fullName, tagName)
+	}
+	return rel, nil
+}
+
+// githubMakeLatest returns true if the release is published via the GitHub releases API to apply --make-latest
+func (o *Options) githubMakeLatest(scmClient *scm.Client) bool {
+	if o.MakeLatest == "" {
+		return false
+	}
+	if scmClient.Driver != scm.DriverGithub {
+		log.Logger().Warnf("the %s git provider does not support --make-latest so it is ignored", scmClient.Driver.String())
+		return false
+	}
+	return true
+}
+
+// releaseURL returns the URL of the published release defaulting to the release page of the tag on the git provider
+func (o *Options) releaseURL(rel *scm.Release, gitInfo *giturl.GitRepository, tagName string) string {
+	if rel != nil && rel.Link != "" {
+		return rel.Link
+	}
+	return gits.ReleaseURL(gitInfo, o.ScmFactory.GitKind, tagName)
+}
+
+// publishTagRelease publishes the release notes on git providers without releases such as Bitbucket by inserting them
+// into the changelog file and, if enabled, annotating the tag with them
+func (o *Options) publishTagRelease(provider, dir, tagName, description string) error {
+	log.Logger().Infof("the %s git provider does not support releases so publishing the release notes via the changelog file and tag %s", provider, info(tagName))
+	if len(o.Assets) > 0 {
+		log.Logger().Warnf("the %s git provider does not support release assets so they are not uploaded", provider)
+	}
+	if o.ChangelogFile == "" {
+		o.ChangelogFile = filepath.Join(dir, gits.DefaultChangelogFile)
+	}
+	if !o.TagDescription {
+		return nil
+	}
+	annotated, err := gits.IsAnnotatedTag(o.Git(), dir, tagName)
+	if err != nil {
+		return err
+	}
+	if annotated {
+		// lets never rewrite annotated or signed tags
+		log.Logger().Infof("not annotating the tag %s with the release notes as it is already annotated", info(tagName))
+		return nil
+	}
+	err = gits.AnnotateTag(o.Git(), dir, tagName, description)
+	if err != nil {
+		return err
+	}
+	err = gits.PushTag(o.Git(), dir, "origin", tagName)
+	if err != nil {
+		log.Logger().Warnf("Failed to push the annotated tag %s: %s", tagName, err)
+		return nil
+	}
+	log.Logger().Infof("annotated the tag %s with the release notes", info(tagName))
+	return nil
+}
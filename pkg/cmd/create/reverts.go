@@ -0,0 +1,25 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// withoutRevertedCommits removes the reverted commits and their reverts from the commits
+func withoutRevertedCommits(commits []*object.Commit, reverted []gits.RevertedCommit) []*object.Commit {
+	if len(reverted) == 0 {
+		return commits
+	}
+	removed := map[string]bool{}
+	for _, r := range reverted {
+		removed[r.Commit.SHA] = true
+		removed[r.Revert.SHA] = true
+	}
+	var answer []*object.Commit
+	for _, c := range commits {
+		if !removed[c.Hash.String()] {
+			answer = append(answer, c)
+		}
+	}
+	return answer
+}
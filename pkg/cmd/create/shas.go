@@ -0,0 +1,49 @@
+package create
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/pkg/errors"
+)
+
+// shaRegex matches abbreviated or full commit SHAs
+var shaRegex = regexp.MustCompile(`^[0-9a-fA-F]{4,40}$`)
+
+// validateSHARange validates the --from-sha and --to-sha do not conflict with the other ways of selecting the range
+func (o *Options) validateSHARange() error {
+	for _, sha := range []string{o.FromSHA, o.ToSHA} {
+		if sha != "" && !shaRegex.MatchString(sha) {
+			return errors.Errorf("invalid commit SHA %s", sha)
+		}
+	}
+	if o.FromSHA != "" && (o.PreviousRevision != "" || o.PreviousDate != "") {
+		return errors.Errorf("cannot use --from-sha together with --previous-rev or --previous-date")
+	}
+	if o.ToSHA != "" && o.CurrentRevision != "" {
+		return errors.Errorf("cannot use --to-sha together with --rev")
+	}
+	return nil
+}
+
+// resolveSHARange resolves the --from-sha and --to-sha to the full SHAs of the commits so that the range is not
+// relative to any tag such as for hotfix branches or environments tracking SHAs
+func (o *Options) resolveSHARange(dir string) (string, string, error) {
+	var answer []string
+	for _, sha := range []string{o.FromSHA, o.ToSHA} {
+		if sha == "" {
+			answer = append(answer, "")
+			continue
+		}
+		full, err := o.Git().Command(dir, "rev-parse", "--verify", sha+"^{commit}")
+		if err != nil {
+			if gits.IsShallowClone(o.Git(), dir) {
+				return "", "", errors.Wrapf(err, "failed to find commit %s in the shallow clone %s. Try fetching more history", sha, dir)
+			}
+			return "", "", errors.Wrapf(err, "failed to find commit %s in %s", sha, dir)
+		}
+		answer = append(answer, strings.TrimSpace(full))
+	}
+	return answer[0], answer[1], nil
+}
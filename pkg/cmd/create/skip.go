@@ -0,0 +1,44 @@
+package create
+
+import (
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// retainCommits returns the commits which are still included in the commit summaries of the release
+func retainCommits(commits []*object.Commit, summaries []v1.CommitSummary) []*object.Commit {
+	kept := map[string]bool{}
+	for i := range summaries {
+		kept[summaries[i].SHA] = true
+	}
+	var answer []*object.Commit
+	for _, c := range commits {
+		if kept[c.Hash.String()] {
+			answer = append(answer, c)
+		}
+	}
+	return answer
+}
+
+// isExcludedAuthor returns true if the author or committer of the commit matches the author exclude patterns of the
+// changelog configuration. The logins are only resolved if there are any patterns
+func (o *Options) isExcludedAuthor(commit *object.Commit) bool {
+	cfg := o.State.Config
+	if !cfg.HasExcludedAuthors() {
+		return false
+	}
+	for _, signature := range []*object.Signature{&commit.Author, &commit.Committer} {
+		identities := []string{signature.Name, signature.Email}
+		u, err := o.State.Resolver.GitSignatureAsUser(o.Context, signature)
+		if err != nil {
+			log.Logger().Debugf("failed to resolve the login of %s <%s>: %s", signature.Name, signature.Email, err.Error())
+		} else if u != nil {
+			identities = append(identities, u.Login)
+		}
+		if cfg.IsExcludedAuthor(identities...) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package create
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+var (
+	// squashSubjectRegex matches the pull request number GitHub appends to the subject of squash merges
+	squashSubjectRegex = regexp.MustCompile(`\(#(\d+)\)$`)
+	// squashMergeRequestRegex matches the merge request reference GitLab adds to the body of squash merges
+	squashMergeRequestRegex = regexp.MustCompile(`(?m)^See merge request \S*!(\d+)$`)
+	// bulletRegex matches the bulleted commit messages in the body of squash merges
+	bulletRegex = regexp.MustCompile(`^\s*[*-]\s+(.+)$`)
+	// mergeSubjectRegex matches the subjects git generates for merge commits
+	mergeSubjectRegex = regexp.MustCompile(`^Merge (branch|remote-tracking branch|pull request) `)
+)
+
+// expandSquashCommits replaces the squash merge commits of pull requests by entries for each of the commits of the
+// pull request. If the commits cannot be listed via the git provider the bulleted lines of the body are used instead
+func (o *Options) expandSquashCommits(commits []v1.CommitSummary) []v1.CommitSummary {
+	var answer []v1.CommitSummary
+	for i := range commits {
+		expanded := o.expandSquashCommit(&commits[i])
+		if len(expanded) == 0 {
+			answer = append(answer, commits[i])
+			continue
+		}
+		answer = append(answer, expanded...)
+	}
+	return answer
+}
+
+func (o *Options) expandSquashCommit(commit *v1.CommitSummary) []v1.CommitSummary {
+	number := squashPullRequestNumber(commit.Message)
+	if number <= 0 {
+		return nil
+	}
+	if !o.Offline && o.ScmFactory.ScmClient != nil {
+		expanded, err := o.pullRequestCommits(commit, number)
+		if err != nil {
+			log.Logger().Warnf("failed to list the commits of pull request %d so using the squash commit message: %s", number, err.Error())
+		} else if len(expanded) > 0 {
+			return expanded
+		}
+	}
+	return squashBulletCommits(commit)
+}
+
+// pullRequestCommits returns entries for the commits of the pull request using the git provider
+func (o *Options) pullRequestCommits(squash *v1.CommitSummary, number int) ([]v1.CommitSummary, error) {
+	client := o.ScmFactory.ScmClient
+	fullName := scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	var prCommits []*scm.Commit
+	err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+		var res *scm.Response
+		var err error
+		prCommits, res, err = scmapi.ListPullRequestCommits(o.Context, client, fullName, number)
+		return res, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var answer []v1.CommitSummary
+	for _, c := range prCommits {
+		if !o.IncludeMergeCommits && mergeSubjectRegex.MatchString(c.Message) {
+			continue
+		}
+		entry := *squash
+		entry.SHA = c.Sha
+		entry.Message = c.Message
+		entry.URL = c.Link
+		author, err := o.State.Resolver.Resolve(o.Context, &scm.User{
+			Login:  c.Author.Login,
+			Name:   c.Author.Name,
+			Email:  c.Author.Email,
+			Avatar: c.Author.Avatar,
+		})
+		if err != nil {
+			log.Logger().Warnf("failed to resolve the author %s of commit %s: %s", c.Author.Name, c.Sha, err.Error())
+		}
+		if author != nil {
+			entry.Author = author
+		}
+		answer = append(answer, entry)
+	}
+	return answer, nil
+}
+
+// squashPullRequestNumber returns the number of the pull request squash merged by the commit or 0 if it is not a squash merge
+func squashPullRequestNumber(message string) int {
+	message = strings.TrimSpace(message)
+	subject := strings.SplitN(message, "\n", 2)[0]
+	m := squashSubjectRegex.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		m = squashMergeRequestRegex.FindStringSubmatch(message)
+	}
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// squashBulletCommits returns entries for each of the bulleted lines in the body of the squash commit
+func squashBulletCommits(squash *v1.CommitSummary) []v1.CommitSummary {
+	lines := strings.Split(strings.TrimSpace(squash.Message), "\n")
+	var answer []v1.CommitSummary
+	for _, line := range lines[1:] {
+		m := bulletRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		entry := *squash
+		entry.Message = strings.TrimSpace(m[1])
+		answer = append(answer, entry)
+	}
+	return answer
+}
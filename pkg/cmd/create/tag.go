@@ -0,0 +1,72 @@
+package create
+
+import (
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// missingVersionTag returns the name of the tag to create for the --version if --create-tag is enabled and the
+// version has no tag yet
+func (o *Options) missingVersionTag(dir string) (string, error) {
+	if !o.CreateTag || o.Version == "" || o.Unreleased {
+		return "", nil
+	}
+	tags, err := gits.ListTags(o.Git(), dir, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the tags in %s", dir)
+	}
+	for _, t := range tags {
+		if t == o.Version || t == "v"+o.Version {
+			return "", nil
+		}
+	}
+	// lets follow the convention of the latest tag
+	if len(tags) > 0 && !strings.HasPrefix(tags[len(tags)-1], "v") {
+		return o.Version, nil
+	}
+	return "v" + o.Version, nil
+}
+
+// createTag creates the annotated tag of the revision with the changelog as its message and pushes it or, with
+// --create-tag-via-api, creates it via the git provider
+func (o *Options) createTag(dir, tag, rev, markdown string) error {
+	message := strings.TrimSpace(markdown)
+	if o.State.Title != "" {
+		message = strings.TrimSpace(o.State.Title + "\n\n" + message)
+	}
+	if message == "" {
+		message = tag
+	}
+	if o.CreateTagViaAPI {
+		if o.SignTag || o.TagSigningKey != "" {
+			log.Logger().Warnf("tags created via the git provider API are not signed")
+		}
+		scmClient := o.ScmFactory.ScmClient
+		if scmClient == nil {
+			return errors.Errorf("no git provider client to create tag %s", tag)
+		}
+		err := o.backoff().Do(o.Context, func() (*scm.Response, error) {
+			return scmapi.CreateTag(o.Context, scmClient, scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository), tag, rev, message)
+		})
+		if err != nil {
+			return err
+		}
+		log.Logger().Infof("created the tag %s via the git provider", info(tag))
+		return nil
+	}
+	err := gits.CreateTag(o.Git(), dir, tag, rev, message, o.SignTag, o.TagSigningKey)
+	if err != nil {
+		return err
+	}
+	err = gits.PushTag(o.Git(), dir, "origin", tag)
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("created the tag %s", info(tag))
+	return nil
+}
@@ -0,0 +1,113 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// TemplateData the data available to the header and footer templates.
+// The ReleaseSpec is embedded so that existing templates can keep using expressions like {{ .Version }}
+type TemplateData struct {
+	v1.ReleaseSpec
+
+	// Reviewers the users who reviewed the pull requests in the release
+	Reviewers []v1.UserDetails
+	// Approvers the users who approved the pull requests in the release
+	Approvers []v1.UserDetails
+	// Contributors the commit authors with the identities of the same user merged together
+	Contributors []*contributors.Contributor
+	// Team the commit authors who are members of the organisation
+	Team []v1.UserDetails
+	// Community the commit authors who are not members of the organisation
+	Community []v1.UserDetails
+	// FirstTimeContributors the commit authors making their first contribution to the repository
+	FirstTimeContributors []v1.UserDetails
+	// ContributorSummary the number of commits and the first and last commit dates of each contributor
+	ContributorSummary []*users.ContributorStats
+	// CommitOwners the owners from the CODEOWNERS file of the paths touched by each commit indexed by the commit SHA
+	CommitOwners map[string][]string
+	// Owners the commits grouped by the owners of the paths they touch
+	Owners []*OwnerCommits
+	// ConventionalCommits the commits parsed using the Conventional Commits specification
+	ConventionalCommits []*conventional.Commit
+	// BreakingChanges the descriptions of the breaking changes of the commits
+	BreakingChanges []string
+	// Compare the link to the comparison with the previous release on the git provider such as {{ .Compare.URL }}
+	Compare *gits.Compare
+	// Title the title of the release rendered from the title template of the configuration
+	Title string
+	// Changelog the parsed changelog with its sections and entries as rendered by the other output formats
+	Changelog *gits.Changelog
+
+	diffStats *gits.DiffStatsCache
+}
+
+// DiffStats returns the number of files and lines changed by the commit with the given SHA such as
+// {{ (.DiffStats .SHA).Insertions }}. The statistics are computed on first use and cached.
+// Returns nil if the commit is not in the local repository
+func (t *TemplateData) DiffStats(sha string) *gits.DiffStats {
+	if t.diffStats == nil {
+		return nil
+	}
+	stats, err := t.diffStats.Get(sha)
+	if err != nil {
+		return nil
+	}
+	return stats
+}
+
+// createTemplateData creates the data used to render the header and footer templates
+func (o *Options) createTemplateData(releaseSpec *v1.ReleaseSpec, changelog *gits.Changelog) *TemplateData {
+	answer := &TemplateData{
+		ReleaseSpec: *releaseSpec,
+	}
+	reviewers := o.State.Reviewers
+	if reviewers == nil {
+		reviewers = &users.Reviewers{}
+	}
+	answer.Reviewers = reviewers.Reviewers
+	answer.Approvers = reviewers.Approvers
+	answer.Contributors = contributors.FromCommits(releaseSpec.Commits)
+	answer.Team, answer.Community = splitContributors(answer.Contributors)
+	answer.FirstTimeContributors = o.State.FirstTimeContributors
+	answer.ContributorSummary = o.State.ContributorSummary
+	answer.CommitOwners = o.State.CommitOwners
+	answer.Owners = groupCommitsByOwner(releaseSpec.Commits, o.State.CommitOwners)
+	answer.ConventionalCommits = conventionalCommits(releaseSpec.Commits)
+	answer.BreakingChanges = gits.BreakingChanges(releaseSpec.Commits, o.State.Config.CommitConvention())
+	answer.diffStats = o.State.DiffStats
+	answer.Compare = o.State.Compare
+	answer.Title = o.State.Title
+	answer.Changelog = changelog
+	return answer
+}
+
+// splitContributors returns the contributors split into organisation members and external users
+func splitContributors(authors []*contributors.Contributor) ([]v1.UserDetails, []v1.UserDetails) {
+	var team, community []v1.UserDetails
+	for _, c := range authors {
+		if c.User.ExternalUser {
+			community = append(community, c.User)
+		} else {
+			team = append(team, c.User)
+		}
+	}
+	return team, community
+}
+
+// conventionalCommits parses the messages of the commits using the Conventional Commits specification
+func conventionalCommits(commits []v1.CommitSummary) []*conventional.Commit {
+	var answer []*conventional.Commit
+	for _, c := range commits {
+		if c.Message == "" {
+			continue
+		}
+		cc := conventional.Parse(c.Message)
+		cc.SHA = c.SHA
+		answer = append(answer, cc)
+	}
+	return answer
+}
@@ -0,0 +1,54 @@
+package create
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// releaseBodyLimit returns the maximum number of characters of the release notes or 0 if there is no limit
+func (o *Options) releaseBodyLimit() int {
+	if o.MaxReleaseBody != 0 {
+		return o.MaxReleaseBody
+	}
+	return gits.ReleaseBodyLimit(o.ScmFactory.GitKind)
+}
+
+// releaseBody returns the markdown truncated at a section boundary if it exceeds the limit of the git provider
+func (o *Options) releaseBody(markdown string, gitInfo *giturl.GitRepository, dir, version string) string {
+	body, truncated := gits.TruncateMarkdown(markdown, o.releaseBodyLimit(), o.truncatedNotice(gitInfo, dir, version))
+	if truncated {
+		log.Logger().Warnf("the changelog of %d characters exceeds the release notes limit of %d characters so it has been truncated", len([]rune(markdown)), o.releaseBodyLimit())
+	}
+	return body
+}
+
+// truncatedNotice returns the notice appended to truncated release notes linking to the complete changelog which is
+// the --changelog-file on the branch it is pushed to or otherwise the comparison of the release
+func (o *Options) truncatedNotice(gitInfo *giturl.GitRepository, dir, version string) string {
+	link := ""
+	if o.ChangelogFile != "" && o.PushChangelog != "" {
+		branch, err := o.changelogBranch(version)
+		if err != nil {
+			log.Logger().Warnf("failed to find the branch of the changelog file %s: %s", o.ChangelogFile, err.Error())
+		}
+		path, err := filepath.Abs(o.ChangelogFile)
+		if err == nil {
+			path, err = filepath.Rel(dir, path)
+		}
+		if branch != "" && branch != "HEAD" && err == nil {
+			link = fmt.Sprintf("[%s](%s)", filepath.Base(o.ChangelogFile), stringhelpers.UrlJoin(gitInfo.HttpsURL(), "blob", branch, filepath.ToSlash(path)))
+		}
+	}
+	if link == "" && o.State.Compare != nil && o.State.Compare.URL != "" {
+		link = fmt.Sprintf("[%s...%s](%s)", o.State.Compare.From, o.State.Compare.To, o.State.Compare.URL)
+	}
+	if link == "" {
+		return "\n---\n\n*The changelog is too long for the release notes so it has been truncated*\n"
+	}
+	return fmt.Sprintf("\n---\n\n*The changelog is too long for the release notes so it has been truncated. See the full changelog in %s*\n", link)
+}
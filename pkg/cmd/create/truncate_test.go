@@ -0,0 +1,124 @@
+// +build unit
+
+package create_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/create"
+	"github.com/jenkins-x/go-scm/scm"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestCreateTruncatedReleaseNotes(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		pushChangelog bool
+		noLimit       bool
+		expectedLink  string
+	}{
+		{
+			name:    "not truncated",
+			noLimit: true,
+		},
+		{
+			name:         "compare",
+			expectedLink: "(https://github.com/jstrachan/foo/compare/v1.0.0...HEAD)",
+		},
+		{
+			name:          "push",
+			pushChangelog: true,
+			expectedLink:  "[CHANGELOG.md](https://github.com/jstrachan/foo/blob/master/CHANGELOG.md)",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir, err := ioutil.TempDir("", "")
+			require.NoError(t, err, "could not create temp dir")
+			origin := filepath.Join(dir, "origin.git")
+			_, err = git.PlainInit(origin, true)
+			require.NoError(t, err, "failed to init the origin repository")
+			dir = filepath.Join(dir, "foo")
+			repo, err := git.PlainInit(dir, false)
+			require.NoError(t, err, "failed to init git repository")
+			_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{origin}})
+			require.NoError(t, err, "failed to add the remote")
+			f, err := os.OpenFile(filepath.Join(dir, ".git", "config"), os.O_APPEND|os.O_WRONLY, 0600)
+			require.NoError(t, err, "failed to open the git config")
+			_, err = f.WriteString("[user]\n\tname = James\n\temail = james@example.com\n")
+			require.NoError(t, err, "failed to configure the git user")
+			require.NoError(t, f.Close())
+			wt, err := repo.Worktree()
+			require.NoError(t, err, "failed to get worktree")
+			messages := []string{"initial commit"}
+			for i := 0; i < 20; i++ {
+				messages = append(messages, "feat: some cheese which is quite a long feature description "+strings.Repeat("x", i))
+			}
+			for i, message := range messages {
+				sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now()}
+				hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+				require.NoError(t, err, "failed to commit %s", message)
+				if i == 0 {
+					_, err = repo.CreateTag("v1.0.0", hash, nil)
+					require.NoError(t, err, "failed to tag %s", hash.String())
+				}
+			}
+
+			scmClient, _ := scmfake.NewDefault()
+			_, o := create.NewCmdChangelogCreate()
+			o.ScmFactory.Dir = dir
+			o.ScmFactory.ScmClient = scmClient
+			o.ScmFactory.SourceURL = "https://github.com/jstrachan/foo"
+			o.ScmFactory.Owner = "jstrachan"
+			o.ScmFactory.Repository = "foo"
+			o.Offline = true
+			o.NoKubernetes = true
+			o.Version = "1.1.0"
+			o.PreviousRevision = "v1.0.0"
+			o.CurrentRevision = "HEAD"
+			o.MaxReleaseBody = 500
+			if tc.noLimit {
+				o.MaxReleaseBody = -1
+			}
+			o.CompareLink = true
+			o.OutputMarkdownFile = filepath.Join(dir, "..", "changelog.md")
+			if tc.pushChangelog {
+				o.ChangelogFile = filepath.Join(dir, "CHANGELOG.md")
+				o.PushChangelog = create.PushChangelogDirect
+			}
+			err = o.Run()
+			require.NoError(t, err, "failed to create the changelog")
+
+			releases, _, err := scmClient.Releases.List(context.Background(), "jstrachan/foo", scm.ReleaseListOptions{})
+			require.NoError(t, err, "failed to list the releases")
+			require.Len(t, releases, 1)
+			description := releases[0].Description
+			if tc.noLimit {
+				assert.NotContains(t, description, "has been truncated")
+			} else {
+				assert.Contains(t, description, "has been truncated")
+				assert.Contains(t, description, tc.expectedLink)
+				assert.NotContains(t, description, "/blob/1.1.0/", "should not link to the tag which does not contain the changelog")
+			}
+
+			data, err := ioutil.ReadFile(o.OutputMarkdownFile)
+			require.NoError(t, err, "failed to read the output file")
+			assert.Contains(t, string(data), strings.Repeat("x", 19), "should write the full changelog to the output file")
+			assert.NotContains(t, string(data), "has been truncated")
+		})
+	}
+}
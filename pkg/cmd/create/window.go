@@ -0,0 +1,58 @@
+package create
+
+import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// validateDateWindow validates the --since and --until do not conflict with the other ways of selecting the range
+func (o *Options) validateDateWindow() error {
+	if o.Since != "" && (o.PreviousRevision != "" || o.PreviousDate != "" || o.FromSHA != "") {
+		return errors.Errorf("cannot use --since together with --previous-rev, --previous-date or --from-sha")
+	}
+	if o.Until != "" && (o.CurrentRevision != "" || o.ToSHA != "") {
+		return errors.Errorf("cannot use --until together with --rev or --to-sha")
+	}
+	return nil
+}
+
+// resolveDateWindow resolves the --since and --until to the commits bounding the window so that all commits in
+// the window are included regardless of tags such as for weekly release notes
+func (o *Options) resolveDateWindow(dir string) (string, string, error) {
+	previousRev, currentRev := "", ""
+	if o.Until != "" {
+		rev, err := gits.GetRevisionBefore(o.Git(), dir, "HEAD", o.Until)
+		if err != nil {
+			return "", "", err
+		}
+		if rev == "" {
+			return "", "", errors.Errorf("no commits found before --until %s", o.Until)
+		}
+		currentRev = rev
+	} else if o.Since != "" && o.CurrentRevision == "" && o.ToSHA == "" {
+		// lets include the commits until now rather than the latest tag
+		rev, err := o.Git().Command(dir, "rev-parse", "HEAD")
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to find the HEAD commit in %s", dir)
+		}
+		currentRev = rev
+	}
+	if o.Since != "" {
+		rev, err := gits.GetRevisionBefore(o.Git(), dir, "HEAD", o.Since)
+		if err != nil {
+			return "", "", err
+		}
+		if rev == "" {
+			// lets include the whole history as the window starts before the first commit
+			rev, err = gits.GetFirstCommitSha(o.Git(), dir)
+			if err != nil {
+				return "", "", errors.Wrap(err, "failed to find the first commit")
+			}
+			o.State.MissingCommits = gits.IsShallowClone(o.Git(), dir)
+		}
+		previousRev = rev
+		log.Logger().Debugf("using commit %s as the start of the window since %s", previousRev, o.Since)
+	}
+	return previousRev, currentRev, nil
+}
@@ -0,0 +1,161 @@
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/create"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cmdLong = templates.LongDesc(`
+		Validates a changelog template by rendering it so that template changes can be checked in pull requests before a release pipeline breaks
+
+		The template is rendered against built-in fixture data using every field of the changelog, against a JSON or YAML changelog file with '--fixture' or against the commits of the repository with '--repo' without updating any release. Template errors are reported with the file and line number.
+`)
+
+	cmdExample = templates.Examples(`
+		# validate a template against the fixture data
+		jx-changelog lint --template changelog.tmpl
+
+		# validate the partial templates of the repository against its latest commits
+		jx-changelog lint --repo
+`)
+)
+
+// Options the options for validating a changelog template
+type Options struct {
+	create.Options
+
+	Fixture     string
+	PartialsDir string
+	Repo        bool
+}
+
+// NewCmdLint creates the command and options
+func NewCmdLint() (*cobra.Command, *Options) {
+	o := &Options{}
+	cmd := &cobra.Command{
+		Use:     "lint",
+		Short:   "Validates a changelog template by rendering it",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Context = cmd.Context()
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	o.Options.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.Fixture, "fixture", "", "", "The JSON or YAML changelog file the template is rendered with such as the output of 'jx-changelog create --output-format json'. Defaults to built-in fixture data")
+	cmd.Flags().StringVarP(&o.PartialsDir, "partials-dir", "", "", fmt.Sprintf("The directory of the partial templates. Defaults to %s in the repository", gits.PartialsDir))
+	cmd.Flags().BoolVarP(&o.Repo, "repo", "", false, "Renders the template with the commits of the repository in dry-run mode without updating any release")
+	return cmd, o
+}
+
+// Run implements the command
+func (o *Options) Run() error {
+	if o.PartialsDir == "" {
+		o.PartialsDir = filepath.Join(o.ScmFactory.Dir, gits.PartialsDir)
+	}
+	var output string
+	var err error
+	if o.Repo {
+		err = o.renderRepository()
+	} else {
+		output, err = o.renderFixture()
+	}
+	if err != nil {
+		if te := gits.ToTemplateError(err); te != nil {
+			te.Template = o.templatePath(te.Template)
+			return te
+		}
+		return err
+	}
+	if output != "" {
+		if o.OutputFile == "" {
+			fmt.Println(output)
+		} else {
+			err = ioutil.WriteFile(o.OutputFile, []byte(output), files.DefaultFileWritePermissions)
+			if err != nil {
+				return errors.Wrapf(err, "failed to save file %s", o.OutputFile)
+			}
+		}
+	}
+	log.Logger().Infof("the template %s is valid", termcolor.ColorInfo(o.templatePath("")))
+	return nil
+}
+
+// renderFixture renders the template with the fixture data
+func (o *Options) renderFixture() (string, error) {
+	changelog := gits.SampleChangelog()
+	if o.Fixture != "" {
+		data, err := ioutil.ReadFile(o.Fixture)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read the fixture %s", o.Fixture)
+		}
+		changelog = &gits.Changelog{}
+		err = yaml.Unmarshal(data, changelog)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse the fixture %s", o.Fixture)
+		}
+	}
+	name, text := "changelog", gits.DefaultMarkdownTemplate
+	if o.TemplateFile != "" {
+		data, err := ioutil.ReadFile(o.TemplateFile)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read the template file %s", o.TemplateFile)
+		}
+		name, text = filepath.Base(o.TemplateFile), string(data)
+	}
+	partials, err := gits.LoadPartials(o.PartialsDir)
+	if err != nil {
+		return "", err
+	}
+	return gits.RenderTemplate(changelog, name, text, partials)
+}
+
+// renderRepository renders the template with the commits of the repository without updating any release
+func (o *Options) renderRepository() error {
+	// lets never create tags, releases or comments on the git provider
+	o.DryRun = true
+	o.UpdateRelease = false
+	o.GenerateCRD = false
+	o.GenerateReleaseYaml = false
+	o.NoKubernetes = true
+	o.ChangelogFile = ""
+	o.PushChangelog = ""
+	o.Outputs = nil
+	o.OutputFormat = create.OutputFormatMarkdown
+	return o.Options.Run()
+}
+
+// templatePath returns the file of the template or partial template with the given name
+func (o *Options) templatePath(name string) string {
+	if o.TemplateFile != "" && (name == "" || name == filepath.Base(o.TemplateFile)) {
+		return o.TemplateFile
+	}
+	if name == "" || name == "changelog" {
+		return "changelog"
+	}
+	fileInfos, err := ioutil.ReadDir(o.PartialsDir)
+	if err == nil {
+		for _, fi := range fileInfos {
+			if strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name())) == name {
+				return filepath.Join(o.PartialsDir, fi.Name())
+			}
+		}
+	}
+	return name
+}
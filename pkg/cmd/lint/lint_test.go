@@ -0,0 +1,71 @@
+// +build unit
+
+package lint_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/lint"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+		line     int
+		column   int
+	}{
+		{
+			name:     "valid.tmpl",
+			text:     "# {{ .Title }}\n{{ range .Sections }}\n## {{ .Title }}\n{{ range .Entries }}* {{ .Subject }} {{ shortSHA .SHA }}\n{{ end }}{{ end }}",
+			expected: "# 1.2.0\n\n## New Features\n* move the config file 1111111\n\n## Bug Fixes\n* the cork 2222222\n",
+		},
+		{
+			name: "parse.tmpl",
+			text: "# {{ .Title }}\n\n{{ range .Sections }}{{ cheese }}{{ end }}\n",
+			line: 3,
+		},
+		{
+			name:   "execute.tmpl",
+			text:   "# {{ .Title }}\n{{ range .Sections }}\n  {{ .Cheese }}{{ end }}\n",
+			line:   3,
+			column: 5,
+		},
+	}
+	for _, tc := range testCases {
+		path := filepath.Join(dir, tc.name)
+		err = ioutil.WriteFile(path, []byte(tc.text), 0600)
+		require.NoError(t, err, "failed to save %s", path)
+		output := filepath.Join(dir, tc.name+".md")
+
+		_, o := lint.NewCmdLint()
+		o.TemplateFile = path
+		o.PartialsDir = filepath.Join(dir, "partials")
+		o.OutputFile = output
+		err = o.Run()
+		if tc.line > 0 {
+			require.Error(t, err, "template %s should be invalid", tc.name)
+			te, ok := err.(*gits.TemplateError)
+			require.True(t, ok, "should return a template error for %s but got %s", tc.name, err.Error())
+			assert.Equal(t, path, te.Template, "template of %s", tc.name)
+			assert.Equal(t, tc.line, te.Line, "line of %s", tc.name)
+			assert.Equal(t, tc.column, te.Column, "column of %s", tc.name)
+			t.Logf("%s\n", te.Error())
+			continue
+		}
+		require.NoError(t, err, "template %s should be valid", tc.name)
+		data, err := ioutil.ReadFile(output)
+		require.NoError(t, err, "failed to read %s", output)
+		assert.Equal(t, tc.expected, string(data))
+	}
+}
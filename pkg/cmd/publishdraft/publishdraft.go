@@ -0,0 +1,213 @@
+package publishdraft
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gitprovider"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/postpublish"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Publishes the draft release of a tag on the git provider
+
+		Use 'jx-changelog create --draft' to create the release as a draft so that the release notes can be reviewed and edited before they are published with this command.
+
+		The steps which 'jx-changelog create' skips for draft releases such as --comment-released, --close-milestone and --commit-status run once the release is published.
+`)
+
+	cmdExample = templates.Examples(`
+		# publish the draft release of the latest tag
+		jx-changelog publish-draft
+
+		# publish the draft release of a version
+		jx-changelog publish-draft --version 1.2.3
+`)
+)
+
+// Options the options for publishing a draft release
+type Options struct {
+	gitprovider.Options
+
+	PostPublish postpublish.Options
+
+	Version              string
+	Tag                  string
+	MakeLatest           string
+	Milestones           []string
+	MilestoneFromVersion bool
+	// Context cancels any calls to the git provider when done. Defaults to a background context
+	Context context.Context
+
+	// Release the published release
+	Release *scm.Release
+}
+
+// NewCmdPublishDraft creates the command and options
+func NewCmdPublishDraft() (*cobra.Command, *Options) {
+	o := &Options{}
+	cmd := &cobra.Command{
+		Use:     "publish-draft",
+		Short:   "Publishes the draft release of a tag",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Context = cmd.Context()
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	o.ScmFactory.DiscoverFromGit = true
+	cmd.Flags().StringVarP(&o.Version, "version", "v", "", "The version of the release whose tag is the version with or without a 'v' prefix")
+	cmd.Flags().StringVarP(&o.Tag, "tag", "t", "", "The tag of the release. Defaults to the tag of the --version or the latest tag")
+	cmd.Flags().StringVarP(&o.MakeLatest, "make-latest", "", "", fmt.Sprintf("Whether the release becomes the latest release of the repository. Supported values: %s. Defaults to the behaviour of the git provider. Only supported on GitHub", strings.Join(scmapi.MakeLatestValues, ", ")))
+	cmd.Flags().StringArrayVarP(&o.Milestones, "milestone", "", nil, "The title of the milestone of the release which --close-milestone closes")
+	cmd.Flags().BoolVarP(&o.MilestoneFromVersion, "milestone-from-version", "", false, "Uses the milestone whose title is the version with or without the 'v' prefix if no --milestone is specified")
+	o.PostPublish.AddFlags(cmd)
+	o.Options.AddFlags(cmd)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *Options) Run() error {
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	if o.MakeLatest != "" && stringhelpers.StringArrayIndex(scmapi.MakeLatestValues, o.MakeLatest) < 0 {
+		return errors.Errorf("unsupported --make-latest %s. Supported values: %s", o.MakeLatest, strings.Join(scmapi.MakeLatestValues, ", "))
+	}
+	err := o.PostPublish.Validate()
+	if err != nil {
+		return err
+	}
+	err = o.Options.Validate()
+	if err != nil {
+		return err
+	}
+	scmClient := o.ScmFactory.ScmClient
+	if scmClient == nil {
+		return errors.Errorf("no git provider client")
+	}
+	fullName := o.FullName()
+
+	tags, err := o.CandidateTags(o.Tag, o.Version)
+	if err != nil {
+		return err
+	}
+	var rel *scm.Release
+	for _, tag := range tags {
+		rel, _, err = scmapi.FindReleaseByTag(o.Context, scmClient, fullName, tag)
+		if scmhelpers.IsScmNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to query release on repo %s for tag %s", fullName, tag)
+		}
+		break
+	}
+	if rel == nil {
+		return errors.Errorf("no release found on repo %s for tag %s", fullName, tags[0])
+	}
+	if !rel.Draft {
+		log.Logger().Infof("the release %s is already published at %s", info(rel.Tag), info(rel.Link))
+		o.Release = rel
+		return nil
+	}
+
+	input := &scm.ReleaseInput{
+		Title:       rel.Title,
+		Tag:         rel.Tag,
+		Commitish:   rel.Commitish,
+		Description: rel.Description,
+		Draft:       false,
+		Prerelease:  rel.Prerelease,
+	}
+	var published *scm.Release
+	switch {
+	case scmClient.Driver == scm.DriverGitea:
+		published, _, err = scmapi.PublishGiteaRelease(o.Context, scmClient, fullName, input)
+	case o.MakeLatest != "" && scmClient.Driver == scm.DriverGithub:
+		published, _, err = scmapi.PublishGitHubRelease(o.Context, scmClient, fullName, rel, input, o.MakeLatest)
+	default:
+		if o.MakeLatest != "" {
+			log.Logger().Warnf("the %s git provider does not support --make-latest so it is ignored", scmClient.Driver.String())
+		}
+		published, _, err = scmClient.Releases.Update(o.Context, fullName, rel.ID, input)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish the release on repo %s for tag %s", fullName, input.Tag)
+	}
+	if published == nil {
+		// some drivers do not return the updated release
+		published = rel
+		published.Draft = false
+	}
+	rel = published
+	o.Release = rel
+	log.Logger().Infof("published the release %s at %s", info(rel.Tag), info(rel.Link))
+	return o.PostPublish.Run(&postpublish.Publisher{Context: o.Context, ScmClient: scmClient}, o.publishedRelease(scmClient, rel))
+}
+
+// publishedRelease returns the published release the post publish steps run for. The pull requests and issues are
+// those linked from the release notes
+func (o *Options) publishedRelease(scmClient *scm.Client, rel *scm.Release) *postpublish.Release {
+	fullName := o.FullName()
+	version := strings.TrimPrefix(rel.Tag, "v")
+	answer := &postpublish.Release{
+		Repository: fullName,
+		Version:    version,
+		Tag:        rel.Tag,
+		Title:      rel.Title,
+		URL:        rel.Link,
+	}
+	answer.PullRequests, answer.Issues = linkedNumbers(fullName, rel.Description)
+	if o.PostPublish.CloseMilestone {
+		titles := postpublish.MilestoneTitles(o.Milestones, o.MilestoneFromVersion, version)
+		answer.Milestone = postpublish.FindMilestone(o.Context, scmClient, nil, fullName, titles)
+	}
+	if o.PostPublish.CommitStatus != "" {
+		text, err := o.Git().Command(o.ScmFactory.Dir, "rev-parse", rel.Tag+"^{commit}")
+		if err != nil {
+			log.Logger().Warnf("failed to find the commit of tag %s: %s", rel.Tag, err.Error())
+		} else {
+			answer.SHA = strings.TrimSpace(text)
+		}
+	}
+	return answer
+}
+
+// linkedNumbers returns the numbers of the pull requests and issues of the repository linked from the markdown
+func linkedNumbers(fullName, markdown string) ([]int, []int) {
+	r := regexp.MustCompile(`/` + regexp.QuoteMeta(fullName) + `/(?:-/)?(pull|pulls|merge_requests|issues)/(\d+)\b`)
+	var pullRequests, issues []int
+	found := map[int]bool{}
+	for _, m := range r.FindAllStringSubmatch(markdown, -1) {
+		n, err := strconv.Atoi(m[2])
+		if err != nil || found[n] {
+			continue
+		}
+		found[n] = true
+		if m[1] == "issues" {
+			issues = append(issues, n)
+		} else {
+			pullRequests = append(pullRequests, n)
+		}
+	}
+	return pullRequests, issues
+}
@@ -0,0 +1,64 @@
+// +build unit
+
+package publishdraft_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/publishdraft"
+	"github.com/jenkins-x/go-scm/scm"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishDraft(t *testing.T) {
+	t.Parallel()
+	scmClient, _ := scmfake.NewDefault()
+	ctx := context.Background()
+	_, _, err := scmClient.Releases.Create(ctx, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.0.0", Tag: "v1.0.0"})
+	require.NoError(t, err)
+	_, _, err = scmClient.Releases.Create(ctx, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.1.0", Tag: "v1.1.0", Description: "cheese", Draft: true})
+	require.NoError(t, err)
+
+	_, o := publishdraft.NewCmdPublishDraft()
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.SourceURL = "https://github.com/jstrachan/foo"
+	o.ScmFactory.GitKind = "github"
+	o.Version = "1.1.0"
+	err = o.Run()
+	require.NoError(t, err, "failed to publish the release")
+
+	require.NotNil(t, o.Release)
+	assert.Equal(t, "v1.1.0", o.Release.Tag)
+	assert.False(t, o.Release.Draft)
+	assert.Equal(t, "cheese", o.Release.Description)
+
+	o.Version = "2.0.0"
+	err = o.Run()
+	assert.Error(t, err, "should fail without a release for the version")
+}
+
+func TestPublishDraftCommentsReleased(t *testing.T) {
+	t.Parallel()
+	scmClient, fakeData := scmfake.NewDefault()
+	ctx := context.Background()
+	description := "### New Features\n\n* cheese ([#1](https://github.com/jstrachan/foo/pull/1)) fixes [#2](https://github.com/jstrachan/foo/issues/2)\n* wine ([#3](https://github.com/jstrachan/bar/pull/3))\n"
+	_, _, err := scmClient.Releases.Create(ctx, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.1.0", Tag: "v1.1.0", Description: description, Draft: true})
+	require.NoError(t, err)
+
+	_, o := publishdraft.NewCmdPublishDraft()
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.SourceURL = "https://github.com/jstrachan/foo"
+	o.ScmFactory.GitKind = "github"
+	o.Version = "1.1.0"
+	o.PostPublish.CommentReleased = true
+	err = o.Run()
+	require.NoError(t, err, "failed to publish the release")
+
+	require.Len(t, fakeData.PullRequestComments[1], 1, "should comment on the pull request of the release")
+	assert.Contains(t, fakeData.PullRequestComments[1][0].Body, "This was released in")
+	assert.Len(t, fakeData.IssueComments[2], 1, "should comment on the issue of the release")
+	assert.Empty(t, fakeData.PullRequestComments[3], "should not comment on the pull requests of other repositories")
+}
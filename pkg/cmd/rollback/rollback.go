@@ -0,0 +1,210 @@
+package rollback
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gitprovider"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	info = termcolor.ColorInfo
+
+	cmdLong = templates.LongDesc(`
+		Rolls back a release by deleting the release on the git provider, optionally its tag, and its section of the changelog file
+
+		Use this command when a release pipeline published a broken version so that the release can be cleanly created again.
+`)
+
+	cmdExample = templates.Examples(`
+		# deletes the release of the latest tag and its section of the CHANGELOG.md
+		jx-changelog rollback --yes
+
+		# displays what would be deleted for a version
+		jx-changelog rollback --version 1.2.3 --dry-run
+
+		# deletes the release and the tag of a version
+		jx-changelog rollback --version 1.2.3 --delete-tag
+`)
+)
+
+// Options the options for rolling back a release
+type Options struct {
+	gitprovider.Options
+
+	Version       string
+	Tag           string
+	DeleteTag     bool
+	DryRun        bool
+	Yes           bool
+	Remote        string
+	ChangelogFile string
+	// Context cancels any calls to the git provider when done. Defaults to a background context
+	Context context.Context
+
+	// Release the deleted release if there was one
+	Release *scm.Release
+}
+
+// NewCmdRollback creates the command and options
+func NewCmdRollback() (*cobra.Command, *Options) {
+	o := &Options{}
+	cmd := &cobra.Command{
+		Use:     "rollback",
+		Aliases: []string{"delete"},
+		Short:   "Deletes the release of a tag and reverts its changelog section",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Context = cmd.Context()
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	o.ScmFactory.DiscoverFromGit = true
+	cmd.Flags().StringVarP(&o.Version, "version", "v", "", "The version of the release whose tag is the version with or without a 'v' prefix")
+	cmd.Flags().StringVarP(&o.Tag, "tag", "t", "", "The tag of the release. Defaults to the tag of the --version or the latest tag if confirmed with --yes")
+	cmd.Flags().BoolVarP(&o.DeleteTag, "delete-tag", "", false, "Also deletes the tag locally and from the remote")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Displays what would be deleted without deleting anything")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "Confirms rolling back the release of the latest tag if no --tag or --version is specified")
+	cmd.Flags().StringVarP(&o.Remote, "remote", "", "origin", "The git remote the tag is deleted from")
+	cmd.Flags().StringVarP(&o.ChangelogFile, "changelog-file", "", "", "The changelog file to remove the section of the release from. Defaults to the CHANGELOG.md file if it exists")
+	o.Options.AddFlags(cmd)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *Options) Run() error {
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	if o.Tag == "" && o.Version == "" && !o.Yes && !o.DryRun {
+		return errors.Errorf("specify the --tag or --version to roll back or confirm rolling back the latest tag with --yes")
+	}
+	err := o.Options.Validate()
+	if err != nil {
+		return err
+	}
+	scmClient := o.ScmFactory.ScmClient
+	if scmClient == nil {
+		return errors.Errorf("no git provider client")
+	}
+	fullName := o.FullName()
+	o.Release = nil
+
+	tags, err := o.CandidateTags(o.Tag, o.Version)
+	if err != nil {
+		return err
+	}
+	tag := tags[0]
+	for _, t := range tags {
+		rel, _, err := scmapi.FindReleaseByTag(o.Context, scmClient, fullName, t)
+		if scmhelpers.IsScmNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to query release on repo %s for tag %s", fullName, t)
+		}
+		tag = t
+		o.Release = rel
+		break
+	}
+
+	if o.DeleteTag {
+		tag, err = o.existingTag(tag, tags)
+		if err != nil {
+			return err
+		}
+	}
+	if o.DryRun {
+		if o.Release != nil {
+			log.Logger().Infof("would delete the release %s", info(tag))
+		}
+		if o.DeleteTag {
+			log.Logger().Infof("would delete the tag %s from %s", info(tag), info(o.Remote))
+		}
+		log.Logger().Infof("would remove the section of %s from the changelog file", info(tag))
+		return nil
+	}
+
+	if o.Release == nil {
+		log.Logger().Infof("there is no release on repo %s for tag %s", info(fullName), info(tag))
+	} else {
+		_, err = scmapi.DeleteRelease(o.Context, scmClient, fullName, o.Release)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete the release on repo %s for tag %s", fullName, tag)
+		}
+		log.Logger().Infof("deleted the release %s", info(tag))
+	}
+
+	if o.DeleteTag {
+		err = gits.DeleteTag(o.Git(), o.ScmFactory.Dir, o.Remote, tag)
+		if err != nil {
+			return err
+		}
+		log.Logger().Infof("deleted the tag %s", info(tag))
+	}
+	return o.revertChangelogFile(tag)
+}
+
+// revertChangelogFile removes the section of the tag from the changelog file if there is one
+func (o *Options) revertChangelogFile(tag string) error {
+	path := o.ChangelogFile
+	if path == "" {
+		path = filepath.Join(o.ScmFactory.Dir, gits.DefaultChangelogFile)
+	}
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		if o.ChangelogFile != "" {
+			return errors.Errorf("changelog file %s does not exist", path)
+		}
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", path)
+	}
+	text, removed := gits.RemoveChangelogFileSection(string(data), tag)
+	if !removed {
+		log.Logger().Infof("the changelog file %s has no section for %s", info(path), info(tag))
+		return nil
+	}
+	err = ioutil.WriteFile(path, []byte(text), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save %s", path)
+	}
+	log.Logger().Infof("removed the section of %s from the changelog file %s", info(tag), info(path))
+	return nil
+}
+
+// existingTag returns the tag to delete which is the tag of the release or otherwise the first candidate tag which
+// exists in the repository
+func (o *Options) existingTag(tag string, candidates []string) (string, error) {
+	existing, err := gits.ListTags(o.Git(), o.ScmFactory.Dir, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the tags in %s", o.ScmFactory.Dir)
+	}
+	for _, t := range append([]string{tag}, candidates...) {
+		if stringhelpers.StringArrayIndex(existing, t) >= 0 {
+			return t, nil
+		}
+	}
+	return "", errors.Errorf("no tag %s found in %s", strings.Join(candidates, " or "), o.ScmFactory.Dir)
+}
@@ -0,0 +1,98 @@
+// +build unit
+
+package rollback_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/rollback"
+	"github.com/jenkins-x/go-scm/scm"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback(t *testing.T) {
+	t.Parallel()
+	scmClient, _ := scmfake.NewDefault()
+	ctx := context.Background()
+	_, _, err := scmClient.Releases.Create(ctx, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.0.0", Tag: "v1.0.0"})
+	require.NoError(t, err)
+	_, _, err = scmClient.Releases.Create(ctx, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.1.0", Tag: "v1.1.0"})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	changelogFile := filepath.Join(dir, "CHANGELOG.md")
+	err = ioutil.WriteFile(changelogFile, []byte("# Changelog\n\n## [1.1.0]\n\n* wine\n\n## [1.0.0]\n\n* cheese\n"), 0600)
+	require.NoError(t, err)
+
+	_, o := rollback.NewCmdRollback()
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.SourceURL = "https://github.com/jstrachan/foo"
+	o.ScmFactory.GitKind = "github"
+	o.ScmFactory.Dir = dir
+	o.Version = "1.1.0"
+	err = o.Run()
+	require.NoError(t, err, "failed to roll back the release")
+
+	require.NotNil(t, o.Release)
+	assert.Equal(t, "v1.1.0", o.Release.Tag)
+	_, _, err = scmClient.Releases.FindByTag(ctx, "jstrachan/foo", "v1.1.0")
+	assert.Equal(t, scm.ErrNotFound, err, "the release should be deleted")
+	_, _, err = scmClient.Releases.FindByTag(ctx, "jstrachan/foo", "v1.0.0")
+	assert.NoError(t, err, "the previous release should remain")
+
+	data, err := ioutil.ReadFile(changelogFile)
+	require.NoError(t, err)
+	assert.Equal(t, "# Changelog\n\n## [1.0.0]\n\n* cheese\n", string(data))
+
+	// lets check we can re-run the rollback
+	err = o.Run()
+	require.NoError(t, err, "failed to roll back the release again")
+	assert.Nil(t, o.Release)
+}
+
+func TestRollbackRequiresConfirmation(t *testing.T) {
+	t.Parallel()
+	scmClient, _ := scmfake.NewDefault()
+	_, o := rollback.NewCmdRollback()
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.Dir = t.TempDir()
+	err := o.Run()
+	require.Error(t, err, "should not roll back the latest tag without confirmation")
+	assert.Contains(t, err.Error(), "--yes")
+}
+
+func TestRollbackDryRun(t *testing.T) {
+	t.Parallel()
+	scmClient, _ := scmfake.NewDefault()
+	ctx := context.Background()
+	_, _, err := scmClient.Releases.Create(ctx, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.1.0", Tag: "v1.1.0"})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	changelogFile := filepath.Join(dir, "CHANGELOG.md")
+	changelog := "# Changelog\n\n## [1.1.0]\n\n* wine\n"
+	err = ioutil.WriteFile(changelogFile, []byte(changelog), 0600)
+	require.NoError(t, err)
+
+	_, o := rollback.NewCmdRollback()
+	o.ScmFactory.ScmClient = scmClient
+	o.ScmFactory.SourceURL = "https://github.com/jstrachan/foo"
+	o.ScmFactory.GitKind = "github"
+	o.ScmFactory.Dir = dir
+	o.Version = "1.1.0"
+	o.DryRun = true
+	err = o.Run()
+	require.NoError(t, err, "failed to dry run the rollback")
+
+	require.NotNil(t, o.Release)
+	_, _, err = scmClient.Releases.FindByTag(ctx, "jstrachan/foo", "v1.1.0")
+	assert.NoError(t, err, "the release should not be deleted")
+	data, err := ioutil.ReadFile(changelogFile)
+	require.NoError(t, err)
+	assert.Equal(t, changelog, string(data), "the changelog file should not be changed")
+}
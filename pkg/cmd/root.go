@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/backfill"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/badges"
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/create"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/lint"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/publishdraft"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/rollback"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
 	"github.com/jenkins-x/jx-logging/v3/pkg/log"
 
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/verify"
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/version"
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/rootcmd"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras"
@@ -30,7 +36,13 @@ func Main() *cobra.Command {
 	}
 	o := options.BaseOptions{}
 	o.AddBaseFlags(cmd)
+	cmd.AddCommand(cobras.SplitCommand(backfill.NewCmdBackfill()))
+	cmd.AddCommand(cobras.SplitCommand(badges.NewCmdBadges()))
 	cmd.AddCommand(cobras.SplitCommand(create.NewCmdChangelogCreate()))
+	cmd.AddCommand(cobras.SplitCommand(lint.NewCmdLint()))
+	cmd.AddCommand(cobras.SplitCommand(publishdraft.NewCmdPublishDraft()))
+	cmd.AddCommand(cobras.SplitCommand(rollback.NewCmdRollback()))
+	cmd.AddCommand(cobras.SplitCommand(verify.NewCmdVerify()))
 	cmd.AddCommand(cobras.SplitCommand(version.NewCmdVersion()))
 	return cmd
 }
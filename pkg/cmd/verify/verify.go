@@ -0,0 +1,314 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	chgit "github.com/antham/chyle/chyle/git"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/config"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const (
+	// OutputFormatText logs the invalid commits
+	OutputFormatText = "text"
+
+	// OutputFormatJSON writes the report as JSON
+	OutputFormatJSON = "json"
+)
+
+// OutputFormats the supported output formats
+var OutputFormats = []string{OutputFormatText, OutputFormatJSON}
+
+var (
+	cmdLong = templates.LongDesc(`
+		Verifies that the commit messages follow the convention used to generate the changelog
+
+		The commits between the latest tag, or the '--previous-rev', and the '--rev' are checked against the convention configured in the changelog configuration file which defaults to Conventional Commits: https://conventionalcommits.org/
+
+		Use '--fail' to return a non zero exit code if any commit is invalid so that the command can gate pull request pipelines.
+`)
+
+	cmdExample = templates.Examples(`
+		# verify the commits since the latest tag
+		jx-changelog verify
+
+		# verify the commits of a pull request failing the pipeline if any are invalid
+		jx-changelog verify --previous-rev origin/main --fail
+
+		# write a JSON report of the invalid commits
+		jx-changelog verify --output json --output-file report.json
+`)
+)
+
+// Options the options for verifying commit messages
+type Options struct {
+	Dir                 string
+	PreviousRevision    string
+	CurrentRevision     string
+	ConfigFile          string
+	Convention          string
+	Pattern             string
+	Output              string
+	OutputFile          string
+	Fail                bool
+	IncludeMergeCommits bool
+	GitClient           gitclient.Interface
+	CommandRunner       cmdrunner.CommandRunner
+
+	// Report the result of the last verification
+	Report *Report
+}
+
+// Report the result of verifying the commit messages
+type Report struct {
+	// Convention the convention or pattern the commit messages were verified against
+	Convention string `json:"convention"`
+	// Total the number of verified commits
+	Total int `json:"total"`
+	// Invalid the commits which do not follow the convention
+	Invalid []InvalidCommit `json:"invalid"`
+}
+
+// InvalidCommit a commit whose message does not follow the convention
+type InvalidCommit struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+	Author  string `json:"author,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// NewCmdVerify creates the command and options
+func NewCmdVerify() (*cobra.Command, *Options) {
+	o := &Options{}
+	cmd := &cobra.Command{
+		Use:     "verify",
+		Short:   "Verifies that the commit messages follow the changelog convention",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory of the git repository")
+	cmd.Flags().StringVarP(&o.PreviousRevision, "previous-rev", "p", "", "the revision after which commits are verified. Defaults to the latest tag")
+	cmd.Flags().StringVarP(&o.CurrentRevision, "rev", "", "HEAD", "the last revision to verify")
+	cmd.Flags().StringVarP(&o.ConfigFile, "config-file", "", "", "The YAML file configuring the commit message convention. Defaults to '"+config.FileName+"' in the root of the repository")
+	cmd.Flags().StringVarP(&o.Convention, "convention", "", "", fmt.Sprintf("The convention the commit messages must follow. Supported values: %s. Defaults to the convention of the configuration file", strings.Join(gits.Conventions, ", ")))
+	cmd.Flags().StringVarP(&o.Pattern, "pattern", "", "", "The regular expression the commit subjects must match instead of the convention. Defaults to the pattern of the configuration file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", OutputFormatText, fmt.Sprintf("The output format. Supported values: %s", strings.Join(OutputFormats, ", ")))
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "The file to write the report to. Defaults to the standard output")
+	cmd.Flags().BoolVarP(&o.Fail, "fail", "", false, "Returns a non zero exit code if any commit message is invalid")
+	cmd.Flags().BoolVarP(&o.IncludeMergeCommits, "include-merge-commits", "", false, "Verifies merge commits too")
+	return cmd, o
+}
+
+// Run implements the command
+func (o *Options) Run() error {
+	if stringhelpers.StringArrayIndex(OutputFormats, o.Output) < 0 {
+		return errors.Errorf("unsupported output format %s. Supported values: %s", o.Output, strings.Join(OutputFormats, ", "))
+	}
+	gitDir, _, err := gitclient.FindGitConfigDir(o.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the git directory of %s", o.Dir)
+	}
+	if gitDir == "" {
+		return errors.Errorf("no git directory could be found from dir %s", o.Dir)
+	}
+
+	configFile := o.ConfigFile
+	if configFile == "" {
+		configFile = filepath.Join(gitDir, config.FileName)
+	}
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the changelog configuration")
+	}
+	verifier, err := o.createVerifier(cfg)
+	if err != nil {
+		return err
+	}
+
+	previousRev := o.PreviousRevision
+	if previousRev == "" {
+		previousRev, _, err = gits.GetCommitPointedToByLatestTag(o.Git(), o.Dir)
+		if err != nil {
+			return err
+		}
+		if previousRev == "" {
+			previousRev, err = gits.GetFirstCommitSha(o.Git(), o.Dir)
+			if err != nil {
+				return errors.Wrap(err, "failed to find the first commit")
+			}
+		}
+	}
+	commits, err := chgit.FetchCommits(gitDir, previousRev, o.CurrentRevision)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch the commits between %s and %s", previousRev, o.CurrentRevision)
+	}
+
+	o.Report = &Report{Convention: verifier.name, Invalid: []InvalidCommit{}}
+	if commits != nil {
+		for i := range *commits {
+			commit := &(*commits)[i]
+			if !o.IncludeMergeCommits && len(commit.ParentHashes) > 1 {
+				continue
+			}
+			if cfg.IsExcluded(commit.Message) {
+				continue
+			}
+			o.Report.Total++
+			reason := verifier.verify(commit.Message)
+			if reason != "" {
+				o.Report.Invalid = append(o.Report.Invalid, invalidCommit(commit, reason))
+			}
+		}
+	}
+
+	err = o.writeReport()
+	if err != nil {
+		return err
+	}
+	if o.Fail && len(o.Report.Invalid) > 0 {
+		return errors.Errorf("%d of %d commit messages are invalid using %s", len(o.Report.Invalid), o.Report.Total, verifier.name)
+	}
+	return nil
+}
+
+// Git returns the git client
+func (o *Options) Git() gitclient.Interface {
+	if o.GitClient == nil {
+		o.GitClient = cli.NewCLIClient("", o.CommandRunner)
+	}
+	return o.GitClient
+}
+
+// writeReport logs the invalid commits or writes the JSON report
+func (o *Options) writeReport() error {
+	if o.Output == OutputFormatJSON {
+		data, err := json.MarshalIndent(o.Report, "", "  ")
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal the report")
+		}
+		if o.OutputFile == "" {
+			fmt.Println(string(data))
+			return nil
+		}
+		err = ioutil.WriteFile(o.OutputFile, data, files.DefaultFileWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save file %s", o.OutputFile)
+		}
+		return nil
+	}
+
+	var buffer strings.Builder
+	for _, c := range o.Report.Invalid {
+		buffer.WriteString(fmt.Sprintf("%s %s: %s\n", shortSHA(c.SHA), c.Subject, c.Reason))
+	}
+	if o.OutputFile != "" {
+		err := ioutil.WriteFile(o.OutputFile, []byte(buffer.String()), files.DefaultFileWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save file %s", o.OutputFile)
+		}
+	} else {
+		for _, c := range o.Report.Invalid {
+			log.Logger().Warnf("%s %s: %s", termcolor.ColorWarning(shortSHA(c.SHA)), c.Subject, c.Reason)
+		}
+	}
+	if len(o.Report.Invalid) == 0 {
+		log.Logger().Infof("all %d commit messages are valid using %s", o.Report.Total, o.Report.Convention)
+	} else {
+		log.Logger().Infof("%d of %d commit messages are invalid using %s", len(o.Report.Invalid), o.Report.Total, o.Report.Convention)
+	}
+	return nil
+}
+
+// verifier checks commit messages against a convention returning the reason they are invalid
+type verifier struct {
+	name   string
+	verify func(message string) string
+}
+
+func (o *Options) createVerifier(cfg *config.Config) (*verifier, error) {
+	pattern := o.Pattern
+	if pattern == "" && o.Convention == "" && cfg != nil {
+		pattern = cfg.Pattern
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %s", pattern)
+		}
+		return &verifier{
+			name: pattern,
+			verify: func(message string) string {
+				if !re.MatchString(subject(message)) {
+					return "the subject does not match the pattern " + pattern
+				}
+				return ""
+			},
+		}, nil
+	}
+
+	convention := o.Convention
+	if convention == "" {
+		convention = cfg.CommitConvention()
+	}
+	if stringhelpers.StringArrayIndex(gits.Conventions, convention) < 0 {
+		return nil, errors.Errorf("unsupported convention %s. Supported values: %s", convention, strings.Join(gits.Conventions, ", "))
+	}
+	groups := cfg.CommitGroups()
+	return &verifier{
+		name: convention,
+		verify: func(message string) string {
+			ci := gits.ParseCommitWithConvention(message, convention)
+			switch {
+			case convention == gits.ConventionGitmoji && ci.Kind == "":
+				return "the subject does not start with a gitmoji"
+			case convention == gits.ConventionConventional && !conventional.Parse(message).Conventional:
+				return "the subject is not a Conventional Commit such as 'fix(scope): description'"
+			case groups != nil && groups.Group(ci.Kind) == nil:
+				return fmt.Sprintf("the type %s is not mapped to any changelog section", ci.Kind)
+			}
+			return ""
+		},
+	}, nil
+}
+
+func invalidCommit(commit *object.Commit, reason string) InvalidCommit {
+	return InvalidCommit{
+		SHA:     commit.Hash.String(),
+		Subject: subject(commit.Message),
+		Author:  commit.Author.Name,
+		Reason:  reason,
+	}
+}
+
+func subject(message string) string {
+	return strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
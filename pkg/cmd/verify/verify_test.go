@@ -0,0 +1,81 @@
+// +build unit
+
+package verify_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	var shas []string
+	for i, message := range []string{"initial commit", "feat: cheese", "fixed the cork", ":sparkles: wine", "chore(release): 1.2.3"} {
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now().Add(time.Duration(i) * time.Minute)}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		shas = append(shas, hash.String())
+	}
+	// lets save the git config so that the repository can be discovered
+	cfg, err := repo.Config()
+	require.NoError(t, err, "failed to get git config")
+	err = repo.Storer.SetConfig(cfg)
+	require.NoError(t, err, "failed to save git config")
+
+	_, o := verify.NewCmdVerify()
+	o.Dir = dir
+	o.PreviousRevision = shas[0]
+	err = o.Run()
+	require.NoError(t, err, "failed to verify")
+	assert.Equal(t, 4, o.Report.Total)
+	require.Len(t, o.Report.Invalid, 2)
+	assert.Equal(t, ":sparkles: wine", o.Report.Invalid[0].Subject)
+	assert.Equal(t, "fixed the cork", o.Report.Invalid[1].Subject)
+
+	o.Fail = true
+	err = o.Run()
+	assert.Error(t, err, "should fail with invalid commits")
+
+	reportFile := filepath.Join(dir, "report.json")
+	_, o = verify.NewCmdVerify()
+	o.Dir = dir
+	o.PreviousRevision = shas[0]
+	o.Convention = "gitmoji"
+	o.Output = verify.OutputFormatJSON
+	o.OutputFile = reportFile
+	err = o.Run()
+	require.NoError(t, err, "failed to verify")
+
+	data, err := ioutil.ReadFile(reportFile)
+	require.NoError(t, err, "failed to load %s", reportFile)
+	report := &verify.Report{}
+	err = json.Unmarshal(data, report)
+	require.NoError(t, err, "failed to parse %s", reportFile)
+	assert.Equal(t, "gitmoji", report.Convention)
+	assert.Len(t, report.Invalid, 3)
+
+	_, o = verify.NewCmdVerify()
+	o.Dir = dir
+	o.PreviousRevision = shas[0]
+	o.Pattern = `^(feat|fix|chore)[(:]`
+	err = o.Run()
+	require.NoError(t, err, "failed to verify")
+	assert.Len(t, o.Report.Invalid, 2)
+}
@@ -0,0 +1,200 @@
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	chgit "github.com/antham/chyle/chyle/git"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/config"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// InitialVersion the version incremented if the repository has no tags yet
+const InitialVersion = "0.0.0"
+
+var (
+	nextLong = templates.LongDesc(`
+		Recommends the next version of the repository from the commits since the latest tag
+
+		Breaking changes increment the major version, features the minor version and bug fixes the patch version. The increments of the commit types can be changed with the 'bumps' of the changelog configuration file or '--bump'. The version is unchanged if no commit is releasable.
+`)
+
+	nextExample = templates.Examples(`
+		# display the next version
+		jx-changelog version next
+
+		# save the next version for the following steps of the pipeline
+		jx-changelog version next --write-file VERSION
+
+		# release documentation changes as patches
+		jx-changelog version next --bump docs=patch
+`)
+)
+
+// NextOptions the options for recommending the next version
+type NextOptions struct {
+	Dir              string
+	PreviousRevision string
+	CurrentRevision  string
+	CurrentVersion   string
+	ConfigFile       string
+	Convention       string
+	Bumps            []string
+	WriteFile        string
+	GitClient        gitclient.Interface
+	CommandRunner    cmdrunner.CommandRunner
+
+	// Bump the version increment of the last run
+	Bump string
+	// NextVersion the recommended version of the last run
+	NextVersion string
+}
+
+// NewCmdVersionNext creates the command and options
+func NewCmdVersionNext() (*cobra.Command, *NextOptions) {
+	o := &NextOptions{}
+	cmd := &cobra.Command{
+		Use:     "next",
+		Short:   "Recommends the next version from the commits since the latest tag",
+		Long:    nextLong,
+		Example: nextExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory of the git repository")
+	cmd.Flags().StringVarP(&o.PreviousRevision, "previous-rev", "p", "", "the revision after which commits are inspected. Defaults to the latest tag")
+	cmd.Flags().StringVarP(&o.CurrentRevision, "rev", "", "HEAD", "the last revision to inspect")
+	cmd.Flags().StringVarP(&o.CurrentVersion, "current-version", "", "", "the version to increment. Defaults to the latest tag or "+InitialVersion+" if there are no tags")
+	cmd.Flags().StringVarP(&o.ConfigFile, "config-file", "", "", "The YAML file configuring the commit convention and bumps. Defaults to '"+config.FileName+"' in the root of the repository")
+	cmd.Flags().StringVarP(&o.Convention, "convention", "", "", fmt.Sprintf("The convention of the commit messages. Supported values: %s. Defaults to the convention of the configuration file", strings.Join(gits.Conventions, ", ")))
+	cmd.Flags().StringArrayVarP(&o.Bumps, "bump", "", nil, fmt.Sprintf("The version increment of a commit type such as 'docs=patch' overriding the configuration file. Supported increments: %s", strings.Join(gits.Bumps, ", ")))
+	cmd.Flags().StringVarP(&o.WriteFile, "write-file", "", "", "The file to write the next version to for the following steps of the pipeline")
+	return cmd, o
+}
+
+// Run implements the command
+func (o *NextOptions) Run() error {
+	gitDir, _, err := gitclient.FindGitConfigDir(o.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the git directory of %s", o.Dir)
+	}
+	if gitDir == "" {
+		return errors.Errorf("no git directory could be found from dir %s", o.Dir)
+	}
+	configFile := o.ConfigFile
+	if configFile == "" {
+		configFile = filepath.Join(gitDir, config.FileName)
+	}
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the changelog configuration")
+	}
+	bumpTypes, err := o.bumpTypes(cfg)
+	if err != nil {
+		return err
+	}
+	convention := stringhelpers.FirstNotEmptyString(o.Convention, cfg.CommitConvention())
+	if stringhelpers.StringArrayIndex(gits.Conventions, convention) < 0 {
+		return errors.Errorf("unsupported convention %s. Supported values: %s", convention, strings.Join(gits.Conventions, ", "))
+	}
+
+	previousRev := o.PreviousRevision
+	currentVersion := o.CurrentVersion
+	if previousRev == "" {
+		tags, err := gits.ListTags(o.Git(), o.Dir, "")
+		if err != nil {
+			return errors.Wrapf(err, "failed to list the tags in %s", o.Dir)
+		}
+		if len(tags) > 0 {
+			var tag string
+			previousRev, tag, err = gits.GetCommitPointedToByLatestTag(o.Git(), o.Dir)
+			if err != nil {
+				return err
+			}
+			if currentVersion == "" {
+				currentVersion = tag
+			}
+		}
+		if previousRev == "" {
+			previousRev, err = gits.GetFirstCommitSha(o.Git(), o.Dir)
+			if err != nil {
+				return errors.Wrap(err, "failed to find the first commit")
+			}
+		}
+	}
+	if currentVersion == "" {
+		currentVersion = InitialVersion
+	}
+	commits, err := chgit.FetchCommits(gitDir, previousRev, o.CurrentRevision)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch the commits between %s and %s", previousRev, o.CurrentRevision)
+	}
+	var summaries []v1.CommitSummary
+	if commits != nil {
+		for i := range *commits {
+			commit := &(*commits)[i]
+			if len(commit.ParentHashes) > 1 || cfg.IsExcluded(commit.Message) {
+				continue
+			}
+			summaries = append(summaries, v1.CommitSummary{SHA: commit.Hash.String(), Message: commit.Message})
+		}
+	}
+
+	o.Bump = gits.ReleaseBump(summaries, convention, bumpTypes)
+	o.NextVersion, err = gits.NextVersion(currentVersion, o.Bump)
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("%d commits since %s require a %s increment to %s", len(summaries), termcolor.ColorInfo(currentVersion), o.Bump, termcolor.ColorInfo(o.NextVersion))
+	if o.WriteFile != "" {
+		err = ioutil.WriteFile(o.WriteFile, []byte(o.NextVersion+"\n"), files.DefaultFileWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save file %s", o.WriteFile)
+		}
+		return nil
+	}
+	fmt.Println(o.NextVersion)
+	return nil
+}
+
+// bumpTypes returns the version increments of the commit types of the configuration file overridden by the --bump
+func (o *NextOptions) bumpTypes(cfg *config.Config) (map[string]string, error) {
+	answer := cfg.BumpTypes()
+	for _, b := range o.Bumps {
+		paths := strings.SplitN(b, "=", 2)
+		if len(paths) != 2 || paths[0] == "" {
+			return nil, errors.Errorf("invalid --bump %s. Use the format type=increment such as 'docs=patch'", b)
+		}
+		answer[strings.ToLower(paths[0])] = paths[1]
+	}
+	for kind, bump := range answer {
+		if stringhelpers.StringArrayIndex(gits.Bumps, bump) < 0 {
+			return nil, errors.Errorf("unsupported increment %s of commit type %s. Supported values: %s", bump, kind, strings.Join(gits.Bumps, ", "))
+		}
+	}
+	return answer, nil
+}
+
+// Git returns the git client
+func (o *NextOptions) Git() gitclient.Interface {
+	if o.GitClient == nil {
+		o.GitClient = cli.NewCLIClient("", o.CommandRunner)
+	}
+	return o.GitClient
+}
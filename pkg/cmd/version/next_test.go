@@ -0,0 +1,65 @@
+// +build unit
+
+package version_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/cmd/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestVersionNext(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	for i, message := range []string{"initial commit", "feat: cheese", "docs: the wine", "fix: the cork"} {
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now().Add(time.Duration(i) * time.Minute)}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		if i == 1 {
+			_, err = repo.CreateTag("v1.2.3", hash, nil)
+			require.NoError(t, err, "failed to tag %s", message)
+		}
+	}
+	// lets save the git config so that the repository can be discovered
+	cfg, err := repo.Config()
+	require.NoError(t, err, "failed to get git config")
+	err = repo.Storer.SetConfig(cfg)
+	require.NoError(t, err, "failed to save git config")
+
+	versionFile := filepath.Join(dir, "VERSION")
+	_, o := version.NewCmdVersionNext()
+	o.Dir = dir
+	o.WriteFile = versionFile
+	err = o.Run()
+	require.NoError(t, err, "failed to recommend the next version")
+	assert.Equal(t, "patch", o.Bump)
+	assert.Equal(t, "v1.2.4", o.NextVersion)
+	data, err := ioutil.ReadFile(versionFile)
+	require.NoError(t, err, "failed to read %s", versionFile)
+	assert.Equal(t, "v1.2.4\n", string(data))
+
+	_, o = version.NewCmdVersionNext()
+	o.Dir = dir
+	o.WriteFile = versionFile
+	o.Bumps = []string{"docs=minor"}
+	err = o.Run()
+	require.NoError(t, err, "failed to recommend the next version with bumps")
+	assert.Equal(t, "v1.3.0", o.NextVersion)
+
+	o.Bumps = []string{"docs=huge"}
+	assert.Error(t, o.Run(), "should fail with an unsupported increment")
+}
@@ -1,6 +1,7 @@
 package version
 
 import (
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
 	"github.com/jenkins-x/jx-logging/v3/pkg/log"
@@ -42,6 +43,7 @@ func NewCmdVersion() (*cobra.Command, *Options) {
 			helper.CheckErr(err)
 		},
 	}
+	cmd.AddCommand(cobras.SplitCommand(NewCmdVersionNext()))
 	return cmd, o
 }
 
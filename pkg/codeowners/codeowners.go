@@ -0,0 +1,164 @@
+package codeowners
+
+import (
+	"bufio"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+)
+
+// FileNames the locations of the CODEOWNERS file relative to the root of the repository in the order they are searched
+var FileNames = []string{
+	"CODEOWNERS",
+	filepath.Join(".github", "CODEOWNERS"),
+	filepath.Join(".gitlab", "CODEOWNERS"),
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// Rule a pattern of paths and the owners of the matching paths
+type Rule struct {
+	Pattern string
+	Owners  []string
+	regex   *regexp.Regexp
+}
+
+// CodeOwners the rules of a CODEOWNERS file
+type CodeOwners struct {
+	Rules []*Rule
+}
+
+// Load loads the CODEOWNERS file in the given repository directory.
+// Returns nil if there is no CODEOWNERS file
+func Load(dir string) (*CodeOwners, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if exists {
+			return LoadFile(path)
+		}
+	}
+	return nil, nil
+}
+
+// LoadFile loads the given CODEOWNERS file
+func LoadFile(path string) (*CodeOwners, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load file %s", path)
+	}
+	answer, err := Parse(string(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse file %s", path)
+	}
+	return answer, nil
+}
+
+// Parse parses the text of a CODEOWNERS file
+func Parse(text string) (*CodeOwners, error) {
+	answer := &CodeOwners{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		idx := strings.Index(line, "#")
+		if idx >= 0 {
+			line = strings.TrimSpace(line[0:idx])
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		regex, err := PatternRegex(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %s", fields[0])
+		}
+		answer.Rules = append(answer.Rules, &Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			regex:   regex,
+		})
+	}
+	return answer, nil
+}
+
+// Owners returns the owners of the given path relative to the root of the repository.
+// As with git providers the last matching rule wins
+func (c *CodeOwners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		rule := c.Rules[i]
+		if rule.regex.MatchString(path) {
+			return rule.Owners
+		}
+	}
+	return nil
+}
+
+// PathsOwners returns the unique owners of the given paths in the order they are found
+func (c *CodeOwners) PathsOwners(paths []string) []string {
+	var answer []string
+	found := map[string]bool{}
+	for _, path := range paths {
+		for _, owner := range c.Owners(path) {
+			if !found[owner] {
+				found[owner] = true
+				answer = append(answer, owner)
+			}
+		}
+	}
+	return answer
+}
+
+// PatternRegex converts the gitignore style pattern to a regular expression matching paths
+func PatternRegex(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+
+	var buffer strings.Builder
+	if anchored {
+		buffer.WriteString("^")
+	} else {
+		buffer.WriteString("(^|/)")
+	}
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch ch {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					buffer.WriteString("(.*/)?")
+				} else {
+					buffer.WriteString(".*")
+				}
+			} else {
+				buffer.WriteString("[^/]*")
+			}
+		case '?':
+			buffer.WriteString("[^/]")
+		default:
+			buffer.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	if directory {
+		buffer.WriteString("/")
+	} else {
+		buffer.WriteString("(/|$)")
+	}
+	return regexp.Compile(buffer.String())
+}
@@ -0,0 +1,46 @@
+// +build unit
+
+package codeowners_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/codeowners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwners(t *testing.T) {
+	t.Parallel()
+	c, err := codeowners.Parse(`
+# the default owners
+*                @acme/core
+
+*.md             @acme/docs # docs
+/pkg/users/      @acme/identity
+pkg/**/rest.go   @acme/api
+build/           @acme/infra
+/cmd/app/main.go @jstrachan @rawlingsj
+`)
+	require.NoError(t, err, "failed to parse CODEOWNERS")
+	require.Len(t, c.Rules, 6)
+
+	testCases := map[string][]string{
+		"go.mod":                   {"@acme/core"},
+		"README.md":                {"@acme/docs"},
+		"docs/guide/README.md":     {"@acme/docs"},
+		"pkg/users/resolver.go":    {"@acme/identity"},
+		"other/pkg/users/x.go":     {"@acme/core"},
+		"pkg/scmapi/rest.go":       {"@acme/api"},
+		"pkg/rest.go":              {"@acme/api"},
+		"deploy/build/Dockerfile":  {"@acme/infra"},
+		"cmd/app/main.go":          {"@jstrachan", "@rawlingsj"},
+		"cmd/app/main-win.go":      {"@acme/core"},
+		"pkg/users/docs/README.md": {"@acme/identity"},
+	}
+	for path, expected := range testCases {
+		assert.Equal(t, expected, c.Owners(path), "owners of %s", path)
+	}
+
+	assert.Equal(t, []string{"@acme/identity", "@acme/docs", "@acme/core"}, c.PathsOwners([]string{"pkg/users/a.go", "README.md", "go.mod", "pkg/users/b.go"}))
+}
@@ -0,0 +1,442 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/pkg/errors"
+)
+
+// FileName the default name of the changelog configuration file relative to the root of the repository
+var FileName = filepath.Join(".jx", "changelog.yaml")
+
+// Config the configuration of how commits are rendered in the changelog
+type Config struct {
+	// Convention the convention used by the commit messages. Defaults to Conventional Commits
+	Convention string `json:"convention,omitempty"`
+	// Sections the changelog sections and the commit types rendered in each of them.
+	// If no sections specify types the default types of the convention are used.
+	// The types of gitmoji commits are the gitmoji codes without the colons
+	Sections []Section `json:"sections,omitempty"`
+	// Other the section for commits whose type is not mapped to any section. Such commits are dropped if not specified
+	Other *Section `json:"other,omitempty"`
+	// GroupByScope renders the commits of each section under a subheading for each scope
+	GroupByScope bool `json:"groupByScope,omitempty"`
+	// ScopeOrder the scopes to render first in the given order when grouping by scope. Other scopes are sorted by name
+	ScopeOrder []string `json:"scopeOrder,omitempty"`
+	// DefaultEmoji renders the default emoji such as '🚀' or '🐛' before the titles of the default sections
+	DefaultEmoji bool `json:"defaultEmoji,omitempty"`
+	// DisableEmoji renders no emoji before the section titles even if they are configured
+	DisableEmoji bool `json:"disableEmoji,omitempty"`
+	// UnscopedTitle the subheading of the commits without a scope when grouping by scope. Defaults to 'Other'
+	UnscopedTitle string `json:"unscopedTitle,omitempty"`
+	// Exclude the regular expressions of the commit messages to exclude from the changelog such as '^chore\(release\)'
+	Exclude []string `json:"exclude,omitempty"`
+	// ExcludeAuthors the regular expressions of the names, emails or logins of the commit authors or committers whose
+	// commits are excluded from the changelog such as release bots, CI users or mirror sync accounts
+	ExcludeAuthors []string `json:"excludeAuthors,omitempty"`
+	// Pattern the regular expression the commit subjects must match when verifying commits instead of the convention
+	Pattern string `json:"pattern,omitempty"`
+	// SkipLabels the pull request labels which exclude the commits of the pull request from the changelog
+	SkipLabels []string `json:"skipLabels,omitempty"`
+	// Labels maps the pull request labels such as 'kind/bug' to the commit types such as 'fix' which override the
+	// types of the commit messages of the pull request. The 'breaking' type marks the commits as breaking changes
+	Labels map[string]string `json:"labels,omitempty"`
+	// Bumps maps the commit types such as 'feat' to the version increments such as 'minor' which override the
+	// gits.DefaultBumpTypes when recommending the next version. Breaking changes always increment the major version
+	Bumps map[string]string `json:"bumps,omitempty"`
+	// IssueTrackers the issue trackers whose keys such as 'PROJ-123' are linked in the changelog
+	IssueTrackers []IssueTracker `json:"issueTrackers,omitempty"`
+	// Keywords the rules classifying the commits which do not follow the convention by the keywords in their subject.
+	// The types are the commit types of the convention
+	Keywords []gits.KeywordRule `json:"keywords,omitempty"`
+	// Title the text/template of the release titles such as 'v{{ .Version }} ({{ .Date }})' which can use the
+	// ReleaseTitleData. Defaults to the version
+	Title string `json:"title,omitempty"`
+	// DateLayout the Go time layout of the date of the release title such as 'January 2, 2006'. Defaults to '2006-01-02'
+	DateLayout string `json:"dateLayout,omitempty"`
+	// Timezone the IANA time zone of the date of the release title such as 'Europe/Berlin'. Defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	// Footer the text/template appended to the notes of every release such as the helm upgrade command of the new
+	// version. It is executed with the same data as the --footer option including the .Changelog
+	Footer string `json:"footer,omitempty"`
+	// FooterFile the file of the footer template relative to the root of the repository if there is no Footer
+	FooterFile string `json:"footerFile,omitempty"`
+	// FrontMatter the YAML front matter prepended to the markdown changelog file such as the title, date and tags of a
+	// Hugo or Jekyll page. The string values are text/templates executed with the FrontMatterData
+	FrontMatter map[string]interface{} `json:"frontMatter,omitempty"`
+
+	excludes       []*regexp.Regexp
+	excludeAuthors []*regexp.Regexp
+	title          *template.Template
+	location       *time.Location
+}
+
+// DefaultDateLayout the default layout of the date of the release title
+const DefaultDateLayout = "2006-01-02"
+
+// ReleaseTitleData the data the release title template is executed with
+type ReleaseTitleData struct {
+	// Version the version of the release
+	Version string
+	// Tag the git tag of the release if known
+	Tag string
+	// Date the date of the release formatted using the date layout in the time zone
+	Date string
+	// Time the time of the release in the time zone for custom formatting such as {{ .Time.Year }}
+	Time time.Time
+}
+
+// IssueTracker an issue tracker such as Jira whose issue keys are referenced in commit messages
+type IssueTracker struct {
+	// Name the optional name of the issue tracker
+	Name string `json:"name,omitempty"`
+	// Pattern the regular expression of the issue keys such as 'PROJ-\d+'
+	Pattern string `json:"pattern"`
+	// URL the base URL of the issues which the key is appended to such as 'https://example.atlassian.net/browse'
+	URL string `json:"url"`
+
+	regex *regexp.Regexp
+}
+
+// IssueKey an issue key found in a commit message
+type IssueKey struct {
+	// Key the issue key such as 'PROJ-123'
+	Key string
+	// URL the link to the issue in the issue tracker
+	URL string
+}
+
+// Section a section of the changelog
+type Section struct {
+	// Title the title of the section
+	Title string `json:"title,omitempty"`
+	// Order the position of the section in the changelog. Defaults to the position in the list of sections
+	Order int `json:"order,omitempty"`
+	// Emoji the optional emoji rendered before the title
+	Emoji string `json:"emoji,omitempty"`
+	// Hidden excludes the commits of the section from the changelog
+	Hidden bool `json:"hidden,omitempty"`
+	// Collapsed renders the commits of the section inside a collapsible '<details>' block on GitHub and GitLab
+	Collapsed bool `json:"collapsed,omitempty"`
+	// Types the commit types rendered in this section
+	Types []string `json:"types,omitempty"`
+	// Subject the regular expression of the commit subjects rendered in this section regardless of their type
+	// such as '(?i)security'. The subject and label rules of the sections are evaluated in order before the types
+	Subject string `json:"subject,omitempty"`
+	// Labels the labels of the pull requests or issues whose commits are rendered in this section regardless of their type
+	Labels []string `json:"labels,omitempty"`
+
+	subject *regexp.Regexp
+}
+
+// LoadConfig loads the configuration from the given file if it exists
+func LoadConfig(path string) (*Config, error) {
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load file %s", path)
+	}
+	answer := &Config{}
+	err = yaml.Unmarshal(data, answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal YAML file %s", path)
+	}
+	if answer.Convention != "" && stringhelpers.StringArrayIndex(gits.Conventions, answer.Convention) < 0 {
+		return nil, errors.Errorf("unsupported convention %s in file %s. Supported values: %s", answer.Convention, path, strings.Join(gits.Conventions, ", "))
+	}
+	if answer.Pattern != "" {
+		_, err = regexp.Compile(answer.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %s in file %s", answer.Pattern, path)
+		}
+	}
+	for i := range answer.Sections {
+		s := &answer.Sections[i]
+		if s.Subject != "" {
+			s.subject, err = regexp.Compile(s.Subject)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid subject %s of section %s in file %s", s.Subject, s.Title, path)
+			}
+		}
+	}
+	for _, pattern := range answer.Exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exclude pattern %s in file %s", pattern, path)
+		}
+		answer.excludes = append(answer.excludes, re)
+	}
+	for _, pattern := range answer.ExcludeAuthors {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exclude author pattern %s in file %s", pattern, path)
+		}
+		answer.excludeAuthors = append(answer.excludeAuthors, re)
+	}
+	if answer.Title != "" {
+		answer.title, err = template.New("title").Parse(answer.Title)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid title %s in file %s", answer.Title, path)
+		}
+	}
+	if answer.Timezone != "" {
+		answer.location, err = time.LoadLocation(answer.Timezone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timezone %s in file %s", answer.Timezone, path)
+		}
+	}
+	for i := range answer.IssueTrackers {
+		tracker := &answer.IssueTrackers[i]
+		if tracker.Pattern == "" || tracker.URL == "" {
+			return nil, errors.Errorf("issue tracker %d in file %s must have a pattern and a url", i+1, path)
+		}
+		tracker.regex, err = regexp.Compile(tracker.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid issue tracker pattern %s in file %s", tracker.Pattern, path)
+		}
+	}
+	return answer, nil
+}
+
+// FindIssueKeys returns the keys of the configured issue trackers found in the commit message in the order they are found
+func (c *Config) FindIssueKeys(message string) []IssueKey {
+	if c == nil {
+		return nil
+	}
+	var answer []IssueKey
+	found := map[string]bool{}
+	for i := range c.IssueTrackers {
+		tracker := &c.IssueTrackers[i]
+		if tracker.regex == nil {
+			continue
+		}
+		for _, key := range tracker.regex.FindAllString(message, -1) {
+			if found[key] {
+				continue
+			}
+			found[key] = true
+			answer = append(answer, IssueKey{
+				Key: key,
+				URL: stringhelpers.UrlJoin(tracker.URL, key),
+			})
+		}
+	}
+	return answer
+}
+
+// ReleaseTitle returns the title of the release using the title template or the version if there is no template
+func (c *Config) ReleaseTitle(version, tag string, date time.Time) (string, error) {
+	if c == nil || c.title == nil {
+		return version, nil
+	}
+	data := &ReleaseTitleData{
+		Version: version,
+		Tag:     tag,
+		Date:    c.FormatDate(date),
+		Time:    date.In(c.timezone()),
+	}
+	var buffer bytes.Buffer
+	err := c.title.Execute(&buffer, data)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render the release title %s", c.Title)
+	}
+	return strings.TrimSpace(buffer.String()), nil
+}
+
+// FormatDate formats the date of a release using the configured layout and timezone
+func (c *Config) FormatDate(date time.Time) string {
+	layout := DefaultDateLayout
+	if c != nil && c.DateLayout != "" {
+		layout = c.DateLayout
+	}
+	return date.In(c.timezone()).Format(layout)
+}
+
+// timezone returns the configured timezone of the release dates defaulting to UTC
+func (c *Config) timezone() *time.Location {
+	if c == nil || c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// PullRequestSkipLabels returns the pull request labels which exclude the commits of the pull request from the changelog
+func (c *Config) PullRequestSkipLabels() []string {
+	if c == nil || len(c.SkipLabels) == 0 {
+		return gits.DefaultSkipLabels
+	}
+	return c.SkipLabels
+}
+
+// LabelTypes returns the mapping of pull request labels to commit types
+func (c *Config) LabelTypes() map[string]string {
+	if c == nil {
+		return nil
+	}
+	return c.Labels
+}
+
+// BumpTypes returns the version increments of the commit types merging the configured bumps into the
+// gits.DefaultBumpTypes
+func (c *Config) BumpTypes() map[string]string {
+	answer := map[string]string{}
+	for k, v := range gits.DefaultBumpTypes {
+		answer[k] = v
+	}
+	if c != nil {
+		for k, v := range c.Bumps {
+			answer[strings.ToLower(k)] = v
+		}
+	}
+	return answer
+}
+
+// IsExcluded returns true if the commit message matches any of the exclude patterns
+func (c *Config) IsExcluded(message string) bool {
+	if c == nil {
+		return false
+	}
+	message = strings.TrimSpace(message)
+	for _, re := range c.excludes {
+		if re.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// KeywordClassifier returns the classifier of the commits which do not follow the convention using the configured
+// keyword rules or, if enabled, the default rules. Returns nil if commits should not be classified by keywords
+func (c *Config) KeywordClassifier(enabled bool) *gits.KeywordClassifier {
+	if c != nil && len(c.Keywords) > 0 {
+		return gits.NewKeywordClassifier(c.Keywords)
+	}
+	if enabled {
+		return gits.NewKeywordClassifier(gits.DefaultKeywordRules)
+	}
+	return nil
+}
+
+// HasExcludedAuthors returns true if commits are excluded by their author or committer
+func (c *Config) HasExcludedAuthors() bool {
+	return c != nil && len(c.excludeAuthors) > 0
+}
+
+// IsExcludedAuthor returns true if any of the names, emails or logins of a commit author or committer matches
+// any of the author exclude patterns
+func (c *Config) IsExcludedAuthor(identities ...string) bool {
+	if c == nil {
+		return false
+	}
+	for _, identity := range identities {
+		if identity == "" {
+			continue
+		}
+		for _, re := range c.excludeAuthors {
+			if re.MatchString(identity) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CommitConvention returns the convention used by the commit messages
+func (c *Config) CommitConvention() string {
+	if c == nil || c.Convention == "" {
+		return gits.ConventionConventional
+	}
+	return c.Convention
+}
+
+// CommitGroups returns the mapping of commit types to changelog sections or nil if the default mapping should be used
+func (c *Config) CommitGroups() *gits.CommitGroups {
+	if c == nil || (len(c.Sections) == 0 && c.Other == nil) {
+		return nil
+	}
+	answer := &gits.CommitGroups{
+		Types: map[string]*gits.CommitGroup{},
+	}
+	hasTypes := false
+	for i := range c.Sections {
+		if len(c.Sections[i].Types) > 0 {
+			hasTypes = true
+		}
+	}
+	if !hasTypes {
+		defaults := gits.ConventionalCommitTitles
+		if c.CommitConvention() == gits.ConventionGitmoji {
+			defaults = gits.GitmojiCommitTitles
+		}
+		for k, v := range defaults {
+			answer.Types[k] = v
+		}
+	}
+	// lets render the sections which only have rules after the default sections unless they have an explicit order
+	base := maxOrder(answer)
+	for i := range c.Sections {
+		s := &c.Sections[i]
+		group := s.commitGroup(base + i + 1)
+		for _, t := range s.Types {
+			answer.Types[strings.ToLower(t)] = group
+		}
+		if s.subject != nil || len(s.Labels) > 0 {
+			answer.Rules = append(answer.Rules, &gits.CommitRule{
+				Subject: s.subject,
+				Labels:  s.Labels,
+				Group:   group,
+			})
+		}
+	}
+	if c.Other != nil {
+		// lets render the other section last unless it has an explicit order
+		answer.Other = c.Other.commitGroup(maxOrder(answer) + 1)
+	}
+	return answer
+}
+
+// maxOrder returns the highest order of the groups of the types and rules
+func maxOrder(groups *gits.CommitGroups) int {
+	answer := 0
+	for _, g := range groups.Types {
+		if g.Order > answer {
+			answer = g.Order
+		}
+	}
+	for _, r := range groups.Rules {
+		if r.Group != nil && r.Group.Order > answer {
+			answer = r.Group.Order
+		}
+	}
+	return answer
+}
+
+func (s *Section) commitGroup(defaultOrder int) *gits.CommitGroup {
+	order := s.Order
+	if order == 0 {
+		order = defaultOrder
+	}
+	return &gits.CommitGroup{
+		Title:     s.Title,
+		Order:     order,
+		Emoji:     s.Emoji,
+		Hidden:    s.Hidden,
+		Collapsed: s.Collapsed,
+	}
+}
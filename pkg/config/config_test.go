@@ -0,0 +1,472 @@
+// +build unit
+
+package config_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/config"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitGroups(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`sections:
+- title: Bug Fixes
+  emoji: ":bug:"
+  order: 2
+  types:
+  - fix
+- title: Features
+  emoji: ":sparkles:"
+  order: 1
+  types:
+  - feat
+  - Feature
+- title: Chores
+  hidden: true
+  types:
+  - chore
+other:
+  title: Other
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{Message: "fix: the bug", SHA: "1"},
+			{Message: "feature: the thing", SHA: "2"},
+			{Message: "chore: the chore", SHA: "3"},
+			{Message: "docs: the docs", SHA: "4"},
+			{Message: "the unconventional", SHA: "5"},
+			{Message: "feat: the feature", SHA: "6"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{Groups: cfg.CommitGroups()})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### :sparkles: Features
+
+* the thing
+* the feature
+
+### :bug: Bug Fixes
+
+* the bug
+
+### Other
+
+* the docs
+* the unconventional
+`
+	assert.Equal(t, expected, markdown)
+}
+
+func TestCommitGroupsDropUnknownTypes(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Sections: []config.Section{
+			{Title: "Features", Types: []string{"feat"}},
+		},
+	}
+	groups := cfg.CommitGroups()
+	assert.Equal(t, "Features", groups.Group("FEAT").Title)
+	assert.Nil(t, groups.Group("docs"))
+	assert.Nil(t, groups.Group(""))
+}
+
+func TestCommitGroupsDefaults(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join("does", "not", "exist.yaml")
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	assert.Nil(t, cfg.CommitGroups())
+
+	cfg = &config.Config{Other: &config.Section{Title: "Other"}}
+	groups := cfg.CommitGroups()
+	assert.Equal(t, "Bug Fixes", groups.Group("fix").Title)
+	assert.Equal(t, "Other", groups.Group("unknown").Title)
+	assert.True(t, groups.Group("unknown").Order > groups.Group("chore").Order)
+}
+
+func TestGitmojiConvention(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`convention: gitmoji
+sections:
+- title: Features
+  types:
+  - sparkles
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	assert.Equal(t, gits.ConventionGitmoji, cfg.CommitConvention())
+	assert.Equal(t, "Features", cfg.CommitGroups().Group("sparkles").Title)
+
+	cfg = &config.Config{Convention: gits.ConventionGitmoji, Other: &config.Section{Title: "Other"}}
+	assert.Equal(t, "Bug Fixes", cfg.CommitGroups().Group("bug").Title)
+
+	err = ioutil.WriteFile(path, []byte(`convention: emojis`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	_, err = config.LoadConfig(path)
+	assert.Error(t, err)
+}
+
+func TestExclude(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`exclude:
+- ^chore\(release\)
+- ^Merge branch
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	assert.True(t, cfg.IsExcluded("chore(release): 1.2.3"))
+	assert.True(t, cfg.IsExcluded("Merge branch 'main' into cheese\n\nsome details"))
+	assert.False(t, cfg.IsExcluded("chore(deps): upgrade wine"))
+	assert.False(t, cfg.IsExcluded("fix: the cork\n\nMerge branch first"))
+
+	var noConfig *config.Config
+	assert.False(t, noConfig.IsExcluded("chore(release): 1.2.3"))
+
+	err = ioutil.WriteFile(path, []byte(`exclude:
+- ^chore(release
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	_, err = config.LoadConfig(path)
+	assert.Error(t, err)
+}
+
+func TestFindIssueKeys(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`issueTrackers:
+- name: jira
+  pattern: '\b(PROJ|OPS)-\d+'
+  url: https://example.atlassian.net/browse/
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	keys := cfg.FindIssueKeys("fix: PROJ-12 the cork\n\nalso fixes OPS-3 and PROJ-12")
+	assert.Equal(t, []config.IssueKey{
+		{Key: "PROJ-12", URL: "https://example.atlassian.net/browse/PROJ-12"},
+		{Key: "OPS-3", URL: "https://example.atlassian.net/browse/OPS-3"},
+	}, keys)
+	assert.Empty(t, cfg.FindIssueKeys("fix: XPROJ-12 the cork"))
+
+	var noConfig *config.Config
+	assert.Empty(t, noConfig.FindIssueKeys("fix: PROJ-12 the cork"))
+
+	err = ioutil.WriteFile(path, []byte(`issueTrackers:
+- pattern: PROJ-\d+
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	_, err = config.LoadConfig(path)
+	assert.Error(t, err, "the url is required")
+}
+
+func TestExcludeAuthors(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`excludeAuthors:
+- ^jenkins-x-bot$
+- '@ci\.example\.com$'
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	assert.True(t, cfg.HasExcludedAuthors())
+	assert.True(t, cfg.IsExcludedAuthor("Jenkins X", "jx@example.com", "jenkins-x-bot"))
+	assert.True(t, cfg.IsExcludedAuthor("Sync", "mirror@ci.example.com"))
+	assert.False(t, cfg.IsExcludedAuthor("James", "james@example.com", "jstrachan"))
+	assert.False(t, cfg.IsExcludedAuthor("", ""))
+
+	var noConfig *config.Config
+	assert.False(t, noConfig.HasExcludedAuthors())
+	assert.False(t, noConfig.IsExcludedAuthor("jenkins-x-bot"))
+}
+
+func TestSectionRules(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`sections:
+- title: Security
+  order: -1
+  subject: (?i)\bsecurity\b|CVE-
+  labels:
+  - security
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{Message: "fix: the bug", SHA: "1"},
+			{Message: "fix: the Security hole", SHA: "2"},
+			{Message: "chore: upgrade wine for CVE-2021-1234", SHA: "3"},
+			{Message: "feat: locks", SHA: "4", IssueIDs: []string{"12"}},
+			{Message: "feat: the feature", SHA: "5"},
+		},
+		PullRequests: []v1.IssueSummary{
+			{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12", Labels: []v1.IssueLabel{{Name: "Security"}}},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{Groups: cfg.CommitGroups()})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### Security
+
+* the Security hole
+* upgrade wine for CVE-2021-1234
+* locks
+
+### New Features
+
+* the feature
+
+### Bug Fixes
+
+* the bug
+
+### Pull Requests
+
+* [#12](https://github.com/jstrachan/foo/pull/12) 
+`
+	assert.Equal(t, expected, markdown)
+
+	err = ioutil.WriteFile(path, []byte(`sections:
+- title: Security
+  subject: (?i)security(
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	_, err = config.LoadConfig(path)
+	assert.Error(t, err)
+}
+
+func TestRuleSectionsOrderedAfterDefaults(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`sections:
+- title: Highlights
+  subject: (?i)\bhighlight\b
+other:
+  title: Other Changes
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+
+	groups := cfg.CommitGroups()
+	require.Len(t, groups.Rules, 1)
+	rule := groups.Rules[0].Group
+	for k, g := range groups.Types {
+		assert.True(t, g.Order < rule.Order, "the rule section should be ordered after the default section %s", k)
+	}
+	require.NotNil(t, groups.Other)
+	assert.True(t, rule.Order < groups.Other.Order, "the other section should be ordered after the rule section")
+}
+
+func TestLabelTypes(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`labels:
+  kind/bug: fix
+  kind/feature: feat
+  breaking-change: breaking
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{Message: "chore: stop the cheese melting", SHA: "1", IssueIDs: []string{"12"}},
+			{Message: "add the wine API", SHA: "2", IssueIDs: []string{"13"}},
+			{Message: "fix: the bug", SHA: "3"},
+		},
+		PullRequests: []v1.IssueSummary{
+			{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12", Labels: []v1.IssueLabel{{Name: "Kind/Bug"}}},
+			{ID: "13", URL: "https://github.com/jstrachan/foo/pull/13", Labels: []v1.IssueLabel{{Name: "kind/feature"}, {Name: "breaking-change"}}},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{LabelTypes: cfg.LabelTypes()})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### ⚠ Breaking Changes
+
+* add the wine API
+
+### New Features
+
+* add the wine API
+
+### Bug Fixes
+
+* stop the cheese melting
+* the bug
+
+### Pull Requests
+
+* [#12](https://github.com/jstrachan/foo/pull/12) 
+* [#13](https://github.com/jstrachan/foo/pull/13) 
+`
+	assert.Equal(t, expected, markdown)
+}
+
+func TestReleaseTitle(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	date := time.Date(2021, time.March, 31, 23, 30, 0, 0, time.UTC)
+
+	var noConfig *config.Config
+	title, err := noConfig.ReleaseTitle("1.2.3", "v1.2.3", date)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", title)
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`title: "v{{ .Version }} ({{ .Date }})"
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	title, err = cfg.ReleaseTitle("1.2.3", "v1.2.3", date)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3 (2021-03-31)", title)
+
+	err = ioutil.WriteFile(path, []byte(`title: "{{ .Tag }} - {{ .Date }}"
+dateLayout: January 2, 2006
+timezone: Europe/Berlin
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	cfg, err = config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	title, err = cfg.ReleaseTitle("1.2.3", "v1.2.3", date)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3 - April 1, 2021", title)
+
+	err = ioutil.WriteFile(path, []byte(`timezone: Nowhere/Special
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	_, err = config.LoadConfig(path)
+	assert.Error(t, err)
+}
+
+func TestFrontMatter(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	date := time.Date(2021, time.March, 31, 23, 30, 0, 0, time.UTC)
+
+	var noConfig *config.Config
+	assert.False(t, noConfig.HasFrontMatter())
+	text, err := noConfig.RenderFrontMatter("Cheese 1.2.3", "1.2.3", "v1.2.3", date, []string{"release"})
+	require.NoError(t, err)
+	assert.Equal(t, `---
+date: "2021-03-31"
+tags:
+- release
+title: Cheese 1.2.3
+version: 1.2.3
+---
+
+`, text)
+
+	path := filepath.Join(tmpDir, "changelog.yaml")
+	err = ioutil.WriteFile(path, []byte(`frontMatter:
+  title: "Release {{ .Version }}"
+  date: "{{ .Time.Format \"2006-01-02T15:04:05Z07:00\" }}"
+  weight: 10
+  tags: [changelog, "{{ .Tag }}"]
+  params:
+    tag: "{{ .Tag }}"
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err, "failed to load %s", path)
+	assert.True(t, cfg.HasFrontMatter())
+	text, err = cfg.RenderFrontMatter("Cheese 1.2.3", "1.2.3", "v1.2.3", date, []string{"release", "changelog"})
+	require.NoError(t, err)
+	assert.Equal(t, `---
+date: "2021-03-31T23:30:00Z"
+params:
+  tag: v1.2.3
+tags:
+- changelog
+- v1.2.3
+- release
+title: Release 1.2.3
+weight: 10
+---
+
+`, text)
+}
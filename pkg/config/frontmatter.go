@@ -0,0 +1,118 @@
+package config
+
+import (
+	"bytes"
+	"text/template"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// FrontMatterData the data the templates of the front matter are executed with
+type FrontMatterData struct {
+	ReleaseTitleData
+	// Title the title of the release
+	Title string
+}
+
+// HasFrontMatter returns true if the front matter is configured
+func (c *Config) HasFrontMatter() bool {
+	return c != nil && len(c.FrontMatter) > 0
+}
+
+// RenderFrontMatter returns the YAML front matter block of the changelog file using the configured front matter or
+// the title, date and version of the release by default. The tags are added to the tags of the front matter
+func (c *Config) RenderFrontMatter(title, version, tag string, date time.Time, tags []string) (string, error) {
+	data := &FrontMatterData{
+		ReleaseTitleData: ReleaseTitleData{
+			Version: version,
+			Tag:     tag,
+			Date:    c.FormatDate(date),
+			Time:    date.In(c.timezone()),
+		},
+		Title: title,
+	}
+	fields := map[string]interface{}{
+		"title":   data.Title,
+		"date":    data.Date,
+		"version": data.Version,
+	}
+	if c.HasFrontMatter() {
+		fields = map[string]interface{}{}
+		for k, v := range c.FrontMatter {
+			value, err := renderFrontMatterValue(k, v, data)
+			if err != nil {
+				return "", err
+			}
+			fields[k] = value
+		}
+	}
+	if len(tags) > 0 {
+		var values []interface{}
+		if existing, ok := fields["tags"].([]interface{}); ok {
+			values = existing
+		}
+		for _, t := range tags {
+			found := false
+			for _, v := range values {
+				if v == t {
+					found = true
+					break
+				}
+			}
+			if !found {
+				values = append(values, t)
+			}
+		}
+		fields["tags"] = values
+	}
+	text, err := yaml.Marshal(fields)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal the front matter")
+	}
+	return "---\n" + string(text) + "---\n\n", nil
+}
+
+// renderFrontMatterValue executes the templates of the string values of the front matter
+func renderFrontMatterValue(key string, value interface{}, data *FrontMatterData) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		tmpl, err := template.New(key).Parse(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid front matter %s", key)
+		}
+		var buffer bytes.Buffer
+		err = tmpl.Execute(&buffer, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the front matter %s", key)
+		}
+		return buffer.String(), nil
+	case []interface{}:
+		var answer []interface{}
+		for _, item := range v {
+			rendered, err := renderFrontMatterValue(key, item, data)
+			if err != nil {
+				return nil, err
+			}
+			if s, ok := rendered.(string); ok && s == "" {
+				// lets omit the empty items such as tags of optional values
+				continue
+			}
+			answer = append(answer, rendered)
+		}
+		return answer, nil
+	case map[string]interface{}:
+		answer := map[string]interface{}{}
+		for k, item := range v {
+			rendered, err := renderFrontMatterValue(key+"."+k, item, data)
+			if err != nil {
+				return nil, err
+			}
+			answer[k] = rendered
+		}
+		return answer, nil
+	default:
+		return value, nil
+	}
+}
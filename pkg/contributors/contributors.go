@@ -0,0 +1,175 @@
+package contributors
+
+import (
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// Contributor a person who authored commits in a release.
+// The different git identities which resolve to the same git provider user are merged into a single contributor
+type Contributor struct {
+	// User the merged details of the contributor
+	User v1.UserDetails `json:"user"`
+	// Identities the details of each identity of the contributor which were merged
+	Identities []v1.UserDetails `json:"identities,omitempty"`
+	// Emails the emails of the contributor
+	Emails []string `json:"emails,omitempty"`
+	// Commits the number of commits authored by the contributor
+	Commits int `json:"commits"`
+}
+
+// Key returns the login of the contributor or their name if they have no login
+func (c *Contributor) Key() string {
+	if c.User.Login != "" {
+		return c.User.Login
+	}
+	return c.User.Name
+}
+
+// FromCommits returns the authors of the commits merging users with the same login or email.
+// The contributors are returned in the order they first authored a commit
+func FromCommits(commits []v1.CommitSummary) []*Contributor {
+	var answer []*Contributor
+	index := map[string]*Contributor{}
+	for i := range commits {
+		author := commits[i].Author
+		if author == nil {
+			continue
+		}
+		keys := userKeys(author)
+		if len(keys) == 0 {
+			continue
+		}
+
+		var c *Contributor
+		for _, key := range keys {
+			existing := index[key]
+			if existing == nil || existing == c {
+				continue
+			}
+			if c == nil {
+				c = existing
+				continue
+			}
+			// the user links two contributors so lets merge the later one into the earlier one
+			if indexOf(answer, existing) < indexOf(answer, c) {
+				c, existing = existing, c
+			}
+			c.merge(existing)
+			for k, v := range index {
+				if v == existing {
+					index[k] = c
+				}
+			}
+			answer = removeContributor(answer, existing)
+		}
+		if c == nil {
+			c = &Contributor{}
+			answer = append(answer, c)
+		}
+		c.add(author)
+		c.Commits++
+		for _, key := range userKeys(&c.User) {
+			index[key] = c
+		}
+		for _, key := range keys {
+			index[key] = c
+		}
+	}
+	return answer
+}
+
+// add merges the user into the contributor
+func (c *Contributor) add(u *v1.UserDetails) {
+	if !c.hasIdentity(u) {
+		c.Identities = append(c.Identities, *u)
+	}
+	if u.Email != "" && !containsFold(c.Emails, u.Email) {
+		c.Emails = append(c.Emails, u.Email)
+	}
+	mergeUser(&c.User, u)
+}
+
+func (c *Contributor) hasIdentity(u *v1.UserDetails) bool {
+	for _, identity := range c.Identities {
+		if identity.Login == u.Login && identity.Name == u.Name && identity.Email == u.Email {
+			return true
+		}
+	}
+	return false
+}
+
+// merge merges the other contributor into this contributor
+func (c *Contributor) merge(other *Contributor) {
+	for i := range other.Identities {
+		c.add(&other.Identities[i])
+	}
+	c.Commits += other.Commits
+}
+
+// mergeUser fills in the empty fields of the user from the other user preferring users with a login
+func mergeUser(user, other *v1.UserDetails) {
+	if user.Login == "" && other.Login != "" {
+		email := user.Email
+		*user = *other
+		if user.Email == "" {
+			user.Email = email
+		}
+		return
+	}
+	if user.Name == "" {
+		user.Name = other.Name
+	}
+	if user.Email == "" {
+		user.Email = other.Email
+	}
+	if user.URL == "" {
+		user.URL = other.URL
+	}
+	if user.AvatarURL == "" {
+		user.AvatarURL = other.AvatarURL
+	}
+}
+
+// userKeys returns the keys which identify the same user
+func userKeys(u *v1.UserDetails) []string {
+	var answer []string
+	if u.Login != "" {
+		answer = append(answer, "login:"+strings.ToLower(u.Login))
+	}
+	if u.Email != "" {
+		answer = append(answer, "email:"+strings.ToLower(u.Email))
+	}
+	if len(answer) == 0 && u.Name != "" {
+		answer = append(answer, "name:"+u.Name)
+	}
+	return answer
+}
+
+func indexOf(contributors []*Contributor, c *Contributor) int {
+	for i, v := range contributors {
+		if v == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeContributor(contributors []*Contributor, c *Contributor) []*Contributor {
+	for i, v := range contributors {
+		if v == c {
+			return append(contributors[0:i], contributors[i+1:]...)
+		}
+	}
+	return contributors
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+// +build unit
+
+package contributors_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCommits(t *testing.T) {
+	t.Parallel()
+	commits := []v1.CommitSummary{
+		{SHA: "1", Author: &v1.UserDetails{Name: "James Strachan", Email: "james@home.example.com"}},
+		{SHA: "2", Author: &v1.UserDetails{Login: "rawlingsj", Name: "rawlingsj", Email: "rawlingsj@example.com"}},
+		{SHA: "3", Author: &v1.UserDetails{Login: "jstrachan", Name: "jstrachan", Email: "james@work.example.com"}},
+		{SHA: "4", Author: &v1.UserDetails{Login: "jstrachan", Name: "jstrachan", Email: "james@home.example.com"}},
+		{SHA: "5"},
+		{SHA: "6", Author: &v1.UserDetails{Login: "rawlingsj", Name: "rawlingsj"}},
+	}
+	results := contributors.FromCommits(commits)
+	require.Len(t, results, 2)
+
+	james := results[0]
+	assert.Equal(t, "jstrachan", james.Key())
+	assert.Equal(t, 3, james.Commits)
+	assert.ElementsMatch(t, []string{"james@home.example.com", "james@work.example.com"}, james.Emails)
+
+	assert.Len(t, james.Identities, 3)
+
+	rawlingsj := results[1]
+	assert.Equal(t, "rawlingsj", rawlingsj.Key())
+	assert.Equal(t, 2, rawlingsj.Commits)
+}
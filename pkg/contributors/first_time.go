@@ -0,0 +1,169 @@
+package contributors
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// FirstTimeDetector detects contributors who are making their first contribution to a repository.
+// A contributor is a first time contributor if none of their git identities authored a commit before the release
+// and, if the git provider supports it, they have no commits in the repository before the release
+type FirstTimeDetector struct {
+	// GitProvider the optional git provider used to find prior commits which are not in the local history such as in shallow clones
+	GitProvider *scm.Client
+	// Repository the full name of the repository
+	Repository string
+	// Before the time of the previous release. Contributions before this time are prior contributions
+	Before time.Time
+	// Mailmap maps the identities of the commits in the history
+	Mailmap *users.Mailmap
+	// Backoff retries git provider calls which are rate limited
+	Backoff *scmapi.Backoff
+	// Offline only uses the local git history
+	Offline bool
+
+	lock       sync.Mutex
+	identities map[string]bool
+	results    map[string]bool
+}
+
+// LoadHistory adds the identities of the authors of all the commits reachable from the given revision
+// and defaults the Before time to the time of the revision
+func (d *FirstTimeDetector) LoadHistory(gitDir, rev string) error {
+	repo, err := git.PlainOpen(gitDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open git repository %s", gitDir)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve revision %s", rev)
+	}
+	iter, err := repo.Log(&git.LogOptions{From: *hash})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the commits before %s", rev)
+	}
+	first := true
+	err = iter.ForEach(func(commit *object.Commit) error {
+		if first {
+			first = false
+			if d.Before.IsZero() {
+				d.Before = commit.Committer.When
+			}
+		}
+		d.AddIdentity(commit.Author.Name, commit.Author.Email)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to iterate the commits before %s", rev)
+	}
+	return nil
+}
+
+// AddIdentity adds the identity of a prior contributor
+func (d *FirstTimeDetector) AddIdentity(name, email string) {
+	name, email = d.Mailmap.Map(name, email)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.identities == nil {
+		d.identities = map[string]bool{}
+	}
+	for _, key := range identityKeys(name, email) {
+		d.identities[key] = true
+	}
+}
+
+// IsFirstTime returns true if the user with the given git identities has not contributed to the repository before.
+// The identities are pairs of names and emails from the commits of the user in the release
+func (d *FirstTimeDetector) IsFirstTime(ctx context.Context, u *v1.UserDetails, identities [][2]string) (bool, error) {
+	if u == nil {
+		return false, nil
+	}
+	resultKey := u.Login
+	if resultKey == "" {
+		resultKey = u.Name
+	}
+	d.lock.Lock()
+	result, ok := d.results[resultKey]
+	d.lock.Unlock()
+	if ok {
+		return result, nil
+	}
+
+	result = !d.hasPriorIdentity(u, identities)
+	if result && u.Login != "" && !d.Offline && d.GitProvider != nil {
+		prior, err := d.hasPriorCommits(ctx, u.Login)
+		if err != nil {
+			return false, err
+		}
+		result = !prior
+	}
+
+	d.lock.Lock()
+	if d.results == nil {
+		d.results = map[string]bool{}
+	}
+	d.results[resultKey] = result
+	d.lock.Unlock()
+	return result, nil
+}
+
+func (d *FirstTimeDetector) hasPriorIdentity(u *v1.UserDetails, identities [][2]string) bool {
+	keys := identityKeys("", u.Email)
+	for _, identity := range identities {
+		name, email := d.Mailmap.Map(identity[0], identity[1])
+		keys = append(keys, identityKeys(name, email)...)
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, key := range keys {
+		if d.identities[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPriorCommits uses the git provider to check for commits by the login before the release
+func (d *FirstTimeDetector) hasPriorCommits(ctx context.Context, login string) (bool, error) {
+	if d.GitProvider.Driver != scm.DriverGithub || d.Before.IsZero() {
+		return false, nil
+	}
+	var results []interface{}
+	path := fmt.Sprintf("repos/%s/commits?author=%s&until=%s&per_page=1", d.Repository, url.QueryEscape(login), url.QueryEscape(d.Before.UTC().Format(time.RFC3339)))
+	err := d.Backoff.Do(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, d.GitProvider, path, &results)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to find prior commits of %s in %s", login, d.Repository)
+	}
+	return len(results) > 0, nil
+}
+
+// identityKeys returns the keys used to match the identities of contributors
+func identityKeys(name, email string) []string {
+	var answer []string
+	if email != "" {
+		answer = append(answer, "email:"+strings.ToLower(email))
+	}
+	if name != "" {
+		answer = append(answer, "name:"+strings.ToLower(name))
+	}
+	return answer
+}
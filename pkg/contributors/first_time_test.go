@@ -0,0 +1,52 @@
+// +build unit
+
+package contributors_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestFirstTimeDetector(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to create git repository")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	err = ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("hello"), 0600)
+	require.NoError(t, err)
+	_, err = wt.Add("README.md")
+	require.NoError(t, err)
+	hash, err := wt.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Now()},
+	})
+	require.NoError(t, err, "failed to commit")
+
+	d := &contributors.FirstTimeDetector{}
+	err = d.LoadHistory(dir, hash.String())
+	require.NoError(t, err, "failed to load history")
+	assert.False(t, d.Before.IsZero(), "should have defaulted the time of the previous release")
+
+	ctx := context.Background()
+	firstTime, err := d.IsFirstTime(ctx, &v1.UserDetails{Login: "alice"}, [][2]string{{"Alice", "Alice@example.com"}})
+	require.NoError(t, err)
+	assert.False(t, firstTime, "alice has contributed before")
+
+	firstTime, err = d.IsFirstTime(ctx, &v1.UserDetails{Login: "bob"}, [][2]string{{"Bob", "bob@example.com"}})
+	require.NoError(t, err)
+	assert.True(t, firstTime, "bob is a first time contributor")
+}
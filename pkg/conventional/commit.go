@@ -0,0 +1,154 @@
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// BreakingChangeToken the footer token describing a breaking change
+	BreakingChangeToken = "BREAKING CHANGE"
+
+	// BreakingChangeTokenAlias the alternative footer token describing a breaking change
+	BreakingChangeTokenAlias = "BREAKING-CHANGE"
+)
+
+var (
+	headerRegex = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9_-]*)(?:\(([^()]*)\))?(!)?:\s*(.*)$`)
+	footerRegex = regexp.MustCompile(`^(BREAKING CHANGE|[a-zA-Z0-9][a-zA-Z0-9-]*)(?:: | #)(.*)$`)
+)
+
+// Footer a footer of a commit message such as 'Reviewed-by: Jane' or 'Fixes #123'
+type Footer struct {
+	Token string `json:"token"`
+	Value string `json:"value"`
+}
+
+// Commit a commit message parsed using the Conventional Commits v1.0.0 specification.
+// see: https://www.conventionalcommits.org/en/v1.0.0/
+type Commit struct {
+	// SHA the optional SHA of the commit
+	SHA string `json:"sha,omitempty"`
+	// Conventional true if the header of the message follows the specification
+	Conventional bool `json:"conventional"`
+	// Type the type of the commit such as 'feat' or 'fix'
+	Type string `json:"type,omitempty"`
+	// Scope the optional scope of the commit
+	Scope string `json:"scope,omitempty"`
+	// Breaking true if the commit is a breaking change either via the '!' marker or a breaking change footer
+	Breaking bool `json:"breaking,omitempty"`
+	// Header the first line of the message
+	Header string `json:"header,omitempty"`
+	// Description the description in the header after the type and scope
+	Description string `json:"description,omitempty"`
+	// Body the optional free form body of the message
+	Body string `json:"body,omitempty"`
+	// Footers the footers at the end of the message
+	Footers []Footer `json:"footers,omitempty"`
+}
+
+// Parse parses the commit message. Messages which do not follow the specification
+// are returned with Conventional false and the header as the description
+func Parse(message string) *Commit {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	message = strings.TrimSpace(message)
+	lines := strings.Split(message, "\n")
+	header := strings.TrimSpace(lines[0])
+	answer := &Commit{
+		Header:      header,
+		Description: header,
+	}
+	m := headerRegex.FindStringSubmatch(header)
+	if m != nil && strings.TrimSpace(m[4]) != "" {
+		answer.Conventional = true
+		answer.Type = m[1]
+		answer.Scope = strings.TrimSpace(m[2])
+		answer.Breaking = m[3] == "!"
+		answer.Description = strings.TrimSpace(m[4])
+	}
+
+	rest := lines[1:]
+	footerStart := findFooterStart(rest)
+	answer.Body = strings.TrimSpace(strings.Join(rest[0:footerStart], "\n"))
+	answer.Footers = parseFooters(rest[footerStart:])
+
+	if answer.Conventional && answer.BreakingChange() != "" {
+		answer.Breaking = true
+	}
+	return answer
+}
+
+// FooterValues returns the values of the footers with the given token ignoring case
+func (c *Commit) FooterValues(token string) []string {
+	var answer []string
+	for _, f := range c.Footers {
+		if strings.EqualFold(f.Token, token) {
+			answer = append(answer, f.Value)
+		}
+	}
+	return answer
+}
+
+// BreakingChange returns the description of the breaking change from the breaking change footers
+// or the description of the commit if it is marked as breaking with '!'
+func (c *Commit) BreakingChange() string {
+	var answer []string
+	for _, f := range c.Footers {
+		if f.Token == BreakingChangeToken || f.Token == BreakingChangeTokenAlias {
+			answer = append(answer, f.Value)
+		}
+	}
+	if len(answer) == 0 && c.Breaking {
+		return c.Description
+	}
+	return strings.Join(answer, "\n")
+}
+
+// findFooterStart returns the index of the first line of the footers which is the start of the last paragraphs
+// after the header which all begin with a footer. Returns the number of lines if there are no footers
+func findFooterStart(lines []string) int {
+	answer := len(lines)
+	// lines following the header without a blank line are part of the header paragraph
+	paragraphStart := false
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			paragraphStart = true
+			continue
+		}
+		if paragraphStart {
+			paragraphStart = false
+			if isFooter(line) {
+				if answer == len(lines) {
+					answer = i
+				}
+			} else {
+				answer = len(lines)
+			}
+		}
+	}
+	return answer
+}
+
+// parseFooters parses the footer lines where a footer value continues until the next footer token
+func parseFooters(lines []string) []Footer {
+	var answer []Footer
+	for _, line := range lines {
+		m := footerRegex.FindStringSubmatch(line)
+		if m != nil {
+			answer = append(answer, Footer{Token: m[1], Value: m[2]})
+			continue
+		}
+		if len(answer) > 0 {
+			last := &answer[len(answer)-1]
+			last.Value += "\n" + line
+		}
+	}
+	for i := range answer {
+		answer[i].Value = strings.TrimSpace(answer[i].Value)
+	}
+	return answer
+}
+
+func isFooter(line string) bool {
+	return footerRegex.MatchString(line)
+}
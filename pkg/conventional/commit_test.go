@@ -0,0 +1,116 @@
+// +build unit
+
+package conventional_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		message  string
+		expected *conventional.Commit
+	}{
+		{
+			message: "something regular",
+			expected: &conventional.Commit{
+				Header:      "something regular",
+				Description: "something regular",
+			},
+		},
+		{
+			message: "feat: cheese",
+			expected: &conventional.Commit{
+				Conventional: true,
+				Type:         "feat",
+				Header:       "feat: cheese",
+				Description:  "cheese",
+			},
+		},
+		{
+			message: "feat(api)!: send an email to the customer when a product is shipped",
+			expected: &conventional.Commit{
+				Conventional: true,
+				Type:         "feat",
+				Scope:        "api",
+				Breaking:     true,
+				Header:       "feat(api)!: send an email to the customer when a product is shipped",
+				Description:  "send an email to the customer when a product is shipped",
+			},
+		},
+		{
+			message: `fix: prevent racing of requests
+
+Introduce a request id and a reference to latest request. Dismiss
+incoming responses other than from latest request.
+
+Remove timeouts which were used to mitigate the racing issue but are
+obsolete now.
+
+Reviewed-by: Z
+Refs: #123
+BREAKING CHANGE: the request API now requires an id
+which must be unique
+`,
+			expected: &conventional.Commit{
+				Conventional: true,
+				Type:         "fix",
+				Breaking:     true,
+				Header:       "fix: prevent racing of requests",
+				Description:  "prevent racing of requests",
+				Body: `Introduce a request id and a reference to latest request. Dismiss
+incoming responses other than from latest request.
+
+Remove timeouts which were used to mitigate the racing issue but are
+obsolete now.`,
+				Footers: []conventional.Footer{
+					{Token: "Reviewed-by", Value: "Z"},
+					{Token: "Refs", Value: "#123"},
+					{Token: "BREAKING CHANGE", Value: "the request API now requires an id\nwhich must be unique"},
+				},
+			},
+		},
+		{
+			message: "chore: release\n\nFixes #42",
+			expected: &conventional.Commit{
+				Conventional: true,
+				Type:         "chore",
+				Header:       "chore: release",
+				Description:  "release",
+				Footers: []conventional.Footer{
+					{Token: "Fixes", Value: "42"},
+				},
+			},
+		},
+		{
+			message: "Revert \"feat: cheese\"\n\nThis reverts commit abc.",
+			expected: &conventional.Commit{
+				Header:      "Revert \"feat: cheese\"",
+				Description: "Revert \"feat: cheese\"",
+				Body:        "This reverts commit abc.",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, conventional.Parse(tc.message), "parsing %s", tc.message)
+	}
+}
+
+func TestBreakingChange(t *testing.T) {
+	t.Parallel()
+	c := conventional.Parse("feat!: drop support for Node 6")
+	assert.Equal(t, "drop support for Node 6", c.BreakingChange())
+
+	c = conventional.Parse("feat: allow config to extend other configs\n\nBREAKING-CHANGE: `extends` key is now used for extending other config files")
+	assert.True(t, c.Breaking)
+	assert.Equal(t, "`extends` key is now used for extending other config files", c.BreakingChange())
+	assert.Equal(t, []string{"`extends` key is now used for extending other config files"}, c.FooterValues("breaking-change"))
+
+	c = conventional.Parse("feat: cheese")
+	assert.False(t, c.Breaking)
+	assert.Empty(t, c.BreakingChange())
+}
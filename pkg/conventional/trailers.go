@@ -0,0 +1,111 @@
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// FixesTokens the trailer tokens which reference the issues closed by the commit
+	FixesTokens = []string{"Fix", "Fixes", "Fixed", "Close", "Closes", "Closed", "Resolve", "Resolves", "Resolved"}
+
+	// RefsTokens the trailer tokens which reference issues related to the commit
+	RefsTokens = []string{"Ref", "Refs", "References", "See-also", "Related", "Related-to"}
+
+	// SignedOffByToken the trailer token of the Developer Certificate of Origin sign offs
+	SignedOffByToken = "Signed-off-by"
+
+	numericRefRegex = regexp.MustCompile(`^\d+$`)
+)
+
+// Trailers the git trailers of a commit message grouped by their meaning
+type Trailers struct {
+	// Fixes the references of the issues closed by the commit such as '#123', 'owner/repo#123' or 'JIRA-123'
+	Fixes []string `json:"fixes,omitempty"`
+	// Refs the references of the issues related to the commit
+	Refs []string `json:"refs,omitempty"`
+	// SignedOffBy the sign offs of the commit
+	SignedOffBy []string `json:"signedOffBy,omitempty"`
+	// Custom any other trailers of the commit
+	Custom []Footer `json:"custom,omitempty"`
+}
+
+// ParseTrailers parses the git trailers at the end of the commit message
+func ParseTrailers(message string) *Trailers {
+	return Parse(message).Trailers()
+}
+
+// Trailers returns the footers of the commit grouped by their meaning
+func (c *Commit) Trailers() *Trailers {
+	answer := &Trailers{}
+	for _, f := range c.Footers {
+		switch {
+		case f.Token == BreakingChangeToken || f.Token == BreakingChangeTokenAlias:
+			continue
+		case matchesToken(FixesTokens, f.Token):
+			answer.Fixes = appendIssueRefs(answer.Fixes, f.Value)
+		case matchesToken(RefsTokens, f.Token):
+			answer.Refs = appendIssueRefs(answer.Refs, f.Value)
+		case strings.EqualFold(f.Token, SignedOffByToken):
+			answer.SignedOffBy = append(answer.SignedOffBy, f.Value)
+		default:
+			answer.Custom = append(answer.Custom, f)
+		}
+	}
+	return answer
+}
+
+// IssueIDs returns the IDs of the issues referenced by the trailers in the current repository which are either
+// numeric IDs or keys of an issue tracker such as JIRA
+func (t *Trailers) IssueIDs() []string {
+	var answer []string
+	for _, ref := range append(append([]string{}, t.Fixes...), t.Refs...) {
+		if strings.Contains(ref, "/") {
+			continue
+		}
+		id := strings.TrimPrefix(ref, "#")
+		if !contains(answer, id) {
+			answer = append(answer, id)
+		}
+	}
+	return answer
+}
+
+// appendIssueRefs appends the comma or space separated issue references in the value
+func appendIssueRefs(refs []string, value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	})
+	for _, ref := range fields {
+		ref = strings.TrimRight(ref, ".;")
+		if ref == "" || strings.EqualFold(ref, "and") {
+			continue
+		}
+		// the '#' separator of footers such as 'Fixes #123' is not part of the value
+		if numericRefRegex.MatchString(ref) {
+			ref = "#" + ref
+		}
+		if !contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func matchesToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
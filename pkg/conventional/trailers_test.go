@@ -0,0 +1,43 @@
+// +build unit
+
+package conventional_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrailers(t *testing.T) {
+	t.Parallel()
+	message := `fix: the cork
+
+some details
+
+Fixes #123, #124
+Closes: jenkins-x/jx#7
+Resolves: https://example.com/issues/9
+Refs: JIRA-12 and 13
+Reviewed-by: Jane Doe <jane@example.com>
+Signed-off-by: James Strachan <james@example.com>
+BREAKING CHANGE: no more wine
+`
+	trailers := conventional.ParseTrailers(message)
+	assert.Equal(t, &conventional.Trailers{
+		Fixes:       []string{"#123", "#124", "jenkins-x/jx#7", "https://example.com/issues/9"},
+		Refs:        []string{"JIRA-12", "#13"},
+		SignedOffBy: []string{"James Strachan <james@example.com>"},
+		Custom: []conventional.Footer{
+			{Token: "Reviewed-by", Value: "Jane Doe <jane@example.com>"},
+		},
+	}, trailers)
+	assert.Equal(t, []string{"123", "124", "JIRA-12", "13"}, trailers.IssueIDs())
+}
+
+func TestParseTrailersWithoutTrailers(t *testing.T) {
+	t.Parallel()
+	trailers := conventional.ParseTrailers("something regular\n\nthis mentions #123 in the body")
+	assert.Equal(t, &conventional.Trailers{}, trailers)
+	assert.Empty(t, trailers.IssueIDs())
+}
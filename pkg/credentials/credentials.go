@@ -0,0 +1,175 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultTokenKeys the keys of the token in a Kubernetes Secret or Vault secret in the order they are looked up if
+// no key is specified
+var DefaultTokenKeys = []string{"password", "token"}
+
+// UsernameKey the optional key of the username in a Kubernetes Secret or Vault secret
+const UsernameKey = "username"
+
+// Options the Kubernetes Secret or Vault secret to read the git provider credentials from so that pipelines running
+// in-cluster do not need to template tokens into environment variables
+type Options struct {
+	// Secret the name of the Kubernetes Secret, optionally prefixed by its namespace such as 'jx/jx-boot-git'
+	Secret string
+	// SecretKey the key of the token in the Kubernetes Secret. Defaults to the DefaultTokenKeys
+	SecretKey string
+	// VaultPath the path of the Vault secret such as 'secret/data/jx/git'
+	VaultPath string
+	// VaultKey the key of the token in the Vault secret. Defaults to the DefaultTokenKeys
+	VaultKey string
+	// VaultAddr the address of the Vault server. The Vault token is read from $VAULT_TOKEN
+	VaultAddr string
+	// KubeClient the client to read the Kubernetes Secret with. Lazily created if nil
+	KubeClient kubernetes.Interface
+	// Namespace the namespace of the Kubernetes Secret if not specified with the name. Defaults to the current namespace
+	Namespace string
+}
+
+// AddFlags adds the CLI flags
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Secret, "git-token-secret", "", "", "The name of the Kubernetes Secret containing the git token, optionally prefixed by its namespace such as 'jx/jx-boot-git'. Only used if no --git-token is specified")
+	cmd.Flags().StringVarP(&o.SecretKey, "git-token-secret-key", "", "", fmt.Sprintf("The key of the git token in the --git-token-secret. Defaults to the first of: %s", strings.Join(DefaultTokenKeys, ", ")))
+	cmd.Flags().StringVarP(&o.VaultPath, "git-token-vault-path", "", "", "The path of the Vault secret containing the git token such as 'secret/data/jx/git'. The Vault token is read from $VAULT_TOKEN. Only used if no --git-token or --git-token-secret is specified")
+	cmd.Flags().StringVarP(&o.VaultKey, "git-token-vault-key", "", "", fmt.Sprintf("The key of the git token in the --git-token-vault-path. Defaults to the first of: %s", strings.Join(DefaultTokenKeys, ", ")))
+	cmd.Flags().StringVarP(&o.VaultAddr, "vault-addr", "", os.Getenv("VAULT_ADDR"), "The address of the Vault server of --git-token-vault-path. Defaults to $VAULT_ADDR")
+}
+
+// Resolve reads the git token and username of the factory from the Kubernetes Secret or Vault secret unless a
+// token is already specified. This needs to be called before the factory is validated
+func (o *Options) Resolve(f *scmhelpers.Options) error {
+	if f.GitToken != "" || (o.Secret == "" && o.VaultPath == "") {
+		return nil
+	}
+	var data map[string]string
+	var err error
+	var source, key string
+	if o.Secret != "" {
+		source, key = "Kubernetes Secret "+o.Secret, o.SecretKey
+		data, err = o.readSecret()
+	} else {
+		source, key = "Vault secret "+o.VaultPath, o.VaultKey
+		data, err = o.readVault()
+	}
+	if err != nil {
+		return err
+	}
+	token := lookup(data, key)
+	if token == "" {
+		return errors.Errorf("no git token found in the %s", source)
+	}
+	f.GitToken = token
+	// lets use the username of the secret unless one is configured
+	if username := data[UsernameKey]; username != "" && os.Getenv("GIT_USERNAME") == "" {
+		_ = os.Setenv("GIT_USERNAME", username)
+	}
+	log.Logger().Debugf("using the git token of the %s", source)
+	return nil
+}
+
+// readSecret reads the data of the Kubernetes Secret
+func (o *Options) readSecret() (map[string]string, error) {
+	ns, name := o.Namespace, o.Secret
+	if i := strings.Index(name, "/"); i > 0 {
+		ns, name = name[:i], name[i+1:]
+	}
+	var err error
+	o.KubeClient, ns, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, ns)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the kubernetes client")
+	}
+	secret, err := o.KubeClient.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the Secret %s in namespace %s", name, ns)
+	}
+	answer := map[string]string{}
+	for k, v := range secret.StringData {
+		answer[k] = v
+	}
+	for k, v := range secret.Data {
+		answer[k] = string(v)
+	}
+	return answer, nil
+}
+
+// readVault reads the data of the Vault secret supporting both version 1 and 2 of the key value secrets engine
+func (o *Options) readVault() (map[string]string, error) {
+	if o.VaultAddr == "" {
+		return nil, errors.Errorf("missing --vault-addr for the Vault secret %s", o.VaultPath)
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, errors.Errorf("missing $VAULT_TOKEN to read the Vault secret %s", o.VaultPath)
+	}
+	u := strings.TrimSuffix(o.VaultAddr, "/") + "/v1/" + strings.TrimPrefix(o.VaultPath, "/")
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the request for %s", u)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the Vault secret %s", o.VaultPath)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 4096))
+		return nil, errors.Errorf("failed to read the Vault secret %s: status %d: %s", o.VaultPath, res.StatusCode, string(body))
+	}
+	secret := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	err = json.NewDecoder(res.Body).Decode(&secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the Vault secret %s", o.VaultPath)
+	}
+	data := secret.Data
+	// version 2 of the key value secrets engine nests the data with its metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	answer := map[string]string{}
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			answer[k] = s
+		}
+	}
+	return answer, nil
+}
+
+// lookup returns the value of the key or, if no key is specified, of the first of the DefaultTokenKeys
+func lookup(data map[string]string, key string) string {
+	if key != "" {
+		return data[key]
+	}
+	for _, k := range DefaultTokenKeys {
+		if data[k] != "" {
+			return data[k]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+// +build unit
+
+package credentials_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/credentials"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveFromSecret(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "jx-boot-git", Namespace: "jx"},
+			Data:       map[string][]byte{"username": []byte("jenkins-x-bot"), "password": []byte("secret-token")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "release-token", Namespace: "ci"},
+			Data:       map[string][]byte{"github": []byte("other-token")},
+		},
+	)
+
+	f := &scmhelpers.Options{}
+	o := &credentials.Options{Secret: "jx/jx-boot-git", KubeClient: kubeClient, Namespace: "ci"}
+	require.NoError(t, o.Resolve(f), "failed to resolve the token")
+	assert.Equal(t, "secret-token", f.GitToken)
+
+	f = &scmhelpers.Options{}
+	o = &credentials.Options{Secret: "release-token", SecretKey: "github", KubeClient: kubeClient, Namespace: "ci"}
+	require.NoError(t, o.Resolve(f), "failed to resolve the token with a key")
+	assert.Equal(t, "other-token", f.GitToken)
+
+	f = &scmhelpers.Options{GitToken: "flag-token"}
+	o = &credentials.Options{Secret: "missing", KubeClient: kubeClient, Namespace: "ci"}
+	require.NoError(t, o.Resolve(f), "should not read the secret if a token is specified")
+	assert.Equal(t, "flag-token", f.GitToken)
+
+	f = &scmhelpers.Options{}
+	assert.Error(t, o.Resolve(f), "should fail if the secret is missing")
+}
+
+func TestResolveFromVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/jx/git":
+			_, _ = w.Write([]byte(`{"data": {"data": {"token": "kv2-token"}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/jx/git":
+			_, _ = w.Write([]byte(`{"data": {"password": "kv1-token"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	os.Setenv("VAULT_TOKEN", "vault-token")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	f := &scmhelpers.Options{}
+	o := &credentials.Options{VaultPath: "secret/data/jx/git", VaultAddr: server.URL}
+	require.NoError(t, o.Resolve(f), "failed to resolve the token from version 2 of the key value engine")
+	assert.Equal(t, "kv2-token", f.GitToken)
+
+	f = &scmhelpers.Options{}
+	o = &credentials.Options{VaultPath: "kv/jx/git", VaultAddr: server.URL}
+	require.NoError(t, o.Resolve(f), "failed to resolve the token from version 1 of the key value engine")
+	assert.Equal(t, "kv1-token", f.GitToken)
+
+	f = &scmhelpers.Options{}
+	o = &credentials.Options{VaultPath: "secret/data/missing", VaultAddr: server.URL}
+	assert.Error(t, o.Resolve(f), "should fail if the Vault secret is missing")
+}
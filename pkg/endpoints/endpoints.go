@@ -0,0 +1,130 @@
+package endpoints
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Options the connection settings of self-hosted git servers such as GitHub Enterprise, GitLab or Gitea which may be
+// installed behind TLS intercepting proxies
+type Options struct {
+	// APIURL the base URL of the REST API of the git provider if it cannot be derived from the git server URL
+	APIURL string
+	// CAFile the PEM file of additional certificate authorities to trust when connecting to the git server
+	CAFile string
+	// Proxy the URL of the HTTP proxy. Defaults to the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables
+	Proxy string
+}
+
+// AddFlags adds the CLI flags
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.APIURL, "git-api-url", "", "", "The base URL of the REST API of the git provider such as https://github.example.com/api/v3/. Defaults to the API of the --git-server")
+	cmd.Flags().StringVarP(&o.CAFile, "git-ca-file", "", os.Getenv("GIT_CA_FILE"), "The PEM file of additional certificate authorities to trust when connecting to an on-premise git server. Defaults to $GIT_CA_FILE")
+	cmd.Flags().StringVarP(&o.Proxy, "git-proxy", "", "", "The URL of the HTTP proxy used to connect to the git server. Defaults to the $HTTPS_PROXY environment variable")
+}
+
+// Validate validates the options
+func (o *Options) Validate() error {
+	if o.APIURL != "" {
+		_, err := url.Parse(o.APIURL)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse --git-api-url %s", o.APIURL)
+		}
+	}
+	if o.Proxy != "" {
+		_, err := url.Parse(o.Proxy)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse --git-proxy %s", o.Proxy)
+		}
+	}
+	return nil
+}
+
+// Transport returns the HTTP transport trusting the CA file and using the proxy
+func (o *Options) Transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if o.Proxy != "" {
+		proxy, err := url.Parse(o.Proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse --git-proxy %s", o.Proxy)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+	if o.CAFile != "" {
+		data, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA file %s", o.CAFile)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.Errorf("no PEM certificates found in CA file %s", o.CAFile)
+		}
+		t.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return t, nil
+}
+
+// Configure makes the CA file and proxy the default for HTTP clients and git commands. This needs to be called before
+// the git provider clients are created as some clients query the server on creation
+func (o *Options) Configure() error {
+	if o.CAFile == "" && o.Proxy == "" {
+		return nil
+	}
+	t, err := o.Transport()
+	if err != nil {
+		return err
+	}
+	http.DefaultTransport = t
+
+	// lets make sure git clones, fetches and pushes can reach the server too
+	if o.CAFile != "" {
+		setenvDefault("GIT_SSL_CAINFO", o.CAFile)
+	}
+	if o.Proxy != "" {
+		setenvDefault("HTTPS_PROXY", o.Proxy)
+		setenvDefault("HTTP_PROXY", o.Proxy)
+	}
+	return nil
+}
+
+// Apply changes the base URL of the client to the --git-api-url if specified
+func (o *Options) Apply(client *scm.Client) error {
+	if client == nil || o.APIURL == "" {
+		return nil
+	}
+	u, err := url.Parse(o.APIURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse --git-api-url %s", o.APIURL)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	client.BaseURL = u
+	if client.Driver == scm.DriverGithub && strings.HasSuffix(u.Path, "/v3/") {
+		graphQL := *u
+		graphQL.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+		client.GraphQLURL = &graphQL
+	}
+	return nil
+}
+
+func setenvDefault(name, value string) {
+	if os.Getenv(name) == "" {
+		_ = os.Setenv(name, value)
+	}
+}
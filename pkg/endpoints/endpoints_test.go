@@ -0,0 +1,83 @@
+// +build unit
+
+package endpoints_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/endpoints"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportTrustsCAFile(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	o := &endpoints.Options{}
+	transport, err := o.Transport()
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: transport}).Get(server.URL)
+	require.Error(t, err, "should not trust the certificate of the test server without the CA file")
+
+	o.CAFile = filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	err = ioutil.WriteFile(o.CAFile, data, 0600)
+	require.NoError(t, err)
+	transport, err = o.Transport()
+	require.NoError(t, err)
+	res, err := (&http.Client{Transport: transport}).Get(server.URL)
+	require.NoError(t, err, "should trust the certificate of the test server with the CA file")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	err = ioutil.WriteFile(o.CAFile, []byte("not a certificate"), 0600)
+	require.NoError(t, err)
+	_, err = o.Transport()
+	assert.Error(t, err, "should fail for a CA file without certificates")
+}
+
+func TestTransportProxy(t *testing.T) {
+	t.Parallel()
+	o := &endpoints.Options{Proxy: "http://proxy.example.com:3128"}
+	transport, err := o.Transport()
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "https://github.example.com/api/v3/", nil)
+	require.NoError(t, err)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxy.String())
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	client, err := github.New("https://github.example.com/api/v3")
+	require.NoError(t, err)
+	o := &endpoints.Options{APIURL: "https://api.example.com/github/api/v3"}
+	err = o.Apply(client)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/github/api/v3/", client.BaseURL.String())
+	assert.Equal(t, "https://api.example.com/github/api/graphql", client.GraphQLURL.String())
+
+	client, err = gitlab.New("https://gitlab.example.com")
+	require.NoError(t, err)
+	o = &endpoints.Options{APIURL: "https://example.com/gitlab/"}
+	err = o.Apply(client)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/gitlab/", client.BaseURL.String())
+
+	o = &endpoints.Options{}
+	err = o.Apply(client)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/gitlab/", client.BaseURL.String(), "should not change the client without an API URL")
+}
@@ -0,0 +1,314 @@
+package githubapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/factory"
+	"github.com/jenkins-x/go-scm/scm/transport/oauth2"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// TokenEnv the environment variable the git credential helper reads the installation token from
+	TokenEnv = "JX_CHANGELOG_GITHUB_APP_TOKEN"
+
+	// gitUsername the username git authenticates with when using an installation token
+	gitUsername = "x-access-token"
+
+	// jwtLifetime the lifetime of the JSON web tokens authenticating as the app which GitHub limits to 10 minutes
+	jwtLifetime = 9 * time.Minute
+
+	// refreshMargin the remaining lifetime below which installation tokens are refreshed
+	refreshMargin = 5 * time.Minute
+)
+
+// Options the settings to authenticate as a GitHub App with short-lived installation tokens instead of a personal
+// access token
+type Options struct {
+	// AppID the ID of the GitHub App
+	AppID int64
+	// PrivateKeyFile the PEM file of the private key of the GitHub App
+	PrivateKeyFile string
+	// InstallationID the ID of the installation of the GitHub App. Defaults to the installation on the repository
+	InstallationID int64
+}
+
+// AddFlags adds the CLI flags
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64VarP(&o.AppID, "github-app-id", "", envInt64("GITHUB_APP_ID"), "The ID of the GitHub App to authenticate as instead of using a personal access token. Defaults to $GITHUB_APP_ID")
+	cmd.Flags().StringVarP(&o.PrivateKeyFile, "github-app-private-key-file", "", os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"), "The PEM file of the private key of the --github-app-id. Defaults to $GITHUB_APP_PRIVATE_KEY_FILE")
+	cmd.Flags().Int64VarP(&o.InstallationID, "github-app-installation-id", "", envInt64("GITHUB_APP_INSTALLATION_ID"), "The ID of the installation of the --github-app-id. Defaults to $GITHUB_APP_INSTALLATION_ID or the installation on the repository")
+}
+
+// Enabled returns true if authenticating as a GitHub App
+func (o *Options) Enabled() bool {
+	return o.AppID != 0
+}
+
+// Validate validates the options
+func (o *Options) Validate() error {
+	if !o.Enabled() {
+		return nil
+	}
+	if o.PrivateKeyFile == "" {
+		return errors.Errorf("missing --github-app-private-key-file for --github-app-id %d", o.AppID)
+	}
+	_, err := o.privateKey()
+	return err
+}
+
+// Prepare lets the git provider client be created without a personal access token when authenticating as a GitHub
+// App. This needs to be called before the factory is validated
+func (o *Options) Prepare(f *scmhelpers.Options) {
+	if o.Enabled() {
+		f.IgnoreMissingToken = true
+	}
+}
+
+// Apply authenticates the git provider client of the factory with installation tokens of the GitHub App which are
+// refreshed before they expire. Git commands authenticate with the tokens via a credential helper.
+// Returns nil if not authenticating as a GitHub App
+func (o *Options) Apply(f *scmhelpers.Options) (*TokenSource, error) {
+	if !o.Enabled() {
+		return nil, nil
+	}
+	if f.ScmClient == nil {
+		client, err := factory.NewClient("github", f.GitServerURL, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create the GitHub client for %s", f.GitServerURL)
+		}
+		f.ScmClient = client
+	}
+	client := f.ScmClient
+	if client.Driver != scm.DriverGithub {
+		return nil, errors.Errorf("cannot authenticate as a GitHub App with the git provider %s", client.Driver.String())
+	}
+	ts, err := o.NewTokenSource(client.BaseURL.String(), scm.Join(f.Owner, f.Repository))
+	if err != nil {
+		return nil, err
+	}
+	// lets mint the first token to fail fast if the app is not installed
+	token, err := ts.Token(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	client.Client = &http.Client{
+		Transport: &oauth2.Transport{
+			Scheme: oauth2.SchemeToken,
+			Source: ts,
+		},
+	}
+	f.GitToken = token.Token
+	err = configureGitCredentials(f.GitServerURL)
+	if err != nil {
+		return nil, err
+	}
+	log.Logger().Debugf("authenticating as GitHub App %d installation %d", o.AppID, ts.installationID)
+	return ts, nil
+}
+
+// NewTokenSource creates the source of the installation tokens of the GitHub App for the repository using the
+// GitHub API at the URL
+func (o *Options) NewTokenSource(apiURL, repo string) (*TokenSource, error) {
+	key, err := o.privateKey()
+	if err != nil {
+		return nil, err
+	}
+	appClient, err := github.New(apiURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the GitHub App client for %s", apiURL)
+	}
+	appClient.Client = &http.Client{
+		Transport: &appTransport{appID: o.AppID, key: key},
+	}
+	return &TokenSource{
+		apps:           appClient.Apps,
+		repo:           repo,
+		installationID: o.InstallationID,
+	}, nil
+}
+
+func (o *Options) privateKey() (*rsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(o.PrivateKeyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the GitHub App private key file %s", o.PrivateKeyFile)
+	}
+	return ParsePrivateKey(data)
+}
+
+// ParsePrivateKey parses the PKCS1 or PKCS8 PEM encoded RSA private key of a GitHub App
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM private key found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the private key")
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("the private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// NewJWT creates the JSON web token signed with the private key which authenticates as the GitHub App
+func NewJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the JWT header")
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// lets allow for clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the JWT claims")
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign the JWT")
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// appTransport authenticates the requests as the GitHub App with a new JSON web token per request
+type appTransport struct {
+	appID int64
+	key   *rsa.PrivateKey
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *appTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := NewJWT(t.appID, t.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	r2 := r.Clone(r.Context())
+	r2.Header.Set("Authorization", "Bearer "+token)
+	return http.DefaultTransport.RoundTrip(r2)
+}
+
+// TokenSource mints the installation tokens of the GitHub App caching them until they are about to expire
+type TokenSource struct {
+	apps           scm.AppService
+	repo           string
+	installationID int64
+
+	lock  sync.Mutex
+	token *scm.Token
+}
+
+// Token returns the cached installation token or mints a new one if it is about to expire
+func (s *TokenSource) Token(ctx context.Context) (*scm.Token, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token != nil && time.Until(s.token.Expires) > refreshMargin {
+		return s.token, nil
+	}
+	if s.installationID == 0 {
+		installation, _, err := s.apps.GetRepositoryInstallation(ctx, s.repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the installation of the GitHub App on repository %s", s.repo)
+		}
+		s.installationID = installation.ID
+	}
+	it, _, err := s.apps.CreateInstallationToken(ctx, s.installationID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create a token of GitHub App installation %d", s.installationID)
+	}
+	if it == nil || it.Token == "" {
+		return nil, errors.Errorf("no token returned for GitHub App installation %d", s.installationID)
+	}
+	s.token = &scm.Token{Token: it.Token}
+	if it.ExpiresAt != nil {
+		s.token.Expires = *it.ExpiresAt
+	}
+	// lets make the token available to the git credential helper
+	_ = os.Setenv(TokenEnv, it.Token)
+	log.Logger().Debugf("created a token of GitHub App installation %d expiring at %s", s.installationID, s.token.Expires.Format(time.RFC3339))
+	return s.token, nil
+}
+
+// Git returns the git client which refreshes the installation token before git commands which access the remote
+func (s *TokenSource) Git(g gitclient.Interface) gitclient.Interface {
+	return &refreshingGit{g: g, ts: s}
+}
+
+// refreshingGit refreshes the installation token before running git commands which access the remote
+type refreshingGit struct {
+	g  gitclient.Interface
+	ts *TokenSource
+}
+
+// Command implements gitclient.Interface
+func (r *refreshingGit) Command(dir string, args ...string) (string, error) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "push", "fetch", "pull", "clone", "ls-remote":
+			_, err := r.ts.Token(context.Background())
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return r.g.Command(dir, args...)
+}
+
+// configureGitCredentials makes git commands authenticate with the installation token when talking to the git server
+// via the git configuration environment variables so that no git configuration file is changed. The credential helper
+// is scoped to the git server so that the other credential helpers are only reset for it and the token is never sent
+// to other hosts such as those of submodules
+func configureGitCredentials(serverURL string) error {
+	u, err := url.Parse(stringhelpers.FirstNotEmptyString(serverURL, giturl.GitHubURL))
+	if err != nil || u.Host == "" {
+		return errors.Errorf("failed to parse the git server URL %s", serverURL)
+	}
+	key := fmt.Sprintf("credential.%s://%s.helper", stringhelpers.FirstNotEmptyString(u.Scheme, "https"), u.Host)
+	count, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	helper := fmt.Sprintf(`!f() { test "$1" = get && echo username=%s && echo "password=$%s"; }; f`, gitUsername, TokenEnv)
+	for i, value := range []string{"", helper} {
+		_ = os.Setenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", count+i), key)
+		_ = os.Setenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", count+i), value)
+	}
+	_ = os.Setenv("GIT_CONFIG_COUNT", strconv.Itoa(count+2))
+	return nil
+}
+
+func envInt64(name string) int64 {
+	value, _ := strconv.ParseInt(strings.TrimSpace(os.Getenv(name)), 10, 64)
+	return value
+}
@@ -0,0 +1,105 @@
+// +build unit
+
+package githubapp_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/githubapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJWT(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate key")
+	now := time.Unix(1600000000, 0)
+
+	token, err := githubapp.NewJWT(123, key, now)
+	require.NoError(t, err, "failed to create JWT")
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err, "failed to decode signature")
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature), "invalid signature")
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err, "failed to decode claims")
+	claims := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(data, &claims))
+	assert.Equal(t, "123", claims["iss"])
+	assert.Equal(t, float64(now.Add(-time.Minute).Unix()), claims["iat"])
+	assert.Equal(t, float64(now.Add(9*time.Minute).Unix()), claims["exp"])
+}
+
+func TestTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate key")
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+	require.NoError(t, err, "failed to save key")
+
+	var minted int32
+	expiresIn := int64(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/jstrachan/foo/installation":
+			_, _ = w.Write([]byte(`{"id": 7}`))
+		case "POST /app/installations/7/access_tokens":
+			n := atomic.AddInt32(&minted, 1)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, n, time.Now().Add(time.Duration(atomic.LoadInt64(&expiresIn))).Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	o := &githubapp.Options{AppID: 123, PrivateKeyFile: keyFile}
+	require.NoError(t, o.Validate(), "failed to validate")
+	ts, err := o.NewTokenSource(server.URL, "jstrachan/foo")
+	require.NoError(t, err, "failed to create token source")
+
+	ctx := context.Background()
+	token, err := ts.Token(ctx)
+	require.NoError(t, err, "failed to mint token")
+	assert.Equal(t, "token-1", token.Token)
+	token, err = ts.Token(ctx)
+	require.NoError(t, err, "failed to get cached token")
+	assert.Equal(t, "token-1", token.Token, "should reuse the token until it is about to expire")
+
+	// lets mint tokens which are about to expire
+	atomic.StoreInt64(&expiresIn, int64(time.Minute))
+	ts, err = o.NewTokenSource(server.URL, "jstrachan/foo")
+	require.NoError(t, err, "failed to create token source")
+	token, err = ts.Token(ctx)
+	require.NoError(t, err, "failed to mint token")
+	assert.Equal(t, "token-2", token.Token)
+	token, err = ts.Token(ctx)
+	require.NoError(t, err, "failed to refresh token")
+	assert.Equal(t, "token-3", token.Token, "should refresh a token which is about to expire")
+}
@@ -0,0 +1,142 @@
+package gitprovider
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/credentials"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/endpoints"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/githubapp"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Options the options shared by the commands which connect to the git provider of the repository so that every
+// command supports the same ways of authenticating and reaching the git server
+type Options struct {
+	ScmFactory    scmhelpers.Options
+	Endpoints     endpoints.Options
+	GitHubApp     githubapp.Options
+	Credentials   credentials.Options
+	GitClient     gitclient.Interface
+	CommandRunner cmdrunner.CommandRunner
+}
+
+// AddFlags adds the CLI flags of the git provider connection
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	o.ScmFactory.AddFlags(cmd)
+	o.Endpoints.AddFlags(cmd)
+	o.GitHubApp.AddFlags(cmd)
+	o.Credentials.AddFlags(cmd)
+}
+
+// Prepare validates the options, configures the connection to the git server and resolves the git credentials.
+// This needs to be called before the repository is discovered
+func (o *Options) Prepare() error {
+	err := o.Endpoints.Validate()
+	if err != nil {
+		return err
+	}
+	err = o.GitHubApp.Validate()
+	if err != nil {
+		return err
+	}
+	err = o.Endpoints.Configure()
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure the connection to the git server")
+	}
+	err = o.Credentials.Resolve(&o.ScmFactory)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the git credentials")
+	}
+	return nil
+}
+
+// Connect discovers the repository and creates the git provider client authenticating as the GitHub App if one is
+// configured in which case the git client uses the token of the installation
+func (o *Options) Connect() error {
+	o.GitHubApp.Prepare(&o.ScmFactory)
+	err := o.ScmFactory.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to discover git repository")
+	}
+	o.applyServerURL()
+	err = o.Endpoints.Apply(o.ScmFactory.ScmClient)
+	if err != nil {
+		return err
+	}
+	ts, err := o.GitHubApp.Apply(&o.ScmFactory)
+	if err != nil {
+		return errors.Wrapf(err, "failed to authenticate as GitHub App")
+	}
+	if ts != nil {
+		o.GitClient = ts.Git(o.Git())
+	}
+	return nil
+}
+
+// Validate prepares the connection and connects to the git provider
+func (o *Options) Validate() error {
+	err := o.Prepare()
+	if err != nil {
+		return err
+	}
+	return o.Connect()
+}
+
+// FullName returns the full name of the repository
+func (o *Options) FullName() string {
+	return scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+}
+
+// Git returns the git client
+func (o *Options) Git() gitclient.Interface {
+	if o.GitClient == nil {
+		o.GitClient = cli.NewCLIClient("", o.CommandRunner)
+	}
+	return o.GitClient
+}
+
+// CandidateTags returns the tags a release of the tag or version may have been created for which are the tag, the
+// version with and without the 'v' prefix or otherwise the latest tag of the repository
+func (o *Options) CandidateTags(tag, version string) ([]string, error) {
+	switch {
+	case tag != "":
+		return []string{tag}, nil
+	case version != "":
+		return []string{version, fmt.Sprintf("v%s", version)}, nil
+	}
+	_, latest, err := gits.GetCommitPointedToByLatestTag(o.Git(), o.ScmFactory.Dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the latest tag")
+	}
+	if latest == "" {
+		return nil, errors.Errorf("no tags could be found in dir %s", o.ScmFactory.Dir)
+	}
+	return []string{latest}, nil
+}
+
+// applyServerURL fixes the owner and repository discovered from the git URL if the git server such as a self-hosted
+// Gitea is hosted below a path of its host
+func (o *Options) applyServerURL() {
+	gitInfo := o.ScmFactory.GitURL
+	if gitInfo == nil {
+		return
+	}
+	owner := gitInfo.Organisation
+	if !gits.ApplyServerURL(gitInfo, o.ScmFactory.GitServerURL) {
+		return
+	}
+	if o.ScmFactory.Owner == owner {
+		o.ScmFactory.Owner = gitInfo.Organisation
+		o.ScmFactory.Repository = gitInfo.Name
+		o.ScmFactory.FullRepositoryName = scm.Join(o.ScmFactory.Owner, o.ScmFactory.Repository)
+	}
+	log.Logger().Debugf("using repository %s on git server %s", o.ScmFactory.FullRepositoryName, o.ScmFactory.GitServerURL)
+}
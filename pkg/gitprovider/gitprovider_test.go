@@ -0,0 +1,24 @@
+// +build unit
+
+package gitprovider_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gitprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidateTags(t *testing.T) {
+	t.Parallel()
+	o := &gitprovider.Options{}
+
+	tags, err := o.CandidateTags("release-1", "1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"release-1"}, tags)
+
+	tags, err = o.CandidateTags("", "1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3", "v1.2.3"}, tags)
+}
@@ -0,0 +1,94 @@
+package gits
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// securityAdvisoriesEmoji the emoji rendered before the title of the security advisories
+const securityAdvisoriesEmoji = "🔒"
+
+// advisoryPullRequestRegex matches the references to pull requests in the description of an advisory such as
+// '#123' or 'https://github.com/owner/repo/pull/123'
+var advisoryPullRequestRegex = regexp.MustCompile(`(?:/pull/|#)(\d+)\b`)
+
+// advisorySHARegex matches the abbreviated or full commit SHAs in the description of an advisory
+var advisorySHARegex = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+
+// SecurityAdvisory a published security advisory which is fixed by a release
+type SecurityAdvisory struct {
+	// ID the ID of the advisory such as 'GHSA-xxxx-xxxx-xxxx'
+	ID string `json:"id"`
+	// CVE the optional CVE ID such as 'CVE-2021-1234'
+	CVE string `json:"cve,omitempty"`
+	// Summary the summary of the advisory
+	Summary string `json:"summary"`
+	// Severity the severity such as 'low', 'medium', 'high' or 'critical'
+	Severity string `json:"severity,omitempty"`
+	// URL the link to the advisory
+	URL string `json:"url,omitempty"`
+}
+
+// AdvisoryFixedByRelease returns true if the text of an advisory references any of the commits or pull requests of
+// the release or any of its patched versions is the version of the release
+func AdvisoryFixedByRelease(spec *v1.ReleaseSpec, version, text string, patchedVersions []string) bool {
+	version = strings.TrimPrefix(version, "v")
+	if version != "" {
+		for _, patched := range patchedVersions {
+			for _, v := range strings.Split(patched, ",") {
+				v = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(v), "=>"))
+				if strings.TrimPrefix(v, "v") == version {
+					return true
+				}
+			}
+		}
+	}
+	for _, sha := range advisorySHARegex.FindAllString(text, -1) {
+		for i := range spec.Commits {
+			if strings.HasPrefix(spec.Commits[i].SHA, sha) {
+				return true
+			}
+		}
+	}
+	prs := map[string]bool{}
+	for i := range spec.PullRequests {
+		prs[spec.PullRequests[i].ID] = true
+	}
+	for _, m := range advisoryPullRequestRegex.FindAllStringSubmatch(text, -1) {
+		n, err := strconv.Atoi(m[1])
+		if err == nil && prs[strconv.Itoa(n)] {
+			return true
+		}
+	}
+	return false
+}
+
+// securityAdvisoriesTitle returns the title of the security advisories with the emoji unless emoji are disabled
+func (o *MarkdownOptions) securityAdvisoriesTitle() string {
+	title := o.Translations.Translate("Security")
+	if o.DisableEmoji {
+		return title
+	}
+	return securityAdvisoriesEmoji + " " + title
+}
+
+// describeSecurityAdvisory describes the advisory such as '**CVE-2021-1234** (high): the summary (GHSA-xxxx)'
+func describeSecurityAdvisory(a *SecurityAdvisory, flavor *MarkdownFlavor) string {
+	id := a.ID
+	if a.URL != "" {
+		id = fmt.Sprintf("[%s](%s)", a.ID, a.URL)
+	}
+	name := a.CVE
+	if name == "" {
+		name = a.ID
+	}
+	severity := ""
+	if a.Severity != "" {
+		severity = " (" + strings.ToLower(a.Severity) + ")"
+	}
+	return fmt.Sprintf("**%s**%s: %s %s", name, severity, flavor.escape(strings.TrimSpace(a.Summary)), id)
+}
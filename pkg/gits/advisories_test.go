@@ -0,0 +1,91 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvisoryFixedByRelease(t *testing.T) {
+	t.Parallel()
+	spec := &v1.ReleaseSpec{
+		Commits:      []v1.CommitSummary{{SHA: "abcdef1234567890", Message: "fix: the cork"}},
+		PullRequests: []v1.IssueSummary{{ID: "12"}},
+	}
+	testCases := []struct {
+		name     string
+		version  string
+		text     string
+		patched  []string
+		expected bool
+	}{
+		{name: "commit", text: "Fixed in abcdef1", expected: true},
+		{name: "pull request", text: "Fixed by #12", expected: true},
+		{name: "pull request link", text: "See https://github.com/jstrachan/foo/pull/12", expected: true},
+		{name: "patched version", version: "v1.2.0", patched: []string{">= 1.2.0"}, expected: true},
+		{name: "other pull request", text: "Fixed by #13"},
+		{name: "other commit", text: "Fixed in 1234567"},
+		{name: "other patched version", version: "1.2.0", patched: []string{"1.1.5, >= 1.3.0"}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, gits.AdvisoryFixedByRelease(spec, tc.version, tc.text, tc.patched), tc.name)
+	}
+}
+
+func TestSecurityAdvisoriesMarkdown(t *testing.T) {
+	t.Parallel()
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	spec := &v1.ReleaseSpec{Commits: []v1.CommitSummary{{SHA: "1111111111", Message: "fix: the cork"}}}
+	markdown, err := gits.GenerateMarkdownWithOptions(spec, gitInfo, &gits.MarkdownOptions{
+		DisableEmoji: true,
+		SecurityAdvisories: []gits.SecurityAdvisory{
+			{ID: "GHSA-abcd-efgh-ijkl", CVE: "CVE-2021-1234", Summary: "Path traversal", Severity: "HIGH", URL: "https://github.com/jstrachan/foo/security/advisories/GHSA-abcd-efgh-ijkl"},
+			{ID: "GHSA-mnop-qrst-uvwx", Summary: "Leaked token"},
+		},
+	})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### Security
+
+* **CVE-2021-1234** (high): Path traversal [GHSA-abcd-efgh-ijkl](https://github.com/jstrachan/foo/security/advisories/GHSA-abcd-efgh-ijkl)
+* **GHSA-mnop-qrst-uvwx**: Leaked token GHSA-mnop-qrst-uvwx
+
+### Bug Fixes
+
+* the cork
+`
+	assert.Equal(t, expected, markdown)
+}
+
+func TestSecurityAdvisoriesTemplate(t *testing.T) {
+	t.Parallel()
+	changelog := &gits.Changelog{
+		SecurityAdvisories: []gits.SecurityAdvisory{
+			{ID: "GHSA-abcd-efgh-ijkl", CVE: "CVE-2021-1234", Summary: "Path traversal", Severity: "high", URL: "https://github.com/jstrachan/foo/security/advisories/GHSA-abcd-efgh-ijkl"},
+			{ID: "GHSA-mnop-qrst-uvwx", Summary: "Leaked token"},
+		},
+	}
+	text, err := gits.RenderTemplate(changelog, "changelog.md", gits.DefaultMarkdownTemplate, nil)
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### Security
+
+* **CVE-2021-1234** (high): Path traversal [GHSA-abcd-efgh-ijkl](https://github.com/jstrachan/foo/security/advisories/GHSA-abcd-efgh-ijkl)
+* **GHSA-mnop-qrst-uvwx**: Leaked token
+`
+	assert.Equal(t, expected, text)
+}
@@ -0,0 +1,84 @@
+package gits
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+)
+
+// DefaultBadgeColor the default color of the badges
+const DefaultBadgeColor = "blue"
+
+// shieldsEscaper escapes the dashes and underscores which separate the parts of a static shields.io badge
+var shieldsEscaper = strings.NewReplacer("-", "--", "_", "__")
+
+// Badge a static shields.io badge such as the latest release of a repository
+type Badge struct {
+	// Label the text on the left of the badge
+	Label string `json:"label"`
+	// Message the text on the right of the badge
+	Message string `json:"message"`
+	// Color the color of the message
+	Color string `json:"color,omitempty"`
+	// Link the URL the badge links to
+	Link string `json:"link,omitempty"`
+}
+
+// ImageURL returns the URL of the badge image
+func (b *Badge) ImageURL() string {
+	color := b.Color
+	if color == "" {
+		color = DefaultBadgeColor
+	}
+	return "https://img.shields.io/badge/" + url.PathEscape(shieldsEscaper.Replace(b.Label)) + "-" +
+		url.PathEscape(shieldsEscaper.Replace(b.Message)) + "-" + url.PathEscape(color)
+}
+
+// Markdown returns the markdown image of the badge linked to the badge link
+func (b *Badge) Markdown() string {
+	image := "![" + b.Label + "](" + b.ImageURL() + ")"
+	if b.Link == "" {
+		return image
+	}
+	return "[" + image + "](" + b.Link + ")"
+}
+
+// ReleaseBadges returns the badges of the latest release of the repository with its tag, its date and the number of
+// commits since the release linked to the comparison with the current revision
+func ReleaseBadges(info *giturl.GitRepository, kind, tag, rev string, date time.Time, commitsSince int, color string) []*Badge {
+	release := &Badge{Label: "release", Message: tag, Color: color, Link: ReleaseURL(info, kind, tag)}
+	since := &Badge{Label: "commits since " + tag, Message: strconv.Itoa(commitsSince), Color: color}
+	if compare := NewCompare(info, kind, tag, rev); compare != nil && commitsSince > 0 {
+		since.Link = compare.URL
+	}
+	return []*Badge{
+		release,
+		{Label: "release date", Message: date.UTC().Format("2006-01-02"), Color: color, Link: release.Link},
+		since,
+	}
+}
+
+// ReleaseURL returns the URL of the release page of the tag on the git provider of the given kind which is detected
+// from the host if the kind is empty. Returns an empty string if the repository is unknown
+func ReleaseURL(info *giturl.GitRepository, kind, tag string) string {
+	if info == nil || info.Host == "" {
+		return ""
+	}
+	if kind == giturl.KindGitlab || (kind == "" && strings.Contains(info.Host, "gitlab")) {
+		return stringhelpers.UrlJoin(info.HttpsURL(), "-", "releases", tag)
+	}
+	return stringhelpers.UrlJoin(info.HttpsURL(), "releases", "tag", tag)
+}
+
+// BadgesMarkdown returns the markdown of the badges separated by spaces
+func BadgesMarkdown(badges []*Badge) string {
+	var images []string
+	for _, b := range badges {
+		images = append(images, b.Markdown())
+	}
+	return strings.Join(images, " ")
+}
@@ -0,0 +1,110 @@
+package gits
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BumpNone leaves the version unchanged as no commit is releasable
+	BumpNone = "none"
+
+	// BumpPatch increments the patch version such as for bug fixes
+	BumpPatch = "patch"
+
+	// BumpMinor increments the minor version such as for new features
+	BumpMinor = "minor"
+
+	// BumpMajor increments the major version for breaking changes
+	BumpMajor = "major"
+)
+
+// Bumps the supported version increments in ascending order
+var Bumps = []string{BumpNone, BumpPatch, BumpMinor, BumpMajor}
+
+// DefaultBumpTypes maps the commit types to the version increments they require. Breaking changes always require a
+// major increment and any other type does not require a release
+var DefaultBumpTypes = map[string]string{
+	"feat": BumpMinor,
+	"fix":  BumpPatch,
+	"perf": BumpPatch,
+}
+
+// CommitBump returns the version increment the commit requires using the mapping of the commit types which defaults
+// to the DefaultBumpTypes
+func CommitBump(ci *CommitInfo, bumpTypes map[string]string) string {
+	if ci.Breaking {
+		return BumpMajor
+	}
+	if bumpTypes == nil {
+		bumpTypes = DefaultBumpTypes
+	}
+	if bump, ok := bumpTypes[strings.ToLower(ci.Kind)]; ok && bump != "" {
+		return bump
+	}
+	return BumpNone
+}
+
+// ReleaseBump returns the largest version increment required by the commits
+func ReleaseBump(commits []v1.CommitSummary, convention string, bumpTypes map[string]string) string {
+	answer := BumpNone
+	for i := range commits {
+		bump := CommitBump(ParseCommitWithConvention(commits[i].Message, convention), bumpTypes)
+		if bumpIndex(bump) > bumpIndex(answer) {
+			answer = bump
+		}
+	}
+	return answer
+}
+
+// NextVersion returns the version after incrementing the current version keeping any 'v' prefix. A pre-release such
+// as '2.0.0-rc.1' becomes its release if that already satisfies the increment
+func NextVersion(current, bump string) (string, error) {
+	if bumpIndex(bump) < 0 {
+		return "", errors.Errorf("unsupported bump %s. Supported values: %s", bump, strings.Join(Bumps, ", "))
+	}
+	prefix := ""
+	if strings.HasPrefix(current, "v") {
+		prefix = "v"
+	}
+	v, err := semver.NewVersion(strings.TrimPrefix(current, prefix))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the version %s", current)
+	}
+	// the release of a pre-release of the same major or minor version already satisfies the increment
+	release := v.Prerelease() != "" && v.Patch() == 0 && (bump == BumpMinor || v.Minor() == 0)
+	var next semver.Version
+	switch {
+	case bump == BumpNone:
+		return current, nil
+	case bump == BumpPatch:
+		// the patch version of pre-releases is not incremented
+		next = v.IncPatch()
+	case release:
+		next, err = v.SetPrerelease("")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to remove the pre-release of %s", current)
+		}
+		next, err = next.SetMetadata("")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to remove the metadata of %s", current)
+		}
+	case bump == BumpMinor:
+		next = v.IncMinor()
+	default:
+		next = v.IncMajor()
+	}
+	return prefix + next.String(), nil
+}
+
+func bumpIndex(bump string) int {
+	for i, b := range Bumps {
+		if b == bump {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,65 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseBump(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		messages []string
+		types    map[string]string
+		expected string
+	}{
+		{name: "none", messages: []string{"chore: tidy", "docs: typo"}, expected: gits.BumpNone},
+		{name: "fix", messages: []string{"chore: tidy", "fix: the cork"}, expected: gits.BumpPatch},
+		{name: "feat", messages: []string{"fix: the cork", "feat: cheese"}, expected: gits.BumpMinor},
+		{name: "breaking", messages: []string{"feat!: new API", "fix: the cork"}, expected: gits.BumpMajor},
+		{name: "breaking footer", messages: []string{"fix: the cork\n\nBREAKING CHANGE: removed the bottle"}, expected: gits.BumpMajor},
+		{name: "mapped", messages: []string{"docs: typo"}, types: map[string]string{"docs": gits.BumpPatch}, expected: gits.BumpPatch},
+	}
+	for _, tc := range testCases {
+		var commits []v1.CommitSummary
+		for _, m := range tc.messages {
+			commits = append(commits, v1.CommitSummary{Message: m})
+		}
+		assert.Equal(t, tc.expected, gits.ReleaseBump(commits, "", tc.types), tc.name)
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		current  string
+		bump     string
+		expected string
+	}{
+		{current: "1.2.3", bump: gits.BumpNone, expected: "1.2.3"},
+		{current: "1.2.3", bump: gits.BumpPatch, expected: "1.2.4"},
+		{current: "v1.2.3", bump: gits.BumpMinor, expected: "v1.3.0"},
+		{current: "v1.2.3", bump: gits.BumpMajor, expected: "v2.0.0"},
+		{current: "0.0.0", bump: gits.BumpMinor, expected: "0.1.0"},
+		{current: "2.0.0-rc.1", bump: gits.BumpMajor, expected: "2.0.0"},
+		{current: "2.1.0-rc.1", bump: gits.BumpMajor, expected: "3.0.0"},
+		{current: "2.1.0-rc.1", bump: gits.BumpMinor, expected: "2.1.0"},
+		{current: "2.1.1-rc.1", bump: gits.BumpPatch, expected: "2.1.1"},
+	}
+	for _, tc := range testCases {
+		v, err := gits.NextVersion(tc.current, tc.bump)
+		require.NoError(t, err, "failed to increment %s", tc.current)
+		assert.Equal(t, tc.expected, v, "%s with a %s increment", tc.current, tc.bump)
+	}
+
+	_, err := gits.NextVersion("cheese", gits.BumpPatch)
+	assert.Error(t, err, "should fail to increment an invalid version")
+	_, err = gits.NextVersion("1.2.3", "huge")
+	assert.Error(t, err, "should fail with an unsupported increment")
+}
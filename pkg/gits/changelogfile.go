@@ -0,0 +1,180 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// UnreleasedVersion the version of the section of a changelog file listing the changes since the latest release
+	UnreleasedVersion = "Unreleased"
+
+	// DefaultChangelogFile the default name of the changelog file
+	DefaultChangelogFile = "CHANGELOG.md"
+
+	// DefaultChangelogFileHeader the header of a new changelog file
+	DefaultChangelogFileHeader = "# Changelog\n\nAll notable changes to this project will be documented in this file.\n"
+)
+
+var linkReferenceRegex = regexp.MustCompile(`^\[[^\]]+\]:\s*\S+`)
+
+// ChangelogFileSection returns the section of a changelog file for a release with the given heading such as
+// '[1.2.3] - 2021-03-31'. The leading level 2 heading of the markdown is replaced by the heading of the section
+func ChangelogFileSection(heading, markdown string) string {
+	markdown = strings.TrimLeft(markdown, "\n")
+	if strings.HasPrefix(markdown, "## ") {
+		idx := strings.Index(markdown, "\n")
+		if idx < 0 {
+			idx = len(markdown) - 1
+		}
+		markdown = markdown[idx+1:]
+	}
+	markdown = strings.Trim(markdown, "\n")
+	if markdown == "" {
+		return "## " + heading + "\n"
+	}
+	return "## " + heading + "\n\n" + markdown + "\n"
+}
+
+// InsertChangelogFileSection inserts the section of the release with the given version into the markdown of a
+// changelog file such as CHANGELOG.md. The section is inserted below the header and the Unreleased section before the
+// previous releases. If the file already has a section for the version it is replaced. The link references at the
+// bottom of the file are preserved
+func InsertChangelogFileSection(existing, version, section string) string {
+	section = strings.Trim(section, "\n") + "\n"
+	if strings.TrimSpace(existing) == "" {
+		return DefaultChangelogFileHeader + "\n" + section
+	}
+	lines, end := changelogFileLines(existing)
+
+	start, stop, insert := -1, end, -1
+	inFence := false
+	for i := 0; i < end; i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if inFence || !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if start >= 0 && stop == end {
+			stop = i
+		}
+		v := changelogFileSectionVersion(line)
+		if start < 0 && sameChangelogVersion(v, version) {
+			start = i
+			stop = end
+		}
+		if insert < 0 && !sameChangelogVersion(v, UnreleasedVersion) {
+			insert = i
+		}
+	}
+
+	before, after := lines, []string(nil)
+	if start >= 0 {
+		before, after = lines[:start], lines[stop:]
+	} else {
+		if insert < 0 {
+			insert = end
+		}
+		before, after = lines[:insert], lines[insert:]
+	}
+	head := strings.TrimRight(strings.Join(before, ""), "\n")
+	tail := strings.TrimLeft(strings.Join(after, ""), "\n")
+	if tail != "" && !strings.HasSuffix(tail, "\n") {
+		tail += "\n"
+	}
+	buffer := strings.Builder{}
+	if head != "" {
+		buffer.WriteString(head + "\n\n")
+	}
+	buffer.WriteString(section)
+	if tail != "" {
+		buffer.WriteString("\n" + tail)
+	}
+	return buffer.String()
+}
+
+// RemoveChangelogFileSection removes the section of the release with the given version from the markdown of a
+// changelog file such as CHANGELOG.md returning false if the file has no section for the version
+func RemoveChangelogFileSection(existing, version string) (string, bool) {
+	lines, end := changelogFileLines(existing)
+	start, stop := -1, end
+	inFence := false
+	for i := 0; i < end; i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if inFence || !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if start >= 0 {
+			stop = i
+			break
+		}
+		if sameChangelogVersion(changelogFileSectionVersion(line), version) {
+			start = i
+		}
+	}
+	if start < 0 {
+		return existing, false
+	}
+	head := strings.TrimRight(strings.Join(lines[:start], ""), "\n")
+	tail := strings.TrimLeft(strings.Join(lines[stop:], ""), "\n")
+	if tail != "" && !strings.HasSuffix(tail, "\n") {
+		tail += "\n"
+	}
+	switch {
+	case head == "":
+		return tail, true
+	case tail == "":
+		return head + "\n", true
+	default:
+		return head + "\n\n" + tail, true
+	}
+}
+
+// changelogFileLines returns the lines of a changelog file and the index of the first of the link references at
+// the bottom of the file
+func changelogFileLines(existing string) ([]string, int) {
+	lines := strings.SplitAfter(existing, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	end := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !linkReferenceRegex.MatchString(line) {
+			break
+		}
+		end = i
+	}
+	return lines, end
+}
+
+// changelogFileSectionVersion returns the version of a level 2 heading of a changelog file such as
+// '## [1.2.3] - 2021-03-31' or '## v1.2.3 (2021-03-31)'
+func changelogFileSectionVersion(heading string) string {
+	fields := strings.Fields(strings.TrimPrefix(heading, "## "))
+	if len(fields) == 0 {
+		return ""
+	}
+	v := fields[0]
+	if strings.HasPrefix(v, "[") {
+		if idx := strings.Index(v, "]"); idx > 0 {
+			v = v[1:idx]
+		}
+	}
+	return strings.Trim(v, "[]")
+}
+
+// sameChangelogVersion returns true if the versions are equal ignoring a 'v' prefix and the case
+func sameChangelogVersion(a, b string) bool {
+	a = strings.TrimPrefix(strings.ToLower(a), "v")
+	b = strings.TrimPrefix(strings.ToLower(b), "v")
+	return a != "" && a == b
+}
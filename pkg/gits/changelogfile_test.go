@@ -0,0 +1,121 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertChangelogFileSection(t *testing.T) {
+	t.Parallel()
+	section := gits.ChangelogFileSection("[1.2.0] - 2021-03-31", "## Changes\n\n### Bug Fixes\n\n* the cork\n")
+	assert.Equal(t, "## [1.2.0] - 2021-03-31\n\n### Bug Fixes\n\n* the cork\n", section)
+
+	existing := `# Changelog
+
+Some intro.
+
+## [Unreleased]
+
+* wine
+
+## [1.1.0] - 2021-03-01
+
+### New Features
+
+* cheese
+
+[Unreleased]: https://github.com/jstrachan/foo/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/jstrachan/foo/releases/tag/v1.1.0
+`
+	expected := `# Changelog
+
+Some intro.
+
+## [Unreleased]
+
+* wine
+
+## [1.2.0] - 2021-03-31
+
+### Bug Fixes
+
+* the cork
+
+## [1.1.0] - 2021-03-01
+
+### New Features
+
+* cheese
+
+[Unreleased]: https://github.com/jstrachan/foo/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/jstrachan/foo/releases/tag/v1.1.0
+`
+	text := gits.InsertChangelogFileSection(existing, "1.2.0", section)
+	assert.Equal(t, expected, text)
+
+	replaced := gits.InsertChangelogFileSection(text, "v1.2.0", gits.ChangelogFileSection("[1.2.0] - 2021-04-01", "* the glass\n"))
+	assert.Equal(t, `# Changelog
+
+Some intro.
+
+## [Unreleased]
+
+* wine
+
+## [1.2.0] - 2021-04-01
+
+* the glass
+
+## [1.1.0] - 2021-03-01
+
+### New Features
+
+* cheese
+
+[Unreleased]: https://github.com/jstrachan/foo/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/jstrachan/foo/releases/tag/v1.1.0
+`, replaced)
+
+	replaced = gits.InsertChangelogFileSection(existing, "1.1.0", gits.ChangelogFileSection("[1.1.0] - 2021-03-01", "* the glass\n"))
+	assert.Contains(t, replaced, "## [1.1.0] - 2021-03-01\n\n* the glass\n\n[Unreleased]:")
+	assert.NotContains(t, replaced, "cheese")
+
+	unreleased := gits.ChangelogFileSection("["+gits.UnreleasedVersion+"]", "## Changes\n\n* beer\n")
+	replaced = gits.InsertChangelogFileSection(existing, gits.UnreleasedVersion, unreleased)
+	assert.Contains(t, replaced, "Some intro.\n\n## [Unreleased]\n\n* beer\n\n## [1.1.0] - 2021-03-01\n")
+	assert.NotContains(t, replaced, "wine")
+
+	assert.Equal(t, gits.DefaultChangelogFileHeader+"\n"+section, gits.InsertChangelogFileSection("", "1.2.0", section))
+	assert.Equal(t, "# Changelog\n\n"+section, gits.InsertChangelogFileSection("# Changelog\n", "1.2.0", section))
+}
+
+func TestRemoveChangelogFileSection(t *testing.T) {
+	t.Parallel()
+	existing := `# Changelog
+
+## [1.2.0] - 2021-04-01
+
+* the glass
+
+## [1.1.0] - 2021-03-01
+
+* cheese
+
+[1.1.0]: https://github.com/jstrachan/foo/releases/tag/v1.1.0
+`
+	removed, ok := gits.RemoveChangelogFileSection(existing, "v1.2.0")
+	assert.True(t, ok)
+	assert.Equal(t, "# Changelog\n\n## [1.1.0] - 2021-03-01\n\n* cheese\n\n[1.1.0]: https://github.com/jstrachan/foo/releases/tag/v1.1.0\n", removed)
+
+	removed, ok = gits.RemoveChangelogFileSection(existing, "1.1.0")
+	assert.True(t, ok)
+	assert.Equal(t, "# Changelog\n\n## [1.2.0] - 2021-04-01\n\n* the glass\n\n[1.1.0]: https://github.com/jstrachan/foo/releases/tag/v1.1.0\n", removed)
+
+	removed, ok = gits.RemoveChangelogFileSection(existing, "1.3.0")
+	assert.False(t, ok)
+	assert.Equal(t, existing, removed)
+}
@@ -0,0 +1,79 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/pkg/errors"
+)
+
+// StableChannel the channel of releases without a semantic version pre-release such as 1.2.0
+const StableChannel = ""
+
+var prereleaseTagRegex = regexp.MustCompile(`(?:^|[^0-9A-Za-z])v?\d+(?:\.\d+)+-([0-9A-Za-z][0-9A-Za-z.-]*)(?:\+[0-9A-Za-z.-]+)?$`)
+
+// PrereleaseChannel returns the channel of the tag such as 'rc' for v1.2.0-rc.1 or 'beta' for 1.2.0-beta2 or
+// StableChannel if the tag has no pre-release. Numeric pre-releases such as 1.2.0-1 use the 'pre' channel
+func PrereleaseChannel(tag string) string {
+	m := prereleaseTagRegex.FindStringSubmatch(tag)
+	if len(m) < 2 {
+		return StableChannel
+	}
+	id := strings.ToLower(strings.SplitN(m[1], ".", 2)[0])
+	channel := strings.TrimRight(id, "0123456789-")
+	if channel == "" {
+		return "pre"
+	}
+	return channel
+}
+
+// IsPrerelease returns true if the tag is a semantic version pre-release such as v1.2.0-rc.1
+func IsPrerelease(tag string) bool {
+	return PrereleaseChannel(tag) != StableChannel
+}
+
+// PreviousChannelTag returns the tag released before the tag in the same channel from the tags in reverse
+// chronological order. A stable release follows the previous stable release while a pre-release follows the previous
+// pre-release of its channel or stable release. If the tag is not in the list, such as when it is not created yet,
+// the latest tag of the channel is returned
+func PreviousChannelTag(tags []string, tag string) string {
+	channel := PrereleaseChannel(tag)
+	for i, t := range tags {
+		if t == tag {
+			tags = tags[i+1:]
+			break
+		}
+	}
+	for _, t := range tags {
+		if t == tag {
+			continue
+		}
+		c := PrereleaseChannel(t)
+		if c == channel || c == StableChannel {
+			return t
+		}
+	}
+	return ""
+}
+
+// GetCommitPointedToByPreviousChannelTag return the SHA of the commit pointed to by the previous tag of the same
+// channel as the tag as well as the tag name for the git repo in dir
+func GetCommitPointedToByPreviousChannelTag(g gitclient.Interface, dir, tag string) (string, string, error) {
+	tags, err := ListTags(g, dir, "")
+	if err != nil {
+		return "", "", errors.Wrapf(err, "listing tags in %s", dir)
+	}
+	for i, j := 0, len(tags)-1; i < j; i, j = i+1, j-1 {
+		tags[i], tags[j] = tags[j], tags[i]
+	}
+	tagName := PreviousChannelTag(tags, tag)
+	if tagName == "" {
+		return "", "", nil
+	}
+	commitSHA, err := g.Command(dir, "rev-list", "-n", "1", tagName)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "running for git rev-list -n 1 %s", tagName)
+	}
+	return commitSHA, tagName, nil
+}
@@ -0,0 +1,43 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrereleaseChannel(t *testing.T) {
+	t.Parallel()
+	for tag, expected := range map[string]string{
+		"v1.2.0":              gits.StableChannel,
+		"1.2.0":               gits.StableChannel,
+		"v1.2.0+build.5":      gits.StableChannel,
+		"my-chart-1.2.0":      gits.StableChannel,
+		"v1.2.0-rc.1":         "rc",
+		"1.2.0-RC2":           "rc",
+		"v1.2.0-beta":         "beta",
+		"v1.2.0-alpha.1+sha":  "alpha",
+		"my-chart-1.2.0-rc.3": "rc",
+		"v1.2.0-1":            "pre",
+	} {
+		assert.Equal(t, expected, gits.PrereleaseChannel(tag), "channel of %s", tag)
+	}
+	assert.True(t, gits.IsPrerelease("v1.2.0-rc.1"))
+	assert.False(t, gits.IsPrerelease("v1.2.0"))
+}
+
+func TestPreviousChannelTag(t *testing.T) {
+	t.Parallel()
+	tags := []string{"v1.2.0", "v1.2.0-rc.2", "v1.2.0-beta.1", "v1.2.0-rc.1", "v1.1.0", "v1.1.0-rc.1", "v1.0.0"}
+	assert.Equal(t, "v1.1.0", gits.PreviousChannelTag(tags, "v1.2.0"))
+	assert.Equal(t, "v1.2.0-rc.1", gits.PreviousChannelTag(tags, "v1.2.0-rc.2"))
+	assert.Equal(t, "v1.1.0", gits.PreviousChannelTag(tags, "v1.2.0-rc.1"))
+	assert.Equal(t, "v1.1.0", gits.PreviousChannelTag(tags, "v1.2.0-beta.1"))
+	assert.Equal(t, "v1.0.0", gits.PreviousChannelTag(tags, "v1.1.0"))
+	assert.Equal(t, "", gits.PreviousChannelTag(tags, "v1.0.0"))
+	assert.Equal(t, "v1.2.0", gits.PreviousChannelTag(tags, "v1.3.0"))
+	assert.Equal(t, "v1.2.0", gits.PreviousChannelTag(tags, "v1.3.0-rc.1"))
+}
@@ -0,0 +1,123 @@
+package gits
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/diff"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const (
+	// CherryPickModeAnnotate marks cherry picked commits with the pull request or commit they were backported from
+	CherryPickModeAnnotate = "annotate"
+
+	// CherryPickModeSkip removes cherry picked commits whose source already shipped in a prior release
+	// and annotates the others
+	CherryPickModeSkip = "skip"
+)
+
+// CherryPickModes the supported ways of handling cherry picked commits
+var CherryPickModes = []string{CherryPickModeAnnotate, CherryPickModeSkip}
+
+var (
+	// cherryPickRegex matches the trailer added by 'git cherry-pick -x'
+	cherryPickRegex = regexp.MustCompile(`(?m)^\(cherry picked from commit ([0-9a-fA-F]{7,40})\)\s*$`)
+
+	mergePullRequestRegex  = regexp.MustCompile(`^Merge pull request #(\d+)`)
+	squashPullRequestRegex = regexp.MustCompile(`\(#(\d+)\)$`)
+)
+
+// CherryPickSource returns the SHA of the commit the message was cherry picked from using the
+// '(cherry picked from commit ...)' trailer or an empty string if there is no trailer
+func CherryPickSource(message string) string {
+	matches := cherryPickRegex.FindAllStringSubmatch(message, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	// lets use the last trailer as commits cherry picked several times have the original source last
+	return strings.ToLower(matches[len(matches)-1][1])
+}
+
+// BackportedFrom returns the text describing the source of a cherry pick such as '#123' for commits
+// merged by a pull request or the short SHA otherwise
+func BackportedFrom(sha string, source *object.Commit) string {
+	if source != nil {
+		subject := strings.TrimSpace(strings.SplitN(strings.TrimSpace(source.Message), "\n", 2)[0])
+		m := mergePullRequestRegex.FindStringSubmatch(subject)
+		if m == nil {
+			m = squashPullRequestRegex.FindStringSubmatch(subject)
+		}
+		if m != nil {
+			return "#" + m[1]
+		}
+	}
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// AnnotateBackport appends the source of the backport to the subject of the commit message
+func AnnotateBackport(message, from string) string {
+	lines := strings.SplitN(strings.TrimSpace(message), "\n", 2)
+	lines[0] += " (backported from " + from + ")"
+	return strings.Join(lines, "\n")
+}
+
+// PatchID returns an identifier of the changes made by the commit which, like 'git patch-id', ignores whitespace and
+// line numbers so that cherry picks of the commit have the same ID. Returns an empty string for merge and root commits
+func PatchID(commit *object.Commit) (string, error) {
+	if commit.NumParents() != 1 {
+		return "", nil
+	}
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the parent of commit %s", commit.Hash.String())
+	}
+	patch, err := parent.Patch(commit)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the changes of commit %s", commit.Hash.String())
+	}
+	hash := sha256.New()
+	for _, fp := range patch.FilePatches() {
+		from, to := fp.Files()
+		if from != nil {
+			hash.Write([]byte("--- " + from.Path() + "\n"))
+		}
+		if to != nil {
+			hash.Write([]byte("+++ " + to.Path() + "\n"))
+		}
+		for _, chunk := range fp.Chunks() {
+			prefix := ""
+			switch chunk.Type() {
+			case diff.Add:
+				prefix = "+"
+			case diff.Delete:
+				prefix = "-"
+			default:
+				continue
+			}
+			for _, line := range strings.Split(chunk.Content(), "\n") {
+				line = removeWhitespace(line)
+				if line != "" {
+					hash.Write([]byte(prefix + line + "\n"))
+				}
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func removeWhitespace(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
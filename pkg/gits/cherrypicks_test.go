@@ -0,0 +1,81 @@
+// +build unit
+
+package gits_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestCherryPickSource(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", gits.CherryPickSource("fix: the cork"))
+	assert.Equal(t, "3e1c8b2f9a", gits.CherryPickSource("fix: the cork (#12)\n\n(cherry picked from commit 3E1C8B2F9A)"))
+	assert.Equal(t, "bbbbbbbb", gits.CherryPickSource("fix: the cork\n\n(cherry picked from commit aaaaaaaa)\n(cherry picked from commit bbbbbbbb)\n"))
+}
+
+func TestBackportedFrom(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "#12", gits.BackportedFrom("3e1c8b2f9a", &object.Commit{Message: "fix: the cork (#12)"}))
+	assert.Equal(t, "#13", gits.BackportedFrom("3e1c8b2f9a", &object.Commit{Message: "Merge pull request #13 from jstrachan/cork\n\nfix: the cork"}))
+	assert.Equal(t, "3e1c8b2", gits.BackportedFrom("3e1c8b2f9a", &object.Commit{Message: "fix: the cork"}))
+	assert.Equal(t, "3e1c8b2", gits.BackportedFrom("3e1c8b2f9a", nil))
+
+	assert.Equal(t, "fix: the cork (backported from #12)\n\nsome body", gits.AnnotateBackport("fix: the cork\n\nsome body\n", "#12"))
+}
+
+func TestPatchID(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	commit := func(file, content, message string) *object.Commit {
+		err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0600)
+		require.NoError(t, err, "failed to write %s", file)
+		_, err = wt.Add(file)
+		require.NoError(t, err, "failed to add %s", file)
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now()}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		c, err := repo.CommitObject(hash)
+		require.NoError(t, err, "failed to find commit %s", message)
+		return c
+	}
+
+	root := commit("README.md", "hello\n", "initial commit")
+	commit("other.txt", "other\n", "chore: other")
+	fix := commit("README.md", "hello\nworld\n", "fix: the world")
+
+	err = wt.Checkout(&git.CheckoutOptions{Hash: root.Hash, Branch: plumbing.NewBranchReferenceName("release"), Create: true})
+	require.NoError(t, err, "failed to create the release branch")
+	pick := commit("README.md", "hello\n  world \n", "fix: the world\n\n(cherry picked from commit "+fix.Hash.String()+")")
+	different := commit("README.md", "hello\nmoon\n", "fix: the moon")
+
+	rootID, err := gits.PatchID(root)
+	require.NoError(t, err)
+	assert.Empty(t, rootID, "root commits have no patch ID")
+
+	fixID, err := gits.PatchID(fix)
+	require.NoError(t, err)
+	pickID, err := gits.PatchID(pick)
+	require.NoError(t, err)
+	differentID, err := gits.PatchID(different)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, fixID)
+	assert.Equal(t, fixID, pickID, "cherry picks should have the same patch ID")
+	assert.NotEqual(t, fixID, differentID)
+}
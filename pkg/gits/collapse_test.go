@@ -0,0 +1,57 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapsedSections(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "fix: the cork"},
+			{SHA: "2222222222", Message: "chore: tidy the cellar"},
+			{SHA: "3333333333", Message: "chore: polish the glasses"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+	opts := &gits.MarkdownOptions{CollapsedSections: []string{"chores"}}
+
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	expected := `## Changes
+
+### Bug Fixes
+
+* the cork
+
+### Chores
+
+<details>
+<summary>2 commits</summary>
+
+* tidy the cellar
+* polish the glasses
+
+</details>
+`
+	assert.Equal(t, expected, markdown)
+
+	rendered, err := gits.RenderTemplate(gits.CreateChangelog(releaseSpec, gitInfo, time.Now(), opts), "changelog", gits.DefaultMarkdownTemplate, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected, rendered)
+
+	opts.Flavor, err = gits.GetMarkdownFlavor(gits.FlavorCommonMark)
+	require.NoError(t, err)
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	assert.NotContains(t, markdown, "<details>")
+}
@@ -3,24 +3,91 @@ package gits
 import (
 	"bytes"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
 	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
 )
 
 type CommitInfo struct {
-	Kind    string
-	Feature string
-	Message string
-	group   *CommitGroup
+	Kind           string
+	Feature        string
+	Message        string
+	Breaking       bool
+	BreakingChange string
+	group          *CommitGroup
 }
 
 type CommitGroup struct {
 	Title string
 	Order int
+	// Emoji the optional emoji rendered before the title
+	Emoji string
+	// Hidden the commits of hidden groups are not rendered
+	Hidden bool
+	// Collapsed renders the commits inside a collapsible '<details>' block if the markdown flavor supports them
+	Collapsed bool
+}
+
+// CommitGroups maps commit types to the groups rendered as changelog sections
+type CommitGroups struct {
+	// Types the groups indexed by the lower case commit type
+	Types map[string]*CommitGroup
+	// Other the group of commits whose type is not mapped. Commits with unmapped types are dropped if nil
+	Other *CommitGroup
+	// Rules the rules which are evaluated in order before the commit types are mapped
+	Rules []*CommitRule
+}
+
+// CommitRule assigns the commits whose subject matches or whose pull requests or issues have any of the labels to a group
+type CommitRule struct {
+	// Subject the optional regular expression of the commit subjects
+	Subject *regexp.Regexp
+	// Labels the optional labels of the pull requests or issues of the commits
+	Labels []string
+	// Group the group of the matching commits
+	Group *CommitGroup
+}
+
+// Group returns the group of the commit type or nil if commits of the type are dropped
+func (g *CommitGroups) Group(kind string) *CommitGroup {
+	group := g.Types[strings.ToLower(kind)]
+	if group == nil {
+		group = g.Other
+	}
+	return group
+}
+
+// RuleGroup returns the group of the first rule matching the commit subject or labels or nil if no rule matches
+func (g *CommitGroups) RuleGroup(subject string, labels []string) *CommitGroup {
+	for _, rule := range g.Rules {
+		if rule.Matches(subject, labels) {
+			return rule.Group
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the subject matches the regular expression or any of the labels is one of the rule labels
+func (r *CommitRule) Matches(subject string, labels []string) bool {
+	if r.Subject != nil && r.Subject.MatchString(subject) {
+		return true
+	}
+	for _, label := range labels {
+		for _, l := range r.Labels {
+			if strings.EqualFold(l, label) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 var (
@@ -40,8 +107,6 @@ var (
 		"chore":    createCommitGroup("Chores"),
 		"":         createCommitGroup(""),
 	}
-
-	unknownKindOrder = len(ConventionalCommitTitles) + 1
 )
 
 func createCommitGroup(title string) *CommitGroup {
@@ -62,20 +127,22 @@ func ParseCommit(message string) *CommitInfo {
 		Message: message,
 	}
 
-	idx := strings.Index(message, ":")
-	if idx > 0 {
-		kind := message[0:idx]
-		if strings.HasSuffix(kind, ")") {
-			idx := strings.Index(kind, "(")
-			if idx > 0 {
-				answer.Feature = strings.TrimSpace(kind[idx+1 : len(kind)-1])
-				kind = strings.TrimSpace(kind[0:idx])
-			}
-		}
-		answer.Kind = kind
-		rest := strings.TrimSpace(message[idx+1:])
+	c := conventional.Parse(message)
+	if c.Conventional {
+		answer.Kind = c.Type
+		answer.Feature = c.Scope
+		answer.Breaking = c.Breaking
+		answer.BreakingChange = c.BreakingChange()
 
-		answer.Message = rest
+		// lets keep the body of the message after the description
+		rest := strings.TrimSpace(message)
+		idx := strings.Index(rest, "\n")
+		if idx >= 0 {
+			rest = rest[idx:]
+		} else {
+			rest = ""
+		}
+		answer.Message = strings.TrimSpace(c.Description + rest)
 	}
 	return answer
 }
@@ -98,13 +165,274 @@ func (c *CommitInfo) Order() int {
 type GroupAndCommitInfos struct {
 	group   *CommitGroup
 	commits []string
+	// scopes the scopes of the commits
+	scopes []string
+	// keys the values the commits are sorted by
+	keys []*entrySortKey
+	// entries the changelog entries of the commits if they are rendered by partial templates
+	entries []*Entry
+}
+
+// MarkdownOptions the additional information used when generating the markdown
+type MarkdownOptions struct {
+	// CoAuthors the resolved co-authors of commits indexed by the commit SHA
+	CoAuthors map[string][]v1.UserDetails
+
+	// Avatars renders the avatars of the contributors at the end of the changes
+	Avatars bool
+
+	// Reviewers the reviewers of the pull requests to render
+	Reviewers []v1.UserDetails
+
+	// Approvers the reviewers who approved the pull requests which are marked as approvers
+	Approvers []v1.UserDetails
+
+	// FirstTimeContributors the contributors making their first contribution to render
+	FirstTimeContributors []v1.UserDetails
+
+	// ContributorSummary the number of commits of each contributor to render as a table
+	ContributorSummary []*users.ContributorStats
+
+	// Groups maps the commit types to the changelog sections. Defaults to the types of the Convention
+	Groups *CommitGroups
+
+	// Convention the convention used by the commit messages. Defaults to Conventional Commits
+	Convention string
+
+	// LabelTypes maps the labels of pull requests such as 'kind/bug' to the commit types such as 'fix' which override
+	// the type of the commit messages of the pull request. Use BreakingLabelType to mark breaking changes
+	LabelTypes map[string]string
+
+	// Reverted the commits reverted within the release to render in a separate section
+	Reverted []RevertedCommit
+
+	// SecurityAdvisories the published security advisories fixed by the release to render in a separate section
+	SecurityAdvisories []SecurityAdvisory
+
+	// CommitBody renders the body of the commit messages indented under each commit
+	CommitBody bool
+
+	// CommitBodyMaxLength the maximum number of characters of the commit bodies to render. Zero means no limit
+	CommitBodyMaxLength int
+
+	// GroupDependencyUpdates renders the dependency update commits in a collapsed section rather than with the other changes
+	GroupDependencyUpdates bool
+
+	// RollUpDependencyUpdates renders multiple updates of the same dependency as a single line from the old to the new version
+	RollUpDependencyUpdates bool
+
+	// Keywords classifies the commits which do not follow the convention by the keywords in their subject
+	Keywords *KeywordClassifier
+
+	// LinkPullRequests renders links to the pull requests of each commit as well as its issues
+	LinkPullRequests bool
+
+	// PullRequestTitles renders the title of the pull request of each commit instead of the commit subject
+	PullRequestTitles bool
+
+	// FixedIssues renders the issues closed by the Fixes, Closes or Resolves trailers of the commits in a separate section
+	FixedIssues bool
+
+	// DCO the sign off compliance of the commits to render at the end of the changelog
+	DCO *DCOReport
+
+	// GroupByScope renders the commits of each section under a subheading for each scope
+	GroupByScope bool
+
+	// ScopeOrder the scopes to render first in the given order when grouping by scope. Other scopes are sorted by name
+	ScopeOrder []string
+
+	// UnscopedTitle the title of the commits without a scope when grouping by scope. Defaults to 'Other'
+	UnscopedTitle string
+
+	// Deduplicate collapses the commits with the same subject into a single entry. Either 'exact' or 'normalized'
+	Deduplicate string
+
+	// DiffStats computes the number of files and lines changed by the commits
+	DiffStats *DiffStatsCache
+
+	// ShowDiffStats renders the insertions and deletions of each commit such as '(+4,200/−3,800)' using the DiffStats
+	ShowDiffStats bool
+
+	// SortByImpact sorts the commits of each section by the number of changed lines using the DiffStats.
+	// Equivalent to a SortBy of 'impact'
+	SortByImpact bool
+
+	// SortBy the strategy used to sort the commits of each section such as 'date' or 'subject'.
+	// The commits are in git log order if it is empty
+	SortBy string
+
+	// CommitDates the commit dates indexed by the commit SHA used to sort the commits by date.
+	// Commits without a date are sorted by their position in git log order
+	CommitDates map[string]time.Time
+
+	// Flavor adjusts the mentions, references and collapsible blocks to the platform rendering the markdown.
+	// Users are linked and collapsible blocks are used if it is nil
+	Flavor *MarkdownFlavor
+
+	// DefaultEmoji renders the default emoji such as '🚀' or '🐛' before the titles of the default sections
+	// which have no configured emoji
+	DefaultEmoji bool
+
+	// DisableEmoji renders no emoji before the section titles even if they are configured
+	DisableEmoji bool
+
+	// Compare the link to the comparison with the previous release rendered at the ComparePosition
+	Compare *Compare
+
+	// ComparePosition renders the Compare link either in the 'header' or the 'footer'. Defaults to the footer
+	ComparePosition string
+
+	// MentionContributors renders the authors and co-authors sorted by their number of commits as mentions which
+	// notify them at the end of the changelog
+	MentionContributors bool
+
+	// Bots the bots excluded from the mentioned contributors. Defaults to the users with the default bot suffixes
+	Bots *users.BotFilter
+
+	// CollapsedSections the titles of the sections such as 'Chores' or 'Tests' to render inside collapsible
+	// '<details>' blocks in addition to the collapsed groups
+	CollapsedSections []string
+
+	// Translations translates the section titles and standard phrases of the changelog. English is used if it is nil
+	Translations Translations
+
+	// EntryLinks links each commit to either its 'commit' or its 'pull-request' falling back to the commit
+	// if it has no pull request. Commits are not linked if it is empty
+	EntryLinks string
+
+	// Partials the partial templates indexed by the name of the header, section, entry, contributors or footer block
+	// they override. The blocks which are not overridden are rendered by the built-in markdown
+	Partials map[string]string
+
+	// Changelog the parsed changelog the header, contributors and footer partial templates are executed with.
+	// Created from the release if it is nil
+	Changelog *Changelog
+}
+
+// parseCommit parses the commit message using the convention of the options
+func (o *MarkdownOptions) parseCommit(message string) *CommitInfo {
+	answer := ParseCommitWithConvention(message, o.Convention)
+	if answer.Kind == "" {
+		answer.Kind = o.Keywords.Classify(message)
+	}
+	return answer
+}
+
+// ParseCommitWithConvention parses the commit message using the given convention which defaults to Conventional Commits
+func ParseCommitWithConvention(message, convention string) *CommitInfo {
+	if convention == ConventionGitmoji {
+		return ParseGitmojiCommit(message)
+	}
+	return ParseCommit(message)
+}
+
+// BreakingChanges returns the unique descriptions of the breaking changes of the commits
+func BreakingChanges(commits []v1.CommitSummary, convention string) []string {
+	var answer []string
+	for i := range commits {
+		ci := ParseCommitWithConvention(commits[i].Message, convention)
+		if !ci.Breaking {
+			continue
+		}
+		text := breakingChangeText(ci)
+		if stringhelpers.StringArrayIndex(answer, text) < 0 {
+			answer = append(answer, text)
+		}
+	}
+	return answer
+}
+
+// collapsed returns true if the commits of the group are rendered inside a collapsible block
+func (o *MarkdownOptions) collapsed(group *CommitGroup) bool {
+	if group == nil {
+		return false
+	}
+	if group.Collapsed {
+		return true
+	}
+	for _, title := range o.CollapsedSections {
+		if group.Title != "" && strings.EqualFold(title, group.Title) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffStats returns the diff statistics of the commit if they are required. Commits which are not in the local
+// repository such as the commits of expanded squash merges have no statistics
+func (o *MarkdownOptions) diffStats(sha string) *DiffStats {
+	if o.DiffStats == nil || (!o.ShowDiffStats && o.sortStrategy() != SortByImpact) {
+		return nil
+	}
+	stats, err := o.DiffStats.Get(sha)
+	if err != nil {
+		return nil
+	}
+	return stats
+}
+
+// pullRequestTitleCommit returns the commit info parsed from the title of the pull request of the commit. The type,
+// scope and breaking change of the commit are used if the title does not have them
+func (o *MarkdownOptions) pullRequestTitleCommit(cs *v1.CommitSummary, ci *CommitInfo, prMap map[string]*v1.IssueSummary) *CommitInfo {
+	for _, id := range cs.IssueIDs {
+		pr := prMap[id]
+		if pr == nil || strings.TrimSpace(pr.Title) == "" {
+			continue
+		}
+		answer := o.parseCommit(strings.TrimSpace(pr.Title))
+		if answer.Kind == "" {
+			answer.Kind = ci.Kind
+			answer.Feature = ci.Feature
+		}
+		if ci.Breaking && !answer.Breaking {
+			answer.Breaking = true
+			answer.BreakingChange = ci.BreakingChange
+		}
+		return answer
+	}
+	return ci
+}
+
+// commitGroup returns the group of the commit or nil if the commit is dropped
+func (o *MarkdownOptions) commitGroup(cs *v1.CommitSummary, ci *CommitInfo, labelMap map[string][]string) *CommitGroup {
+	if o.Groups != nil {
+		if len(o.Groups.Rules) > 0 {
+			var labels []string
+			for _, id := range cs.IssueIDs {
+				labels = append(labels, labelMap[id]...)
+			}
+			group := o.Groups.RuleGroup(strings.SplitN(strings.TrimSpace(cs.Message), "\n", 2)[0], labels)
+			if group != nil {
+				return group
+			}
+		}
+		return o.Groups.Group(ci.Kind)
+	}
+	if o.Convention == ConventionGitmoji {
+		return GitmojiCommitTitles[ci.Kind]
+	}
+	return ci.Group()
 }
 
 // GenerateMarkdown generates the markdown document for the commits
 func GenerateMarkdown(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository) (string, error) {
+	return GenerateMarkdownWithOptions(releaseSpec, gitInfo, &MarkdownOptions{})
+}
+
+// GenerateMarkdownWithOptions generates the markdown document for the commits using the given options
+func GenerateMarkdownWithOptions(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository, opts *MarkdownOptions) (string, error) {
+	if opts == nil {
+		opts = &MarkdownOptions{}
+	}
+	partials, err := opts.partialRenderer(releaseSpec, gitInfo)
+	if err != nil {
+		return "", err
+	}
 	var commitInfos []*CommitInfo
 
-	groupAndCommits := map[int]*GroupAndCommitInfos{}
+	groupAndCommits := map[*CommitGroup]*GroupAndCommitInfos{}
+	var groups []*GroupAndCommitInfos
 
 	issues := releaseSpec.Issues
 	issueMap := map[string]*v1.IssueSummary{}
@@ -112,25 +440,90 @@ func GenerateMarkdown(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository
 		cp := issue
 		issueMap[cp.ID] = &cp
 	}
+	labelMap := issueLabels(releaseSpec)
+	prMap := map[string]*v1.IssueSummary{}
+	for i := range releaseSpec.PullRequests {
+		prMap[releaseSpec.PullRequests[i].ID] = &releaseSpec.PullRequests[i]
+	}
+	linkMap := issueMap
+	if opts.LinkPullRequests {
+		linkMap = map[string]*v1.IssueSummary{}
+		for k, v := range prMap {
+			linkMap[k] = v
+		}
+		for k, v := range issueMap {
+			linkMap[k] = v
+		}
+	}
 
-	for _, cs := range releaseSpec.Commits {
+	var breakingChanges []string
+	var dependencyCommits []v1.CommitSummary
+	releaseCommits, duplicates := DeduplicateCommits(releaseSpec.Commits, opts.Deduplicate)
+	for index, cs := range releaseCommits {
 		commits := cs
 		message := commits.Message
 		if message != "" {
-			ci := ParseCommit(message)
+			ci := opts.parseCommit(message)
+			if opts.PullRequestTitles {
+				ci = opts.pullRequestTitleCommit(&commits, ci, prMap)
+			}
+			ci = opts.labelCommit(&commits, ci, prMap)
+			if ci.Breaking {
+				msg := "* " + describeBreakingChange(gitInfo, &commits, ci, opts) + "\n"
+				if stringhelpers.StringArrayIndex(breakingChanges, msg) < 0 {
+					breakingChanges = append(breakingChanges, msg)
+				}
+			}
 
-			description := "* " + describeCommit(gitInfo, &commits, ci, issueMap) + "\n"
-			group := ci.Group()
-			if group != nil {
-				gac := groupAndCommits[group.Order]
+			if opts.GroupDependencyUpdates && IsDependencyUpdate(&commits, ci) {
+				dependencyCommits = append(dependencyCommits, commits)
+				commitInfos = append(commitInfos, ci)
+				continue
+			}
+
+			description := "* " + describeCommit(gitInfo, &commits, ci, linkMap, prMap, opts)
+			if shas := duplicates[commits.SHA]; len(shas) > 1 {
+				description += describeDuplicates(gitInfo, shas)
+			}
+			stats := opts.diffStats(commits.SHA)
+			if opts.ShowDiffStats && stats != nil {
+				description += " (" + stats.String() + ")"
+			}
+			description += "\n"
+			if opts.CommitBody {
+				description += describeCommitBody(message, opts.CommitBodyMaxLength)
+			}
+			var entry *Entry
+			if partials.overrides("entry") || partials.overrides("section") {
+				entry = createEntry(gitInfo, &commits, ci, issueMap, prMap, opts)
+				if shas := duplicates[commits.SHA]; len(shas) > 1 {
+					entry.Duplicates = shas
+				}
+				if opts.ShowDiffStats {
+					entry.Stats = stats
+				}
+			}
+			if partials.overrides("entry") {
+				description, err = partials.render("entry", entry)
+				if err != nil {
+					return "", err
+				}
+			}
+			group := opts.commitGroup(&commits, ci, labelMap)
+			if group != nil && !group.Hidden {
+				gac := groupAndCommits[group]
 				if gac == nil {
 					gac = &GroupAndCommitInfos{
 						group:   group,
 						commits: []string{},
 					}
-					groupAndCommits[group.Order] = gac
+					groupAndCommits[group] = gac
+					groups = append(groups, gac)
 				}
 				gac.commits = append(gac.commits, description)
+				gac.scopes = append(gac.scopes, ci.Feature)
+				gac.keys = append(gac.keys, opts.entrySortKey(index, &commits, ci, stats))
+				gac.entries = append(gac.entries, entry)
 			}
 			commitInfos = append(commitInfos, ci)
 		}
@@ -139,46 +532,169 @@ func GenerateMarkdown(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository
 	prs := releaseSpec.PullRequests
 
 	var buffer bytes.Buffer
-	if len(commitInfos) == 0 && len(issues) == 0 && len(prs) == 0 {
+	if len(commitInfos) == 0 && len(issues) == 0 && len(prs) == 0 && len(opts.Reverted) == 0 {
 		return "", nil
 	}
 
-	buffer.WriteString("## Changes\n")
+	t := opts.Translations
+	if partials.overrides("header") {
+		text, err := partials.render("header", partials.changelog)
+		if err != nil {
+			return "", err
+		}
+		buffer.WriteString(text)
+	} else {
+		buffer.WriteString("## " + t.Translate("Changes") + "\n")
+		if opts.Compare != nil && opts.ComparePosition == CompareLinkHeader {
+			buffer.WriteString("\n" + describeCompare(opts.Compare, t))
+		}
+
+		if len(breakingChanges) > 0 {
+			buffer.WriteString("\n### " + opts.breakingChangesTitle() + "\n\n")
+			for _, msg := range breakingChanges {
+				buffer.WriteString(msg)
+			}
+		}
+
+		if len(opts.SecurityAdvisories) > 0 {
+			buffer.WriteString("\n### " + opts.securityAdvisoriesTitle() + "\n\n")
+			for i := range opts.SecurityAdvisories {
+				buffer.WriteString("* " + describeSecurityAdvisory(&opts.SecurityAdvisories[i], opts.Flavor) + "\n")
+			}
+		}
+	}
 
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].group.Order < groups[j].group.Order
+	})
+	if strategy := opts.sortStrategy(); strategy != "" {
+		for _, gac := range groups {
+			sort.Stable(byStrategy{gac: gac, strategy: strategy})
+		}
+	}
 	hasTitle := false
-	for i := 0; i <= unknownKindOrder; i++ {
-		gac := groupAndCommits[i]
-		if gac != nil && len(gac.commits) > 0 {
+	for _, gac := range groups {
+		if len(gac.commits) > 0 && partials.overrides("section") {
+			title := t.Translate(gac.group.Title)
+			if title == "" {
+				title = t.Translate("Other Changes")
+			}
+			text, err := partials.render("section", &Section{
+				Title:     title,
+				Emoji:     opts.sectionEmoji(gac.group),
+				Collapsed: opts.collapsed(gac.group) && opts.Flavor.details(),
+				Entries:   gac.entries,
+			})
+			if err != nil {
+				return "", err
+			}
+			buffer.WriteString(text)
+			continue
+		}
+		if len(gac.commits) > 0 {
 			group := gac.group
 			if group != nil {
 				legend := ""
+				title := t.Translate(group.Title)
 				buffer.WriteString("\n")
-				if group.Title == "" && hasTitle {
-					group.Title = "Other Changes"
+				if title == "" && hasTitle {
+					title = t.Translate("Other Changes")
 					legend = "These commits did not use [Conventional Commits](https://conventionalcommits.org/) formatted messages:\n\n"
+					if opts.Convention == ConventionGitmoji {
+						legend = "These commits did not use [gitmoji](https://gitmoji.dev/) formatted messages:\n\n"
+					}
 				}
-				if group.Title != "" {
+				if title != "" {
 					hasTitle = true
-					buffer.WriteString("### " + group.Title + "\n\n" + legend)
+					if emoji := opts.sectionEmoji(group); emoji != "" {
+						title = emoji + " " + title
+					}
+					buffer.WriteString("### " + title + "\n\n" + legend)
 				}
 			}
-			previous := ""
-			for _, msg := range gac.commits {
-				if msg != previous {
-					buffer.WriteString(msg)
-					previous = msg
-				}
+			collapsed := opts.collapsed(group) && opts.Flavor.details()
+			if collapsed {
+				buffer.WriteString(fmt.Sprintf("<details>\n<summary>%d %s</summary>\n\n", len(gac.commits), t.Translate("commits")))
+			}
+			if opts.GroupByScope {
+				writeScopedCommits(&buffer, gac, opts)
+			} else {
+				writeCommits(&buffer, gac.commits)
+			}
+			if collapsed {
+				buffer.WriteString("\n</details>\n")
+			}
+		}
+	}
+
+	if len(opts.Reverted) > 0 {
+		buffer.WriteString("\n### " + t.Translate("Reverted") + "\n\n")
+		for i := range opts.Reverted {
+			cs := &opts.Reverted[i].Commit
+			buffer.WriteString("* " + describeCommit(gitInfo, cs, opts.parseCommit(cs.Message), issueMap, prMap, opts) + "\n")
+		}
+	}
+
+	avatars := ""
+	if opts.Avatars {
+		avatars = describeAvatars(gitInfo, releaseSpec, opts)
+	}
+	if partials.overrides("contributors") {
+		text, err := partials.render("contributors", partials.changelog)
+		if err != nil {
+			return "", err
+		}
+		buffer.WriteString(text)
+	} else if avatars != "" || len(opts.ContributorSummary) > 0 {
+		buffer.WriteString("\n### " + t.Translate("Contributors") + "\n\n")
+		if avatars != "" {
+			buffer.WriteString(avatars + "\n")
+		}
+		if len(opts.ContributorSummary) > 0 {
+			if avatars != "" {
+				buffer.WriteString("\n")
 			}
+			buffer.WriteString(describeContributorSummary(gitInfo, opts.ContributorSummary, opts.Flavor))
+		}
+	}
+
+	if len(opts.FirstTimeContributors) > 0 {
+		buffer.WriteString("\n### " + t.Translate("New Contributors") + "\n\n")
+		for i := range opts.FirstTimeContributors {
+			buffer.WriteString("* " + describeUserLink(gitInfo, &opts.FirstTimeContributors[i], opts.Flavor) + " " + t.Translate("made their first contribution") + "\n")
+		}
+	}
+
+	if len(opts.Reviewers) > 0 {
+		buffer.WriteString("\n### " + t.Translate("Reviewers") + "\n\n")
+		for i := range opts.Reviewers {
+			reviewer := &opts.Reviewers[i]
+			text := describeUserLink(gitInfo, reviewer, opts.Flavor)
+			if isApprover(opts.Approvers, reviewer) {
+				text += " (" + t.Translate("approved") + ")"
+			}
+			buffer.WriteString("* " + text + "\n")
+		}
+	}
+
+	var fixedIssues []string
+	if opts.FixedIssues {
+		fixedIssues = describeFixedIssues(gitInfo, releaseSpec, issueMap, opts.Flavor)
+	}
+	if len(fixedIssues) > 0 {
+		buffer.WriteString("\n### " + t.Translate("Issues Fixed") + "\n\n")
+		for _, msg := range fixedIssues {
+			buffer.WriteString("* " + msg + "\n")
 		}
 	}
 
 	if len(issues) > 0 {
-		buffer.WriteString("\n### Issues\n\n")
+		buffer.WriteString("\n### " + t.Translate("Issues") + "\n\n")
 
 		previous := ""
 		for _, issue := range issues {
 			i := issue
-			msg := describeIssue(gitInfo, &i)
+			msg := describeIssue(gitInfo, &i, opts.Flavor)
 			if msg != previous {
 				buffer.WriteString("* " + msg + "\n")
 				previous = msg
@@ -186,12 +702,12 @@ func GenerateMarkdown(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository
 		}
 	}
 	if len(prs) > 0 {
-		buffer.WriteString("\n### Pull Requests\n\n")
+		buffer.WriteString("\n### " + t.Translate("Pull Requests") + "\n\n")
 
 		previous := ""
 		for _, pr := range prs {
 			pullRequest := pr
-			msg := describeIssue(gitInfo, &pullRequest)
+			msg := describeIssue(gitInfo, &pullRequest, opts.Flavor)
 			if msg != previous {
 				buffer.WriteString("* " + msg + "\n")
 				previous = msg
@@ -199,8 +715,25 @@ func GenerateMarkdown(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository
 		}
 	}
 
+	if len(releaseSpec.DependencyUpdates) > 0 || len(dependencyCommits) > 0 {
+		buffer.WriteString("\n### " + t.Translate("Dependency Updates") + "\n\n")
+	}
+	if len(dependencyCommits) > 0 {
+		updates := describeDependencyUpdates(gitInfo, dependencyCommits, opts.RollUpDependencyUpdates, opts)
+		if opts.Flavor.details() {
+			buffer.WriteString(fmt.Sprintf("<details>\n<summary>%d %s</summary>\n\n", len(updates), t.Translate("dependency updates")))
+		}
+		for _, msg := range updates {
+			buffer.WriteString("* " + msg + "\n")
+		}
+		if opts.Flavor.details() {
+			buffer.WriteString("\n</details>\n")
+		}
+		if len(releaseSpec.DependencyUpdates) > 0 {
+			buffer.WriteString("\n")
+		}
+	}
 	if len(releaseSpec.DependencyUpdates) > 0 {
-		buffer.WriteString("\n### Dependency Updates\n\n")
 		var previous v1.DependencyUpdate
 		sequence := make([]v1.DependencyUpdate, 0)
 		buffer.WriteString("| Dependency | Component | New Version | Old Version |\n")
@@ -220,31 +753,86 @@ func GenerateMarkdown(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository
 			previous = du
 		}
 	}
+	if opts.MentionContributors {
+		mentions := describeMentions(gitInfo, MentionedContributors(releaseSpec.Commits, opts.CoAuthors, opts.Bots), opts)
+		if mentions != "" {
+			buffer.WriteString("\n" + mentions)
+		}
+	}
+	if opts.DCO != nil {
+		buffer.WriteString(describeDCOReport(opts.DCO, t))
+	}
+	if partials.overrides("footer") {
+		text, err := partials.render("footer", partials.changelog)
+		if err != nil {
+			return "", err
+		}
+		buffer.WriteString(text)
+	} else if opts.Compare != nil && opts.ComparePosition != CompareLinkHeader {
+		buffer.WriteString("\n" + describeCompare(opts.Compare, t))
+	}
 	return buffer.String(), nil
 }
 
-func describeIssue(info *giturl.GitRepository, issue *v1.IssueSummary) string {
-	return describeIssueShort(issue) + issue.Title + describeUser(info, issue.User)
+// partialRenderer returns the renderer of the partial templates creating the changelog they are executed with if
+// required. Returns nil if there are no partial templates
+func (o *MarkdownOptions) partialRenderer(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository) (*partialRenderer, error) {
+	if len(o.Partials) == 0 {
+		return nil, nil
+	}
+	changelog := o.Changelog
+	if changelog == nil {
+		changelog = CreateChangelog(releaseSpec, gitInfo, time.Now(), o)
+	}
+	return newPartialRenderer(changelog, o.Partials)
+}
+
+func describeIssue(info *giturl.GitRepository, issue *v1.IssueSummary, flavor *MarkdownFlavor) string {
+	return describeIssueShort(issue, flavor) + flavor.escape(issue.Title, issue.ID) + describeUser(info, issue.User, flavor)
 }
 
-func describeIssueShort(issue *v1.IssueSummary) string {
+func describeIssueShort(issue *v1.IssueSummary, flavor *MarkdownFlavor) string {
 	prefix := ""
 	id := issue.ID
 	if len(id) > 0 {
 		// lets only add the hash prefix for numeric ids
 		_, err := strconv.Atoi(id)
 		if err == nil {
-			prefix = "#"
+			prefix = flavor.issuePrefix(issue.URL)
 		}
 	}
 	return "[" + prefix + issue.ID + "](" + issue.URL + ") "
 }
 
-func describeUser(info *giturl.GitRepository, user *v1.UserDetails) string {
-	answer := ""
+func describeUser(info *giturl.GitRepository, user *v1.UserDetails, flavor *MarkdownFlavor) string {
+	answer := describeUserLink(info, user, flavor)
+	if answer != "" {
+		answer = " (" + answer + ")"
+	}
+	return answer
+}
+
+func describeUsers(info *giturl.GitRepository, users []*v1.UserDetails, flavor *MarkdownFlavor) string {
+	var links []string
+	for _, user := range users {
+		link := describeUserLink(info, user, flavor)
+		if link != "" && stringhelpers.StringArrayIndex(links, link) < 0 {
+			links = append(links, link)
+		}
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(links, ", ") + ")"
+}
+
+func describeUserLink(info *giturl.GitRepository, user *v1.UserDetails, flavor *MarkdownFlavor) string {
+	userText := ""
 	if user != nil {
-		userText := ""
 		login := user.Login
+		if login != "" && flavor.mention() {
+			return "@" + login
+		}
 		url := user.URL
 		label := login
 		if label == "" {
@@ -253,6 +841,7 @@ func describeUser(info *giturl.GitRepository, user *v1.UserDetails) string {
 		if url == "" && login != "" {
 			url = stringhelpers.UrlJoin(info.HostURL(), login)
 		}
+		label = flavor.escape(label)
 		if url == "" {
 			userText = label
 		} else {
@@ -260,32 +849,263 @@ func describeUser(info *giturl.GitRepository, user *v1.UserDetails) string {
 				userText = "[" + label + "](" + url + ")"
 			}
 		}
-		if userText != "" {
-			answer = " (" + userText + ")"
+	}
+	return userText
+}
+
+func isApprover(approvers []v1.UserDetails, user *v1.UserDetails) bool {
+	for _, approver := range approvers {
+		if approver.Login == user.Login && approver.Name == user.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// describeAvatars returns the avatar image links of the authors and co-authors of the commits
+func describeAvatars(info *giturl.GitRepository, releaseSpec *v1.ReleaseSpec, opts *MarkdownOptions) string {
+	var avatars []string
+	found := map[string]bool{}
+	addAvatar := func(user *v1.UserDetails) {
+		if user == nil || user.AvatarURL == "" || user.Login == "" || found[user.Login] {
+			return
+		}
+		found[user.Login] = true
+		url := user.URL
+		if url == "" {
+			url = stringhelpers.UrlJoin(info.HostURL(), user.Login)
+		}
+		avatars = append(avatars, "[!["+user.Login+"]("+user.AvatarURL+")]("+url+")")
+	}
+	for i := range releaseSpec.Commits {
+		cs := &releaseSpec.Commits[i]
+		addAvatar(cs.Author)
+		for j := range opts.CoAuthors[cs.SHA] {
+			addAvatar(&opts.CoAuthors[cs.SHA][j])
+		}
+	}
+	return strings.Join(avatars, " ")
+}
+
+// describeContributorSummary returns a table of the number of commits of each contributor
+func describeContributorSummary(info *giturl.GitRepository, summary []*users.ContributorStats, flavor *MarkdownFlavor) string {
+	var buffer strings.Builder
+	buffer.WriteString("| Contributor | Commits | First Commit | Last Commit |\n")
+	buffer.WriteString("| --- | --- | --- | --- |\n")
+	for _, s := range summary {
+		buffer.WriteString(fmt.Sprintf("| %s | %d | %s | %s |\n", describeUserLink(info, &s.User, flavor), s.Commits,
+			s.FirstCommit.Format("2006-01-02"), s.LastCommit.Format("2006-01-02")))
+	}
+	return buffer.String()
+}
+
+// describeCommitBody returns the markdown escaped body of the commit message without its trailers
+// indented so that it renders under the bullet of the commit
+func describeCommitBody(message string, maxLength int) string {
+	body := conventional.Parse(message).Body
+	if maxLength > 0 {
+		runes := []rune(body)
+		if len(runes) > maxLength {
+			body = strings.TrimSpace(string(runes[:maxLength])) + "…"
+		}
+	}
+	if body == "" {
+		return ""
+	}
+	var buffer strings.Builder
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			buffer.WriteString("\n")
+			continue
+		}
+		buffer.WriteString("  " + escapeMarkdown(line) + "\n")
+	}
+	return "\n" + buffer.String()
+}
+
+// markdownEscaper escapes the characters which could change the structure of the changelog
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", "\\*",
+	"_", "\\_",
+	"[", "\\[",
+	"]", "\\]",
+	"<", "\\<",
+	">", "\\>",
+	"#", "\\#",
+	"|", "\\|",
+)
+
+// escapeMarkdown escapes the markdown formatting characters in the text
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// describeFixedIssues describes the issues closed by the Fixes, Closes or Resolves trailers of the commits
+func describeFixedIssues(info *giturl.GitRepository, releaseSpec *v1.ReleaseSpec, issueMap map[string]*v1.IssueSummary, flavor *MarkdownFlavor) []string {
+	var answer []string
+	var refs []string
+	for i := range releaseSpec.Commits {
+		for _, ref := range conventional.ParseTrailers(releaseSpec.Commits[i].Message).Fixes {
+			if stringhelpers.StringArrayIndex(refs, ref) >= 0 {
+				continue
+			}
+			refs = append(refs, ref)
+			issue := issueMap[strings.TrimPrefix(ref, "#")]
+			if issue != nil {
+				answer = append(answer, describeIssue(info, issue, flavor))
+			} else {
+				answer = append(answer, describeIssueRef(info, ref))
+			}
 		}
 	}
 	return answer
 }
 
-func describeCommit(info *giturl.GitRepository, cs *v1.CommitSummary, ci *CommitInfo, issueMap map[string]*v1.IssueSummary) string {
+// describeIssueRef returns a link to the issue reference such as '#123' or 'owner/repo#123' if possible
+func describeIssueRef(info *giturl.GitRepository, ref string) string {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return "[" + ref + "](" + ref + ")"
+	}
+	idx := strings.Index(ref, "#")
+	if idx < 0 || info == nil || info.Host == "" {
+		return ref
+	}
+	repo := ref[:idx]
+	if repo == "" {
+		repo = info.Organisation + "/" + info.Name
+	}
+	return "[" + ref + "](" + stringhelpers.UrlJoin(info.HostURL(), repo, "issues", ref[idx+1:]) + ")"
+}
+
+// describeBreakingChange describes the breaking change of the commit
+func describeBreakingChange(info *giturl.GitRepository, cs *v1.CommitSummary, ci *CommitInfo, opts *MarkdownOptions) string {
+	return opts.Flavor.escape(breakingChangeText(ci), cs.IssueIDs...) + describeUsers(info, commitAuthors(cs, opts), opts.Flavor)
+}
+
+// breakingChangeText returns the single line description of the breaking change prefixed with the scope
+func breakingChangeText(ci *CommitInfo) string {
 	prefix := ""
 	if ci.Feature != "" {
 		prefix = ci.Feature + ": "
 	}
-	message := strings.TrimSpace(ci.Message)
-	lines := strings.Split(message, "\n")
+	return prefix + strings.Join(strings.Fields(ci.BreakingChange), " ")
+}
 
-	// TODO add link to issue etc...
+// commitAuthors returns the author, or the committer if there is no author, and the co-authors of the commit
+func commitAuthors(cs *v1.CommitSummary, opts *MarkdownOptions) []*v1.UserDetails {
 	user := cs.Author
 	if user == nil {
 		user = cs.Committer
 	}
+	authors := []*v1.UserDetails{user}
+	for i := range opts.CoAuthors[cs.SHA] {
+		authors = append(authors, &opts.CoAuthors[cs.SHA][i])
+	}
+	return authors
+}
+
+func describeCommit(info *giturl.GitRepository, cs *v1.CommitSummary, ci *CommitInfo, issueMap, prMap map[string]*v1.IssueSummary, opts *MarkdownOptions) string {
+	prefix := ""
+	if ci.Feature != "" && !opts.GroupByScope {
+		prefix = opts.Flavor.escape(ci.Feature) + ": "
+	}
+	message := strings.TrimSpace(ci.Message)
+	lines := strings.Split(message, "\n")
+
+	// TODO add link to issue etc...
+	authors := commitAuthors(cs, opts)
+	pr := opts.entryPullRequest(cs, prMap)
 	issueText := ""
 	for _, issueId := range cs.IssueIDs {
+		if pr != nil && issueId == pr.ID {
+			continue
+		}
 		issue := issueMap[issueId]
 		if issue != nil {
-			issueText += " " + describeIssueShort(issue)
+			issueText += " " + describeIssueShort(issue, opts.Flavor)
+		}
+	}
+	subject := opts.Flavor.escape(trimPullRequestSuffix(lines[0], pr), cs.IssueIDs...)
+	return prefix + subject + describeEntryLink(info, cs, pr, opts) + describeUsers(info, authors, opts.Flavor) + issueText
+}
+
+// issueLabels returns the label names of the issues and pull requests of the release indexed by their ID
+func issueLabels(releaseSpec *v1.ReleaseSpec) map[string][]string {
+	answer := map[string][]string{}
+	for _, list := range [][]v1.IssueSummary{releaseSpec.Issues, releaseSpec.PullRequests} {
+		for i := range list {
+			for _, label := range list[i].Labels {
+				answer[list[i].ID] = append(answer[list[i].ID], label.Name)
+			}
+		}
+	}
+	return answer
+}
+
+// writeCommits writes the commit descriptions skipping consecutive duplicates
+func writeCommits(buffer *bytes.Buffer, commits []string) {
+	previous := ""
+	for _, msg := range commits {
+		if msg != previous {
+			buffer.WriteString(msg)
+			previous = msg
+		}
+	}
+}
+
+// writeScopedCommits writes the commit descriptions of the group under a subheading for each scope. The scopes of the
+// scope order come first then the other scopes by name and then the commits without a scope
+func writeScopedCommits(buffer *bytes.Buffer, gac *GroupAndCommitInfos, opts *MarkdownOptions) {
+	var scopes []string
+	titles := map[string]string{}
+	scopeCommits := map[string][]string{}
+	for i, msg := range gac.commits {
+		key := strings.ToLower(gac.scopes[i])
+		if _, ok := scopeCommits[key]; !ok {
+			scopes = append(scopes, key)
+			titles[key] = gac.scopes[i]
+		}
+		scopeCommits[key] = append(scopeCommits[key], msg)
+	}
+	if len(scopes) == 1 && scopes[0] == "" {
+		writeCommits(buffer, gac.commits)
+		return
+	}
+	order := map[string]int{}
+	for i, scope := range opts.ScopeOrder {
+		order[strings.ToLower(scope)] = i + 1
+	}
+	rank := func(scope string) int {
+		if scope == "" {
+			return len(opts.ScopeOrder) + 2
+		}
+		if o, ok := order[scope]; ok {
+			return o
+		}
+		return len(opts.ScopeOrder) + 1
+	}
+	sort.SliceStable(scopes, func(i, j int) bool {
+		ri, rj := rank(scopes[i]), rank(scopes[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return scopes[i] < scopes[j]
+	})
+	for i, scope := range scopes {
+		title := titles[scope]
+		if scope == "" {
+			title = opts.UnscopedTitle
+			if title == "" {
+				title = opts.Translations.Translate("Other")
+			}
+		}
+		if i > 0 {
+			buffer.WriteString("\n")
 		}
+		buffer.WriteString("#### " + opts.Flavor.escape(title) + "\n\n")
+		writeCommits(buffer, scopeCommits[scope])
 	}
-	return prefix + lines[0] + describeUser(info, user) + issueText
 }
@@ -4,8 +4,10 @@ package gits_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
 	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
 	"github.com/stretchr/testify/assert"
@@ -114,3 +116,248 @@ These commits did not use [Conventional Commits](https://conventionalcommits.org
 `
 	assert.Equal(t, expectedMarkdown, markdown)
 }
+
+func TestChangelogMarkdownWithAvatarsAndCoAuthors(t *testing.T) {
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{
+				Message: "fix: some commit 1\n\nCo-authored-by: James Rawlings <rawlingsj@example.com>",
+				SHA:     "123",
+				Author: &v1.UserDetails{
+					Name:      "James Strachan",
+					Login:     "jstrachan",
+					AvatarURL: "https://avatars.example.com/jstrachan",
+				},
+			},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	opts := &gits.MarkdownOptions{
+		Avatars: true,
+		CoAuthors: map[string][]v1.UserDetails{
+			"123": {
+				{
+					Name:      "James Rawlings",
+					Login:     "rawlingsj",
+					AvatarURL: "https://avatars.example.com/rawlingsj",
+				},
+			},
+		},
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	assert.Nil(t, err)
+
+	expectedMarkdown := `## Changes
+
+### Bug Fixes
+
+* some commit 1 ([jstrachan](https://github.com/jstrachan), [rawlingsj](https://github.com/rawlingsj))
+
+### Contributors
+
+[![jstrachan](https://avatars.example.com/jstrachan)](https://github.com/jstrachan) [![rawlingsj](https://avatars.example.com/rawlingsj)](https://github.com/rawlingsj)
+`
+	assert.Equal(t, expectedMarkdown, markdown)
+}
+
+func TestChangelogMarkdownWithContributorSummary(t *testing.T) {
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{
+				Message: "fix: some commit 1",
+				SHA:     "123",
+				Author: &v1.UserDetails{
+					Name:  "James Strachan",
+					Login: "jstrachan",
+				},
+			},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	opts := &gits.MarkdownOptions{
+		ContributorSummary: []*users.ContributorStats{
+			{
+				User: v1.UserDetails{
+					Name:  "James Strachan",
+					Login: "jstrachan",
+				},
+				Commits:     2,
+				FirstCommit: time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC),
+				LastCommit:  time.Date(2021, time.March, 5, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	assert.Nil(t, err)
+
+	expectedMarkdown := `## Changes
+
+### Bug Fixes
+
+* some commit 1 ([jstrachan](https://github.com/jstrachan))
+
+### Contributors
+
+| Contributor | Commits | First Commit | Last Commit |
+| --- | --- | --- | --- |
+| [jstrachan](https://github.com/jstrachan) | 2 | 2021-03-01 | 2021-03-05 |
+`
+	assert.Equal(t, expectedMarkdown, markdown)
+}
+
+func TestChangelogMarkdownWithBreakingChanges(t *testing.T) {
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{
+				Message: "feat(api)!: remove the v1 API",
+				SHA:     "123",
+				Author: &v1.UserDetails{
+					Login: "jstrachan",
+				},
+			},
+			{
+				Message: "fix: use the new config format\n\nBREAKING CHANGE: the config file\nmust be YAML",
+				SHA:     "456",
+				Author: &v1.UserDetails{
+					Login: "rawlingsj",
+				},
+			},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdown(releaseSpec, gitInfo)
+	assert.Nil(t, err)
+
+	expectedMarkdown := `## Changes
+
+### ⚠ Breaking Changes
+
+* api: remove the v1 API ([jstrachan](https://github.com/jstrachan))
+* the config file must be YAML ([rawlingsj](https://github.com/rawlingsj))
+
+### New Features
+
+* api: remove the v1 API ([jstrachan](https://github.com/jstrachan))
+
+### Bug Fixes
+
+* use the new config format ([rawlingsj](https://github.com/rawlingsj))
+`
+	assert.Equal(t, expectedMarkdown, markdown)
+}
+
+func TestChangelogMarkdownWithFixedIssues(t *testing.T) {
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{
+				Message:  "fix: the cork\n\nFixes #123\nCloses: jenkins-x/jx#7",
+				SHA:      "123",
+				IssueIDs: []string{"123"},
+			},
+			{
+				Message: "fix: the bottle\n\nFixes: 123",
+				SHA:     "456",
+			},
+		},
+		Issues: []v1.IssueSummary{
+			{
+				ID:    "123",
+				URL:   "https://github.com/jstrachan/foo/issues/123",
+				Title: "the cork is stuck",
+			},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{FixedIssues: true})
+	assert.Nil(t, err)
+
+	expectedMarkdown := `## Changes
+
+### Bug Fixes
+
+* the cork [#123](https://github.com/jstrachan/foo/issues/123) 
+* the bottle
+
+### Issues Fixed
+
+* [#123](https://github.com/jstrachan/foo/issues/123) the cork is stuck
+* [jenkins-x/jx#7](https://github.com/jenkins-x/jx/issues/7)
+
+### Issues
+
+* [#123](https://github.com/jstrachan/foo/issues/123) the cork is stuck
+`
+	assert.Equal(t, expectedMarkdown, markdown)
+
+	markdown, err = gits.GenerateMarkdown(releaseSpec, gitInfo)
+	assert.Nil(t, err)
+	assert.NotContains(t, markdown, "Issues Fixed", "the fixed issues should only be rendered if enabled")
+}
+
+func TestChangelogMarkdownWithCommitBody(t *testing.T) {
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{
+				Message: "fix: the cork\n\nThe *cork* was stuck in the #1 bottle.\n\nIt now uses a_screw.\n\nSigned-off-by: James <james@example.com>",
+				SHA:     "123",
+			},
+			{
+				Message: "fix: the bottle\n\nThis is a very long description",
+				SHA:     "456",
+			},
+			{
+				Message: "fix: the glass",
+				SHA:     "789",
+			},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+		CommitBody:          true,
+		CommitBodyMaxLength: 60,
+	})
+	assert.Nil(t, err)
+
+	expectedMarkdown := `## Changes
+
+### Bug Fixes
+
+* the cork
+
+  The \*cork\* was stuck in the \#1 bottle.
+
+  It now uses a\_screw.
+* the bottle
+
+  This is a very long description
+* the glass
+`
+	assert.Equal(t, expectedMarkdown, markdown)
+
+	markdown, err = gits.GenerateMarkdownWithOptions(&v1.ReleaseSpec{Commits: releaseSpec.Commits[1:2]}, gitInfo, &gits.MarkdownOptions{
+		CommitBody:          true,
+		CommitBodyMaxLength: 15,
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, markdown, "  This is a very…\n")
+}
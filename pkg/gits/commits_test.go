@@ -6,7 +6,10 @@ import (
 	"testing"
 
 	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseCommits(t *testing.T) {
@@ -18,6 +21,13 @@ func TestParseCommits(t *testing.T) {
 		Kind:    "feat",
 		Message: "cheese",
 	})
+	assertParseCommit(t, "feat(beer)!: wine is good too\n\nBREAKING CHANGE: beer is gone", &gits.CommitInfo{
+		Kind:           "feat",
+		Feature:        "beer",
+		Message:        "wine is good too\n\nBREAKING CHANGE: beer is gone",
+		Breaking:       true,
+		BreakingChange: "beer is gone",
+	})
 	assertParseCommit(t, "feat(beer): wine is good too", &gits.CommitInfo{
 		Kind:    "feat",
 		Feature: "beer",
@@ -33,3 +43,58 @@ func assertParseCommit(t *testing.T, input string, expected *gits.CommitInfo) {
 	assert.Equal(t, expected.Message, info.Message, "Message for Commit %s", info)
 	assert.Equal(t, expected, info, "CommitInfo for Commit %s", info)
 }
+
+func TestBreakingChanges(t *testing.T) {
+	t.Parallel()
+	commits := []v1.CommitSummary{
+		{Message: "feat(api)!: remove the v1 API"},
+		{Message: "fix: the cork\n\nBREAKING CHANGE: corks are\nno longer supported"},
+		{Message: "feat(api)!: remove the v1 API"},
+		{Message: "fix: the bottle"},
+		{Message: ":boom: drop wine"},
+	}
+	assert.Equal(t, []string{"api: remove the v1 API", "corks are no longer supported"}, gits.BreakingChanges(commits, gits.ConventionConventional))
+	assert.Equal(t, []string{"drop wine"}, gits.BreakingChanges(commits, gits.ConventionGitmoji))
+}
+
+func TestPullRequestTitles(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "3", Message: "wip", IssueIDs: []string{"12"}},
+			{SHA: "2", Message: "fix: the cork", IssueIDs: []string{"13"}},
+			{SHA: "1", Message: "feat: more wine", IssueIDs: []string{"12"}},
+		},
+		PullRequests: []v1.IssueSummary{
+			{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12", Title: "feat(cellar): add wine"},
+			{ID: "13", URL: "https://github.com/jstrachan/foo/pull/13", Title: "Cork improvements"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+		LinkPullRequests:  true,
+		PullRequestTitles: true,
+	})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### New Features
+
+* cellar: add wine [#12](https://github.com/jstrachan/foo/pull/12) 
+
+### Bug Fixes
+
+* Cork improvements [#13](https://github.com/jstrachan/foo/pull/13) 
+
+### Pull Requests
+
+* [#12](https://github.com/jstrachan/foo/pull/12) feat(cellar): add wine
+* [#13](https://github.com/jstrachan/foo/pull/13) Cork improvements
+`
+	assert.Equal(t, expected, markdown)
+}
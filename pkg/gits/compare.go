@@ -0,0 +1,79 @@
+package gits
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/azure"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+)
+
+const (
+	// CompareLinkHeader renders the compare link below the title of the changelog
+	CompareLinkHeader = "header"
+
+	// CompareLinkFooter renders the compare link at the bottom of the changelog
+	CompareLinkFooter = "footer"
+)
+
+// CompareLinkPositions the supported positions of the compare link
+var CompareLinkPositions = []string{CompareLinkHeader, CompareLinkFooter}
+
+// Compare a link to the comparison of the previous and current release on the git provider
+type Compare struct {
+	// From the previous tag or revision
+	From string `json:"from"`
+	// To the current tag or revision
+	To string `json:"to"`
+	// URL the URL of the comparison on the git provider
+	URL string `json:"url"`
+}
+
+// shaRegex matches full commit SHAs
+var shaRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// NewCompare returns the comparison of the two revisions on the git provider of the given kind, detecting the kind
+// from the host if it is empty. Returns nil if the revisions or repository are unknown
+func NewCompare(info *giturl.GitRepository, kind, from, to string) *Compare {
+	if info == nil || info.Host == "" || info.Organisation == "" || info.Name == "" || from == "" || to == "" {
+		return nil
+	}
+	if kind == "" {
+		kind = giturl.SaasGitKind(info.HostURL())
+		if kind == "" && strings.Contains(info.Host, "gitlab") {
+			kind = giturl.KindGitlab
+		}
+	}
+	repoURL := info.HttpsURL()
+	var link string
+	switch kind {
+	case giturl.KindGitlab:
+		link = stringhelpers.UrlJoin(repoURL, "-", "compare", from+"..."+to)
+	case giturl.KindBitBucketCloud:
+		link = stringhelpers.UrlJoin(repoURL, "branches", "compare", url.PathEscape(to)+"%0D"+url.PathEscape(from))
+	case giturl.KindBitBucketServer:
+		link = stringhelpers.UrlJoin(info.HostURL(), "projects", info.Organisation, "repos", info.Name, "compare", "commits") +
+			"?sourceBranch=" + url.QueryEscape(to) + "&targetBranch=" + url.QueryEscape(from)
+	case azure.GitKind:
+		link = stringhelpers.UrlJoin(repoURL, "branchCompare") +
+			"?baseVersion=" + url.QueryEscape(azureVersion(from)) + "&targetVersion=" + url.QueryEscape(azureVersion(to))
+	default:
+		link = stringhelpers.UrlJoin(repoURL, "compare", from+"..."+to)
+	}
+	return &Compare{From: from, To: to, URL: link}
+}
+
+// azureVersion returns the Azure DevOps version descriptor of the tag or commit
+func azureVersion(rev string) string {
+	if shaRegex.MatchString(rev) {
+		return "GC" + rev
+	}
+	return "GT" + rev
+}
+
+// describeCompare describes the link to the comparison of the releases
+func describeCompare(compare *Compare, t Translations) string {
+	return "**" + t.Translate("Full Changelog") + "**: [" + compare.From + "..." + compare.To + "](" + compare.URL + ")\n"
+}
@@ -0,0 +1,112 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/azure"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCompare(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		host     string
+		kind     string
+		expected string
+	}{
+		{
+			host:     "github.com",
+			expected: "https://github.com/jstrachan/foo/compare/v1.2.0...v1.3.0",
+		},
+		{
+			host:     "gitlab.com",
+			expected: "https://gitlab.com/jstrachan/foo/-/compare/v1.2.0...v1.3.0",
+		},
+		{
+			host:     "gitlab.example.com",
+			expected: "https://gitlab.example.com/jstrachan/foo/-/compare/v1.2.0...v1.3.0",
+		},
+		{
+			host:     "git.example.com",
+			kind:     giturl.KindGitlab,
+			expected: "https://git.example.com/jstrachan/foo/-/compare/v1.2.0...v1.3.0",
+		},
+		{
+			host:     "bitbucket.org",
+			expected: "https://bitbucket.org/jstrachan/foo/branches/compare/v1.3.0%0Dv1.2.0",
+		},
+		{
+			host:     "git.example.com",
+			kind:     giturl.KindGitea,
+			expected: "https://git.example.com/jstrachan/foo/compare/v1.2.0...v1.3.0",
+		},
+		{
+			host:     "dev.azure.com",
+			kind:     azure.GitKind,
+			expected: "https://dev.azure.com/jstrachan/foo/branchCompare?baseVersion=GTv1.2.0&targetVersion=GTv1.3.0",
+		},
+	}
+	for _, tc := range testCases {
+		info := &giturl.GitRepository{Host: tc.host, Organisation: "jstrachan", Name: "foo"}
+		compare := gits.NewCompare(info, tc.kind, "v1.2.0", "v1.3.0")
+		require.NotNil(t, compare, "host %s", tc.host)
+		assert.Equal(t, tc.expected, compare.URL, "host %s kind %s", tc.host, tc.kind)
+	}
+
+	assert.Nil(t, gits.NewCompare(&giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}, "", "", "v1.3.0"))
+}
+
+func TestCompareMarkdown(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "fix: the cork"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+	opts := &gits.MarkdownOptions{Compare: gits.NewCompare(gitInfo, "", "v1.2.0", "v1.3.0")}
+
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	expected := `## Changes
+
+### Bug Fixes
+
+* the cork
+
+**Full Changelog**: [v1.2.0...v1.3.0](https://github.com/jstrachan/foo/compare/v1.2.0...v1.3.0)
+`
+	assert.Equal(t, expected, markdown)
+}
+
+func TestCompareMarkdownInHeader(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "fix: the cork"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+	opts := &gits.MarkdownOptions{
+		Compare:         gits.NewCompare(gitInfo, "", "v1.2.0", "v1.3.0"),
+		ComparePosition: gits.CompareLinkHeader,
+	}
+
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	expected := `## Changes
+
+**Full Changelog**: [v1.2.0...v1.3.0](https://github.com/jstrachan/foo/compare/v1.2.0...v1.3.0)
+
+### Bug Fixes
+
+* the cork
+`
+	assert.Equal(t, expected, markdown)
+}
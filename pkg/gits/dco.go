@@ -0,0 +1,89 @@
+package gits
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// DCOReport the result of checking the commits of a release for the Developer Certificate of Origin sign offs
+type DCOReport struct {
+	// Total the number of checked commits
+	Total int `json:"total"`
+	// Violations the commits which are not signed off by their author
+	Violations []DCOViolation `json:"violations,omitempty"`
+}
+
+// DCOViolation a commit which is not signed off by its author
+type DCOViolation struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+	Author  string `json:"author,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// CheckDCO checks that each commit has a 'Signed-off-by' trailer matching the name or email of its author
+func CheckDCO(commits []*object.Commit) *DCOReport {
+	answer := &DCOReport{}
+	for _, commit := range commits {
+		answer.Total++
+		reason := dcoViolation(commit)
+		if reason != "" {
+			answer.Violations = append(answer.Violations, DCOViolation{
+				SHA:     commit.Hash.String(),
+				Subject: strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0],
+				Author:  commit.Author.Name,
+				Reason:  reason,
+			})
+		}
+	}
+	return answer
+}
+
+// Compliant returns true if all the commits are signed off
+func (r *DCOReport) Compliant() bool {
+	return r == nil || len(r.Violations) == 0
+}
+
+func dcoViolation(commit *object.Commit) string {
+	signOffs := conventional.ParseTrailers(commit.Message).SignedOffBy
+	if len(signOffs) == 0 {
+		return "no Signed-off-by trailer"
+	}
+	for _, signOff := range signOffs {
+		name, email := signOff, ""
+		address, err := mail.ParseAddress(signOff)
+		if err == nil {
+			name, email = address.Name, address.Address
+		}
+		if email != "" && strings.EqualFold(email, commit.Author.Email) {
+			return ""
+		}
+		if email == "" && name != "" && strings.EqualFold(strings.TrimSpace(name), commit.Author.Name) {
+			return ""
+		}
+	}
+	return "not signed off by the commit author"
+}
+
+// describeDCOReport describes the sign off compliance of the release
+func describeDCOReport(report *DCOReport, t Translations) string {
+	var buffer strings.Builder
+	buffer.WriteString("\n### " + t.Translate("Sign-off Compliance") + "\n\n")
+	if report.Compliant() {
+		buffer.WriteString(fmt.Sprintf("All %d commits are signed off according to the [Developer Certificate of Origin](https://developercertificate.org/).\n", report.Total))
+		return buffer.String()
+	}
+	buffer.WriteString(fmt.Sprintf("%d of %d commits are not signed off according to the [Developer Certificate of Origin](https://developercertificate.org/):\n\n", len(report.Violations), report.Total))
+	for _, v := range report.Violations {
+		sha := v.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		buffer.WriteString("* " + sha + " " + escapeMarkdown(v.Subject) + ": " + v.Reason + "\n")
+	}
+	return buffer.String()
+}
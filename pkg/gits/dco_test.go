@@ -0,0 +1,54 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestCheckDCO(t *testing.T) {
+	t.Parallel()
+	james := object.Signature{Name: "James Strachan", Email: "james@example.com"}
+	commits := []*object.Commit{
+		{Hash: plumbing.NewHash("1111111111"), Author: james, Message: "feat: cheese\n\nSigned-off-by: James Strachan <JAMES@example.com>"},
+		{Hash: plumbing.NewHash("2222222222"), Author: james, Message: "fix: the cork"},
+		{Hash: plumbing.NewHash("3333333333"), Author: james, Message: "fix: wine\n\nSigned-off-by: Someone <someone@example.com>"},
+		{Hash: plumbing.NewHash("4444444444"), Author: james, Message: "chore: tidy\n\nSigned-off-by: James Strachan"},
+	}
+
+	report := gits.CheckDCO(commits)
+	assert.Equal(t, 4, report.Total)
+	assert.False(t, report.Compliant())
+	require.Len(t, report.Violations, 2)
+	assert.Equal(t, "fix: the cork", report.Violations[0].Subject)
+	assert.Equal(t, "no Signed-off-by trailer", report.Violations[0].Reason)
+	assert.Equal(t, "fix: wine", report.Violations[1].Subject)
+	assert.Equal(t, "not signed off by the commit author", report.Violations[1].Reason)
+
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: commits[0].Hash.String(), Message: commits[0].Message},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{DCO: gits.CheckDCO(commits[:1])})
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "### Sign-off Compliance\n\nAll 1 commits are signed off")
+
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{DCO: report})
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "2 of 4 commits are not signed off")
+	assert.Contains(t, markdown, "* 2222222 fix: the cork: no Signed-off-by trailer\n")
+}
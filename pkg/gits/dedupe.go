@@ -0,0 +1,101 @@
+package gits
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+)
+
+const (
+	// DeduplicateExact collapses the commits with exactly the same subject
+	DeduplicateExact = "exact"
+
+	// DeduplicateNormalized collapses the commits with the same subject ignoring case, whitespace, trailing
+	// punctuation and trailing issue references such as '(#123)'
+	DeduplicateNormalized = "normalized"
+)
+
+// DeduplicateModes the supported ways of collapsing duplicate commits
+var DeduplicateModes = []string{DeduplicateExact, DeduplicateNormalized}
+
+var (
+	trailingIssueRefsRegex = regexp.MustCompile(`(\s*[(\[]?\s*(#\d+|[A-Z][A-Z0-9]+-\d+)\s*[)\]]?)+$`)
+	whitespaceRegex        = regexp.MustCompile(`\s+`)
+)
+
+// DeduplicateCommits collapses the commits with the same subject into the first of them. Returns the remaining commits
+// and the SHAs of all the commits with the same subject indexed by the SHA of the remaining commit if there are duplicates
+func DeduplicateCommits(commits []v1.CommitSummary, mode string) ([]v1.CommitSummary, map[string][]string) {
+	if mode == "" {
+		return commits, nil
+	}
+	var answer []v1.CommitSummary
+	duplicates := map[string][]string{}
+	indexes := map[string]int{}
+	for i := range commits {
+		cs := commits[i]
+		key := subjectKey(cs.Message, mode)
+		idx, ok := indexes[key]
+		if !ok || key == "" {
+			indexes[key] = len(answer)
+			answer = append(answer, cs)
+			continue
+		}
+		kept := &answer[idx]
+		if len(duplicates[kept.SHA]) == 0 {
+			duplicates[kept.SHA] = []string{kept.SHA}
+		}
+		duplicates[kept.SHA] = append(duplicates[kept.SHA], cs.SHA)
+		for _, id := range cs.IssueIDs {
+			if stringhelpers.StringArrayIndex(kept.IssueIDs, id) < 0 {
+				kept.IssueIDs = append(kept.IssueIDs, id)
+			}
+		}
+	}
+	return answer, duplicates
+}
+
+// subjectKey returns the key of the commit subject used to find duplicates
+func subjectKey(message, mode string) string {
+	subject := strings.TrimSpace(strings.SplitN(strings.TrimSpace(message), "\n", 2)[0])
+	if mode != DeduplicateNormalized {
+		return subject
+	}
+	subject = trailingIssueRefsRegex.ReplaceAllString(subject, "")
+	subject = whitespaceRegex.ReplaceAllString(subject, " ")
+	subject = strings.TrimRight(subject, " .!;,")
+	return strings.ToLower(subject)
+}
+
+// describeDuplicates describes the number of duplicate commits with links to each of them
+func describeDuplicates(info *giturl.GitRepository, shas []string) string {
+	var links []string
+	for _, sha := range shas {
+		short := sha
+		if len(short) > 7 {
+			short = short[:7]
+		}
+		link := commitURL(info, sha)
+		if link == "" {
+			links = append(links, short)
+			continue
+		}
+		links = append(links, "["+short+"]("+link+")")
+	}
+	return " (" + strconv.Itoa(len(shas)) + " commits: " + strings.Join(links, ", ") + ")"
+}
+
+// commitURL returns the URL of the commit on the git provider or an empty string if it is not known
+func commitURL(info *giturl.GitRepository, sha string) string {
+	if info == nil || info.Host == "" || info.Organisation == "" || info.Name == "" {
+		return ""
+	}
+	if strings.Contains(info.Host, "gitlab") {
+		return stringhelpers.UrlJoin(info.HttpsURL(), "-", "commit", sha)
+	}
+	return stringhelpers.UrlJoin(info.HttpsURL(), "commit", sha)
+}
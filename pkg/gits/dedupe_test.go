@@ -0,0 +1,67 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduplicateCommits(t *testing.T) {
+	t.Parallel()
+	commits := []v1.CommitSummary{
+		{SHA: "4444444444", Message: "fix: the cork"},
+		{SHA: "3333333333", Message: "fix: The cork. (#12)", IssueIDs: []string{"12"}},
+		{SHA: "2222222222", Message: "fix: the cork"},
+		{SHA: "1111111111", Message: "feat: wine"},
+	}
+
+	kept, duplicates := gits.DeduplicateCommits(commits, "")
+	assert.Len(t, kept, 4)
+	assert.Empty(t, duplicates)
+
+	kept, duplicates = gits.DeduplicateCommits(commits, gits.DeduplicateExact)
+	require.Len(t, kept, 3)
+	assert.Equal(t, []string{"4444444444", "2222222222"}, duplicates["4444444444"])
+
+	kept, duplicates = gits.DeduplicateCommits(commits, gits.DeduplicateNormalized)
+	require.Len(t, kept, 2)
+	assert.Equal(t, []string{"4444444444", "3333333333", "2222222222"}, duplicates["4444444444"])
+	assert.Equal(t, []string{"12"}, kept[0].IssueIDs)
+	assert.Equal(t, "1111111111", kept[1].SHA)
+}
+
+func TestDeduplicateMarkdown(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "4444444444", Message: "fix: the cork"},
+			{SHA: "2222222222", Message: "fix: the cork"},
+			{SHA: "1111111111", Message: "feat: wine"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{Deduplicate: gits.DeduplicateExact})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### New Features
+
+* wine
+
+### Bug Fixes
+
+* the cork (2 commits: [4444444](https://github.com/jstrachan/foo/commit/4444444444), [2222222](https://github.com/jstrachan/foo/commit/2222222222))
+`
+	assert.Equal(t, expected, markdown)
+}
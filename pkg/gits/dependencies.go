@@ -0,0 +1,111 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+)
+
+// DependencyScope the conventional commit scope of dependency updates
+const DependencyScope = "deps"
+
+var (
+	// DependencyBotPrefixes the login or name prefixes of the bots which update dependencies
+	DependencyBotPrefixes = []string{"dependabot", "renovate"}
+
+	dependencyBumpRegex   = regexp.MustCompile(`(?i)\b(?:bump|bumps|upgrade|update)\s+(?:module\s+|dependency\s+)?(\S+)\s+from\s+(\S+)\s+to\s+(\S+)`)
+	dependencyUpdateRegex = regexp.MustCompile(`(?i)\bupdate\s+(?:module|dependency)\s+(\S+)\s+to\s+(\S+)`)
+)
+
+// DependencyBump a dependency update described by a commit message
+type DependencyBump struct {
+	// Module the name of the updated module or dependency
+	Module string
+	// From the previous version if known
+	From string
+	// To the new version
+	To string
+}
+
+// ParseDependencyBump parses commit messages such as 'bump foo from 1.0.0 to 1.1.0' or 'update module foo to v1.1.0'.
+// Returns nil if the message does not describe a dependency update
+func ParseDependencyBump(message string) *DependencyBump {
+	subject := strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+	m := dependencyBumpRegex.FindStringSubmatch(subject)
+	if m != nil {
+		return &DependencyBump{Module: m[1], From: m[2], To: trimVersion(m[3])}
+	}
+	m = dependencyUpdateRegex.FindStringSubmatch(subject)
+	if m != nil {
+		return &DependencyBump{Module: m[1], To: trimVersion(m[2])}
+	}
+	return nil
+}
+
+// IsDependencyUpdate returns true if the commit is authored by a dependency bot, uses the 'deps' scope
+// or its message describes a dependency update
+func IsDependencyUpdate(cs *v1.CommitSummary, ci *CommitInfo) bool {
+	if strings.EqualFold(ci.Feature, DependencyScope) {
+		return true
+	}
+	for _, user := range []*v1.UserDetails{cs.Author, cs.Committer} {
+		if user != nil && (isDependencyBot(user.Login) || isDependencyBot(user.Name)) {
+			return true
+		}
+	}
+	return ParseDependencyBump(cs.Message) != nil
+}
+
+func isDependencyBot(name string) bool {
+	name = strings.ToLower(name)
+	for _, prefix := range DependencyBotPrefixes {
+		if name != "" && strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimVersion removes any punctuation following the version in the message
+func trimVersion(version string) string {
+	return strings.TrimRight(version, ".,;)")
+}
+
+// describeDependencyUpdates describes the dependency update commits. If rollUp is true multiple updates of the same
+// module are described by a single line from the oldest to the newest version. The commits are expected in git log
+// order with the newest commit first
+func describeDependencyUpdates(info *giturl.GitRepository, commits []v1.CommitSummary, rollUp bool, opts *MarkdownOptions) []string {
+	var answer []string
+	var modules []string
+	bumps := map[string]*DependencyBump{}
+	for i := range commits {
+		cs := &commits[i]
+		bump := ParseDependencyBump(cs.Message)
+		if !rollUp || bump == nil {
+			answer = append(answer, describeCommit(info, cs, opts.parseCommit(cs.Message), nil, nil, opts))
+			continue
+		}
+		existing := bumps[bump.Module]
+		if existing == nil {
+			copied := *bump
+			bumps[bump.Module] = &copied
+			modules = append(modules, bump.Module)
+			continue
+		}
+		// older commits come later so they have the earlier from version
+		if bump.From != "" {
+			existing.From = bump.From
+		}
+	}
+	for _, module := range modules {
+		bump := bumps[module]
+		if bump.From != "" {
+			answer = append(answer, module+": "+bump.From+" → "+bump.To)
+		} else {
+			answer = append(answer, module+": "+bump.To)
+		}
+	}
+	return answer
+}
@@ -0,0 +1,71 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDependencyBump(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, &gits.DependencyBump{Module: "github.com/foo/bar", From: "1.0.0", To: "1.1.0"},
+		gits.ParseDependencyBump("chore(deps): bump github.com/foo/bar from 1.0.0 to 1.1.0\n\nBumps [github.com/foo/bar](https://github.com/foo/bar) from 1.0.0 to 1.1.0."))
+	assert.Equal(t, &gits.DependencyBump{Module: "github.com/foo/bar", To: "v1.2.0"},
+		gits.ParseDependencyBump("fix(deps): update module github.com/foo/bar to v1.2.0"))
+	assert.Nil(t, gits.ParseDependencyBump("fix: the cork"))
+}
+
+func TestGroupDependencyUpdates(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "5", Message: "chore(deps): bump github.com/foo/bar from 1.1.0 to 1.2.0"},
+			{SHA: "4", Message: "fix: the cork"},
+			{SHA: "3", Message: "chore: bump github.com/foo/bar from 1.0.0 to 1.1.0", Author: &v1.UserDetails{Login: "dependabot[bot]"}},
+			{SHA: "2", Message: "chore(deps): tidy go.sum"},
+			{SHA: "1", Message: "fix(deps): update module github.com/foo/wine to v2.0.0", Author: &v1.UserDetails{Login: "renovate[bot]"}},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+		GroupDependencyUpdates:  true,
+		RollUpDependencyUpdates: true,
+	})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### Bug Fixes
+
+* the cork
+
+### Dependency Updates
+
+<details>
+<summary>3 dependency updates</summary>
+
+* deps: tidy go.sum
+* github.com/foo/bar: 1.0.0 → 1.2.0
+* github.com/foo/wine: v2.0.0
+
+</details>
+`
+	assert.Equal(t, expected, markdown)
+
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+		GroupDependencyUpdates: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "<summary>4 dependency updates</summary>")
+	assert.Contains(t, markdown, "* deps: bump github.com/foo/bar from 1.1.0 to 1.2.0\n")
+}
@@ -0,0 +1,102 @@
+package gits
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// DiffStats the number of files and lines changed by a commit
+type DiffStats struct {
+	FilesChanged int `json:"filesChanged"`
+	Insertions   int `json:"insertions"`
+	Deletions    int `json:"deletions"`
+}
+
+// Impact returns the number of changed lines
+func (s *DiffStats) Impact() int {
+	if s == nil {
+		return 0
+	}
+	return s.Insertions + s.Deletions
+}
+
+// String returns the insertions and deletions such as '+4,200/−3,800'
+func (s *DiffStats) String() string {
+	if s == nil {
+		return ""
+	}
+	return "+" + formatThousands(s.Insertions) + "/−" + formatThousands(s.Deletions)
+}
+
+// DiffStatsCache computes the diff statistics of the commits of a repository on demand caching the results
+// as they are expensive to compute on large repositories
+type DiffStatsCache struct {
+	gitDir string
+	lock   sync.Mutex
+	repo   *git.Repository
+	stats  map[string]*DiffStats
+}
+
+// NewDiffStatsCache creates a cache of the diff statistics of the commits in the given git repository.
+// The repository is only opened when the statistics of a commit are first requested
+func NewDiffStatsCache(gitDir string) *DiffStatsCache {
+	return &DiffStatsCache{
+		gitDir: gitDir,
+		stats:  map[string]*DiffStats{},
+	}
+}
+
+// Get returns the diff statistics of the commit compared to its first parent
+func (c *DiffStatsCache) Get(sha string) (*DiffStats, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if s, ok := c.stats[sha]; ok {
+		return s, nil
+	}
+	if c.repo == nil {
+		repo, err := git.PlainOpen(c.gitDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open git repository %s", c.gitDir)
+		}
+		c.repo = repo
+	}
+	commit, err := c.repo.CommitObject(plumbing.NewHash(sha))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find commit %s", sha)
+	}
+	fileStats, err := commit.Stats()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the files changed by commit %s", sha)
+	}
+	answer := &DiffStats{FilesChanged: len(fileStats)}
+	for _, fs := range fileStats {
+		answer.Insertions += fs.Addition
+		answer.Deletions += fs.Deletion
+	}
+	c.stats[sha] = answer
+	return answer, nil
+}
+
+// Set caches the diff statistics of the commit
+func (c *DiffStatsCache) Set(sha string, stats *DiffStats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stats[sha] = stats
+}
+
+// formatThousands formats the number with comma separated thousands
+func formatThousands(n int) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	text := strconv.Itoa(n)
+	if len(text) <= 3 {
+		return text
+	}
+	return fmt.Sprintf("%s,%s", formatThousands(n/1000), text[len(text)-3:])
+}
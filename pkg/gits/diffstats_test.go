@@ -0,0 +1,110 @@
+// +build unit
+
+package gits_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestDiffStatsString(t *testing.T) {
+	t.Parallel()
+	var stats *gits.DiffStats
+	assert.Equal(t, "", stats.String())
+	assert.Equal(t, 0, stats.Impact())
+
+	stats = &gits.DiffStats{FilesChanged: 12, Insertions: 4200, Deletions: 3800}
+	assert.Equal(t, "+4,200/−3,800", stats.String())
+	assert.Equal(t, 8000, stats.Impact())
+
+	stats = &gits.DiffStats{Insertions: 1234567, Deletions: 7}
+	assert.Equal(t, "+1,234,567/−7", stats.String())
+}
+
+func TestDiffStatsCache(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-diff-stats-")
+	require.NoError(t, err, "failed to create temp dir")
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	commit := func(message string, files map[string]string) string {
+		for file, content := range files {
+			err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0600)
+			require.NoError(t, err, "failed to write %s", file)
+			_, err = wt.Add(file)
+			require.NoError(t, err, "failed to add %s", file)
+		}
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now()}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		return hash.String()
+	}
+
+	commit("initial commit", map[string]string{"a.txt": "one\ntwo\nthree\n"})
+	sha := commit("fix: numbers", map[string]string{
+		"a.txt": "one\n2\nthree\nfour\n",
+		"b.txt": "new\n",
+	})
+
+	cache := gits.NewDiffStatsCache(dir)
+	stats, err := cache.Get(sha)
+	require.NoError(t, err)
+	assert.Equal(t, &gits.DiffStats{FilesChanged: 2, Insertions: 3, Deletions: 1}, stats)
+
+	again, err := cache.Get(sha)
+	require.NoError(t, err)
+	assert.True(t, stats == again, "should return the cached statistics")
+
+	_, err = cache.Get("0000000000000000000000000000000000000000")
+	assert.Error(t, err, "should fail for an unknown commit")
+}
+
+func TestDiffStatsMarkdown(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "3333333333", Message: "fix: typo"},
+			{SHA: "2222222222", Message: "fix: huge refactor"},
+			{SHA: "1111111111", Message: "fix: unknown"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	cache := gits.NewDiffStatsCache("")
+	cache.Set("3333333333", &gits.DiffStats{FilesChanged: 1, Insertions: 1, Deletions: 1})
+	cache.Set("2222222222", &gits.DiffStats{FilesChanged: 80, Insertions: 4200, Deletions: 3800})
+
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+		DiffStats:     cache,
+		ShowDiffStats: true,
+		SortByImpact:  true,
+	})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### Bug Fixes
+
+* huge refactor (+4,200/−3,800)
+* typo (+1/−1)
+* unknown
+`
+	assert.Equal(t, expected, markdown)
+}
@@ -0,0 +1,38 @@
+package gits
+
+// defaultSectionEmoji the emoji of the default sections of the Conventional Commit types
+var defaultSectionEmoji = map[*CommitGroup]string{
+	ConventionalCommitTitles["feat"]:     "🚀",
+	ConventionalCommitTitles["fix"]:      "🐛",
+	ConventionalCommitTitles["perf"]:     "⚡",
+	ConventionalCommitTitles["refactor"]: "♻️",
+	ConventionalCommitTitles["docs"]:     "📝",
+	ConventionalCommitTitles["test"]:     "✅",
+	ConventionalCommitTitles["revert"]:   "⏪",
+	ConventionalCommitTitles["style"]:    "💄",
+	ConventionalCommitTitles["chore"]:    "🔧",
+}
+
+// breakingChangesEmoji the emoji rendered before the title of the breaking changes
+const breakingChangesEmoji = "⚠"
+
+// breakingChangesTitle returns the title of the breaking changes section
+func (o *MarkdownOptions) breakingChangesTitle() string {
+	title := o.Translations.Translate("Breaking Changes")
+	if o.DisableEmoji {
+		return title
+	}
+	return breakingChangesEmoji + " " + title
+}
+
+// sectionEmoji returns the emoji rendered before the title of the group. The configured emoji of the group is used
+// unless emoji are disabled. The default sections use their default emoji if enabled
+func (o *MarkdownOptions) sectionEmoji(group *CommitGroup) string {
+	if group == nil || o.DisableEmoji {
+		return ""
+	}
+	if group.Emoji != "" || !o.DefaultEmoji {
+		return group.Emoji
+	}
+	return defaultSectionEmoji[group]
+}
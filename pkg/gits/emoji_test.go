@@ -0,0 +1,69 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSectionEmoji(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "3333333333", Message: "feat!: wine\n\nBREAKING CHANGE: bottles are required"},
+			{SHA: "2222222222", Message: "fix: the cork"},
+			{SHA: "1111111111", Message: "chore: tidy"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	groups := &gits.CommitGroups{
+		Types: map[string]*gits.CommitGroup{
+			"feat":  gits.ConventionalCommitTitles["feat"],
+			"fix":   gits.ConventionalCommitTitles["fix"],
+			"chore": {Title: "Housekeeping", Order: 20, Emoji: "🧹"},
+		},
+	}
+
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{Groups: groups})
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "### ⚠ Breaking Changes\n")
+	assert.Contains(t, markdown, "### New Features\n")
+	assert.Contains(t, markdown, "### 🧹 Housekeeping\n")
+
+	opts := &gits.MarkdownOptions{Groups: groups, DefaultEmoji: true}
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "### 🚀 New Features\n")
+	assert.Contains(t, markdown, "### 🐛 Bug Fixes\n")
+	assert.Contains(t, markdown, "### 🧹 Housekeeping\n")
+
+	changelog := gits.CreateChangelog(releaseSpec, gitInfo, time.Now(), opts)
+	require.Len(t, changelog.Sections, 3)
+	assert.Equal(t, "🚀", changelog.Sections[0].Emoji)
+	assert.Equal(t, "🧹", changelog.Sections[2].Emoji)
+	assert.Equal(t, "⚠", changelog.BreakingChangesEmoji)
+
+	opts = &gits.MarkdownOptions{Groups: groups, DefaultEmoji: true, DisableEmoji: true}
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "### Breaking Changes\n")
+	assert.Contains(t, markdown, "### New Features\n")
+	assert.Contains(t, markdown, "### Housekeeping\n")
+
+	changelog = gits.CreateChangelog(releaseSpec, gitInfo, time.Now(), opts)
+	assert.Empty(t, changelog.Sections[0].Emoji)
+	assert.Empty(t, changelog.BreakingChangesEmoji)
+	message := gits.CreateSlackMessage(changelog, "")
+	assert.Equal(t, "*Breaking Changes*\n• bottles are required", message.Blocks[1].Text.Text)
+}
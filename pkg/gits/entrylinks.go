@@ -0,0 +1,67 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+)
+
+const (
+	// EntryLinkCommit links each changelog entry to its commit
+	EntryLinkCommit = "commit"
+
+	// EntryLinkPullRequest links each changelog entry to the pull request which merged it falling back to the commit
+	EntryLinkPullRequest = "pull-request"
+)
+
+// EntryLinks the supported values of the entry links
+var EntryLinks = []string{EntryLinkCommit, EntryLinkPullRequest}
+
+// pullRequestSuffixRegex matches the '(#123)' suffix which git providers add to the subject of squash merged commits
+var pullRequestSuffixRegex = regexp.MustCompile(`\s*\(#(\d+)\)\s*$`)
+
+// entryPullRequest returns the pull request the entry links to or nil if entries do not link to pull requests
+// or the commit has no pull request
+func (o *MarkdownOptions) entryPullRequest(cs *v1.CommitSummary, prMap map[string]*v1.IssueSummary) *v1.IssueSummary {
+	if o.EntryLinks != EntryLinkPullRequest {
+		return nil
+	}
+	for _, id := range cs.IssueIDs {
+		if pr := prMap[id]; pr != nil && pr.URL != "" {
+			return pr
+		}
+	}
+	return nil
+}
+
+// describeEntryLink describes the link of the entry to its pull request or commit
+func describeEntryLink(info *giturl.GitRepository, cs *v1.CommitSummary, pr *v1.IssueSummary, opts *MarkdownOptions) string {
+	if pr != nil {
+		return " ([" + opts.Flavor.issuePrefix(pr.URL) + pr.ID + "](" + pr.URL + "))"
+	}
+	if opts.EntryLinks == "" {
+		return ""
+	}
+	link := cs.URL
+	if link == "" {
+		link = commitURL(info, cs.SHA)
+	}
+	if link == "" {
+		return ""
+	}
+	return " ([" + shortSHA(cs.SHA) + "](" + link + "))"
+}
+
+// trimPullRequestSuffix removes the '(#123)' suffix of the pull request from the subject as the entry links to it
+func trimPullRequestSuffix(subject string, pr *v1.IssueSummary) string {
+	if pr == nil {
+		return subject
+	}
+	m := pullRequestSuffixRegex.FindStringSubmatch(subject)
+	if m == nil || m[1] != strings.TrimPrefix(pr.ID, "#") {
+		return subject
+	}
+	return strings.TrimSpace(subject[:len(subject)-len(m[0])])
+}
@@ -0,0 +1,79 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryLinks(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "fix: the cork (#12)", IssueIDs: []string{"12"}},
+			{SHA: "2222222222", Message: "fix: the bottle"},
+		},
+		PullRequests: []v1.IssueSummary{
+			{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+
+	testCases := []struct {
+		entryLinks string
+		expected   string
+	}{
+		{
+			expected: `## Changes
+
+### Bug Fixes
+
+* the cork (#12)
+* the bottle
+
+### Pull Requests
+
+* [#12](https://github.com/jstrachan/foo/pull/12) 
+`,
+		},
+		{
+			entryLinks: gits.EntryLinkCommit,
+			expected: `## Changes
+
+### Bug Fixes
+
+* the cork (#12) ([1111111](https://github.com/jstrachan/foo/commit/1111111111))
+* the bottle ([2222222](https://github.com/jstrachan/foo/commit/2222222222))
+
+### Pull Requests
+
+* [#12](https://github.com/jstrachan/foo/pull/12) 
+`,
+		},
+		{
+			entryLinks: gits.EntryLinkPullRequest,
+			expected: `## Changes
+
+### Bug Fixes
+
+* the cork ([#12](https://github.com/jstrachan/foo/pull/12))
+* the bottle ([2222222](https://github.com/jstrachan/foo/commit/2222222222))
+
+### Pull Requests
+
+* [#12](https://github.com/jstrachan/foo/pull/12) 
+`,
+		},
+	}
+	for _, tc := range testCases {
+		markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{EntryLinks: tc.entryLinks})
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, markdown, "entry links %s", tc.entryLinks)
+	}
+}
@@ -0,0 +1,107 @@
+package gits
+
+import (
+	"strings"
+)
+
+// escape escapes the characters of a commit subject, scope or user name which would otherwise break the lists and
+// tables of the changelog, render as HTML or create unintended links to issues. Code spans are kept as they are.
+// The '#123' references to the given issue or pull request IDs are kept so that they still link to them
+func (f *MarkdownFlavor) escape(text string, refs ...string) string {
+	var buffer strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch c {
+		case '`':
+			n := backtickRun(text, i)
+			if end := closingBackticks(text, i+n, n); end >= 0 {
+				// lets keep the code span
+				buffer.WriteString(text[i : end+n])
+				i = end + n - 1
+				continue
+			}
+			buffer.WriteString(strings.Repeat("\\`", n))
+			i += n - 1
+		case '\\', '*', '_', '[', ']', '|', '~':
+			buffer.WriteByte('\\')
+			buffer.WriteByte(c)
+		case '<', '>':
+			if f != nil && f.Name == FlavorBitbucket {
+				// Bitbucket does not support escaping HTML with a backslash
+				if c == '<' {
+					buffer.WriteString("&lt;")
+				} else {
+					buffer.WriteString("&gt;")
+				}
+				continue
+			}
+			buffer.WriteByte('\\')
+			buffer.WriteByte(c)
+		case '#':
+			id := leadingDigits(text[i+1:])
+			if (id != "" && !containsRef(refs, id)) || (id == "" && i == 0) {
+				buffer.WriteByte('\\')
+			}
+			buffer.WriteByte(c)
+		case '!':
+			if f != nil && f.Name == FlavorGitLab && leadingDigits(text[i+1:]) != "" {
+				// lets avoid unintended links to merge requests
+				buffer.WriteByte('\\')
+			}
+			buffer.WriteByte(c)
+		default:
+			buffer.WriteByte(c)
+		}
+	}
+	return buffer.String()
+}
+
+// EscapeMarkdown escapes the text such as a commit subject for use in GitHub flavored markdown
+func EscapeMarkdown(text string) string {
+	flavor, _ := GetMarkdownFlavor(FlavorGitHub)
+	return flavor.escape(text)
+}
+
+// backtickRun returns the number of backticks starting at the index
+func backtickRun(text string, start int) int {
+	n := 0
+	for start+n < len(text) && text[start+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// closingBackticks returns the index of the run of exactly n backticks closing a code span or -1 if there is none
+func closingBackticks(text string, start, n int) int {
+	for i := start; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := backtickRun(text, i)
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// leadingDigits returns the digits at the start of the text
+func leadingDigits(text string) string {
+	i := 0
+	for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+		i++
+	}
+	return text[:i]
+}
+
+// containsRef returns true if the references contain the ID with or without a '#' prefix
+func containsRef(refs []string, id string) bool {
+	for _, ref := range refs {
+		if strings.TrimPrefix(ref, "#") == id {
+			return true
+		}
+	}
+	return false
+}
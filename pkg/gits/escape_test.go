@@ -0,0 +1,117 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]string{
+		"plain subject":                   "plain subject",
+		"use `a | b` in `code`":           "use `a | b` in `code`",
+		"unbalanced ` backtick":           "unbalanced \\` backtick",
+		"#1 priority":                     "\\#1 priority",
+		"# heading":                       "\\# heading",
+		"the C# client":                   "the C# client",
+		"* not a bullet":                  "\\* not a bullet",
+		"a | b | c":                       "a \\| b \\| c",
+		"<script>alert(1)</script>":       "\\<script\\>alert(1)\\</script\\>",
+		"[link](https://example.com)":     "\\[link\\](https://example.com)",
+		"snake_case and ~~strike~~":       "snake\\_case and \\~\\~strike\\~\\~",
+		`back\slash`:                      `back\\slash`,
+		"``double `backtick` span``":      "``double `backtick` span``",
+		"<img src=x onerror=alert(1)> **": "\\<img src=x onerror=alert(1)\\> \\*\\*",
+	}
+	for text, expected := range testCases {
+		assert.Equal(t, expected, gits.EscapeMarkdown(text), "escaping %s", text)
+	}
+}
+
+func TestEscapeCommitSubjects(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "fix(a|b): the <cork> | #1 priority (#12)", IssueIDs: []string{"12"}, Author: &v1.UserDetails{Name: "Jo *Bloggs*"}},
+			{SHA: "2222222222", Message: "feat: support `<details>` in !3 and #4"},
+		},
+		Issues: []v1.IssueSummary{{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12", Title: "the | cork"}},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+
+	testCases := []struct {
+		flavor   string
+		expected string
+	}{
+		{
+			flavor: gits.FlavorGitHub,
+			expected: `## Changes
+
+### New Features
+
+* support ` + "`<details>`" + ` in !3 and \#4
+
+### Bug Fixes
+
+* a\|b: the \<cork\> \| \#1 priority (#12) (Jo \*Bloggs\*) [#12](https://github.com/jstrachan/foo/pull/12) 
+
+### Issues
+
+* [#12](https://github.com/jstrachan/foo/pull/12) the \| cork
+`,
+		},
+		{
+			flavor: gits.FlavorGitLab,
+			expected: `## Changes
+
+### New Features
+
+* support ` + "`<details>`" + ` in \!3 and \#4
+
+### Bug Fixes
+
+* a\|b: the \<cork\> \| \#1 priority (#12) (Jo \*Bloggs\*) [#12](https://github.com/jstrachan/foo/pull/12) 
+
+### Issues
+
+* [#12](https://github.com/jstrachan/foo/pull/12) the \| cork
+`,
+		},
+		{
+			flavor: gits.FlavorBitbucket,
+			expected: `## Changes
+
+### New Features
+
+* support ` + "`<details>`" + ` in !3 and \#4
+
+### Bug Fixes
+
+* a\|b: the &lt;cork&gt; \| \#1 priority (#12) (Jo \*Bloggs\*) [#12](https://github.com/jstrachan/foo/pull/12) 
+
+### Issues
+
+* [#12](https://github.com/jstrachan/foo/pull/12) the \| cork
+`,
+		},
+	}
+	for _, tc := range testCases {
+		flavor, err := gits.GetMarkdownFlavor(tc.flavor)
+		require.NoError(t, err)
+		markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{Flavor: flavor})
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, markdown, "flavor %s", tc.flavor)
+	}
+
+	rendered, err := gits.RenderTemplate(gits.CreateChangelog(releaseSpec, gitInfo, time.Now(), nil), "changelog", gits.DefaultMarkdownTemplate, nil)
+	require.NoError(t, err)
+	assert.Contains(t, rendered, "* a\\|b: the \\<cork\\> \\| \\#1 priority (\\#12) (Jo \\*Bloggs\\*)")
+}
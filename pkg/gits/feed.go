@@ -0,0 +1,193 @@
+package gits
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FeedFormatAtom an Atom feed
+	FeedFormatAtom = "atom"
+
+	// FeedFormatRSS an RSS 2.0 feed
+	FeedFormatRSS = "rss"
+
+	atomNamespace = "http://www.w3.org/2005/Atom"
+)
+
+// FeedFormats the supported feed formats
+var FeedFormats = []string{FeedFormatAtom, FeedFormatRSS}
+
+// Feed the details of a release feed
+type Feed struct {
+	// Title the title of the feed
+	Title string
+	// Link the URL of the web page of the releases
+	Link string
+}
+
+// FeedItem a release in a feed
+type FeedItem struct {
+	// Title the title of the release
+	Title string
+	// Link the URL of the release which also identifies the item
+	Link string
+	// Date the date of the release
+	Date time.Time
+	// Content the HTML description of the release
+	Content string
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Updated string      `xml:"updated"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	ID          string `xml:",chardata"`
+}
+
+// AddFeedItem adds the item to the start of the existing feed data, creating a new feed if the data is empty.
+// An item with the same link is replaced so that regenerating the changelog of a release does not duplicate it.
+// Only the elements supported by this function are preserved in the existing feed
+func AddFeedItem(data []byte, format string, feed *Feed, item *FeedItem) ([]byte, error) {
+	empty := strings.TrimSpace(string(data)) == ""
+	var answer interface{}
+	switch format {
+	case FeedFormatAtom:
+		f := &atomFeed{}
+		if !empty {
+			err := xml.Unmarshal(data, f)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse the Atom feed")
+			}
+		}
+		addAtomEntry(f, feed, item)
+		answer = f
+	case FeedFormatRSS:
+		f := &rssFeed{}
+		if !empty {
+			err := xml.Unmarshal(data, f)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse the RSS feed")
+			}
+		}
+		addRSSItem(f, feed, item)
+		answer = f
+	default:
+		return nil, errors.Errorf("unsupported feed format %s. Supported values: %s", format, strings.Join(FeedFormats, ", "))
+	}
+	result, err := xml.MarshalIndent(answer, "", "  ")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the %s feed", format)
+	}
+	return append([]byte(xml.Header), append(result, '\n')...), nil
+}
+
+func addAtomEntry(f *atomFeed, feed *Feed, item *FeedItem) {
+	if f.Title == "" {
+		f.Title = feed.Title
+	}
+	if f.ID == "" {
+		f.ID = feed.Link
+	}
+	if f.Link == nil && feed.Link != "" {
+		f.Link = &atomLink{Href: feed.Link}
+	}
+	updated := item.Date.UTC().Format(time.RFC3339)
+	f.Updated = updated
+	entry := atomEntry{
+		Title:   item.Title,
+		ID:      item.Link,
+		Updated: updated,
+		Content: atomContent{Type: "html", Body: item.Content},
+	}
+	if item.Link != "" {
+		entry.Link = &atomLink{Href: item.Link}
+	}
+	entries := []atomEntry{entry}
+	for _, e := range f.Entries {
+		if e.ID != entry.ID {
+			entries = append(entries, e)
+		}
+	}
+	f.XMLName = xml.Name{Space: atomNamespace, Local: "feed"}
+	f.Entries = entries
+}
+
+func addRSSItem(f *rssFeed, feed *Feed, item *FeedItem) {
+	f.Version = "2.0"
+	c := &f.Channel
+	if c.Title == "" {
+		c.Title = feed.Title
+	}
+	if c.Link == "" {
+		c.Link = feed.Link
+	}
+	if c.Description == "" {
+		c.Description = feed.Title
+	}
+	pubDate := item.Date.UTC().Format(time.RFC1123Z)
+	c.LastBuildDate = pubDate
+	i := rssItem{
+		Title:       item.Title,
+		Link:        item.Link,
+		GUID:        rssGUID{IsPermaLink: item.Link != "", ID: item.Link},
+		PubDate:     pubDate,
+		Description: item.Content,
+	}
+	items := []rssItem{i}
+	for _, existing := range c.Items {
+		if existing.GUID.ID != i.GUID.ID {
+			items = append(items, existing)
+		}
+	}
+	c.Items = items
+}
@@ -0,0 +1,61 @@
+// +build unit
+
+package gits_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFeedItem(t *testing.T) {
+	t.Parallel()
+	feed := &gits.Feed{Title: "foo releases", Link: "https://github.com/jstrachan/foo/releases"}
+	first := &gits.FeedItem{
+		Title:   "1.0.0",
+		Link:    "https://github.com/jstrachan/foo/releases/tag/v1.0.0",
+		Date:    time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		Content: "<h2>Bug Fixes</h2>",
+	}
+	second := &gits.FeedItem{
+		Title:   "1.1.0",
+		Link:    "https://github.com/jstrachan/foo/releases/tag/v1.1.0",
+		Date:    time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC),
+		Content: "<h2>New Features</h2>",
+	}
+
+	for _, format := range gits.FeedFormats {
+		data, err := gits.AddFeedItem(nil, format, feed, first)
+		require.NoError(t, err, "format %s", format)
+		data, err = gits.AddFeedItem(data, format, feed, second)
+		require.NoError(t, err, "format %s", format)
+		data, err = gits.AddFeedItem(data, format, feed, second)
+		require.NoError(t, err, "format %s", format)
+
+		text := string(data)
+		assert.Equal(t, 1, strings.Count(text, "<title>foo releases</title>"), "format %s", format)
+		assert.Equal(t, 1, strings.Count(text, "<title>1.1.0</title>"), "format %s should replace the existing item", format)
+		assert.Contains(t, text, "&lt;h2&gt;Bug Fixes&lt;/h2&gt;", "format %s", format)
+		assert.True(t, strings.Index(text, "1.1.0") < strings.Index(text, "1.0.0"), "format %s should add the newest item first", format)
+	}
+
+	data, err := gits.AddFeedItem(nil, gits.FeedFormatAtom, feed, first)
+	require.NoError(t, err)
+	text := string(data)
+	assert.Contains(t, text, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, text, "<updated>2021-03-04T00:00:00Z</updated>")
+	assert.Contains(t, text, `<content type="html">`)
+
+	data, err = gits.AddFeedItem(nil, gits.FeedFormatRSS, feed, first)
+	require.NoError(t, err)
+	text = string(data)
+	assert.Contains(t, text, `<rss version="2.0">`)
+	assert.Contains(t, text, "<pubDate>Thu, 04 Mar 2021 00:00:00 +0000</pubDate>")
+
+	_, err = gits.AddFeedItem([]byte("not xml"), gits.FeedFormatAtom, feed, first)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package gits
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FlavorGitHub GitHub Flavored Markdown
+	FlavorGitHub = "github"
+
+	// FlavorGitLab GitLab Flavored Markdown
+	FlavorGitLab = "gitlab"
+
+	// FlavorBitbucket the markdown of Bitbucket
+	FlavorBitbucket = "bitbucket"
+
+	// FlavorCommonMark plain CommonMark without any platform extensions
+	FlavorCommonMark = "commonmark"
+)
+
+// MarkdownFlavors the supported markdown flavors
+var MarkdownFlavors = []string{FlavorGitHub, FlavorGitLab, FlavorBitbucket, FlavorCommonMark}
+
+// MarkdownFlavor the markdown features of the platform the changelog is rendered on
+type MarkdownFlavor struct {
+	// Name the name of the flavor
+	Name string
+	// Mentions renders users with a login as '@login' mentions rather than links
+	Mentions bool
+	// Details supports collapsible '<details>' blocks
+	Details bool
+	// MergeRequestPrefix the prefix of merge request references if they differ from issues such as '!' on GitLab
+	MergeRequestPrefix string
+}
+
+// GetMarkdownFlavor returns the markdown flavor with the given name or nil if the name is empty
+func GetMarkdownFlavor(name string) (*MarkdownFlavor, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return nil, nil
+	case FlavorGitHub:
+		return &MarkdownFlavor{Name: FlavorGitHub, Mentions: true, Details: true}, nil
+	case FlavorGitLab:
+		return &MarkdownFlavor{Name: FlavorGitLab, Mentions: true, Details: true, MergeRequestPrefix: "!"}, nil
+	case FlavorBitbucket:
+		// Bitbucket mentions use account IDs rather than logins so lets link to the users instead
+		return &MarkdownFlavor{Name: FlavorBitbucket}, nil
+	case FlavorCommonMark:
+		return &MarkdownFlavor{Name: FlavorCommonMark}, nil
+	default:
+		return nil, errors.Errorf("unsupported markdown flavor %s. Supported values: %s", name, strings.Join(MarkdownFlavors, ", "))
+	}
+}
+
+// mention returns true if the users should be mentioned rather than linked
+func (f *MarkdownFlavor) mention() bool {
+	return f != nil && f.Mentions
+}
+
+// details returns true if collapsible '<details>' blocks are supported which is the default
+func (f *MarkdownFlavor) details() bool {
+	return f == nil || f.Details
+}
+
+// issuePrefix returns the prefix of the reference of the numeric issue or pull request with the given URL
+func (f *MarkdownFlavor) issuePrefix(url string) string {
+	if f != nil && f.MergeRequestPrefix != "" && strings.Contains(url, "/merge_requests/") {
+		return f.MergeRequestPrefix
+	}
+	return "#"
+}
@@ -0,0 +1,89 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownFlavors(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{
+				SHA:      "4444444444",
+				Message:  "fix: the cork",
+				Author:   &v1.UserDetails{Login: "jstrachan"},
+				IssueIDs: []string{"12"},
+			},
+			{
+				SHA:     "3333333333",
+				Message: "chore(deps): bump foo from 1.0.0 to 1.1.0",
+				Author:  &v1.UserDetails{Login: "dependabot"},
+			},
+		},
+		Issues: []v1.IssueSummary{
+			{ID: "12", URL: "https://gitlab.com/jstrachan/foo/-/merge_requests/12", Title: "fix the cork"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "gitlab.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+
+	testCases := []struct {
+		flavor  string
+		fix     string
+		details bool
+	}{
+		{
+			flavor:  "",
+			fix:     "* the cork ([jstrachan](https://gitlab.com/jstrachan)) [#12](https://gitlab.com/jstrachan/foo/-/merge_requests/12) \n",
+			details: true,
+		},
+		{
+			flavor:  gits.FlavorGitHub,
+			fix:     "* the cork (@jstrachan) [#12](https://gitlab.com/jstrachan/foo/-/merge_requests/12) \n",
+			details: true,
+		},
+		{
+			flavor:  gits.FlavorGitLab,
+			fix:     "* the cork (@jstrachan) [!12](https://gitlab.com/jstrachan/foo/-/merge_requests/12) \n",
+			details: true,
+		},
+		{
+			flavor: gits.FlavorBitbucket,
+			fix:    "* the cork ([jstrachan](https://gitlab.com/jstrachan)) [#12](https://gitlab.com/jstrachan/foo/-/merge_requests/12) \n",
+		},
+		{
+			flavor: gits.FlavorCommonMark,
+			fix:    "* the cork ([jstrachan](https://gitlab.com/jstrachan)) [#12](https://gitlab.com/jstrachan/foo/-/merge_requests/12) \n",
+		},
+	}
+	for _, tc := range testCases {
+		flavor, err := gits.GetMarkdownFlavor(tc.flavor)
+		require.NoError(t, err, "flavor %s", tc.flavor)
+
+		markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+			Flavor:                 flavor,
+			GroupDependencyUpdates: true,
+		})
+		require.NoError(t, err, "flavor %s", tc.flavor)
+		assert.Contains(t, markdown, tc.fix, "flavor %s", tc.flavor)
+		if tc.details {
+			assert.Contains(t, markdown, "<details>", "flavor %s", tc.flavor)
+		} else {
+			assert.NotContains(t, markdown, "<details>", "flavor %s", tc.flavor)
+		}
+	}
+
+	_, err := gits.GetMarkdownFlavor("wiki")
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// ConventionConventional commit messages use Conventional Commits: https://conventionalcommits.org/
+	ConventionConventional = "conventional"
+
+	// ConventionGitmoji commit messages start with a gitmoji: https://gitmoji.dev/
+	ConventionGitmoji = "gitmoji"
+)
+
+// Conventions the supported commit message conventions
+var Conventions = []string{ConventionConventional, ConventionGitmoji}
+
+var (
+	// GitmojiCommitTitles maps the gitmoji codes to the groups of the Conventional Commit types
+	GitmojiCommitTitles = map[string]*CommitGroup{
+		"sparkles":            ConventionalCommitTitles["feat"],
+		"tada":                ConventionalCommitTitles["feat"],
+		"boom":                ConventionalCommitTitles["feat"],
+		"bug":                 ConventionalCommitTitles["fix"],
+		"ambulance":           ConventionalCommitTitles["fix"],
+		"adhesive_bandage":    ConventionalCommitTitles["fix"],
+		"lock":                ConventionalCommitTitles["fix"],
+		"zap":                 ConventionalCommitTitles["perf"],
+		"recycle":             ConventionalCommitTitles["refactor"],
+		"art":                 ConventionalCommitTitles["refactor"],
+		"fire":                ConventionalCommitTitles["refactor"],
+		"truck":               ConventionalCommitTitles["refactor"],
+		"memo":                ConventionalCommitTitles["docs"],
+		"bulb":                ConventionalCommitTitles["docs"],
+		"white_check_mark":    ConventionalCommitTitles["test"],
+		"test_tube":           ConventionalCommitTitles["test"],
+		"rewind":              ConventionalCommitTitles["revert"],
+		"lipstick":            ConventionalCommitTitles["style"],
+		"wrench":              ConventionalCommitTitles["chore"],
+		"arrow_up":            ConventionalCommitTitles["chore"],
+		"arrow_down":          ConventionalCommitTitles["chore"],
+		"heavy_plus_sign":     ConventionalCommitTitles["chore"],
+		"heavy_minus_sign":    ConventionalCommitTitles["chore"],
+		"package":             ConventionalCommitTitles["chore"],
+		"bookmark":            ConventionalCommitTitles["chore"],
+		"rocket":              ConventionalCommitTitles["chore"],
+		"construction_worker": ConventionalCommitTitles["chore"],
+		"green_heart":         ConventionalCommitTitles["chore"],
+		"see_no_evil":         ConventionalCommitTitles["chore"],
+		"":                    ConventionalCommitTitles[""],
+	}
+
+	// gitmojiUnicode maps the unicode emojis to their gitmoji codes
+	gitmojiUnicode = map[string]string{
+		"✨": "sparkles",
+		"🎉": "tada",
+		"💥": "boom",
+		"🐛": "bug",
+		"🚑": "ambulance",
+		"🩹": "adhesive_bandage",
+		"🔒": "lock",
+		"⚡": "zap",
+		"♻": "recycle",
+		"🎨": "art",
+		"🔥": "fire",
+		"🚚": "truck",
+		"📝": "memo",
+		"💡": "bulb",
+		"✅": "white_check_mark",
+		"🧪": "test_tube",
+		"⏪": "rewind",
+		"💄": "lipstick",
+		"🔧": "wrench",
+		"⬆": "arrow_up",
+		"⬇": "arrow_down",
+		"➕": "heavy_plus_sign",
+		"➖": "heavy_minus_sign",
+		"📦": "package",
+		"🔖": "bookmark",
+		"🚀": "rocket",
+		"👷": "construction_worker",
+		"💚": "green_heart",
+		"🙈": "see_no_evil",
+	}
+
+	gitmojiCodeRegex  = regexp.MustCompile(`^:([a-z0-9_+-]+):`)
+	gitmojiScopeRegex = regexp.MustCompile(`^\(([^()]*)\):?`)
+)
+
+// ParseGitmojiCommit parses a commit whose message starts with a gitmoji code such as ':sparkles:' or its unicode emoji.
+// The kind of the commit is the gitmoji code without the colons
+// see: https://gitmoji.dev/
+func ParseGitmojiCommit(message string) *CommitInfo {
+	answer := &CommitInfo{
+		Message: message,
+	}
+	rest := strings.TrimSpace(message)
+	code := ""
+	m := gitmojiCodeRegex.FindStringSubmatch(rest)
+	if m != nil {
+		code = m[1]
+		rest = rest[len(m[0]):]
+	} else {
+		for emoji, c := range gitmojiUnicode {
+			if strings.HasPrefix(rest, emoji) {
+				code = c
+				rest = strings.TrimPrefix(rest[len(emoji):], "\ufe0f")
+				break
+			}
+		}
+	}
+	if code == "" {
+		return answer
+	}
+	rest = strings.TrimSpace(rest)
+	m = gitmojiScopeRegex.FindStringSubmatch(rest)
+	if m != nil {
+		answer.Feature = m[1]
+		rest = strings.TrimSpace(rest[len(m[0]):])
+	}
+	answer.Kind = code
+	answer.Message = rest
+	if code == "boom" {
+		answer.Breaking = true
+		answer.BreakingChange = strings.SplitN(rest, "\n", 2)[0]
+	}
+	return answer
+}
@@ -0,0 +1,82 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitmojiCommits(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		message  string
+		expected *gits.CommitInfo
+	}{
+		{
+			message:  "something regular",
+			expected: &gits.CommitInfo{Message: "something regular"},
+		},
+		{
+			message:  ":sparkles: add cheese",
+			expected: &gits.CommitInfo{Kind: "sparkles", Message: "add cheese"},
+		},
+		{
+			message:  "🐛 (wine): fix the cork",
+			expected: &gits.CommitInfo{Kind: "bug", Feature: "wine", Message: "fix the cork"},
+		},
+		{
+			message:  "♻️ tidy up",
+			expected: &gits.CommitInfo{Kind: "recycle", Message: "tidy up"},
+		},
+		{
+			message:  ":boom: remove the v1 API",
+			expected: &gits.CommitInfo{Kind: "boom", Message: "remove the v1 API", Breaking: true, BreakingChange: "remove the v1 API"},
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, gits.ParseGitmojiCommit(tc.message), "for message %s", tc.message)
+	}
+}
+
+func TestGitmojiMarkdown(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{Message: ":bug: fix the cork", SHA: "1"},
+			{Message: ":sparkles: add cheese", SHA: "2"},
+			{Message: "something regular", SHA: "3"},
+			{Message: ":unknown: dropped", SHA: "4"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{Convention: gits.ConventionGitmoji})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### New Features
+
+* add cheese
+
+### Bug Fixes
+
+* fix the cork
+
+### Other Changes
+
+These commits did not use [gitmoji](https://gitmoji.dev/) formatted messages:
+
+* something regular
+`
+	assert.Equal(t, expected, markdown)
+}
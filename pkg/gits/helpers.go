@@ -17,6 +17,16 @@ func GetRevisionBeforeDateText(g gitclient.Interface, dir string, dateText strin
 	return g.Command(dir, "rev-list", "-1", "--before=\""+dateText+"\"", "--max-count=1", branch)
 }
 
+// GetRevisionBefore returns the last commit reachable from the revision which was committed before the date in any
+// format git understands such as "2021-05-01" or "1 week ago". Returns an empty string if there is no such commit
+func GetRevisionBefore(g gitclient.Interface, dir, rev, date string) (string, error) {
+	text, err := g.Command(dir, "rev-list", "-1", "--before="+date, rev)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the commit before %s", date)
+	}
+	return strings.TrimSpace(text), nil
+}
+
 // GetCommitPointedToByLatestTag return the SHA of the commit pointed to by the latest git tag as well as the tag name
 // for the git repo in dir
 func GetCommitPointedToByLatestTag(g gitclient.Interface, dir string) (string, string, error) {
@@ -104,3 +114,126 @@ func FilterTags(g gitclient.Interface, dir string, filter string) ([]string, err
 	}
 	return split, nil
 }
+
+// ListTags returns the names of the tags starting with the prefix from the repository at the given directory in
+// chronological order
+func ListTags(g gitclient.Interface, dir string, prefix string) ([]string, error) {
+	args := []string{
+		"for-each-ref",
+		"--sort=creatordate",
+		"--format=%(refname:short)",
+		"refs/tags",
+	}
+	out, err := g.Command(dir, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "running git %s", strings.Join(args, " "))
+	}
+	var answer []string
+	for _, tag := range strings.Split(out, "\n") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && strings.HasPrefix(tag, prefix) {
+			answer = append(answer, tag)
+		}
+	}
+	return answer, nil
+}
+
+// AnnotateTag replaces the tag with an annotated tag of the same commit using the message as its description. The
+// message is kept verbatim so that markdown headings are not stripped as comments
+func AnnotateTag(g gitclient.Interface, dir, tag, message string) error {
+	args := []string{"tag", "--annotate", "--force", "--cleanup=verbatim", "--message", message, tag, tag + "^{commit}"}
+	_, err := g.Command(dir, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to annotate tag %s", tag)
+	}
+	return nil
+}
+
+// PushTag pushes the tag to the remote. Existing tags on the remote are never overwritten
+func PushTag(g gitclient.Interface, dir, remote, tag string) error {
+	_, err := g.Command(dir, "push", remote, "refs/tags/"+tag)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push tag %s to %s", tag, remote)
+	}
+	return nil
+}
+
+// IsAnnotatedTag returns true if the tag is an annotated or signed tag rather than a lightweight tag
+func IsAnnotatedTag(g gitclient.Interface, dir, tag string) (bool, error) {
+	out, err := g.Command(dir, "cat-file", "-t", "refs/tags/"+tag)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to find tag %s", tag)
+	}
+	return strings.TrimSpace(out) == "tag", nil
+}
+
+// IsShallowClone returns true if the repository at the given directory is a shallow clone whose history is incomplete
+func IsShallowClone(g gitclient.Interface, dir string) bool {
+	out, err := g.Command(dir, "rev-parse", "--is-shallow-repository")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// DeleteTag deletes the tag from the remote and the repository at the given directory
+func DeleteTag(g gitclient.Interface, dir, remote, tag string) error {
+	_, err := g.Command(dir, "push", remote, ":refs/tags/"+tag)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete tag %s from %s", tag, remote)
+	}
+	_, err = g.Command(dir, "tag", "--delete", tag)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete tag %s", tag)
+	}
+	return nil
+}
+
+// CreateTag creates the annotated tag of the revision with the message which is kept verbatim. If sign is true the tag
+// is signed with GPG using the key or, if the key is empty, the default key of the git configuration
+func CreateTag(g gitclient.Interface, dir, tag, rev, message string, sign bool, key string) error {
+	args := []string{"tag", "--annotate", "--cleanup=verbatim", "--message", message}
+	if sign || key != "" {
+		args = append(args, "--sign")
+	}
+	if key != "" {
+		args = append(args, "--local-user", key)
+	}
+	args = append(args, tag, rev)
+	_, err := g.Command(dir, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create tag %s", tag)
+	}
+	return nil
+}
+
+// CommitFile commits the changes of the file with the message returning false if the file has no changes
+func CommitFile(g gitclient.Interface, dir, file, message string) (bool, error) {
+	out, err := g.Command(dir, "status", "--porcelain", "--", file)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check the changes of %s", file)
+	}
+	if strings.TrimSpace(out) == "" {
+		return false, nil
+	}
+	_, err = g.Command(dir, "add", "--", file)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to add %s", file)
+	}
+	_, err = g.Command(dir, "commit", "--message", message, "--", file)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to commit %s", file)
+	}
+	return true, nil
+}
+
+// PushBranch pushes the revision to the branch of the remote. If force is true an existing branch is replaced
+func PushBranch(g gitclient.Interface, dir, remote, rev, branch string, force bool) error {
+	args := []string{"push"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, remote, rev+":refs/heads/"+branch)
+	_, err := g.Command(dir, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push %s to branch %s of %s", rev, branch, remote)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package gits
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultHTMLTemplate the default template used to render the changelog as a standalone HTML page
+const DefaultHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Changelog{{ with .Title }} {{ . }}{{ else }}{{ with .Version }} {{ . }}{{ end }}{{ end }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; color: #24292e; max-width: 50em; margin: 2em auto; padding: 0 1em; }
+h1 { border-bottom: 1px solid #eaecef; padding-bottom: .3em; }
+h2 { margin-top: 1.5em; }
+a { color: #0366d6; text-decoration: none; }
+a:hover { text-decoration: underline; }
+.date { color: #6a737d; }
+.scope { font-weight: 600; }
+.breaking { color: #cb2431; }
+.authors, .sha { color: #6a737d; font-size: 90%; }
+code { font-family: SFMono-Regular, Consolas, Menlo, monospace; }
+</style>
+</head>
+<body>
+<h1>{{ if .Title }}{{ .Title }}{{ else if .Version }}{{ .Version }}{{ else }}Changelog{{ end }}</h1>
+<p class="date">{{ .Date.Format "2006-01-02" }}{{ with .URL }} &middot; <a href="{{ . }}">{{ . }}</a>{{ end }}{{ with .Compare }} &middot; <a href="{{ .URL }}">{{ .From }}...{{ .To }}</a>{{ end }}</p>
+{{- template "changes" . }}
+</body>
+</html>
+{{- define "changes" }}
+{{- if .BreakingChanges }}
+<h2 class="breaking">{{ with .BreakingChangesEmoji }}{{ . }} {{ end }}Breaking Changes</h2>
+<ul>
+{{- range .BreakingChanges }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- range .Sections }}
+<h2>{{ with .Emoji }}{{ . }} {{ end }}{{ .Title }}</h2>
+{{- if .Collapsed }}
+<details>
+<summary>{{ len .Entries }} commits</summary>
+{{- end }}
+<ul>
+{{- range .Entries }}
+<li>{{ with .Scope }}<span class="scope">{{ . }}:</span> {{ end }}{{ .Subject }}
+{{- with .PullRequest }} <a href="{{ .URL }}">#{{ .ID }}</a>{{ end }}
+{{- range .Issues }} <a href="{{ .URL }}">{{ .ID }}</a>{{ end }}
+{{- if .Authors }} <span class="authors">({{ range $i, $a := .Authors }}{{ if $i }}, {{ end }}{{ if $a.Login }}{{ $a.Login }}{{ else }}{{ $a.Name }}{{ end }}{{ end }})</span>{{ end }}
+{{- if .URL }} <a class="sha" href="{{ .URL }}"><code>{{ shortSHA .SHA }}</code></a>{{ else }} <code class="sha">{{ shortSHA .SHA }}</code>{{ end }}</li>
+{{- end }}
+</ul>
+{{- if .Collapsed }}
+</details>
+{{- end }}
+{{- end }}
+{{- if .Contributors }}
+<h2>Contributors</h2>
+<ul>
+{{- range .Contributors }}
+<li>{{ if .User.URL }}<a href="{{ .User.URL }}">{{ if .User.Login }}{{ .User.Login }}{{ else }}{{ .User.Name }}{{ end }}</a>{{ else if .User.Login }}{{ .User.Login }}{{ else }}{{ .User.Name }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+`
+
+// RenderHTMLChanges renders the sections and contributors of the changelog as an HTML fragment without the page and its
+// stylesheet such as for the content of a feed entry
+func RenderHTMLChanges(changelog *Changelog) (string, error) {
+	return renderHTML(changelog, DefaultHTMLTemplate, "changes")
+}
+
+// RenderHTML renders the changelog as HTML using the given html/template text or the DefaultHTMLTemplate if it is empty.
+// The template is executed with the *Changelog and can use the shortSHA function
+func RenderHTML(changelog *Changelog, templateText string) (string, error) {
+	if templateText == "" {
+		templateText = DefaultHTMLTemplate
+	}
+	return renderHTML(changelog, templateText, "changelog")
+}
+
+func renderHTML(changelog *Changelog, templateText, name string) (string, error) {
+	funcs := template.FuncMap{
+		"shortSHA": shortSHA,
+	}
+	tmpl, err := template.New("changelog").Funcs(funcs).Parse(templateText)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the HTML template")
+	}
+	var buffer bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buffer, name, changelog)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render the HTML template")
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,56 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	t.Parallel()
+	changelog := &gits.Changelog{
+		Version: "1.2.0",
+		Date:    time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		Sections: []*gits.Section{
+			{
+				Title: "Bug Fixes",
+				Entries: []*gits.Entry{
+					{
+						SHA:         "4444444444",
+						URL:         "https://github.com/jstrachan/foo/commit/4444444444",
+						Scope:       "api",
+						Subject:     "escape <script> tags",
+						Authors:     []*v1.UserDetails{{Login: "jstrachan"}},
+						PullRequest: &gits.Reference{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12"},
+					},
+				},
+			},
+		},
+	}
+
+	html, err := gits.RenderHTML(changelog, "")
+	require.NoError(t, err)
+	assert.Contains(t, html, "<title>Changelog 1.2.0</title>")
+	assert.Contains(t, html, "<style>")
+	assert.Contains(t, html, `<p class="date">2021-03-04</p>`)
+	assert.Contains(t, html, "<h2>Bug Fixes</h2>")
+	assert.Contains(t, html, `<li><span class="scope">api:</span> escape &lt;script&gt; tags <a href="https://github.com/jstrachan/foo/pull/12">#12</a> <span class="authors">(jstrachan)</span> <a class="sha" href="https://github.com/jstrachan/foo/commit/4444444444"><code>4444444</code></a></li>`)
+
+	html, err = gits.RenderHTMLChanges(changelog)
+	require.NoError(t, err)
+	assert.NotContains(t, html, "<style>")
+	assert.Contains(t, html, "<h2>Bug Fixes</h2>")
+
+	html, err = gits.RenderHTML(changelog, `{{ range .Sections }}<h3>{{ .Title }}</h3>{{ range .Entries }}{{ shortSHA .SHA }}{{ end }}{{ end }}`)
+	require.NoError(t, err)
+	assert.Equal(t, "<h3>Bug Fixes</h3>4444444", html)
+
+	_, err = gits.RenderHTML(changelog, "{{ .Missing ")
+	assert.Error(t, err)
+}
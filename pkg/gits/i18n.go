@@ -0,0 +1,224 @@
+package gits
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Translations the translations of the section titles and standard phrases of the changelog indexed by their
+// English text such as 'Bug Fixes' or 'Full Changelog'
+type Translations map[string]string
+
+// BuiltinTranslations the built-in translations indexed by the locale
+var BuiltinTranslations = map[string]Translations{
+	"de": {
+		"Changes":                       "Änderungen",
+		"Breaking Changes":              "Inkompatible Änderungen",
+		"Security":                      "Sicherheit",
+		"New Features":                  "Neue Funktionen",
+		"Bug Fixes":                     "Fehlerbehebungen",
+		"Performance Improvements":      "Leistungsverbesserungen",
+		"Code Refactoring":              "Code-Refactoring",
+		"Documentation":                 "Dokumentation",
+		"Tests":                         "Tests",
+		"Reverts":                       "Rücknahmen",
+		"Styles":                        "Stil",
+		"Chores":                        "Wartung",
+		"Other Changes":                 "Weitere Änderungen",
+		"Other":                         "Sonstiges",
+		"Reverted":                      "Zurückgenommen",
+		"Contributors":                  "Mitwirkende",
+		"New Contributors":              "Neue Mitwirkende",
+		"made their first contribution": "hat zum ersten Mal beigetragen",
+		"Reviewers":                     "Prüfer",
+		"approved":                      "genehmigt",
+		"Issues Fixed":                  "Behobene Issues",
+		"Issues":                        "Issues",
+		"Pull Requests":                 "Pull Requests",
+		"Dependency Updates":            "Aktualisierte Abhängigkeiten",
+		"dependency updates":            "aktualisierte Abhängigkeiten",
+		"Sign-off Compliance":           "Sign-off-Konformität",
+		"Full Changelog":                "Vollständiges Änderungsprotokoll",
+		"commits":                       "Commits",
+	},
+	"es": {
+		"Changes":                       "Cambios",
+		"Breaking Changes":              "Cambios incompatibles",
+		"Security":                      "Seguridad",
+		"New Features":                  "Nuevas funcionalidades",
+		"Bug Fixes":                     "Correcciones de errores",
+		"Performance Improvements":      "Mejoras de rendimiento",
+		"Code Refactoring":              "Refactorización de código",
+		"Documentation":                 "Documentación",
+		"Tests":                         "Pruebas",
+		"Reverts":                       "Reversiones",
+		"Styles":                        "Estilos",
+		"Chores":                        "Mantenimiento",
+		"Other Changes":                 "Otros cambios",
+		"Other":                         "Otros",
+		"Reverted":                      "Revertidos",
+		"Contributors":                  "Colaboradores",
+		"New Contributors":              "Nuevos colaboradores",
+		"made their first contribution": "hizo su primera contribución",
+		"Reviewers":                     "Revisores",
+		"approved":                      "aprobado",
+		"Issues Fixed":                  "Incidencias resueltas",
+		"Issues":                        "Incidencias",
+		"Pull Requests":                 "Pull requests",
+		"Dependency Updates":            "Actualizaciones de dependencias",
+		"dependency updates":            "actualizaciones de dependencias",
+		"Sign-off Compliance":           "Cumplimiento de sign-off",
+		"Full Changelog":                "Registro de cambios completo",
+		"commits":                       "commits",
+	},
+	"fr": {
+		"Changes":                       "Modifications",
+		"Breaking Changes":              "Changements incompatibles",
+		"Security":                      "Sécurité",
+		"New Features":                  "Nouvelles fonctionnalités",
+		"Bug Fixes":                     "Corrections de bugs",
+		"Performance Improvements":      "Améliorations des performances",
+		"Code Refactoring":              "Refactorisation du code",
+		"Documentation":                 "Documentation",
+		"Tests":                         "Tests",
+		"Reverts":                       "Annulations",
+		"Styles":                        "Styles",
+		"Chores":                        "Maintenance",
+		"Other Changes":                 "Autres modifications",
+		"Other":                         "Autres",
+		"Reverted":                      "Annulés",
+		"Contributors":                  "Contributeurs",
+		"New Contributors":              "Nouveaux contributeurs",
+		"made their first contribution": "a fait sa première contribution",
+		"Reviewers":                     "Relecteurs",
+		"approved":                      "approuvé",
+		"Issues Fixed":                  "Tickets résolus",
+		"Issues":                        "Tickets",
+		"Pull Requests":                 "Pull requests",
+		"Dependency Updates":            "Mises à jour des dépendances",
+		"dependency updates":            "mises à jour des dépendances",
+		"Sign-off Compliance":           "Conformité des sign-off",
+		"Full Changelog":                "Journal des modifications complet",
+		"commits":                       "commits",
+	},
+	"ja": {
+		"Changes":                       "変更点",
+		"Breaking Changes":              "破壊的変更",
+		"Security":                      "セキュリティ",
+		"New Features":                  "新機能",
+		"Bug Fixes":                     "バグ修正",
+		"Performance Improvements":      "パフォーマンス改善",
+		"Code Refactoring":              "リファクタリング",
+		"Documentation":                 "ドキュメント",
+		"Tests":                         "テスト",
+		"Reverts":                       "取り消し",
+		"Styles":                        "スタイル",
+		"Chores":                        "雑務",
+		"Other Changes":                 "その他の変更",
+		"Other":                         "その他",
+		"Reverted":                      "取り消された変更",
+		"Contributors":                  "コントリビューター",
+		"New Contributors":              "新しいコントリビューター",
+		"made their first contribution": "が初めてコントリビュートしました",
+		"Reviewers":                     "レビュアー",
+		"approved":                      "承認済み",
+		"Issues Fixed":                  "修正された課題",
+		"Issues":                        "課題",
+		"Pull Requests":                 "プルリクエスト",
+		"Dependency Updates":            "依存関係の更新",
+		"dependency updates":            "件の依存関係の更新",
+		"Sign-off Compliance":           "サインオフの遵守状況",
+		"Full Changelog":                "全変更履歴",
+		"commits":                       "件のコミット",
+	},
+	"zh": {
+		"Changes":                       "变更",
+		"Breaking Changes":              "不兼容变更",
+		"Security":                      "安全",
+		"New Features":                  "新功能",
+		"Bug Fixes":                     "问题修复",
+		"Performance Improvements":      "性能改进",
+		"Code Refactoring":              "代码重构",
+		"Documentation":                 "文档",
+		"Tests":                         "测试",
+		"Reverts":                       "回退",
+		"Styles":                        "代码风格",
+		"Chores":                        "日常维护",
+		"Other Changes":                 "其他变更",
+		"Other":                         "其他",
+		"Reverted":                      "已回退",
+		"Contributors":                  "贡献者",
+		"New Contributors":              "新贡献者",
+		"made their first contribution": "完成了首次贡献",
+		"Reviewers":                     "评审者",
+		"approved":                      "已批准",
+		"Issues Fixed":                  "已修复的问题",
+		"Issues":                        "问题",
+		"Pull Requests":                 "拉取请求",
+		"Dependency Updates":            "依赖更新",
+		"dependency updates":            "项依赖更新",
+		"Sign-off Compliance":           "签署合规性",
+		"Full Changelog":                "完整变更日志",
+		"commits":                       "个提交",
+	},
+}
+
+// Locales returns the locales with built-in translations
+func Locales() []string {
+	var answer []string
+	for locale := range BuiltinTranslations {
+		answer = append(answer, locale)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// LoadTranslations returns the built-in translations of the locale overridden by the translations in the optional
+// YAML strings file which maps the English text to its translation. The region of a locale such as 'de-AT' falls back
+// to the language. Returns nil if both the locale and the file are empty
+func LoadTranslations(locale, file string) (Translations, error) {
+	answer := Translations{}
+	if locale != "" && !strings.EqualFold(locale, "en") {
+		locale = strings.ToLower(strings.Replace(locale, "_", "-", -1))
+		builtin := BuiltinTranslations[locale]
+		if builtin == nil {
+			builtin = BuiltinTranslations[strings.SplitN(locale, "-", 2)[0]]
+		}
+		if builtin == nil && file == "" {
+			return nil, errors.Errorf("unsupported locale %s. Supported values: %s or a strings file", locale, strings.Join(Locales(), ", "))
+		}
+		for k, v := range builtin {
+			answer[k] = v
+		}
+	}
+	if file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the strings file %s", file)
+		}
+		strs := map[string]string{}
+		err = yaml.Unmarshal(data, &strs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal the strings file %s", file)
+		}
+		for k, v := range strs {
+			answer[k] = v
+		}
+	}
+	if len(answer) == 0 {
+		return nil, nil
+	}
+	return answer, nil
+}
+
+// Translate returns the translation of the English text or the text itself if it has no translation
+func (t Translations) Translate(text string) string {
+	if translated := t[text]; translated != "" {
+		return translated
+	}
+	return text
+}
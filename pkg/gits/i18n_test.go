@@ -0,0 +1,69 @@
+// +build unit
+
+package gits_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslations(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	translations, err := gits.LoadTranslations("", "")
+	require.NoError(t, err)
+	assert.Nil(t, translations)
+	assert.Equal(t, "Bug Fixes", translations.Translate("Bug Fixes"))
+
+	translations, err = gits.LoadTranslations("de_AT", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Fehlerbehebungen", translations.Translate("Bug Fixes"))
+
+	_, err = gits.LoadTranslations("tlh", "")
+	assert.Error(t, err)
+
+	path := filepath.Join(tmpDir, "strings.yaml")
+	err = ioutil.WriteFile(path, []byte(`Bug Fixes: Korrekturen
+Security: Sicherheit
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	translations, err = gits.LoadTranslations("de", path)
+	require.NoError(t, err)
+
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "feat: the wine"},
+			{SHA: "2222222222", Message: "fix: the cork"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+	opts := &gits.MarkdownOptions{
+		Translations: translations,
+		Compare:      gits.NewCompare(gitInfo, "", "v1.2.0", "v1.3.0"),
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	expected := `## Änderungen
+
+### Neue Funktionen
+
+* the wine
+
+### Korrekturen
+
+* the cork
+
+**Vollständiges Änderungsprotokoll**: [v1.2.0...v1.3.0](https://github.com/jstrachan/foo/compare/v1.2.0...v1.3.0)
+`
+	assert.Equal(t, expected, markdown)
+}
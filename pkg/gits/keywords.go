@@ -0,0 +1,74 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+)
+
+// KeywordRule classifies commits which do not follow the convention by the keywords in their subject
+type KeywordRule struct {
+	// Type the commit type of the matching commits such as 'fix'
+	Type string `json:"type"`
+	// Keywords the words which identify the type. Plurals and past tenses such as 'fixes' or 'fixed' also match
+	Keywords []string `json:"keywords"`
+}
+
+// DefaultKeywordRules the default rules for classifying commits which are not Conventional Commits.
+// The rules are checked in order so more specific types come first
+var DefaultKeywordRules = []KeywordRule{
+	{Type: "revert", Keywords: []string{"revert", "rollback", "roll back"}},
+	{Type: "fix", Keywords: []string{"fix", "bug", "bugfix", "hotfix", "patch", "resolve", "crash", "broken"}},
+	{Type: "docs", Keywords: []string{"doc", "documentation", "readme", "changelog", "typo"}},
+	{Type: "test", Keywords: []string{"test", "testing", "spec", "coverage"}},
+	{Type: "perf", Keywords: []string{"performance", "perf", "speed up", "faster", "optimize", "optimise"}},
+	{Type: "refactor", Keywords: []string{"refactor", "cleanup", "clean up", "rename", "move", "simplify", "tidy"}},
+	{Type: "style", Keywords: []string{"format", "formatting", "lint", "whitespace"}},
+	{Type: "chore", Keywords: []string{"upgrade", "bump", "dependency", "dependencies", "release", "version", "ci", "build"}},
+	{Type: "feat", Keywords: []string{"add", "feature", "implement", "introduce", "new", "support", "allow", "enable"}},
+}
+
+// KeywordClassifier classifies commits using keyword rules
+type KeywordClassifier struct {
+	rules   []KeywordRule
+	regexes []*regexp.Regexp
+}
+
+// NewKeywordClassifier creates a classifier for the given rules
+func NewKeywordClassifier(rules []KeywordRule) *KeywordClassifier {
+	answer := &KeywordClassifier{}
+	for _, rule := range rules {
+		var words []string
+		for _, k := range rule.Keywords {
+			k = strings.TrimSpace(k)
+			if k == "" {
+				continue
+			}
+			words = append(words, regexp.QuoteMeta(k))
+			if strings.HasSuffix(k, "e") {
+				// lets match words such as 'upgrading'
+				words = append(words, regexp.QuoteMeta(strings.TrimSuffix(k, "e")+"ing"))
+			}
+		}
+		if rule.Type == "" || len(words) == 0 {
+			continue
+		}
+		answer.rules = append(answer.rules, rule)
+		answer.regexes = append(answer.regexes, regexp.MustCompile(`(?i)\b(`+strings.Join(words, "|")+`)(s|es|d|ed|ing)?\b`))
+	}
+	return answer
+}
+
+// Classify returns the type of the first rule with a keyword in the subject of the message
+// or an empty string if there is no match
+func (c *KeywordClassifier) Classify(message string) string {
+	if c == nil {
+		return ""
+	}
+	subject := strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+	for i, re := range c.regexes {
+		if re.MatchString(subject) {
+			return strings.ToLower(c.rules[i].Type)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywordClassifier(t *testing.T) {
+	t.Parallel()
+	classifier := gits.NewKeywordClassifier(gits.DefaultKeywordRules)
+	testCases := map[string]string{
+		"Fixed the cork":                   "fix",
+		"Added cheese":                     "feat",
+		"Update the README":                "docs",
+		"upgrading wine to 2.0":            "chore",
+		"Bumps github.com/foo/bar":         "chore",
+		"Refactoring the cellar":           "refactor",
+		"Revert \"Added cheese\"":          "revert",
+		"address the prefix\n\nfix things": "",
+		"wip":                              "",
+	}
+	for message, expected := range testCases {
+		assert.Equal(t, expected, classifier.Classify(message), "for message %s", message)
+	}
+
+	custom := gits.NewKeywordClassifier([]gits.KeywordRule{{Type: "Feat", Keywords: []string{"cheese"}}})
+	assert.Equal(t, "feat", custom.Classify("more cheese"))
+	assert.Equal(t, "", custom.Classify("Fixed the cork"))
+
+	var noClassifier *gits.KeywordClassifier
+	assert.Equal(t, "", noClassifier.Classify("Fixed the cork"))
+}
+
+func TestClassifyByKeywordsMarkdown(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "3", Message: "Fixed the cork"},
+			{SHA: "2", Message: "feat: wine"},
+			{SHA: "1", Message: "Added cheese"},
+			{SHA: "0", Message: "wip"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+		Keywords: gits.NewKeywordClassifier(gits.DefaultKeywordRules),
+	})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### New Features
+
+* wine
+* Added cheese
+
+### Bug Fixes
+
+* Fixed the cork
+
+### Other Changes
+
+These commits did not use [Conventional Commits](https://conventionalcommits.org/) formatted messages:
+
+* wip
+`
+	assert.Equal(t, expected, markdown)
+}
@@ -0,0 +1,60 @@
+package gits
+
+import (
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// BreakingLabelType the type of the labels which mark the commits of their pull requests as breaking changes
+const BreakingLabelType = "breaking"
+
+// labelCommit returns the commit info with the type mapped from the labels of the pull requests of the commit which
+// overrides the type of the commit message. Labels mapped to BreakingLabelType mark the commit as a breaking change
+func (o *MarkdownOptions) labelCommit(cs *v1.CommitSummary, ci *CommitInfo, prMap map[string]*v1.IssueSummary) *CommitInfo {
+	if len(o.LabelTypes) == 0 {
+		return ci
+	}
+	kind := ""
+	breaking := false
+	for _, id := range cs.IssueIDs {
+		pr := prMap[id]
+		if pr == nil {
+			continue
+		}
+		for _, label := range pr.Labels {
+			t, ok := o.labelType(label.Name)
+			if !ok {
+				continue
+			}
+			if t == BreakingLabelType {
+				breaking = true
+			} else if kind == "" {
+				kind = t
+			}
+		}
+	}
+	if kind == "" && (!breaking || ci.Breaking) {
+		return ci
+	}
+	answer := *ci
+	answer.group = nil
+	if kind != "" {
+		answer.Kind = kind
+	}
+	if breaking && !answer.Breaking {
+		answer.Breaking = true
+		answer.BreakingChange = strings.SplitN(strings.TrimSpace(answer.Message), "\n", 2)[0]
+	}
+	return &answer
+}
+
+// labelType returns the commit type the label is mapped to ignoring case
+func (o *MarkdownOptions) labelType(label string) (string, bool) {
+	for k, v := range o.LabelTypes {
+		if strings.EqualFold(k, label) {
+			return strings.ToLower(v), true
+		}
+	}
+	return "", false
+}
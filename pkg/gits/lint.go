@@ -0,0 +1,117 @@
+package gits
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/pkg/errors"
+)
+
+// templateErrorRegex matches the 'template: name:line:column: message' errors of text/template and html/template
+var templateErrorRegex = regexp.MustCompile(`template: ([^:]+):(\d+)(?::(\d+))?: (.*)`)
+
+// TemplateError an error parsing or executing a template at a line of the template
+type TemplateError struct {
+	// Template the name of the template or partial template
+	Template string
+	Line     int
+	// Column the column of the error which is only known for execution errors
+	Column  int
+	Message string
+}
+
+// Error returns the error in the 'name:line:column: message' format of compilers
+func (e *TemplateError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", e.Template, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.Template, e.Line, e.Message)
+}
+
+// ToTemplateError returns the template error with the line number of an error returned by RenderTemplate or nil if
+// the error has no line number
+func ToTemplateError(err error) *TemplateError {
+	if err == nil {
+		return nil
+	}
+	m := templateErrorRegex.FindStringSubmatch(errors.Cause(err).Error())
+	if m == nil {
+		return nil
+	}
+	line, _ := strconv.Atoi(m[2])
+	column, _ := strconv.Atoi(m[3])
+	return &TemplateError{Template: m[1], Line: line, Column: column, Message: m[4]}
+}
+
+// SampleChangelog returns a changelog using every field of the model which is used as fixture data to validate
+// changelog templates without a git repository
+func SampleChangelog() *Changelog {
+	url := "https://github.com/jstrachan/foo"
+	james := v1.UserDetails{Login: "jstrachan", Name: "James Strachan", URL: "https://github.com/jstrachan"}
+	cheese := v1.UserDetails{Login: "cheese", Name: "Cheese", URL: "https://github.com/cheese"}
+	return &Changelog{
+		Version:         "1.2.0",
+		Title:           "1.2.0",
+		Date:            time.Date(2021, time.March, 31, 10, 0, 0, 0, time.UTC),
+		URL:             url,
+		ReleaseNotesURL: url + "/releases/tag/v1.2.0",
+		Compare:         &Compare{From: "v1.1.0", To: "v1.2.0", URL: url + "/compare/v1.1.0...v1.2.0"},
+		BreakingChanges: []string{"the config file has moved to .jx/changelog.yaml"},
+		Sections: []*Section{
+			{
+				Title: "New Features",
+				Entries: []*Entry{
+					{
+						SHA:         "1111111111111111111111111111111111111111",
+						URL:         url + "/commit/1111111111111111111111111111111111111111",
+						Type:        "feat",
+						Scope:       "config",
+						Subject:     "move the config file",
+						Body:        "BREAKING CHANGE: the config file has moved to .jx/changelog.yaml",
+						Breaking:    true,
+						Authors:     []*v1.UserDetails{&james},
+						PullRequest: &Reference{ID: "#12", URL: url + "/pull/12", Title: "Move the config file"},
+						Issues:      []*Reference{{ID: "#10", URL: url + "/issues/10", Title: "Config file location"}},
+						Stats:       &DiffStats{FilesChanged: 3, Insertions: 42, Deletions: 7},
+					},
+				},
+			},
+			{
+				Title: "Bug Fixes",
+				Entries: []*Entry{
+					{
+						SHA:        "2222222222222222222222222222222222222222",
+						URL:        url + "/commit/2222222222222222222222222222222222222222",
+						Type:       "fix",
+						Subject:    "the cork",
+						Authors:    []*v1.UserDetails{&cheese, &james},
+						Duplicates: []string{"3333333333333333333333333333333333333333"},
+					},
+				},
+			},
+		},
+		Contributors: []*contributors.Contributor{
+			{User: james, Emails: []string{"james@example.com"}, Commits: 1},
+			{User: cheese, Emails: []string{"cheese@example.com"}, Commits: 1},
+		},
+		DependencyUpdates: []v1.DependencyUpdate{
+			{
+				DependencyUpdateDetails: v1.DependencyUpdateDetails{
+					Host:               "github.com",
+					Owner:              "jenkins-x",
+					Repo:               "jx-helpers",
+					URL:                "https://github.com/jenkins-x/jx-helpers",
+					Component:          "jx-helpers",
+					FromVersion:        "3.0.1",
+					FromReleaseHTMLURL: "https://github.com/jenkins-x/jx-helpers/releases/tag/v3.0.1",
+					ToVersion:          "3.0.2",
+					ToReleaseHTMLURL:   "https://github.com/jenkins-x/jx-helpers/releases/tag/v3.0.2",
+				},
+			},
+		},
+	}
+}
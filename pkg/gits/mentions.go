@@ -0,0 +1,70 @@
+package gits
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+)
+
+// MentionedContributors returns the authors and co-authors of the commits sorted by their number of commits with the
+// most active first. Bots detected by the filter or by the default bot suffixes if it is nil are excluded
+func MentionedContributors(commits []v1.CommitSummary, coAuthors map[string][]v1.UserDetails, bots *users.BotFilter) []*contributors.Contributor {
+	if bots == nil {
+		bots = users.NewBotFilter(nil, nil)
+	}
+	var authored []v1.CommitSummary
+	for i := range commits {
+		cs := &commits[i]
+		if cs.Author != nil {
+			authored = append(authored, v1.CommitSummary{SHA: cs.SHA, Author: cs.Author})
+		}
+		for j := range coAuthors[cs.SHA] {
+			authored = append(authored, v1.CommitSummary{SHA: cs.SHA, Author: &coAuthors[cs.SHA][j]})
+		}
+	}
+	var answer []*contributors.Contributor
+	for _, c := range contributors.FromCommits(authored) {
+		if !bots.IsBotUser(&c.User) {
+			answer = append(answer, c)
+		}
+	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		return answer[i].Commits > answer[j].Commits
+	})
+	return answer
+}
+
+// describeMentions describes the contributors as mentions which notify them on the git provider
+func describeMentions(info *giturl.GitRepository, contributors []*contributors.Contributor, opts *MarkdownOptions) string {
+	var mentions []string
+	for _, c := range contributors {
+		if text := describeMention(info, &c.User, opts.Flavor); text != "" {
+			mentions = append(mentions, text)
+		}
+	}
+	if len(mentions) == 0 {
+		return ""
+	}
+	return "**" + opts.Translations.Translate("Contributors") + "**: " + strings.Join(mentions, ", ") + "\n"
+}
+
+// describeMention returns the mention of the user. Bitbucket mentions use the account ID of the user so users
+// without a Bitbucket account are linked instead as are users without a login
+func describeMention(info *giturl.GitRepository, user *v1.UserDetails, flavor *MarkdownFlavor) string {
+	if user.Login == "" {
+		return describeUserLink(info, user, flavor)
+	}
+	if flavor != nil && flavor.Name == FlavorBitbucket {
+		for _, account := range user.Accounts {
+			if strings.Contains(strings.ToLower(account.Provider), "bitbucket") && account.ID != "" {
+				return "@{" + account.ID + "}"
+			}
+		}
+		return describeUserLink(info, user, flavor)
+	}
+	return "@" + user.Login
+}
@@ -0,0 +1,53 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionContributors(t *testing.T) {
+	t.Parallel()
+	alice := &v1.UserDetails{Login: "alice", Accounts: []v1.AccountReference{{Provider: "bitbucket", ID: "557058:1234"}}}
+	bob := &v1.UserDetails{Login: "bob", Email: "bob@example.com"}
+	bot := &v1.UserDetails{Login: "dependabot[bot]"}
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "fix: the cork", Author: alice},
+			{SHA: "2222222222", Message: "fix: the bottle", Author: bob},
+			{SHA: "3333333333", Message: "chore: bump deps", Author: bot},
+			{SHA: "4444444444", Message: "feat: the wine", Author: bob},
+			{SHA: "5555555555", Message: "fix: the glass", Author: alice},
+		},
+	}
+	coAuthors := map[string][]v1.UserDetails{
+		"4444444444": {{Name: "Carol", Email: "carol@example.com"}},
+		"5555555555": {{Name: "Bob", Email: "bob@example.com"}},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+
+	contributors := gits.MentionedContributors(releaseSpec.Commits, coAuthors, nil)
+	var keys []string
+	for _, c := range contributors {
+		keys = append(keys, c.Key())
+	}
+	assert.Equal(t, []string{"bob", "alice", "Carol"}, keys)
+
+	opts := &gits.MarkdownOptions{MentionContributors: true, CoAuthors: coAuthors}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "\n**Contributors**: @bob, @alice, Carol\n")
+
+	flavor, err := gits.GetMarkdownFlavor(gits.FlavorBitbucket)
+	require.NoError(t, err)
+	opts.Flavor = flavor
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "\n**Contributors**: [bob](https://github.com/bob), @{557058:1234}, Carol\n")
+}
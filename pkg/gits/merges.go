@@ -0,0 +1,96 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const (
+	// MergeStrategyAll includes all the commits of the merged branches and the merge commits
+	MergeStrategyAll = "all"
+
+	// MergeStrategyNoMerges includes all the commits of the merged branches but not the merge commits
+	MergeStrategyNoMerges = "no-merges"
+
+	// MergeStrategyFirstParent only includes the commits on the first parent history of the current revision
+	MergeStrategyFirstParent = "first-parent"
+
+	// MergeStrategyPullRequests only includes the commits on the first parent history using the pull request
+	// title in the body of the merge commits as their message
+	MergeStrategyPullRequests = "pull-requests"
+)
+
+// MergeStrategies the supported strategies for handling merge commits
+var MergeStrategies = []string{MergeStrategyAll, MergeStrategyNoMerges, MergeStrategyFirstParent, MergeStrategyPullRequests}
+
+var mergeSubjectRegex = regexp.MustCompile(`^Merge (pull request|branch|remote-tracking branch) `)
+
+// FilterCommitsByMergeStrategy returns the commits to include in the changelog using the merge strategy.
+// The commits are expected in the order returned by git log with the current revision first
+func FilterCommitsByMergeStrategy(commits []object.Commit, strategy string) []object.Commit {
+	switch strategy {
+	case MergeStrategyAll:
+		return commits
+	case MergeStrategyFirstParent:
+		return firstParentCommits(commits)
+	case MergeStrategyPullRequests:
+		answer := firstParentCommits(commits)
+		for i := range answer {
+			if len(answer[i].ParentHashes) > 1 {
+				answer[i].Message = pullRequestMergeMessage(answer[i].Message)
+			}
+		}
+		return answer
+	default:
+		var answer []object.Commit
+		for i := range commits {
+			if len(commits[i].ParentHashes) <= 1 {
+				answer = append(answer, commits[i])
+			}
+		}
+		return answer
+	}
+}
+
+// firstParentCommits returns the commits on the first parent history of the first commit
+func firstParentCommits(commits []object.Commit) []object.Commit {
+	if len(commits) == 0 {
+		return commits
+	}
+	indexes := map[string]int{}
+	for i := range commits {
+		indexes[commits[i].Hash.String()] = i
+	}
+	var answer []object.Commit
+	idx := 0
+	for {
+		c := commits[idx]
+		answer = append(answer, c)
+		if len(c.ParentHashes) == 0 {
+			break
+		}
+		next, ok := indexes[c.ParentHashes[0].String()]
+		if !ok {
+			break
+		}
+		idx = next
+	}
+	return answer
+}
+
+// pullRequestMergeMessage returns the message of a merge commit using the pull request title in the body as the subject
+// followed by the original subject so that the pull request can still be linked
+func pullRequestMergeMessage(message string) string {
+	message = strings.TrimSpace(message)
+	lines := strings.SplitN(message, "\n", 2)
+	if len(lines) < 2 || !mergeSubjectRegex.MatchString(lines[0]) {
+		return message
+	}
+	body := strings.TrimSpace(lines[1])
+	if body == "" {
+		return message
+	}
+	return body + "\n\n" + lines[0]
+}
@@ -0,0 +1,58 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestFilterCommitsByMergeStrategy(t *testing.T) {
+	t.Parallel()
+	hash := func(s string) plumbing.Hash {
+		return plumbing.NewHash(s)
+	}
+	// main: 1 <- 4 (merge of 2, 3 from the feature branch) <- 5
+	commits := []object.Commit{
+		{Hash: hash("5555"), Message: "fix: the cork", ParentHashes: []plumbing.Hash{hash("4444")}},
+		{Hash: hash("4444"), Message: "Merge pull request #12 from jstrachan/cheese\n\nfeat: add cheese", ParentHashes: []plumbing.Hash{hash("1111"), hash("3333")}},
+		{Hash: hash("3333"), Message: "feat: more cheese", ParentHashes: []plumbing.Hash{hash("2222")}},
+		{Hash: hash("2222"), Message: "feat: cheese", ParentHashes: []plumbing.Hash{hash("1111")}},
+		{Hash: hash("1111"), Message: "chore: wine", ParentHashes: []plumbing.Hash{hash("0000")}},
+	}
+
+	testCases := []struct {
+		strategy string
+		expected []string
+	}{
+		{
+			strategy: gits.MergeStrategyAll,
+			expected: []string{"fix: the cork", "Merge pull request #12 from jstrachan/cheese\n\nfeat: add cheese", "feat: more cheese", "feat: cheese", "chore: wine"},
+		},
+		{
+			strategy: gits.MergeStrategyNoMerges,
+			expected: []string{"fix: the cork", "feat: more cheese", "feat: cheese", "chore: wine"},
+		},
+		{
+			strategy: gits.MergeStrategyFirstParent,
+			expected: []string{"fix: the cork", "Merge pull request #12 from jstrachan/cheese\n\nfeat: add cheese", "chore: wine"},
+		},
+		{
+			strategy: gits.MergeStrategyPullRequests,
+			expected: []string{"fix: the cork", "feat: add cheese\n\nMerge pull request #12 from jstrachan/cheese", "chore: wine"},
+		},
+	}
+	for _, tc := range testCases {
+		input := append([]object.Commit{}, commits...)
+		filtered := gits.FilterCommitsByMergeStrategy(input, tc.strategy)
+		var messages []string
+		for i := range filtered {
+			messages = append(messages, filtered[i].Message)
+		}
+		assert.Equal(t, tc.expected, messages, "for strategy %s", tc.strategy)
+	}
+}
@@ -0,0 +1,212 @@
+package gits
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+)
+
+// Changelog the parsed changelog of a release which is rendered by the output formats other than markdown
+type Changelog struct {
+	// Version the version of the release
+	Version string `json:"version,omitempty"`
+	// Title the title of the release. Defaults to the version
+	Title string `json:"title,omitempty"`
+	// Date the date the changelog was generated
+	Date time.Time `json:"date"`
+	// URL the URL of the git repository
+	URL string `json:"url,omitempty"`
+	// ReleaseNotesURL the URL of the release on the git provider if it has been updated
+	ReleaseNotesURL string `json:"releaseNotesUrl,omitempty"`
+	// Compare the link to the comparison with the previous release
+	Compare *Compare `json:"compare,omitempty"`
+	// BreakingChanges the descriptions of the breaking changes
+	BreakingChanges []string `json:"breakingChanges,omitempty"`
+	// BreakingChangesEmoji the emoji rendered before the title of the breaking changes unless emoji are disabled
+	BreakingChangesEmoji string `json:"breakingChangesEmoji,omitempty"`
+	// SecurityAdvisories the published security advisories fixed by the release
+	SecurityAdvisories []SecurityAdvisory `json:"securityAdvisories,omitempty"`
+	// Sections the sections of the changelog in the order they are rendered
+	Sections []*Section `json:"sections,omitempty"`
+	// Contributors the authors of the commits
+	Contributors []*contributors.Contributor `json:"contributors,omitempty"`
+	// DependencyUpdates the updated dependencies
+	DependencyUpdates []v1.DependencyUpdate `json:"dependencyUpdates,omitempty"`
+}
+
+// Section a section of the changelog such as 'New Features' or 'Bug Fixes'
+type Section struct {
+	Title string `json:"title"`
+	Emoji string `json:"emoji,omitempty"`
+	// Collapsed the entries are rendered inside a collapsible block
+	Collapsed bool     `json:"collapsed,omitempty"`
+	Entries   []*Entry `json:"entries"`
+}
+
+// Entry a commit in a section of the changelog
+type Entry struct {
+	SHA      string `json:"sha"`
+	URL      string `json:"url,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body,omitempty"`
+	Breaking bool   `json:"breaking,omitempty"`
+	// Authors the author and co-authors of the commit
+	Authors []*v1.UserDetails `json:"authors,omitempty"`
+	// PullRequest the pull request which merged the commit
+	PullRequest *Reference `json:"pullRequest,omitempty"`
+	// Issues the issues referenced by the commit
+	Issues []*Reference `json:"issues,omitempty"`
+	// Duplicates the SHAs of the commits with the same subject collapsed into this entry
+	Duplicates []string   `json:"duplicates,omitempty"`
+	Stats      *DiffStats `json:"stats,omitempty"`
+}
+
+// Reference a reference to an issue or pull request
+type Reference struct {
+	ID    string `json:"id"`
+	URL   string `json:"url,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+// CreateChangelog creates the changelog of the release grouping the commits into sections in the same way as
+// GenerateMarkdownWithOptions so that every output format has the same content
+func CreateChangelog(releaseSpec *v1.ReleaseSpec, gitInfo *giturl.GitRepository, date time.Time, opts *MarkdownOptions) *Changelog {
+	if opts == nil {
+		opts = &MarkdownOptions{}
+	}
+	answer := &Changelog{
+		Version:           releaseSpec.Version,
+		Date:              date,
+		URL:               releaseSpec.GitHTTPURL,
+		ReleaseNotesURL:   releaseSpec.ReleaseNotesURL,
+		Compare:           opts.Compare,
+		Contributors:      contributors.FromCommits(releaseSpec.Commits),
+		DependencyUpdates: releaseSpec.DependencyUpdates,
+	}
+	answer.SecurityAdvisories = opts.SecurityAdvisories
+	if !opts.DisableEmoji {
+		answer.BreakingChangesEmoji = breakingChangesEmoji
+	}
+	issueMap := map[string]*v1.IssueSummary{}
+	for i := range releaseSpec.Issues {
+		issueMap[releaseSpec.Issues[i].ID] = &releaseSpec.Issues[i]
+	}
+	prMap := map[string]*v1.IssueSummary{}
+	for i := range releaseSpec.PullRequests {
+		prMap[releaseSpec.PullRequests[i].ID] = &releaseSpec.PullRequests[i]
+	}
+	labelMap := issueLabels(releaseSpec)
+
+	sections := map[*CommitGroup]*Section{}
+	var groups []*CommitGroup
+	keys := map[*Entry]*entrySortKey{}
+	commits, duplicates := DeduplicateCommits(releaseSpec.Commits, opts.Deduplicate)
+	for i := range commits {
+		cs := &commits[i]
+		if cs.Message == "" {
+			continue
+		}
+		ci := opts.parseCommit(cs.Message)
+		if opts.PullRequestTitles {
+			ci = opts.pullRequestTitleCommit(cs, ci, prMap)
+		}
+		ci = opts.labelCommit(cs, ci, prMap)
+		if ci.Breaking {
+			text := breakingChangeText(ci)
+			if stringhelpers.StringArrayIndex(answer.BreakingChanges, text) < 0 {
+				answer.BreakingChanges = append(answer.BreakingChanges, text)
+			}
+		}
+		group := opts.commitGroup(cs, ci, labelMap)
+		if group == nil || group.Hidden {
+			continue
+		}
+		entry := createEntry(gitInfo, cs, ci, issueMap, prMap, opts)
+		if shas := duplicates[cs.SHA]; len(shas) > 1 {
+			entry.Duplicates = shas
+		}
+		stats := opts.diffStats(cs.SHA)
+		if opts.ShowDiffStats {
+			entry.Stats = stats
+		}
+		keys[entry] = opts.entrySortKey(i, cs, ci, stats)
+
+		section := sections[group]
+		if section == nil {
+			title := opts.Translations.Translate(group.Title)
+			if title == "" {
+				title = opts.Translations.Translate("Other Changes")
+			}
+			section = &Section{Title: title, Emoji: opts.sectionEmoji(group), Collapsed: opts.collapsed(group) && opts.Flavor.details()}
+			sections[group] = section
+			groups = append(groups, group)
+		}
+		section.Entries = append(section.Entries, entry)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Order < groups[j].Order
+	})
+	for _, group := range groups {
+		section := sections[group]
+		if strategy := opts.sortStrategy(); strategy != "" {
+			sort.SliceStable(section.Entries, func(i, j int) bool {
+				return lessEntry(strategy, keys[section.Entries[i]], keys[section.Entries[j]])
+			})
+		}
+		answer.Sections = append(answer.Sections, section)
+	}
+	return answer
+}
+
+// createEntry creates the changelog entry of the commit
+func createEntry(info *giturl.GitRepository, cs *v1.CommitSummary, ci *CommitInfo, issueMap, prMap map[string]*v1.IssueSummary, opts *MarkdownOptions) *Entry {
+	message := strings.TrimSpace(ci.Message)
+	lines := strings.SplitN(message, "\n", 2)
+	entry := &Entry{
+		SHA:      cs.SHA,
+		URL:      cs.URL,
+		Type:     ci.Kind,
+		Scope:    ci.Feature,
+		Subject:  strings.TrimSpace(lines[0]),
+		Breaking: ci.Breaking,
+	}
+	if entry.URL == "" {
+		entry.URL = commitURL(info, cs.SHA)
+	}
+	if len(lines) > 1 {
+		entry.Body = strings.TrimSpace(lines[1])
+	}
+	for _, user := range commitAuthors(cs, opts) {
+		if user != nil {
+			entry.Authors = append(entry.Authors, user)
+		}
+	}
+	for _, id := range cs.IssueIDs {
+		if pr := prMap[id]; pr != nil {
+			if entry.PullRequest == nil {
+				entry.PullRequest = createReference(pr)
+			}
+			continue
+		}
+		if issue := issueMap[id]; issue != nil {
+			entry.Issues = append(entry.Issues, createReference(issue))
+		}
+	}
+	return entry
+}
+
+func createReference(issue *v1.IssueSummary) *Reference {
+	return &Reference{
+		ID:    issue.ID,
+		URL:   issue.URL,
+		Title: issue.Title,
+	}
+}
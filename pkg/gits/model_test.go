@@ -0,0 +1,69 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogModel(t *testing.T) {
+	t.Parallel()
+	james := &v1.UserDetails{Login: "jstrachan", Name: "James Strachan"}
+	releaseSpec := &v1.ReleaseSpec{
+		Version:    "1.2.0",
+		GitHTTPURL: "https://github.com/jstrachan/foo",
+		Commits: []v1.CommitSummary{
+			{SHA: "4444444444", Message: "fix(api): the cork\n\nit was leaking", Author: james, IssueIDs: []string{"12", "7"}},
+			{SHA: "3333333333", Message: "feat!: wine\n\nBREAKING CHANGE: bottles are required", Author: james},
+			{SHA: "2222222222", Message: "chore: tidy", Author: james},
+			{SHA: "1111111111", Message: "cheese", Author: &v1.UserDetails{Name: "Bob"}},
+		},
+		Issues: []v1.IssueSummary{
+			{ID: "7", URL: "https://github.com/jstrachan/foo/issues/7", Title: "leaky cork"},
+		},
+		PullRequests: []v1.IssueSummary{
+			{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12", Title: "fix the cork"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	date := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	changelog := gits.CreateChangelog(releaseSpec, gitInfo, date, nil)
+
+	assert.Equal(t, "1.2.0", changelog.Version)
+	assert.Equal(t, date, changelog.Date)
+	assert.Equal(t, []string{"bottles are required"}, changelog.BreakingChanges)
+	require.Len(t, changelog.Contributors, 2)
+
+	var titles []string
+	for _, s := range changelog.Sections {
+		titles = append(titles, s.Title)
+	}
+	assert.Equal(t, []string{"New Features", "Bug Fixes", "Chores", "Other Changes"}, titles)
+
+	fix := changelog.Sections[1].Entries[0]
+	assert.Equal(t, "4444444444", fix.SHA)
+	assert.Equal(t, "https://github.com/jstrachan/foo/commit/4444444444", fix.URL)
+	assert.Equal(t, "fix", fix.Type)
+	assert.Equal(t, "api", fix.Scope)
+	assert.Equal(t, "the cork", fix.Subject)
+	assert.Equal(t, "it was leaking", fix.Body)
+	assert.Equal(t, []*v1.UserDetails{james}, fix.Authors)
+	require.NotNil(t, fix.PullRequest)
+	assert.Equal(t, "12", fix.PullRequest.ID)
+	require.Len(t, fix.Issues, 1)
+	assert.Equal(t, "leaky cork", fix.Issues[0].Title)
+
+	assert.True(t, changelog.Sections[0].Entries[0].Breaking)
+}
@@ -0,0 +1,115 @@
+package gits
+
+import (
+	"regexp"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/codeowners"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// PathFilter filters the commits of a monorepo by the paths they touch
+type PathFilter struct {
+	includes []*regexp.Regexp
+	excludes []*regexp.Regexp
+}
+
+// NewPathFilter creates a filter of the commits touching paths which match any of the include globs, or any path if
+// there are no include globs, and do not match any of the exclude globs. The globs use the gitignore syntax
+// such as 'charts/' or 'docs/**/*.md'. Returns nil if there are no globs
+func NewPathFilter(includes, excludes []string) (*PathFilter, error) {
+	if len(includes) == 0 && len(excludes) == 0 {
+		return nil, nil
+	}
+	answer := &PathFilter{}
+	for _, glob := range includes {
+		re, err := codeowners.PatternRegex(glob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid include path %s", glob)
+		}
+		answer.includes = append(answer.includes, re)
+	}
+	for _, glob := range excludes {
+		re, err := codeowners.PatternRegex(glob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exclude path %s", glob)
+		}
+		answer.excludes = append(answer.excludes, re)
+	}
+	return answer, nil
+}
+
+// Matches returns true if any of the paths relative to the root of the repository matches the filter
+func (f *PathFilter) Matches(paths []string) bool {
+	if f == nil {
+		return true
+	}
+	for _, path := range paths {
+		if f.matchesPath(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *PathFilter) matchesPath(path string) bool {
+	for _, re := range f.excludes {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	if len(f.includes) == 0 {
+		return true
+	}
+	for _, re := range f.includes {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesCommit returns true if the commit touches any path matching the filter
+func (f *PathFilter) MatchesCommit(commit *object.Commit) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+	paths, err := ChangedPaths(commit)
+	if err != nil {
+		return false, err
+	}
+	return f.Matches(paths), nil
+}
+
+// ChangedPaths returns the paths of the files changed by the commit compared to its first parent
+func ChangedPaths(commit *object.Commit) ([]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the tree of commit %s", commit.Hash.String())
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the parent of commit %s", commit.Hash.String())
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the tree of commit %s", parent.Hash.String())
+		}
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the files changed by commit %s", commit.Hash.String())
+	}
+	var answer []string
+	for _, change := range changes {
+		if change.From.Name != "" {
+			answer = append(answer, change.From.Name)
+		}
+		if change.To.Name != "" && change.To.Name != change.From.Name {
+			answer = append(answer, change.To.Name)
+		}
+	}
+	return answer, nil
+}
@@ -0,0 +1,86 @@
+// +build unit
+
+package gits_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestPathFilter(t *testing.T) {
+	t.Parallel()
+	filter, err := gits.NewPathFilter([]string{"charts/", "pkg/**/*.go"}, []string{"*_test.go"})
+	require.NoError(t, err)
+
+	assert.True(t, filter.Matches([]string{"README.md", "charts/cheese/values.yaml"}))
+	assert.True(t, filter.Matches([]string{"pkg/wine/cork.go"}))
+	assert.False(t, filter.Matches([]string{"pkg/wine/cork_test.go"}), "excluded paths should not match")
+	assert.False(t, filter.Matches([]string{"docs/charts.md", "cmd/main.go"}))
+
+	filter, err = gits.NewPathFilter(nil, []string{"docs/"})
+	require.NoError(t, err)
+	assert.True(t, filter.Matches([]string{"docs/README.md", "main.go"}))
+	assert.False(t, filter.Matches([]string{"docs/README.md"}))
+
+	filter, err = gits.NewPathFilter(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+	assert.True(t, filter.Matches([]string{"docs/README.md"}), "a nil filter should match everything")
+}
+
+func TestChangedPaths(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err, "failed to init git repository")
+	wt, err := repo.Worktree()
+	require.NoError(t, err, "failed to get worktree")
+
+	commit := func(message string, files ...string) *object.Commit {
+		for _, file := range files {
+			path := filepath.Join(dir, file)
+			err := os.MkdirAll(filepath.Dir(path), 0700)
+			require.NoError(t, err, "failed to create the directory of %s", file)
+			err = ioutil.WriteFile(path, []byte(message), 0600)
+			require.NoError(t, err, "failed to write %s", file)
+			_, err = wt.Add(file)
+			require.NoError(t, err, "failed to add %s", file)
+		}
+		sig := &object.Signature{Name: "James", Email: "james@example.com", When: time.Now()}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err, "failed to commit %s", message)
+		c, err := repo.CommitObject(hash)
+		require.NoError(t, err, "failed to find commit %s", message)
+		return c
+	}
+
+	root := commit("initial commit", "README.md")
+	change := commit("feat: cheese", "charts/cheese/values.yaml", "pkg/cheese.go")
+
+	paths, err := gits.ChangedPaths(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md"}, paths)
+
+	paths, err = gits.ChangedPaths(change)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"charts/cheese/values.yaml", "pkg/cheese.go"}, paths)
+
+	filter, err := gits.NewPathFilter([]string{"charts/"}, nil)
+	require.NoError(t, err)
+	matches, err := filter.MatchesCommit(change)
+	require.NoError(t, err)
+	assert.True(t, matches)
+	matches, err = filter.MatchesCommit(root)
+	require.NoError(t, err)
+	assert.False(t, matches)
+}
@@ -0,0 +1,82 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+var (
+	revertSubjectRegex = regexp.MustCompile(`^Revert "(.*)"$`)
+	revertBodyRegex    = regexp.MustCompile(`This reverts commit ([0-9a-fA-F]{7,40})`)
+)
+
+// RevertedCommit a commit which was reverted by another commit in the same release
+type RevertedCommit struct {
+	// Commit the reverted commit
+	Commit v1.CommitSummary
+	// Revert the commit reverting it
+	Revert v1.CommitSummary
+}
+
+// RemoveRevertedCommits removes the commits which are reverted by other commits in the same list together with the
+// commits reverting them. Reverts are detected using the 'This reverts commit <sha>' message body git generates or
+// failing that the 'Revert "<subject>"' subject
+func RemoveRevertedCommits(commits []v1.CommitSummary) ([]v1.CommitSummary, []RevertedCommit) {
+	removed := map[int]bool{}
+	var reverted []RevertedCommit
+	for i := range commits {
+		if removed[i] {
+			continue
+		}
+		j := findRevertedCommit(commits, i, removed)
+		if j < 0 {
+			continue
+		}
+		removed[i] = true
+		removed[j] = true
+		reverted = append(reverted, RevertedCommit{Commit: commits[j], Revert: commits[i]})
+	}
+	if len(reverted) == 0 {
+		return commits, nil
+	}
+	var answer []v1.CommitSummary
+	for i := range commits {
+		if !removed[i] {
+			answer = append(answer, commits[i])
+		}
+	}
+	return answer, reverted
+}
+
+// findRevertedCommit returns the index of the commit reverted by the commit at the given index or -1 if it is not a
+// revert of any of the commits
+func findRevertedCommit(commits []v1.CommitSummary, idx int, removed map[int]bool) int {
+	message := strings.TrimSpace(commits[idx].Message)
+	m := revertBodyRegex.FindStringSubmatch(message)
+	if m != nil {
+		sha := strings.ToLower(m[1])
+		for j := range commits {
+			if j != idx && !removed[j] && commits[j].SHA != "" && strings.HasPrefix(strings.ToLower(commits[j].SHA), sha) {
+				return j
+			}
+		}
+		return -1
+	}
+	m = revertSubjectRegex.FindStringSubmatch(subject(message))
+	if m == nil {
+		return -1
+	}
+	for j := range commits {
+		if j != idx && !removed[j] && subject(strings.TrimSpace(commits[j].Message)) == m[1] {
+			return j
+		}
+	}
+	return -1
+}
+
+// subject returns the first line of the commit message
+func subject(message string) string {
+	return strings.SplitN(message, "\n", 2)[0]
+}
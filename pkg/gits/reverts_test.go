@@ -0,0 +1,80 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveRevertedCommits(t *testing.T) {
+	t.Parallel()
+	commits := []v1.CommitSummary{
+		{SHA: "5555555555", Message: "Revert \"fix: the cork\"\n\nThis reverts commit 11111111.\n"},
+		{SHA: "4444444444", Message: "Revert \"feat: cheese\""},
+		{SHA: "3333333333", Message: "feat: wine"},
+		{SHA: "2222222222", Message: "feat: cheese"},
+		{SHA: "1111111111", Message: "fix: the cork"},
+		{SHA: "0000000000", Message: "Revert \"feat: older\"\n\nThis reverts commit abcdef12."},
+	}
+
+	kept, reverted := gits.RemoveRevertedCommits(commits)
+
+	var keptSHAs []string
+	for _, c := range kept {
+		keptSHAs = append(keptSHAs, c.SHA)
+	}
+	assert.Equal(t, []string{"3333333333", "0000000000"}, keptSHAs)
+	require.Len(t, reverted, 2)
+	assert.Equal(t, "1111111111", reverted[0].Commit.SHA)
+	assert.Equal(t, "5555555555", reverted[0].Revert.SHA)
+	assert.Equal(t, "2222222222", reverted[1].Commit.SHA)
+	assert.Equal(t, "4444444444", reverted[1].Revert.SHA)
+}
+
+func TestRemoveRevertedRevert(t *testing.T) {
+	t.Parallel()
+	commits := []v1.CommitSummary{
+		{SHA: "3333333333", Message: "Revert \"Revert \"feat: cheese\"\"\n\nThis reverts commit 2222222222."},
+		{SHA: "2222222222", Message: "Revert \"feat: cheese\"\n\nThis reverts commit 1111111111."},
+		{SHA: "1111111111", Message: "feat: cheese"},
+	}
+
+	kept, reverted := gits.RemoveRevertedCommits(commits)
+	require.Len(t, kept, 1)
+	assert.Equal(t, "1111111111", kept[0].SHA)
+	assert.Len(t, reverted, 1)
+}
+
+func TestRevertedMarkdown(t *testing.T) {
+	t.Parallel()
+	kept, reverted := gits.RemoveRevertedCommits([]v1.CommitSummary{
+		{SHA: "2222222222", Message: "Revert \"feat: cheese\""},
+		{SHA: "1111111111", Message: "feat: cheese"},
+		{SHA: "0000000000", Message: "fix: the cork"},
+	})
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(&v1.ReleaseSpec{Commits: kept}, gitInfo, &gits.MarkdownOptions{Reverted: reverted})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### Bug Fixes
+
+* the cork
+
+### Reverted
+
+* cheese
+`
+	assert.Equal(t, expected, markdown)
+}
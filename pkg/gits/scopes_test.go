@@ -0,0 +1,65 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByScope(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "6", Message: "feat(cli): cheese flag"},
+			{SHA: "5", Message: "feat: wine"},
+			{SHA: "4", Message: "feat(API): cheese endpoint"},
+			{SHA: "3", Message: "feat(charts): wine chart"},
+			{SHA: "2", Message: "feat(api): wine endpoint"},
+			{SHA: "1", Message: "fix: the cork"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, &gits.MarkdownOptions{
+		GroupByScope:  true,
+		ScopeOrder:    []string{"api"},
+		UnscopedTitle: "General",
+	})
+	require.NoError(t, err)
+
+	expected := `## Changes
+
+### New Features
+
+#### API
+
+* cheese endpoint
+* wine endpoint
+
+#### charts
+
+* wine chart
+
+#### cli
+
+* cheese flag
+
+#### General
+
+* wine
+
+### Bug Fixes
+
+* the cork
+`
+	assert.Equal(t, expected, markdown)
+}
@@ -0,0 +1,62 @@
+package gits
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+)
+
+// ApplyServerURL fixes the repository parsed from the git URL of a git server hosted below a path such as
+// https://example.com/gitea where the path would otherwise be mistaken for the owner. The host of the repository then
+// includes the scheme and path of the server so that links to the repository use the server URL.
+// Returns true if the repository was changed
+func ApplyServerURL(info *giturl.GitRepository, serverURL string) bool {
+	if info == nil || serverURL == "" {
+		return false
+	}
+	server, err := url.Parse(serverURL)
+	if err != nil || server.Host == "" {
+		return false
+	}
+	prefix := strings.Trim(server.Path, "/")
+	if prefix == "" {
+		return false
+	}
+	host, path := splitGitURL(info.URL)
+	if !strings.EqualFold(host, server.Hostname()) {
+		return false
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if !strings.HasPrefix(path, prefix+"/") {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(path, prefix+"/"), "/")
+	if len(parts) < 2 {
+		return false
+	}
+	info.Organisation = strings.Join(parts[:len(parts)-1], "/")
+	info.Name = parts[len(parts)-1]
+	info.Host = server.Scheme + "://" + server.Host + "/" + prefix
+	return true
+}
+
+// splitGitURL returns the host name and path of a git URL which may be a scp-like URL such as git@host:owner/repo.git
+func splitGitURL(gitURL string) (string, string) {
+	if !strings.Contains(gitURL, "://") {
+		idx := strings.Index(gitURL, ":")
+		if idx < 0 {
+			return "", ""
+		}
+		host := gitURL[:idx]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		return host, gitURL[idx+1:]
+	}
+	u, err := url.Parse(gitURL)
+	if err != nil {
+		return "", ""
+	}
+	return u.Hostname(), u.Path
+}
@@ -0,0 +1,31 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyServerURL(t *testing.T) {
+	t.Parallel()
+	for _, gitURL := range []string{"https://example.com/gitea/jstrachan/foo.git", "git@example.com:gitea/jstrachan/foo.git"} {
+		info, err := giturl.ParseGitURL(gitURL)
+		require.NoError(t, err, "failed to parse %s", gitURL)
+		require.True(t, gits.ApplyServerURL(info, "https://example.com/gitea/"), "should apply server URL to %s", gitURL)
+		assert.Equal(t, "jstrachan", info.Organisation)
+		assert.Equal(t, "foo", info.Name)
+		assert.Equal(t, "https://example.com/gitea/jstrachan/foo", info.HttpsURL())
+		assert.Equal(t, "https://example.com/gitea/jstrachan/foo/releases/tag/v1.0.0", gits.ReleaseURL(info, giturl.KindGitea, "v1.0.0"))
+	}
+
+	info, err := giturl.ParseGitURL("https://github.com/jstrachan/foo.git")
+	require.NoError(t, err)
+	assert.False(t, gits.ApplyServerURL(info, "https://github.com"))
+	assert.False(t, gits.ApplyServerURL(info, "https://example.com/gitea"))
+	assert.Equal(t, "https://github.com/jstrachan/foo", info.HttpsURL())
+}
@@ -0,0 +1,85 @@
+package gits
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/conventional"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// ChangelogTrailerToken the trailer which excludes the commit from the changelog when its value is 'none' or 'skip'
+const ChangelogTrailerToken = "Changelog"
+
+// DefaultSkipLabels the pull request labels which exclude the commits of the pull request from the changelog
+var DefaultSkipLabels = []string{"skip-changelog", "no-changelog", "changelog: none"}
+
+var skipMarkerRegex = regexp.MustCompile(`(?i)\[(skip changelog|changelog skip|no changelog)\]`)
+
+// HasSkipMarker returns true if the commit message opts out of the changelog via a '[skip changelog]' marker
+// or a 'Changelog: none' trailer
+func HasSkipMarker(message string) bool {
+	if skipMarkerRegex.MatchString(message) {
+		return true
+	}
+	for _, value := range conventional.Parse(message).FooterValues(ChangelogTrailerToken) {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "none" || value == "skip" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSkipLabel returns true if the pull request has any of the labels ignoring case
+func HasSkipLabel(pr *v1.IssueSummary, labels []string) bool {
+	for _, l := range pr.Labels {
+		for _, name := range labels {
+			if strings.EqualFold(l.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemoveSkippedPullRequests removes the pull requests with any of the skip labels and the commits which reference them.
+// Returns the number of removed commits
+func RemoveSkippedPullRequests(spec *v1.ReleaseSpec, labels []string) int {
+	skipped := map[string]bool{}
+	var prs []v1.IssueSummary
+	for i := range spec.PullRequests {
+		pr := &spec.PullRequests[i]
+		if HasSkipLabel(pr, labels) {
+			skipped[pr.ID] = true
+			continue
+		}
+		prs = append(prs, *pr)
+	}
+	if len(skipped) == 0 {
+		return 0
+	}
+	spec.PullRequests = prs
+
+	count := 0
+	var commits []v1.CommitSummary
+	for i := range spec.Commits {
+		c := &spec.Commits[i]
+		if referencesAny(c, skipped) {
+			count++
+			continue
+		}
+		commits = append(commits, *c)
+	}
+	spec.Commits = commits
+	return count
+}
+
+func referencesAny(commit *v1.CommitSummary, ids map[string]bool) bool {
+	for _, id := range commit.IssueIDs {
+		if ids[id] {
+			return true
+		}
+	}
+	return false
+}
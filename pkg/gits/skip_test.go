@@ -0,0 +1,46 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasSkipMarker(t *testing.T) {
+	t.Parallel()
+	assert.True(t, gits.HasSkipMarker("chore: tidy up [skip changelog]"))
+	assert.True(t, gits.HasSkipMarker("chore: tidy up\n\nsome details [Changelog Skip]"))
+	assert.True(t, gits.HasSkipMarker("chore: tidy up\n\nChangelog: none"))
+	assert.True(t, gits.HasSkipMarker("chore: tidy up\n\nChangelog: Skip\nSigned-off-by: James <james@example.com>"))
+	assert.False(t, gits.HasSkipMarker("chore: tidy up\n\nChangelog: added"))
+	assert.False(t, gits.HasSkipMarker("fix: skip the changelog of the cheese"))
+}
+
+func TestRemoveSkippedPullRequests(t *testing.T) {
+	t.Parallel()
+	spec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1", Message: "fix: the cork", IssueIDs: []string{"12"}},
+			{SHA: "2", Message: "chore: tidy up", IssueIDs: []string{"13"}},
+			{SHA: "3", Message: "feat: cheese"},
+		},
+		PullRequests: []v1.IssueSummary{
+			{ID: "12", Labels: []v1.IssueLabel{{Name: "bug"}}},
+			{ID: "13", Labels: []v1.IssueLabel{{Name: "Skip-Changelog"}}},
+		},
+	}
+
+	count := gits.RemoveSkippedPullRequests(spec, gits.DefaultSkipLabels)
+	assert.Equal(t, 1, count)
+	assert.Len(t, spec.Commits, 2)
+	assert.Equal(t, "1", spec.Commits[0].SHA)
+	assert.Equal(t, "3", spec.Commits[1].SHA)
+	assert.Len(t, spec.PullRequests, 1)
+	assert.Equal(t, "12", spec.PullRequests[0].ID)
+
+	assert.Equal(t, 0, gits.RemoveSkippedPullRequests(spec, gits.DefaultSkipLabels))
+}
@@ -0,0 +1,218 @@
+package gits
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// slackMaxTextLength the maximum length of the text of a Slack section block. Slack counts characters so
+	// limiting the bytes keeps any text within the limit
+	slackMaxTextLength = 3000
+
+	// slackMaxContextElements the maximum number of elements in a Slack context block
+	slackMaxContextElements = 10
+
+	// slackMaxBlocks the maximum number of blocks in a Slack message
+	slackMaxBlocks = 50
+
+	// slackMaxHeaderLength the maximum number of characters of the text of a Slack header block
+	slackMaxHeaderLength = 150
+)
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// SlackMessage a Slack message using Block Kit layout blocks which can be posted to a Slack webhook or the chat.postMessage API
+type SlackMessage struct {
+	Text   string        `json:"text"`
+	Blocks []*SlackBlock `json:"blocks"`
+}
+
+// SlackBlock a Block Kit layout block
+type SlackBlock struct {
+	Type     string          `json:"type"`
+	Text     *SlackText      `json:"text,omitempty"`
+	Elements []*SlackElement `json:"elements,omitempty"`
+}
+
+// SlackText a Block Kit text object
+type SlackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// SlackElement a Block Kit element such as an image or a button
+type SlackElement struct {
+	Type     string     `json:"type"`
+	Text     *SlackText `json:"text,omitempty"`
+	URL      string     `json:"url,omitempty"`
+	ImageURL string     `json:"image_url,omitempty"`
+	AltText  string     `json:"alt_text,omitempty"`
+}
+
+// CreateSlackMessage creates the Slack Block Kit message of the changelog with a header, a section for each changelog
+// section, the avatars of the contributors and buttons linking to the release and the repository
+func CreateSlackMessage(changelog *Changelog, title string) *SlackMessage {
+	if title == "" {
+		title = changelog.Title
+	}
+	if title == "" {
+		title = "Release " + changelog.Version
+		if changelog.Version == "" {
+			title = "Changelog"
+		}
+	}
+	answer := &SlackMessage{
+		Text: title,
+		Blocks: []*SlackBlock{
+			{
+				Type: "header",
+				Text: &SlackText{Type: "plain_text", Text: slackHeader(title), Emoji: true},
+			},
+		},
+	}
+	if len(changelog.BreakingChanges) > 0 {
+		var lines []string
+		for _, text := range changelog.BreakingChanges {
+			lines = append(lines, "• "+slackEscaper.Replace(text))
+		}
+		heading := "*Breaking Changes*"
+		if changelog.BreakingChangesEmoji != "" {
+			heading = ":warning: " + heading
+		}
+		answer.addSection(heading, lines)
+	}
+	for _, section := range changelog.Sections {
+		heading := "*" + slackEscaper.Replace(section.Title) + "*"
+		if section.Emoji != "" {
+			heading = section.Emoji + " " + heading
+		}
+		var lines []string
+		for _, entry := range section.Entries {
+			lines = append(lines, "• "+describeSlackEntry(entry))
+		}
+		answer.addSection(heading, lines)
+	}
+
+	var trailing []*SlackBlock
+	var avatars []*SlackElement
+	for _, c := range changelog.Contributors {
+		if c.User.AvatarURL == "" {
+			continue
+		}
+		if len(avatars) == slackMaxContextElements-1 {
+			// leave room for the remaining count
+			break
+		}
+		avatars = append(avatars, &SlackElement{Type: "image", ImageURL: c.User.AvatarURL, AltText: c.Key()})
+	}
+	if len(changelog.Contributors) > 0 {
+		text := "contributor"
+		if len(changelog.Contributors) > 1 {
+			text += "s"
+		}
+		avatars = append(avatars, &SlackElement{Type: "mrkdwn", Text: &SlackText{Type: "mrkdwn", Text: formatThousands(len(changelog.Contributors)) + " " + text}})
+		trailing = append(trailing, &SlackBlock{Type: "context", Elements: avatars})
+	}
+
+	var buttons []*SlackElement
+	if changelog.ReleaseNotesURL != "" {
+		buttons = append(buttons, slackButton("View Release", changelog.ReleaseNotesURL))
+	}
+	if changelog.Compare != nil {
+		buttons = append(buttons, slackButton("Compare Changes", changelog.Compare.URL))
+	}
+	if changelog.URL != "" {
+		buttons = append(buttons, slackButton("Repository", changelog.URL))
+	}
+	if len(buttons) > 0 {
+		trailing = append(trailing, &SlackBlock{Type: "actions", Elements: buttons})
+	}
+	answer.capBlocks(slackMaxBlocks - len(trailing))
+	answer.Blocks = append(answer.Blocks, trailing...)
+	return answer
+}
+
+// capBlocks replaces the section blocks beyond the maximum with a final section counting the changes left out
+func (m *SlackMessage) capBlocks(max int) {
+	if len(m.Blocks) <= max {
+		return
+	}
+	keep := max - 1
+	count := 0
+	for _, block := range m.Blocks[keep:] {
+		if block.Text != nil {
+			count += strings.Count("\n"+block.Text.Text, "\n• ")
+		}
+	}
+	m.Blocks = append(m.Blocks[:keep], slackSection(fmt.Sprintf("…and %s more changes", formatThousands(count))))
+}
+
+// addSection adds the heading and lines as section blocks splitting them into several blocks if they are too long
+func (m *SlackMessage) addSection(heading string, lines []string) {
+	text := heading
+	for _, line := range lines {
+		if len(line) > slackMaxTextLength-1 {
+			// lets not cut a multi-byte character in half
+			cut := slackMaxTextLength - 4
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			line = line[:cut] + "..."
+		}
+		if len(text)+1+len(line) > slackMaxTextLength {
+			m.Blocks = append(m.Blocks, slackSection(text))
+			text = line
+			continue
+		}
+		text += "\n" + line
+	}
+	m.Blocks = append(m.Blocks, slackSection(text))
+}
+
+// slackHeader returns the text of the header truncated to the maximum length without cutting a character in half
+func slackHeader(title string) string {
+	if utf8.RuneCountInString(title) <= slackMaxHeaderLength {
+		return title
+	}
+	return string([]rune(title)[:slackMaxHeaderLength-1]) + "…"
+}
+
+// describeSlackEntry describes the entry using Slack mrkdwn links
+func describeSlackEntry(entry *Entry) string {
+	text := slackEscaper.Replace(entry.Subject)
+	if entry.Scope != "" {
+		text = "*" + slackEscaper.Replace(entry.Scope) + ":* " + text
+	}
+	if pr := entry.PullRequest; pr != nil {
+		text += " " + slackLink(pr.URL, "#"+pr.ID)
+	}
+	for _, issue := range entry.Issues {
+		text += " " + slackLink(issue.URL, issue.ID)
+	}
+	sha := entry.SHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	if sha != "" {
+		text += " " + slackLink(entry.URL, "`"+sha+"`")
+	}
+	return text
+}
+
+func slackLink(url, label string) string {
+	if url == "" {
+		return label
+	}
+	return "<" + url + "|" + label + ">"
+}
+
+func slackSection(text string) *SlackBlock {
+	return &SlackBlock{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: text}}
+}
+
+func slackButton(text, url string) *SlackElement {
+	return &SlackElement{Type: "button", Text: &SlackText{Type: "plain_text", Text: text}, URL: url}
+}
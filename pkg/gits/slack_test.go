@@ -0,0 +1,117 @@
+// +build unit
+
+package gits_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSlackMessage(t *testing.T) {
+	t.Parallel()
+	changelog := &gits.Changelog{
+		Version:         "1.2.0",
+		URL:             "https://github.com/jstrachan/foo",
+		ReleaseNotesURL: "https://github.com/jstrachan/foo/releases/tag/v1.2.0",
+		Sections: []*gits.Section{
+			{
+				Title: "Bug Fixes",
+				Entries: []*gits.Entry{
+					{
+						SHA:         "4444444444",
+						URL:         "https://github.com/jstrachan/foo/commit/4444444444",
+						Scope:       "api",
+						Subject:     "handle <nil> & empty values",
+						PullRequest: &gits.Reference{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12"},
+					},
+				},
+			},
+		},
+		Contributors: []*contributors.Contributor{
+			{User: v1.UserDetails{Login: "jstrachan", AvatarURL: "https://avatars.githubusercontent.com/u/30140"}},
+			{User: v1.UserDetails{Name: "Bob"}},
+		},
+	}
+
+	message := gits.CreateSlackMessage(changelog, "")
+	require.Len(t, message.Blocks, 4)
+	assert.Equal(t, "header", message.Blocks[0].Type)
+	assert.Equal(t, "Release 1.2.0", message.Blocks[0].Text.Text)
+	assert.Equal(t, "*Bug Fixes*\n• *api:* handle &lt;nil&gt; &amp; empty values <https://github.com/jstrachan/foo/pull/12|#12> <https://github.com/jstrachan/foo/commit/4444444444|`4444444`>", message.Blocks[1].Text.Text)
+
+	context := message.Blocks[2]
+	assert.Equal(t, "context", context.Type)
+	require.Len(t, context.Elements, 2)
+	assert.Equal(t, "jstrachan", context.Elements[0].AltText)
+	assert.Equal(t, "2 contributors", context.Elements[1].Text.Text)
+
+	actions := message.Blocks[3]
+	require.Len(t, actions.Elements, 2)
+	assert.Equal(t, changelog.ReleaseNotesURL, actions.Elements[0].URL)
+
+	_, err := json.Marshal(message)
+	require.NoError(t, err)
+}
+
+func TestSlackMessageSplitsLongSections(t *testing.T) {
+	t.Parallel()
+	section := &gits.Section{Title: "Chores"}
+	for i := 0; i < 100; i++ {
+		section.Entries = append(section.Entries, &gits.Entry{Subject: strings.Repeat("x", 100)})
+	}
+	message := gits.CreateSlackMessage(&gits.Changelog{Sections: []*gits.Section{section}}, "foo")
+
+	require.Len(t, message.Blocks, 5)
+	for _, block := range message.Blocks[1:] {
+		assert.True(t, len(block.Text.Text) <= 3000, "section text should fit in a Slack block")
+	}
+}
+
+func TestSlackMessageTruncatesLongLinesOnRuneBoundaries(t *testing.T) {
+	t.Parallel()
+	section := &gits.Section{Title: "Chores", Entries: []*gits.Entry{{Subject: "x" + strings.Repeat("é", 4000)}}}
+	message := gits.CreateSlackMessage(&gits.Changelog{Sections: []*gits.Section{section}}, "foo")
+
+	for _, block := range message.Blocks[1:] {
+		assert.True(t, utf8.ValidString(block.Text.Text), "section text should be valid UTF-8")
+		assert.True(t, len(block.Text.Text) <= 3000, "section text should fit in a Slack block")
+	}
+}
+
+func TestSlackMessageCapsBlocks(t *testing.T) {
+	t.Parallel()
+	changelog := &gits.Changelog{ReleaseNotesURL: "https://github.com/jstrachan/foo/releases/tag/v1.2.0"}
+	for i := 0; i < 60; i++ {
+		changelog.Sections = append(changelog.Sections, &gits.Section{
+			Title:   "Section " + strconv.Itoa(i),
+			Entries: []*gits.Entry{{Subject: "first"}, {Subject: "second"}},
+		})
+	}
+	message := gits.CreateSlackMessage(changelog, "foo")
+
+	require.Len(t, message.Blocks, 50, "should not exceed the Slack block limit")
+	assert.Equal(t, "*Section 46*\n• first\n• second", message.Blocks[47].Text.Text)
+	assert.Equal(t, "…and 26 more changes", message.Blocks[48].Text.Text)
+	assert.Equal(t, "actions", message.Blocks[49].Type, "should keep the buttons")
+}
+
+func TestSlackMessageTruncatesLongHeaders(t *testing.T) {
+	t.Parallel()
+	title := "x" + strings.Repeat("é", 200)
+	message := gits.CreateSlackMessage(&gits.Changelog{}, title)
+
+	header := message.Blocks[0].Text.Text
+	assert.True(t, utf8.ValidString(header), "header text should be valid UTF-8")
+	assert.Equal(t, 150, utf8.RuneCountInString(header), "header text should fit in a Slack header block")
+	assert.True(t, strings.HasSuffix(header, "é…"))
+	assert.Equal(t, title, message.Text, "should keep the full title as the notification text")
+}
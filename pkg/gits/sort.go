@@ -0,0 +1,111 @@
+package gits
+
+import (
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+const (
+	// SortByDate sorts the entries of each section by their commit date with the oldest first
+	SortByDate = "date"
+
+	// SortByAuthor sorts the entries of each section by the login or name of their author
+	SortByAuthor = "author"
+
+	// SortByScope sorts the entries of each section by their scope with the entries without a scope last
+	SortByScope = "scope"
+
+	// SortBySubject sorts the entries of each section by their subject
+	SortBySubject = "subject"
+
+	// SortByImpact sorts the entries of each section by the number of changed lines with the largest changes first
+	SortByImpact = "impact"
+)
+
+// SortStrategies the supported strategies to sort the entries of each section. The entries are in git log
+// order by default
+var SortStrategies = []string{SortByDate, SortByAuthor, SortByScope, SortBySubject, SortByImpact}
+
+// entrySortKey the values the entries of a section are sorted by
+type entrySortKey struct {
+	// index the position of the commit in git log order which is used if the commit date is unknown
+	index   int
+	date    time.Time
+	author  string
+	scope   string
+	subject string
+	impact  int
+}
+
+// sortStrategy returns the strategy used to sort the entries of each section or an empty string for git log order
+func (o *MarkdownOptions) sortStrategy() string {
+	if o.SortBy == "" && o.SortByImpact {
+		return SortByImpact
+	}
+	return o.SortBy
+}
+
+// entrySortKey returns the sort key of the commit at the given position in git log order
+func (o *MarkdownOptions) entrySortKey(index int, cs *v1.CommitSummary, ci *CommitInfo, stats *DiffStats) *entrySortKey {
+	key := &entrySortKey{
+		index:   index,
+		date:    o.CommitDates[cs.SHA],
+		scope:   strings.ToLower(ci.Feature),
+		subject: strings.ToLower(strings.TrimSpace(strings.SplitN(strings.TrimSpace(ci.Message), "\n", 2)[0])),
+		impact:  stats.Impact(),
+	}
+	if cs.Author != nil {
+		key.author = strings.ToLower(cs.Author.Login)
+		if key.author == "" {
+			key.author = strings.ToLower(cs.Author.Name)
+		}
+	}
+	return key
+}
+
+// lessEntry returns true if the entry with the key a should be rendered before the entry with the key b
+func lessEntry(strategy string, a, b *entrySortKey) bool {
+	switch strategy {
+	case SortByDate:
+		if !a.date.IsZero() && !b.date.IsZero() && !a.date.Equal(b.date) {
+			return a.date.Before(b.date)
+		}
+		// git log order has the newest commits first
+		return a.index > b.index
+	case SortByAuthor:
+		return a.author < b.author
+	case SortByScope:
+		if a.scope == "" || b.scope == "" {
+			return b.scope == "" && a.scope != ""
+		}
+		return a.scope < b.scope
+	case SortBySubject:
+		return a.subject < b.subject
+	case SortByImpact:
+		return a.impact > b.impact
+	}
+	return false
+}
+
+// byStrategy sorts the commits of a group using the sort strategy
+type byStrategy struct {
+	gac      *GroupAndCommitInfos
+	strategy string
+}
+
+func (s byStrategy) Len() int {
+	return len(s.gac.commits)
+}
+
+func (s byStrategy) Less(i, j int) bool {
+	return lessEntry(s.strategy, s.gac.keys[i], s.gac.keys[j])
+}
+
+func (s byStrategy) Swap(i, j int) {
+	g := s.gac
+	g.commits[i], g.commits[j] = g.commits[j], g.commits[i]
+	g.scopes[i], g.scopes[j] = g.scopes[j], g.scopes[i]
+	g.keys[i], g.keys[j] = g.keys[j], g.keys[i]
+}
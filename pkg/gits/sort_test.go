@@ -0,0 +1,89 @@
+// +build unit
+
+package gits_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortBy(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "fix(ui): the cork", Author: &v1.UserDetails{Login: "wine"}},
+			{SHA: "2222222222", Message: "fix: Bottle", Author: &v1.UserDetails{Login: "cheese"}},
+			{SHA: "3333333333", Message: "fix(api): the glass", Author: &v1.UserDetails{Name: "Apple"}},
+		},
+	}
+	gitInfo := &giturl.GitRepository{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+	now := time.Now()
+	authors := map[string]string{
+		"ui: the cork":   " ([wine](https://github.com/wine))",
+		"Bottle":         " ([cheese](https://github.com/cheese))",
+		"api: the glass": " (Apple)",
+	}
+
+	testCases := []struct {
+		sortBy      string
+		commitDates map[string]time.Time
+		expected    []string
+	}{
+		{
+			expected: []string{"ui: the cork", "Bottle", "api: the glass"},
+		},
+		{
+			sortBy:   gits.SortByDate,
+			expected: []string{"api: the glass", "Bottle", "ui: the cork"},
+		},
+		{
+			sortBy: gits.SortByDate,
+			commitDates: map[string]time.Time{
+				"1111111111": now.Add(-time.Hour),
+				"2222222222": now,
+				"3333333333": now.Add(-time.Minute),
+			},
+			expected: []string{"ui: the cork", "api: the glass", "Bottle"},
+		},
+		{
+			sortBy:   gits.SortByAuthor,
+			expected: []string{"api: the glass", "Bottle", "ui: the cork"},
+		},
+		{
+			sortBy:   gits.SortByScope,
+			expected: []string{"api: the glass", "ui: the cork", "Bottle"},
+		},
+		{
+			sortBy:   gits.SortBySubject,
+			expected: []string{"Bottle", "ui: the cork", "api: the glass"},
+		},
+	}
+	for _, tc := range testCases {
+		opts := &gits.MarkdownOptions{SortBy: tc.sortBy, CommitDates: tc.commitDates}
+		markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts)
+		require.NoError(t, err)
+		expected := "## Changes\n\n### Bug Fixes\n\n"
+		for _, line := range tc.expected {
+			expected += "* " + line + authors[line] + "\n"
+		}
+		assert.Equal(t, expected, markdown, "sort by %s", tc.sortBy)
+
+		changelog := gits.CreateChangelog(releaseSpec, gitInfo, now, opts)
+		require.Len(t, changelog.Sections, 1)
+		var subjects []string
+		for _, e := range changelog.Sections[0].Entries {
+			subject := e.Subject
+			if e.Scope != "" {
+				subject = e.Scope + ": " + subject
+			}
+			subjects = append(subjects, subject)
+		}
+		assert.Equal(t, tc.expected, subjects, "changelog sorted by %s", tc.sortBy)
+	}
+}
@@ -0,0 +1,209 @@
+package gits
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/pkg/errors"
+)
+
+// PartialsDir the directory in the repository containing the partial templates which override the header, section,
+// entry, contributors and footer blocks of the changelog. Each file overrides the block with the name of the file
+// without its extension such as 'entry.tmpl'. The blocks which are not overridden keep the built-in markdown
+var PartialsDir = filepath.Join(".jx", "changelog", "templates")
+
+// DefaultMarkdownTemplate the markdown layout of the changelog as a template whose blocks can be overridden by
+// partial templates. Partial templates can use the blocks which are not overridden such as the entry block.
+// Each block is executed with the *Changelog apart from the section and entry blocks which are executed with
+// the *Section and *Entry
+const DefaultMarkdownTemplate = `{{- template "header" . }}
+{{- range .Sections }}{{ template "section" . }}{{ end }}
+{{- template "contributors" . }}
+{{- template "footer" . }}
+{{- define "header" }}## Changes
+{{ if .BreakingChanges }}
+### {{ with .BreakingChangesEmoji }}{{ . }} {{ end }}Breaking Changes
+
+{{ range .BreakingChanges }}* {{ escapeMarkdown . }}
+{{ end }}{{ end }}{{ if .SecurityAdvisories }}
+### Security
+
+{{ range $a := .SecurityAdvisories }}* **{{ with $a.CVE }}{{ . }}{{ else }}{{ $a.ID }}{{ end }}**{{ with $a.Severity }} ({{ . }}){{ end }}: {{ escapeMarkdown $a.Summary }}{{ with $a.URL }} [{{ $a.ID }}]({{ . }}){{ end }}
+{{ end }}{{ end }}{{ end }}
+{{- define "section" }}
+### {{ with .Emoji }}{{ . }} {{ end }}{{ .Title }}
+
+{{ if .Collapsed }}<details>
+<summary>{{ len .Entries }} commits</summary>
+
+{{ end }}{{ range .Entries }}{{ template "entry" . }}{{ end }}{{ if .Collapsed }}
+</details>
+{{ end }}{{ end }}
+{{- define "entry" }}* {{ with .Scope }}{{ escapeMarkdown . }}: {{ end }}{{ escapeMarkdown .Subject }}
+{{- with .Authors }} ({{ range $i, $a := . }}{{ if $i }}, {{ end }}{{ if $a.Login }}{{ $a.Login }}{{ else }}{{ escapeMarkdown $a.Name }}{{ end }}{{ end }}){{ end }}
+{{- with .PullRequest }} [#{{ .ID }}]({{ .URL }}){{ end }}
+{{- range .Issues }} [{{ .ID }}]({{ .URL }}){{ end }}
+{{ end }}
+{{- define "contributors" }}{{ if .Contributors }}
+### Contributors
+
+{{ range .Contributors }}* {{ with .User.Login }}{{ . }}{{ else }}{{ escapeMarkdown .User.Name }}{{ end }}
+{{ end }}{{ end }}{{ end }}
+{{- define "footer" }}{{ with .Compare }}
+**Full Changelog**: [{{ .From }}...{{ .To }}]({{ .URL }})
+{{ end }}{{ end -}}
+`
+
+// issueRefRegex matches the '#123' issue references which are not already part of a markdown link or word
+var issueRefRegex = regexp.MustCompile(`(^|[\s(])#(\d+)\b`)
+
+// TemplateFuncs returns the functions available to changelog templates: the sprig functions together with
+//
+// * shortSHA to shorten a commit SHA to 7 characters
+//
+// * linkify to turn the '#123' references in some text into markdown links to the issues of the repository
+//
+// * formatDate to format a date using a Go time layout such as {{ formatDate "2006-01-02" .Date }}
+//
+// * escapeMarkdown to escape the markdown formatting characters of a subject, scope or name
+func TemplateFuncs(changelog *Changelog) template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	funcs["shortSHA"] = shortSHA
+	funcs["linkify"] = func(text string) string {
+		return linkifyIssueRefs(changelog.URL, text)
+	}
+	funcs["formatDate"] = func(layout string, t time.Time) string {
+		return t.Format(layout)
+	}
+	funcs["escapeMarkdown"] = EscapeMarkdown
+	return funcs
+}
+
+// RenderTemplate renders the changelog using the text/template with the TemplateFuncs.
+// The partial templates indexed by name override the blocks of the template with the same name
+func RenderTemplate(changelog *Changelog, name, templateText string, partials map[string]string) (string, error) {
+	tmpl, err := parseTemplate(changelog, name, templateText, partials)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, changelog)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render the %s template", name)
+	}
+	return buffer.String(), nil
+}
+
+func parseTemplate(changelog *Changelog, name, templateText string, partials map[string]string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs(changelog)).Parse(templateText)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the %s template", name)
+	}
+	var names []string
+	for partial := range partials {
+		names = append(names, partial)
+	}
+	sort.Strings(names)
+	for _, partial := range names {
+		_, err = tmpl.New(partial).Parse(partials[partial])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the %s partial template", partial)
+		}
+	}
+	return tmpl, nil
+}
+
+// partialRenderer renders the blocks of the built-in markdown which are overridden by partial templates
+type partialRenderer struct {
+	tmpl      *template.Template
+	partials  map[string]string
+	changelog *Changelog
+}
+
+// newPartialRenderer returns the renderer of the partial templates or nil if there are none
+func newPartialRenderer(changelog *Changelog, partials map[string]string) (*partialRenderer, error) {
+	if len(partials) == 0 {
+		return nil, nil
+	}
+	tmpl, err := parseTemplate(changelog, "changelog", DefaultMarkdownTemplate, partials)
+	if err != nil {
+		return nil, err
+	}
+	return &partialRenderer{tmpl: tmpl, partials: partials, changelog: changelog}, nil
+}
+
+// overrides returns true if there is a partial template for the block
+func (p *partialRenderer) overrides(name string) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.partials[name]
+	return ok
+}
+
+// render renders the partial template of the block with the data
+func (p *partialRenderer) render(name string, data interface{}) (string, error) {
+	var buffer bytes.Buffer
+	err := p.tmpl.ExecuteTemplate(&buffer, name, data)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render the %s partial template", name)
+	}
+	return buffer.String(), nil
+}
+
+// LoadPartials loads the partial templates in the directory indexed by the file name without its extension.
+// Returns nil if the directory does not exist
+func LoadPartials(dir string) (map[string]string, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read the partial templates directory %s", dir)
+	}
+	answer := map[string]string{}
+	for _, fi := range fileInfos {
+		if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the partial template %s", path)
+		}
+		answer[strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))] = string(data)
+	}
+	return answer, nil
+}
+
+// shortSHA returns the first 7 characters of the commit SHA
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// linkifyIssueRefs replaces the '#123' references in the text with markdown links to the issues of the repository
+func linkifyIssueRefs(repoURL, text string) string {
+	if repoURL == "" {
+		return text
+	}
+	return issueRefRegex.ReplaceAllStringFunc(text, func(match string) string {
+		m := issueRefRegex.FindStringSubmatch(match)
+		link := stringhelpers.UrlJoin(repoURL, "issues", m[2])
+		if strings.Contains(repoURL, "gitlab") {
+			link = stringhelpers.UrlJoin(repoURL, "-", "issues", m[2])
+		}
+		return m[1] + "[#" + m[2] + "](" + link + ")"
+	})
+}
@@ -0,0 +1,171 @@
+// +build unit
+
+package gits_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/contributors"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	t.Parallel()
+	changelog := &gits.Changelog{
+		Version: "1.2.0",
+		Date:    time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		URL:     "https://github.com/jstrachan/foo",
+		Sections: []*gits.Section{
+			{
+				Title: "Bug Fixes",
+				Entries: []*gits.Entry{
+					{SHA: "4444444444", Subject: "the cork (#12)"},
+					{SHA: "3333333333", Subject: "see [#7](https://example.com/7) and issue#8"},
+				},
+			},
+		},
+	}
+	templateText := `# {{ .Version }} ({{ formatDate "Jan 2, 2006" .Date }})
+{{ range .Sections }}
+## {{ .Title | upper }}
+{{ range .Entries }}
+- {{ linkify .Subject }} {{ shortSHA .SHA }}
+{{- end }}
+{{ end }}`
+
+	text, err := gits.RenderTemplate(changelog, "changelog.md", templateText, nil)
+	require.NoError(t, err)
+
+	expected := `# 1.2.0 (Mar 4, 2021)
+
+## BUG FIXES
+
+- the cork ([#12](https://github.com/jstrachan/foo/issues/12)) 4444444
+- see [#7](https://example.com/7) and issue#8 3333333
+`
+	assert.Equal(t, expected, text)
+
+	_, err = gits.RenderTemplate(changelog, "broken.md", "{{ .Version ", nil)
+	assert.Error(t, err)
+}
+
+func TestRenderTemplatePartials(t *testing.T) {
+	t.Parallel()
+	changelog := &gits.Changelog{
+		Version: "1.2.0",
+		Sections: []*gits.Section{
+			{
+				Title: "Bug Fixes",
+				Entries: []*gits.Entry{
+					{
+						SHA:         "4444444444",
+						Scope:       "api",
+						Subject:     "the cork",
+						Authors:     []*v1.UserDetails{{Login: "jstrachan"}},
+						PullRequest: &gits.Reference{ID: "12", URL: "https://github.com/jstrachan/foo/pull/12"},
+					},
+				},
+			},
+		},
+		Contributors: []*contributors.Contributor{{User: v1.UserDetails{Login: "jstrachan"}}},
+	}
+
+	text, err := gits.RenderTemplate(changelog, "changelog", gits.DefaultMarkdownTemplate, nil)
+	require.NoError(t, err)
+	expected := `## Changes
+
+### Bug Fixes
+
+* api: the cork (jstrachan) [#12](https://github.com/jstrachan/foo/pull/12)
+
+### Contributors
+
+* jstrachan
+`
+	assert.Equal(t, expected, text)
+
+	dir, err := ioutil.TempDir("", "test-partials-")
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "entry.tmpl"), []byte("- {{ .Subject }} {{ shortSHA .SHA }}\n"), 0600)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "footer.md"), []byte("\nUpgrade with `helm upgrade foo {{ .Version }}`\n"), 0600)
+	require.NoError(t, err)
+
+	partials, err := gits.LoadPartials(dir)
+	require.NoError(t, err)
+	assert.Len(t, partials, 2)
+
+	text, err = gits.RenderTemplate(changelog, "changelog", gits.DefaultMarkdownTemplate, partials)
+	require.NoError(t, err)
+	expected = "## Changes\n\n### Bug Fixes\n\n- the cork 4444444\n\n### Contributors\n\n* jstrachan\n\nUpgrade with `helm upgrade foo 1.2.0`\n"
+	assert.Equal(t, expected, text)
+
+	partials, err = gits.LoadPartials(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, partials)
+}
+
+func TestGenerateMarkdownWithPartials(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Version: "1.2.0",
+		Commits: []v1.CommitSummary{
+			{SHA: "1111111111", Message: "feat: more wine\n\nfixes #12", IssueIDs: []string{"12"}, Author: &v1.UserDetails{Login: "jstrachan", Name: "James Strachan"}},
+			{SHA: "2222222222", Message: "fix: the cork", Author: &v1.UserDetails{Login: "rawlingsj", Name: "James Rawlings"}},
+		},
+		Issues: []v1.IssueSummary{
+			{ID: "12", URL: "https://github.com/jstrachan/foo/issues/12", Title: "need a bottle"},
+		},
+	}
+	gitInfo := &giturl.GitRepository{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	opts := func(partials map[string]string) *gits.MarkdownOptions {
+		return &gits.MarkdownOptions{
+			Compare:  &gits.Compare{From: "v1.1.0", To: "v1.2.0", URL: "https://github.com/jstrachan/foo/compare/v1.1.0...v1.2.0"},
+			Partials: partials,
+		}
+	}
+	builtIn, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts(nil))
+	require.NoError(t, err)
+	require.Contains(t, builtIn, "**Full Changelog**")
+
+	markdown, err := gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts(map[string]string{
+		"footer": "\nUpgrade to {{ .Version }}\n",
+	}))
+	require.NoError(t, err)
+	expected := builtIn[:strings.Index(builtIn, "\n**Full Changelog**")] + "\nUpgrade to 1.2.0\n"
+	assert.Equal(t, expected, markdown, "only the footer should be overridden")
+
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts(map[string]string{
+		"entry": "- {{ .Subject }} {{ shortSHA .SHA }}\n",
+	}))
+	require.NoError(t, err)
+	lines := strings.Split(builtIn, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.Contains(line, "more wine") && strings.HasPrefix(line, "* "):
+			lines[i] = "- more wine 1111111"
+		case strings.Contains(line, "the cork"):
+			lines[i] = "- the cork 2222222"
+		}
+	}
+	assert.Equal(t, strings.Join(lines, "\n"), markdown, "only the entries should be overridden")
+
+	markdown, err = gits.GenerateMarkdownWithOptions(releaseSpec, gitInfo, opts(map[string]string{
+		"section": "\n#### {{ .Title }}\n\n{{ range .Entries }}{{ template \"entry\" . }}{{ end }}",
+	}))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(markdown, "## Changes\n\n#### New Features\n\n* more wine (jstrachan) [12](https://github.com/jstrachan/foo/issues/12)\n\n#### Bug Fixes\n\n* the cork (rawlingsj)\n"), "the sections should be overridden: %s", markdown)
+	assert.Contains(t, markdown, builtIn[strings.Index(builtIn, "\n### Issues\n"):], "the blocks after the sections should not be overridden")
+}
@@ -0,0 +1,56 @@
+package gits
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+)
+
+const (
+	// GitHubReleaseBodyLimit the maximum number of characters of the body of a GitHub release
+	GitHubReleaseBodyLimit = 125000
+
+	// GitLabReleaseBodyLimit the maximum number of characters of the description of a GitLab release
+	GitLabReleaseBodyLimit = 1000000
+)
+
+// ReleaseBodyLimit returns the maximum number of characters of the release notes of the git provider of the given kind
+// or 0 if there is no known limit
+func ReleaseBodyLimit(kind string) int {
+	switch kind {
+	case giturl.KindGitHub:
+		return GitHubReleaseBodyLimit
+	case giturl.KindGitlab:
+		return GitLabReleaseBodyLimit
+	default:
+		return 0
+	}
+}
+
+// TruncateMarkdown truncates the markdown to at most limit characters including the notice which is appended if the
+// markdown is truncated. The markdown is cut at the last section heading which fits so that no section is garbled or,
+// if the first section is too long, at the last line which fits. Returns true if the markdown was truncated
+func TruncateMarkdown(markdown string, limit int, notice string) (string, bool) {
+	if limit <= 0 || utf8.RuneCountInString(markdown) <= limit {
+		return markdown, false
+	}
+	available := limit - utf8.RuneCountInString(notice)
+	if available <= 0 {
+		return "", true
+	}
+	runes := []rune(markdown)
+	head := string(runes[:available])
+	cut := -1
+	for _, heading := range []string{"\n### ", "\n## "} {
+		// lets keep at least the first section
+		if idx := strings.LastIndex(head, heading); idx > strings.Index(head, heading) {
+			cut = idx + 1
+			break
+		}
+	}
+	if cut < 0 {
+		cut = strings.LastIndex(head, "\n") + 1
+	}
+	return head[:cut] + notice, true
+}
@@ -0,0 +1,42 @@
+// +build unit
+
+package gits_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/gits"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateMarkdown(t *testing.T) {
+	t.Parallel()
+	markdown := "## Changes\n\n### New Features\n\n* cheese\n* wine\n\n### Bug Fixes\n\n* the cork\n"
+	notice := "\n*truncated*\n"
+
+	body, truncated := gits.TruncateMarkdown(markdown, 0, notice)
+	assert.False(t, truncated)
+	assert.Equal(t, markdown, body)
+
+	body, truncated = gits.TruncateMarkdown(markdown, len(markdown), notice)
+	assert.False(t, truncated)
+	assert.Equal(t, markdown, body)
+
+	body, truncated = gits.TruncateMarkdown(markdown, len(markdown)-1, notice)
+	assert.True(t, truncated)
+	assert.Equal(t, "## Changes\n\n### New Features\n\n* cheese\n* wine\n\n"+notice, body)
+
+	body, truncated = gits.TruncateMarkdown(markdown, 52, notice)
+	assert.True(t, truncated)
+	assert.Equal(t, "## Changes\n\n### New Features\n\n* cheese\n"+notice, body)
+	assert.True(t, len(body) <= 52)
+
+	body, truncated = gits.TruncateMarkdown(strings.Repeat("ü", 20), 10, "…")
+	assert.True(t, truncated)
+	assert.Equal(t, "…", body)
+
+	assert.Equal(t, gits.GitHubReleaseBodyLimit, gits.ReleaseBodyLimit(giturl.KindGitHub))
+	assert.Equal(t, 0, gits.ReleaseBodyLimit(giturl.KindBitBucketServer))
+}
@@ -0,0 +1,74 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/azure"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// AzureBoardsService the issue provider of the work items of an Azure DevOps project which are referenced as 'AB#123'
+type AzureBoardsService struct {
+	Client *azure.Client
+}
+
+func CreateAzureBoardsIssueProvider(client *azure.Client) (IssueProvider, error) {
+	if client == nil || client.Repository == nil {
+		return nil, fmt.Errorf("no Azure DevOps repository specified")
+	}
+	return &AzureBoardsService{Client: client}, nil
+}
+
+func (i *AzureBoardsService) GetIssue(key string) (*scm.Issue, error) {
+	id, err := workItemID(key)
+	if err != nil {
+		return nil, err
+	}
+	issue, _, err := i.Client.GetWorkItem(context.Background(), id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find work item %d in project %s", id, i.Client.Repository.Project)
+	}
+	return issue, nil
+}
+
+func (i *AzureBoardsService) SearchIssues(_ string) ([]*scm.Issue, error) {
+	return nil, fmt.Errorf("searching work items is not supported")
+}
+
+func (i *AzureBoardsService) SearchIssuesClosedSince(_ time.Time) ([]*scm.Issue, error) {
+	return nil, nil
+}
+
+func (i *AzureBoardsService) CreateIssue(_ *scm.Issue) (*scm.Issue, error) {
+	return nil, fmt.Errorf("creating work items is not supported")
+}
+
+func (i *AzureBoardsService) CreateIssueComment(_ string, _ string) error {
+	return fmt.Errorf("commenting on work items is not supported")
+}
+
+func (i *AzureBoardsService) IssueURL(key string) string {
+	id, err := workItemID(key)
+	if err != nil {
+		return ""
+	}
+	return i.Client.Repository.WorkItemURL(id)
+}
+
+func (i *AzureBoardsService) HomeURL() string {
+	return i.Client.Repository.ServerURL + "/" + i.Client.Repository.Project + "/_boards"
+}
+
+// workItemID converts a work item key such as 'AB#123' or '123' to its number
+func workItemID(key string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(key, azure.WorkItemPrefix))
+	if err != nil {
+		return n, errors.Wrapf(err, "failed to convert work item key '%s' to number", key)
+	}
+	return n, nil
+}
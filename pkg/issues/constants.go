@@ -5,4 +5,6 @@ const (
 	Jira     = "jira"
 	Trello   = "trello"
 	Git      = "git"
+
+	AzureBoards = "azure-boards"
 )
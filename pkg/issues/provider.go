@@ -31,9 +31,11 @@ type IssueProvider interface {
 
 // GetIssueProvider returns the kind of issue provider
 func GetIssueProvider(tracker IssueProvider) string {
-	_, ok := tracker.(*JiraService)
-	if ok {
+	switch tracker.(type) {
+	case *JiraService:
 		return Jira
+	case *AzureBoardsService:
+		return AzureBoards
 	}
 	return Git
 }
@@ -0,0 +1,96 @@
+package postpublish
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// DefaultReleasedComment the default template of the comment added to the pull requests and issues of a release
+const DefaultReleasedComment = `This was released in {{ if .URL }}[{{ .Tag }}]({{ .URL }}){{ else }}{{ .Tag }}{{ end }} 🎉`
+
+// releasedCommentMarker the hidden marker of the released comments so that the comments are only added once per tag
+const releasedCommentMarker = "<!-- jx-changelog released %s -->"
+
+// ReleasedCommentData the data the --released-comment template is executed with
+type ReleasedCommentData struct {
+	// Version the version of the release
+	Version string
+	// Tag the git tag of the release
+	Tag string
+	// Title the title of the release
+	Title string
+	// URL the link to the release notes if known
+	URL string
+}
+
+// RenderReleasedComment renders the comment template with the data
+func RenderReleasedComment(templateText string, data *ReleasedCommentData) (string, error) {
+	tmpl, err := template.New("released-comment").Parse(templateText)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the released comment template %s", templateText)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render the released comment template %s", templateText)
+	}
+	return strings.TrimSpace(buffer.String()), nil
+}
+
+// commentReleased comments on the pull requests and the git provider issues of the release that they were released
+func (p *Publisher) commentReleased(templateText string, release *Release) error {
+	body, err := RenderReleasedComment(templateText, &ReleasedCommentData{
+		Version: release.Version,
+		Tag:     release.Tag,
+		Title:   release.Title,
+		URL:     release.URL,
+	})
+	if err != nil {
+		return err
+	}
+	marker := fmt.Sprintf(releasedCommentMarker, release.Tag)
+
+	commented := map[int]bool{}
+	count := 0
+	comment := func(number int, pullRequest bool) {
+		if number <= 0 || commented[number] {
+			return
+		}
+		commented[number] = true
+		if p.DryRun {
+			log.Logger().Infof("would comment on #%d: %s", number, body)
+			return
+		}
+		var added bool
+		err = p.Backoff.Do(p.Context, func() (*scm.Response, error) {
+			var res *scm.Response
+			var err error
+			added, res, err = scmapi.CreateCommentOnce(p.Context, p.ScmClient, release.Repository, number, pullRequest, marker, body)
+			return res, err
+		})
+		if err != nil {
+			log.Logger().Warnf("failed to comment on #%d: %s", number, err.Error())
+			return
+		}
+		if added {
+			count++
+		}
+	}
+	for _, n := range release.PullRequests {
+		comment(n, true)
+	}
+	for _, n := range release.Issues {
+		comment(n, false)
+	}
+	if count > 0 {
+		log.Logger().Infof("commented on %d pull requests and issues that they were released in %s", count, info(release.Tag))
+	}
+	return nil
+}
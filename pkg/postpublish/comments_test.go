@@ -0,0 +1,29 @@
+// +build unit
+
+package postpublish_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/postpublish"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderReleasedComment(t *testing.T) {
+	t.Parallel()
+	text, err := postpublish.RenderReleasedComment(postpublish.DefaultReleasedComment, &postpublish.ReleasedCommentData{Version: "1.2.3", Tag: "v1.2.3", URL: "https://github.com/jstrachan/foo/releases/tag/v1.2.3"})
+	require.NoError(t, err)
+	assert.Equal(t, "This was released in [v1.2.3](https://github.com/jstrachan/foo/releases/tag/v1.2.3) 🎉", text)
+
+	text, err = postpublish.RenderReleasedComment(postpublish.DefaultReleasedComment, &postpublish.ReleasedCommentData{Version: "1.2.3", Tag: "v1.2.3"})
+	require.NoError(t, err)
+	assert.Equal(t, "This was released in v1.2.3 🎉", text)
+
+	text, err = postpublish.RenderReleasedComment("Shipped in {{ .Title }}", &postpublish.ReleasedCommentData{Title: "Cheese 1.2.3"})
+	require.NoError(t, err)
+	assert.Equal(t, "Shipped in Cheese 1.2.3", text)
+
+	_, err = postpublish.RenderReleasedComment("{{ .Missing", &postpublish.ReleasedCommentData{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package postpublish
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+// MilestoneTitles returns the titles of the milestone of the release in the order they are looked up which are the
+// given titles or, if fromVersion is true, the version with and without the 'v' prefix
+func MilestoneTitles(milestones []string, fromVersion bool, version string) []string {
+	var answer []string
+	for _, m := range milestones {
+		m = strings.TrimSpace(m)
+		if m != "" {
+			answer = append(answer, m)
+		}
+	}
+	if len(answer) > 0 || !fromVersion || version == "" {
+		return answer
+	}
+	version = strings.TrimPrefix(version, "v")
+	return []string{version, "v" + version}
+}
+
+// FindMilestone finds the first milestone with one of the titles returning nil if there is none
+func FindMilestone(ctx context.Context, scmClient *scm.Client, backoff *scmapi.Backoff, repo string, titles []string) *scmapi.Milestone {
+	if len(titles) == 0 || scmClient == nil {
+		return nil
+	}
+	for _, title := range titles {
+		var m *scmapi.Milestone
+		err := backoff.Do(ctx, func() (*scm.Response, error) {
+			var res *scm.Response
+			var err error
+			m, res, err = scmapi.FindMilestone(ctx, scmClient, repo, title)
+			return res, err
+		})
+		if err == scm.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			log.Logger().Warnf("failed to find the milestone %s: %s", title, err.Error())
+			return nil
+		}
+		log.Logger().Debugf("found milestone %s with %d open and %d closed issues", m.Title, m.OpenIssues, m.ClosedIssues)
+		return m
+	}
+	log.Logger().Warnf("no milestone %s found", strings.Join(titles, " or "))
+	return nil
+}
+
+// closeMilestone closes the milestone of the release
+func (p *Publisher) closeMilestone(release *Release) {
+	m := release.Milestone
+	if m == nil || m.IsClosed() {
+		return
+	}
+	if m.OpenIssues > 0 {
+		log.Logger().Warnf("closing milestone %s which still has %d open issues", m.Title, m.OpenIssues)
+	}
+	if p.DryRun {
+		log.Logger().Infof("would close the milestone %s", info(m.Title))
+		return
+	}
+	err := p.Backoff.Do(p.Context, func() (*scm.Response, error) {
+		return scmapi.CloseMilestone(p.Context, p.ScmClient, release.Repository, m)
+	})
+	if err != nil {
+		log.Logger().Warnf("failed to close the milestone %s: %s", m.Title, err.Error())
+		return
+	}
+	m.State = "closed"
+	log.Logger().Infof("closed the milestone %s", info(m.Title))
+}
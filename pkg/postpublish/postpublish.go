@@ -0,0 +1,108 @@
+package postpublish
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var info = termcolor.ColorInfo
+
+// Options the steps which run once a release is published such as notifying its pull requests and issues. Draft
+// releases skip these steps until they are published with 'jx-changelog publish-draft'
+type Options struct {
+	// CommentReleased comments on the pull requests and issues of the release
+	CommentReleased bool
+	// ReleasedComment the text/template of the comment
+	ReleasedComment string
+	// CloseMilestone closes the milestone of the release
+	CloseMilestone bool
+	// CommitStatus reports the published release on the released commit as a 'status' or 'check-run'
+	CommitStatus string
+	// CommitStatusContext the context of the commit status or the name of the check run
+	CommitStatusContext string
+}
+
+// Release the published release the steps run for
+type Release struct {
+	// Repository the full name of the repository
+	Repository string
+	// Version the version of the release
+	Version string
+	// Tag the git tag of the release
+	Tag string
+	// Title the title of the release
+	Title string
+	// URL the link to the release notes if known
+	URL string
+	// SHA the released commit
+	SHA string
+	// PullRequests the numbers of the pull requests of the release
+	PullRequests []int
+	// Issues the numbers of the git provider issues of the release
+	Issues []int
+	// Milestone the milestone of the release if there is one
+	Milestone *scmapi.Milestone
+}
+
+// Publisher runs the steps on the git provider
+type Publisher struct {
+	Context   context.Context
+	ScmClient *scm.Client
+	Backoff   *scmapi.Backoff
+	DryRun    bool
+}
+
+// AddFlags adds the CLI flags
+func (o *Options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&o.CloseMilestone, "close-milestone", "", false, "Closes the milestone of the release after the release is published. Supported on GitHub, GitLab and Gitea")
+	cmd.Flags().StringVarP(&o.CommitStatus, "commit-status", "", "", fmt.Sprintf("Reports the published release on the released commit linking to the release so that other automation can gate on it. 'check-run' requires the token of a GitHub App. Supported values: %s", strings.Join(CommitStatusModes, ", ")))
+	cmd.Flags().StringVarP(&o.CommitStatusContext, "commit-status-context", "", "changelog", "The context of the --commit-status or the name of the check run")
+	cmd.Flags().BoolVarP(&o.CommentReleased, "comment-released", "", false, "Comments on the pull requests and issues of the release after it is published so that their authors and reporters are notified. Each is only commented on once per tag")
+	cmd.Flags().StringVarP(&o.ReleasedComment, "released-comment", "", DefaultReleasedComment, "The text/template of the --comment-released comment which can use the .Version, .Tag, .Title and .URL of the release")
+}
+
+// Validate validates the options
+func (o *Options) Validate() error {
+	if o.CommitStatus != "" && stringhelpers.StringArrayIndex(CommitStatusModes, o.CommitStatus) < 0 {
+		return errors.Errorf("unsupported --commit-status %s. Supported values: %s", o.CommitStatus, strings.Join(CommitStatusModes, ", "))
+	}
+	if o.CommentReleased {
+		_, err := RenderReleasedComment(o.ReleasedComment, &ReleasedCommentData{})
+		if err != nil {
+			return errors.Wrapf(err, "invalid --released-comment")
+		}
+	}
+	return nil
+}
+
+// Run closes the milestone, comments on the pull requests and issues and reports the commit status of the
+// published release
+func (o *Options) Run(p *Publisher, release *Release) error {
+	if p == nil || p.ScmClient == nil || release == nil {
+		return nil
+	}
+	if p.Context == nil {
+		p.Context = context.Background()
+	}
+	if o.CloseMilestone {
+		p.closeMilestone(release)
+	}
+	if o.CommentReleased {
+		err := p.commentReleased(o.ReleasedComment, release)
+		if err != nil {
+			return err
+		}
+	}
+	if o.CommitStatus != "" {
+		p.reportCommitStatus(o.CommitStatus, o.CommitStatusContext, release)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package postpublish
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+const (
+	// CommitStatusStatus reports a commit status which is supported by most git providers
+	CommitStatusStatus = "status"
+
+	// CommitStatusCheckRun reports a check run which requires the token of a GitHub App
+	CommitStatusCheckRun = "check-run"
+
+	// publishedDescription the description of the commit status once the release is published
+	publishedDescription = "published"
+)
+
+// CommitStatusModes the supported ways of reporting the published release on the released commit
+var CommitStatusModes = []string{CommitStatusStatus, CommitStatusCheckRun}
+
+// reportCommitStatus reports that the release is published on the released commit linking to the release so that
+// other automation can gate on it. Failures are only logged as the release is already published
+func (p *Publisher) reportCommitStatus(mode, context string, release *Release) {
+	sha := release.SHA
+	if sha == "" {
+		log.Logger().Warnf("cannot report the %s of %s as the released commit is unknown", mode, release.Tag)
+		return
+	}
+	if p.DryRun {
+		log.Logger().Infof("would report the %s %s on commit %s", mode, info(context+": "+publishedDescription), info(sha))
+		return
+	}
+	err := p.Backoff.Do(p.Context, func() (*scm.Response, error) {
+		if mode == CommitStatusCheckRun {
+			return scmapi.CreateCheckRun(p.Context, p.ScmClient, release.Repository, &scmapi.CheckRun{
+				Name:       context,
+				SHA:        sha,
+				Conclusion: "success",
+				Title:      publishedDescription,
+				Summary:    fmt.Sprintf("Published the release of tag %s", release.Tag),
+				DetailsURL: release.URL,
+			})
+		}
+		_, res, err := p.ScmClient.Repositories.CreateStatus(p.Context, release.Repository, sha, &scm.StatusInput{
+			State:  scm.StateSuccess,
+			Label:  context,
+			Desc:   publishedDescription,
+			Target: release.URL,
+			Link:   release.URL,
+		})
+		return res, err
+	})
+	if err != nil {
+		log.Logger().Warnf("failed to report the %s on commit %s: %s", mode, sha, err.Error())
+		return
+	}
+	log.Logger().Infof("reported the %s %s on commit %s", mode, info(context+": "+publishedDescription), info(sha))
+}
@@ -0,0 +1,83 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// advisoriesPageSize the number of security advisories listed per page
+const advisoriesPageSize = 100
+
+// SecurityAdvisory a published security advisory of a repository which go-scm does not expose
+type SecurityAdvisory struct {
+	// ID the ID of the advisory such as 'GHSA-xxxx-xxxx-xxxx'
+	ID string
+	// CVE the optional CVE ID such as 'CVE-2021-1234'
+	CVE string
+	// Summary the summary of the advisory
+	Summary string
+	// Description the markdown description of the advisory which may reference the fixing commits or pull requests
+	Description string
+	// Severity the severity such as 'low', 'medium', 'high' or 'critical'
+	Severity string
+	// Link the link to the advisory
+	Link string
+	// PatchedVersions the version ranges of the vulnerable packages which contain the fix
+	PatchedVersions []string
+}
+
+type githubSecurityAdvisory struct {
+	GHSAID          string `json:"ghsa_id"`
+	CVEID           string `json:"cve_id"`
+	Summary         string `json:"summary"`
+	Description     string `json:"description"`
+	Severity        string `json:"severity"`
+	HTMLURL         string `json:"html_url"`
+	Vulnerabilities []struct {
+		PatchedVersions string `json:"patched_versions"`
+	} `json:"vulnerabilities"`
+}
+
+// ListSecurityAdvisories lists the published security advisories of the repository. Supported on GitHub
+func ListSecurityAdvisories(ctx context.Context, client *scm.Client, repo string) ([]*SecurityAdvisory, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	if client.Driver != scm.DriverGithub {
+		return nil, nil, errors.Errorf("git provider %s does not support security advisories", client.Driver.String())
+	}
+	var answer []*SecurityAdvisory
+	var res *scm.Response
+	for page := 1; ; page++ {
+		var advisories []githubSecurityAdvisory
+		var err error
+		res, err = GetJSON(ctx, client, fmt.Sprintf("repos/%s/security-advisories?state=published&per_page=%d&page=%d", repo, advisoriesPageSize, page), &advisories)
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to list the security advisories of %s", repo)
+		}
+		for i := range advisories {
+			a := &advisories[i]
+			sa := &SecurityAdvisory{
+				ID:          a.GHSAID,
+				CVE:         a.CVEID,
+				Summary:     a.Summary,
+				Description: a.Description,
+				Severity:    a.Severity,
+				Link:        a.HTMLURL,
+			}
+			for _, v := range a.Vulnerabilities {
+				if v.PatchedVersions != "" {
+					sa.PatchedVersions = append(sa.PatchedVersions, v.PatchedVersions)
+				}
+			}
+			answer = append(answer, sa)
+		}
+		if len(advisories) < advisoriesPageSize {
+			break
+		}
+	}
+	return answer, res, nil
+}
@@ -0,0 +1,57 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSecurityAdvisories(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/jstrachan/foo/security-advisories" || r.URL.Query().Get("state") != "published" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{
+  "ghsa_id": "GHSA-abcd-efgh-ijkl",
+  "cve_id": "CVE-2021-1234",
+  "summary": "Path traversal in archive extraction",
+  "description": "Fixed by #12",
+  "severity": "high",
+  "html_url": "https://github.com/jstrachan/foo/security/advisories/GHSA-abcd-efgh-ijkl",
+  "vulnerabilities": [{"patched_versions": ">= 1.2.0"}, {"patched_versions": null}]
+}]`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	advisories, _, err := scmapi.ListSecurityAdvisories(ctx, client, "jstrachan/foo")
+	require.NoError(t, err, "failed to list the security advisories")
+	require.Len(t, advisories, 1)
+	assert.Equal(t, &scmapi.SecurityAdvisory{
+		ID:              "GHSA-abcd-efgh-ijkl",
+		CVE:             "CVE-2021-1234",
+		Summary:         "Path traversal in archive extraction",
+		Description:     "Fixed by #12",
+		Severity:        "high",
+		Link:            "https://github.com/jstrachan/foo/security/advisories/GHSA-abcd-efgh-ijkl",
+		PatchedVersions: []string{">= 1.2.0"},
+	}, advisories[0])
+
+	client, err = gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+	_, _, err = scmapi.ListSecurityAdvisories(ctx, client, "jstrachan/foo")
+	assert.Error(t, err, "GitLab should not support security advisories")
+}
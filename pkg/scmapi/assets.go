@@ -0,0 +1,281 @@
+package scmapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultUploadAttempts the default number of attempts to upload a release asset
+	DefaultUploadAttempts = 3
+
+	// DefaultUploadRetryInterval the default initial wait before retrying a failed upload
+	DefaultUploadRetryInterval = 2 * time.Second
+)
+
+// contentTypes the content types of common release assets which are not always known to the mime package
+var contentTypes = map[string]string{
+	".gz":     "application/gzip",
+	".tgz":    "application/gzip",
+	".zip":    "application/zip",
+	".tar":    "application/x-tar",
+	".json":   "application/json",
+	".yaml":   "application/yaml",
+	".yml":    "application/yaml",
+	".sha256": "text/plain",
+	".sha512": "text/plain",
+	".sig":    "application/pgp-signature",
+	".asc":    "application/pgp-signature",
+	".pem":    "application/x-pem-file",
+	".spdx":   "text/spdx",
+	".deb":    "application/vnd.debian.binary-package",
+	".rpm":    "application/x-rpm",
+}
+
+// ReleaseAsset a file to upload to a release
+type ReleaseAsset struct {
+	// Path the path of the file
+	Path string
+	// Label the optional display name of the asset which defaults to the file name
+	Label string
+}
+
+// Name returns the file name of the asset
+func (a *ReleaseAsset) Name() string {
+	return filepath.Base(a.Path)
+}
+
+// ContentType detects the content type of the file from its extension falling back to sniffing its content
+func ContentType(path string, data []byte) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if t, ok := contentTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// IsTransient returns true if the call failed with a network or server error which may succeed if retried
+func IsTransient(res *scm.Response, err error) bool {
+	if err == nil {
+		return false
+	}
+	if res == nil {
+		return true
+	}
+	return res.Status >= http.StatusInternalServerError
+}
+
+// Retry invokes the function up to the given number of attempts while it fails with a transient error doubling
+// the wait between attempts
+func Retry(ctx context.Context, attempts int, interval time.Duration, fn func() (*scm.Response, error)) error {
+	for i := 1; ; i++ {
+		res, err := fn()
+		if i >= attempts || !IsTransient(res, err) {
+			return err
+		}
+		log.Logger().Warnf("retrying in %s after attempt %d failed: %s", interval.String(), i, err.Error())
+		err = sleepContext(ctx, interval)
+		if err != nil {
+			return err
+		}
+		interval *= 2
+	}
+}
+
+type releaseAssetInfo struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// UploadReleaseAsset uploads the file to the GitHub or Gitea release replacing any existing asset of the same name.
+// Transient failures are retried
+func UploadReleaseAsset(ctx context.Context, client *scm.Client, repo string, rel *scm.Release, asset *ReleaseAsset) (*scm.Response, error) {
+	if client == nil {
+		return nil, errors.Errorf("no git provider client")
+	}
+	data, err := ioutil.ReadFile(asset.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read release asset %s", asset.Path)
+	}
+	name := asset.Name()
+
+	var listPath, deletePath string
+	switch client.Driver {
+	case scm.DriverGithub:
+		listPath = fmt.Sprintf("repos/%s/releases/%d/assets", repo, rel.ID)
+		deletePath = fmt.Sprintf("repos/%s/releases/assets/%%d", repo)
+	case scm.DriverGitea:
+		listPath = fmt.Sprintf("api/v1/repos/%s/releases/%d/assets", repo, rel.ID)
+		deletePath = fmt.Sprintf("api/v1/repos/%s/releases/%d/assets/%%d", repo, rel.ID)
+	default:
+		return nil, errors.Errorf("git provider %s does not support uploading release assets", client.Driver.String())
+	}
+
+	// the name of an asset must be unique so lets replace any asset uploaded by a previous run
+	var existing []releaseAssetInfo
+	res, err := GetJSON(ctx, client, listPath, &existing)
+	if err != nil && err != scm.ErrNotFound {
+		return res, errors.Wrapf(err, "failed to list the assets of release %s", rel.Tag)
+	}
+	for _, a := range existing {
+		if a.Name == name {
+			res, err = DoJSON(ctx, client, http.MethodDelete, fmt.Sprintf(deletePath, a.ID), nil, nil)
+			if err != nil {
+				return res, errors.Wrapf(err, "failed to delete the existing asset %s of release %s", name, rel.Tag)
+			}
+		}
+	}
+
+	err = Retry(ctx, DefaultUploadAttempts, DefaultUploadRetryInterval, func() (*scm.Response, error) {
+		var req *scm.Request
+		var err error
+		if client.Driver == scm.DriverGithub {
+			req = githubUploadRequest(client, repo, rel.ID, asset, data)
+		} else {
+			req, err = multipartRequest(fmt.Sprintf("api/v1/repos/%s/releases/%d/assets?name=%s", repo, rel.ID, url.QueryEscape(name)), "attachment", name, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		res, err = doRequest(ctx, client, req, nil)
+		return res, err
+	})
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to upload asset %s to release %s", name, rel.Tag)
+	}
+	return res, nil
+}
+
+// githubUploadRequest returns the request to upload the asset to the uploads host of github.com or the uploads API
+// of GitHub Enterprise
+func githubUploadRequest(client *scm.Client, repo string, id int, asset *ReleaseAsset, data []byte) *scm.Request {
+	base := *client.BaseURL
+	switch {
+	case base.Host == "api.github.com":
+		base.Host = "uploads.github.com"
+	case strings.HasSuffix(base.Path, "/api/v3/"):
+		base.Path = strings.TrimSuffix(base.Path, "v3/") + "uploads/"
+	}
+	query := url.Values{"name": []string{asset.Name()}}
+	if asset.Label != "" {
+		query.Set("label", asset.Label)
+	}
+	u := base.String() + fmt.Sprintf("repos/%s/releases/%d/assets?%s", repo, id, query.Encode())
+	return &scm.Request{
+		Method: http.MethodPost,
+		Path:   u,
+		Header: http.Header{
+			"Accept":       []string{"application/json"},
+			"Content-Type": []string{ContentType(asset.Path, data)},
+		},
+		Body: bytes.NewReader(data),
+	}
+}
+
+// UploadGitLabFile uploads the file to the project uploads of the GitLab repository returning a release asset link
+// to it as GitLab releases only link to assets. Transient failures are retried
+func UploadGitLabFile(ctx context.Context, client *scm.Client, repo string, asset *ReleaseAsset) (*ReleaseAssetLink, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	data, err := ioutil.ReadFile(asset.Path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read release asset %s", asset.Path)
+	}
+	name := asset.Name()
+	uploaded := struct {
+		URL      string `json:"url"`
+		FullPath string `json:"full_path"`
+	}{}
+	var res *scm.Response
+	err = Retry(ctx, DefaultUploadAttempts, DefaultUploadRetryInterval, func() (*scm.Response, error) {
+		req, err := multipartRequest(fmt.Sprintf("api/v4/projects/%s/uploads", url.PathEscape(repo)), "file", name, data)
+		if err != nil {
+			return nil, err
+		}
+		res, err = doRequest(ctx, client, req, &uploaded)
+		return res, err
+	})
+	if err != nil {
+		return nil, res, errors.Wrapf(err, "failed to upload asset %s", name)
+	}
+	// older GitLab versions only return the path relative to the project
+	path := uploaded.FullPath
+	if path == "" {
+		path = "/" + repo + uploaded.URL
+	}
+	server := url.URL{Scheme: client.BaseURL.Scheme, Host: client.BaseURL.Host}
+	link := &ReleaseAssetLink{
+		Name: asset.Label,
+		URL:  server.String() + path,
+	}
+	if link.Name == "" {
+		link.Name = name
+	}
+	return link, res, nil
+}
+
+// multipartRequest returns a POST request uploading the data as the file of the multipart form field
+func multipartRequest(path, field, name string, data []byte) (*scm.Request, error) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	part, err := w.CreateFormFile(field, name)
+	if err == nil {
+		_, err = part.Write(data)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the multipart form of %s", name)
+	}
+	return &scm.Request{
+		Method: http.MethodPost,
+		Path:   path,
+		Header: http.Header{
+			"Accept":       []string{"application/json"},
+			"Content-Type": []string{w.FormDataContentType()},
+		},
+		Body: bytes.NewReader(buf.Bytes()),
+	}, nil
+}
+
+// doRequest invokes the request unmarshalling the JSON response into the result if it is not nil
+func doRequest(ctx context.Context, client *scm.Client, req *scm.Request, result interface{}) (*scm.Response, error) {
+	res, err := client.Do(ctx, req)
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to invoke %s %s", req.Method, req.Path)
+	}
+	defer res.Body.Close()
+
+	if res.Status >= 300 {
+		data, _ := ioutil.ReadAll(io.LimitReader(res.Body, 4096))
+		return res, errors.Errorf("%s %s returned status %d: %s", req.Method, req.Path, res.Status, string(data))
+	}
+	if result == nil {
+		return res, nil
+	}
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil && err != io.EOF {
+		return res, errors.Wrapf(err, "failed to parse JSON response of %s %s", req.Method, req.Path)
+	}
+	return res, nil
+}
@@ -0,0 +1,139 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/gitea"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadReleaseAsset(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "foo-linux-amd64.tar.gz")
+	err := ioutil.WriteFile(path, []byte("cheese"), 0600)
+	require.NoError(t, err)
+	asset := &scmapi.ReleaseAsset{Path: path, Label: "Linux binary"}
+
+	var requests []string
+	uploads := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		requests = append(requests, key)
+		switch key {
+		case "GET /api/v1/version":
+			_, _ = w.Write([]byte(`{"version": "1.17.0"}`))
+		case "GET /repos/jstrachan/foo/releases/3/assets", "GET /api/v1/repos/jstrachan/foo/releases/3/assets":
+			_, _ = w.Write([]byte(`[{"id": 5, "name": "foo-linux-amd64.tar.gz"}, {"id": 6, "name": "other.txt"}]`))
+		case "DELETE /repos/jstrachan/foo/releases/assets/5", "DELETE /api/v1/repos/jstrachan/foo/releases/3/assets/5":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST /repos/jstrachan/foo/releases/3/assets":
+			data, _ := ioutil.ReadAll(r.Body)
+			uploads[r.URL.Query().Get("name")] = r.URL.Query().Get("label") + ":" + r.Header.Get("Content-Type") + ":" + string(data)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 7}`))
+		case "POST /api/v1/repos/jstrachan/foo/releases/3/assets":
+			file, _, err := r.FormFile("attachment")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := ioutil.ReadAll(file)
+			uploads[r.URL.Query().Get("name")] = string(data)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 7}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	rel := &scm.Release{ID: 3, Tag: "v1.0.0"}
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	_, err = scmapi.UploadReleaseAsset(ctx, client, "jstrachan/foo", rel, asset)
+	require.NoError(t, err, "failed to upload to GitHub")
+	assert.Equal(t, "Linux binary:application/gzip:cheese", uploads["foo-linux-amd64.tar.gz"])
+	assert.Contains(t, requests, "DELETE /repos/jstrachan/foo/releases/assets/5")
+	assert.NotContains(t, requests, "DELETE /repos/jstrachan/foo/releases/assets/6")
+
+	uploads = map[string]string{}
+	client, err = gitea.New(server.URL)
+	require.NoError(t, err, "failed to create Gitea client")
+	_, err = scmapi.UploadReleaseAsset(ctx, client, "jstrachan/foo", rel, asset)
+	require.NoError(t, err, "failed to upload to Gitea")
+	assert.Equal(t, "cheese", uploads["foo-linux-amd64.tar.gz"])
+	assert.Contains(t, requests, "DELETE /api/v1/repos/jstrachan/foo/releases/3/assets/5")
+}
+
+func TestUploadGitLabFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "checksums.txt")
+	err := ioutil.WriteFile(path, []byte("abc123  foo"), 0600)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.EscapedPath() != "/api/v4/projects/jstrachan%2Ffoo/uploads" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"url": "/uploads/abc/` + header.Filename + `", "full_path": "/jstrachan/foo/uploads/abc/` + header.Filename + `"}`))
+	}))
+	defer server.Close()
+
+	client, err := gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+	link, _, err := scmapi.UploadGitLabFile(context.Background(), client, "jstrachan/foo", &scmapi.ReleaseAsset{Path: path})
+	require.NoError(t, err, "failed to upload to GitLab")
+	assert.Equal(t, "checksums.txt", link.Name)
+	assert.Equal(t, server.URL+"/jstrachan/foo/uploads/abc/checksums.txt", link.URL)
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	err := scmapi.Retry(context.Background(), 3, time.Millisecond, func() (*scm.Response, error) {
+		calls++
+		if calls < 3 {
+			return &scm.Response{Status: http.StatusBadGateway}, errors.New("bad gateway")
+		}
+		return &scm.Response{Status: http.StatusCreated}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = scmapi.Retry(context.Background(), 3, time.Millisecond, func() (*scm.Response, error) {
+		calls++
+		return &scm.Response{Status: http.StatusUnprocessableEntity}, errors.New("already exists")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls, "should not retry client errors")
+}
+
+func TestContentType(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "application/gzip", scmapi.ContentType("foo.tar.gz", nil))
+	assert.Equal(t, "application/json", scmapi.ContentType("sbom.spdx.json", nil))
+	assert.Equal(t, "text/plain; charset=utf-8", scmapi.ContentType("foo-linux-amd64", []byte("#!/bin/sh\necho hello")))
+	assert.Equal(t, "application/octet-stream", scmapi.ContentType("foo-linux-amd64", []byte{0x7f, 'E', 'L', 'F', 0, 1, 2}))
+}
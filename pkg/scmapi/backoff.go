@@ -0,0 +1,250 @@
+package scmapi
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultInitialInterval the default initial wait before retrying a rate limited call
+	DefaultInitialInterval = time.Second
+
+	// DefaultMaxInterval the default maximum wait between retries of a rate limited call
+	DefaultMaxInterval = 30 * time.Second
+
+	// DefaultMaxWait the default maximum total time spent waiting for rate limits
+	DefaultMaxWait = 2 * time.Minute
+
+	// DefaultWarnRemaining the default number of remaining calls of the rate limit below which a warning is logged
+	DefaultWarnRemaining = 100
+)
+
+// ErrRateLimited is returned when a call is still rate limited after the maximum wait
+var ErrRateLimited = errors.New("git provider rate limit exceeded")
+
+// Backoff retries git provider calls which are rate limited using exponential backoff with jitter
+type Backoff struct {
+	// InitialInterval the initial wait before retrying
+	InitialInterval time.Duration
+	// MaxInterval the maximum wait between retries
+	MaxInterval time.Duration
+	// MaxWait the maximum total time spent waiting across all calls before rate limited calls fail fast
+	MaxWait time.Duration
+	// WarnRemaining logs a warning once when the remaining calls of the rate limit fall to this number.
+	// Zero disables the warning
+	WarnRemaining int
+
+	sleep func(ctx context.Context, d time.Duration) error
+
+	lock   sync.Mutex
+	rate   scm.Rate
+	warned bool
+	waited time.Duration
+}
+
+// NewBackoff creates a new backoff with the default intervals and the given maximum wait
+func NewBackoff(maxWait time.Duration) *Backoff {
+	return &Backoff{
+		InitialInterval: DefaultInitialInterval,
+		MaxInterval:     DefaultMaxInterval,
+		MaxWait:         maxWait,
+		WarnRemaining:   DefaultWarnRemaining,
+	}
+}
+
+// Budget returns the last rate limit reported by the git provider and false if none was reported yet
+func (b *Backoff) Budget() (scm.Rate, bool) {
+	if b == nil {
+		return scm.Rate{}, false
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.rate, b.rate.Limit > 0
+}
+
+// record records the rate limit of the response warning if the remaining budget is low
+func (b *Backoff) record(res *scm.Response) {
+	rate, ok := RateLimit(res)
+	if !ok {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.rate = rate
+	if b.warned || b.WarnRemaining <= 0 || rate.Remaining > b.WarnRemaining {
+		return
+	}
+	b.warned = true
+	log.Logger().Warnf("only %d of %d git provider calls remain until the rate limit resets at %s", rate.Remaining, rate.Limit, time.Unix(rate.Reset, 0).Format(time.RFC3339))
+}
+
+// reserve adds the wait to the total time waited returning false if it would exceed the maximum wait
+func (b *Backoff) reserve(wait time.Duration) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.waited+wait > b.MaxWait {
+		return false
+	}
+	b.waited += wait
+	return true
+}
+
+// Waited returns the total time spent waiting for rate limits across all calls
+func (b *Backoff) Waited() time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.waited
+}
+
+// exhausted returns how long to pause until the rate limit resets if the last response said no calls remain
+func (b *Backoff) exhausted() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.rate.Limit <= 0 || b.rate.Remaining > 0 || b.rate.Reset <= 0 {
+		return 0
+	}
+	return time.Until(time.Unix(b.rate.Reset, 0))
+}
+
+// Do invokes the function retrying it while the git provider reports a rate limit. If a previous call used up the
+// rate limit the call is paused until the limit resets rather than failing.
+// The maximum wait is shared by all calls so once it is used up rate limited calls return ErrRateLimited
+// without waiting
+func (b *Backoff) Do(ctx context.Context, fn func() (*scm.Response, error)) error {
+	if b == nil {
+		_, err := fn()
+		return err
+	}
+	sleep := b.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+	interval := b.InitialInterval
+	if interval <= 0 {
+		interval = DefaultInitialInterval
+	}
+	maxInterval := b.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxInterval
+	}
+	if wait := b.exhausted(); wait > 0 {
+		if !b.reserve(wait) {
+			return errors.Wrapf(ErrRateLimited, "the rate limit resets in %s", wait.Round(time.Second).String())
+		}
+		log.Logger().Warnf("git provider rate limit used up, pausing for %s until it resets", wait.Round(time.Millisecond).String())
+		err := sleep(ctx, wait)
+		if err != nil {
+			return err
+		}
+	}
+	for {
+		res, err := fn()
+		b.record(res)
+		if !IsRateLimited(res, err) {
+			return err
+		}
+		wait := RetryAfter(res)
+		if wait <= 0 {
+			// lets add up to 50% jitter so concurrent callers do not retry at the same time
+			wait = interval + time.Duration(rand.Int63n(int64(interval)/2+1))
+			interval *= 2
+			if interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+		if !b.reserve(wait) {
+			return errors.Wrapf(ErrRateLimited, "gave up after waiting %s", b.Waited().String())
+		}
+		log.Logger().Warnf("git provider rate limit reached, retrying in %s", wait.Round(time.Millisecond).String())
+		err = sleep(ctx, wait)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// IsRateLimited returns true if the response or error indicates the git provider rate limit was exceeded
+func IsRateLimited(res *scm.Response, err error) bool {
+	if res != nil {
+		if res.Status == http.StatusTooManyRequests {
+			return true
+		}
+		if res.Status == http.StatusForbidden && res.Header != nil {
+			if res.Header.Get("X-RateLimit-Remaining") == "0" || res.Header.Get("Retry-After") != "" {
+				return true
+			}
+		}
+	}
+	if err != nil {
+		text := strings.ToLower(err.Error())
+		return strings.Contains(text, "rate limit") || strings.Contains(text, "abuse detection")
+	}
+	return false
+}
+
+// RateLimit returns the rate limit reported by the GitHub or GitLab response headers and false if there are none
+func RateLimit(res *scm.Response) (scm.Rate, bool) {
+	if res == nil {
+		return scm.Rate{}, false
+	}
+	if res.Header != nil {
+		for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+			limit, err := strconv.Atoi(res.Header.Get(prefix + "Limit"))
+			if err != nil || limit <= 0 {
+				continue
+			}
+			rate := scm.Rate{Limit: limit}
+			rate.Remaining, _ = strconv.Atoi(res.Header.Get(prefix + "Remaining"))
+			rate.Reset, _ = strconv.ParseInt(res.Header.Get(prefix+"Reset"), 10, 64)
+			return rate, true
+		}
+	}
+	return res.Rate, res.Rate.Limit > 0
+}
+
+// RetryAfter returns how long the git provider asked us to wait before retrying or zero if it did not say
+func RetryAfter(res *scm.Response) time.Duration {
+	if res == nil || res.Header == nil {
+		return 0
+	}
+	value := res.Header.Get("Retry-After")
+	if value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	if res.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err == nil {
+			d := time.Until(time.Unix(reset, 0))
+			if d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,160 @@
+// +build unit
+
+package scmapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffRetriesRateLimitedCalls(t *testing.T) {
+	t.Parallel()
+	var waits []time.Duration
+	b := NewBackoff(time.Minute)
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	calls := 0
+	err := b.Do(context.TODO(), func() (*scm.Response, error) {
+		calls++
+		if calls < 3 {
+			return &scm.Response{Status: http.StatusTooManyRequests, Header: http.Header{}}, errors.New("too many requests")
+		}
+		return &scm.Response{Status: http.StatusOK}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Len(t, waits, 2)
+	assert.True(t, waits[1] >= 2*time.Second, "should back off exponentially but waited %s", waits[1])
+}
+
+func TestBackoffGivesUpAfterMaxWait(t *testing.T) {
+	t.Parallel()
+	b := NewBackoff(5 * time.Second)
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		return nil
+	}
+	err := b.Do(context.TODO(), func() (*scm.Response, error) {
+		header := http.Header{}
+		header.Set("Retry-After", "2")
+		return &scm.Response{Status: http.StatusForbidden, Header: header}, errors.New("secondary rate limit")
+	})
+	assert.Equal(t, ErrRateLimited, errors.Cause(err))
+}
+
+func TestBackoffSharesMaxWaitAcrossCalls(t *testing.T) {
+	t.Parallel()
+	b := NewBackoff(5 * time.Second)
+	waits := 0
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		waits++
+		return nil
+	}
+	rateLimitedOnce := func() func() (*scm.Response, error) {
+		calls := 0
+		return func() (*scm.Response, error) {
+			calls++
+			if calls > 1 {
+				return &scm.Response{Status: http.StatusOK}, nil
+			}
+			header := http.Header{}
+			header.Set("Retry-After", "3")
+			return &scm.Response{Status: http.StatusTooManyRequests, Header: header}, errors.New("too many requests")
+		}
+	}
+
+	err := b.Do(context.TODO(), rateLimitedOnce())
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, b.Waited())
+
+	err = b.Do(context.TODO(), rateLimitedOnce())
+	assert.Equal(t, ErrRateLimited, errors.Cause(err), "should fail fast once the shared budget is used up")
+	assert.Equal(t, 1, waits)
+}
+
+func TestBackoffDoesNotRetryOtherErrors(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	err := NewBackoff(time.Minute).Do(context.TODO(), func() (*scm.Response, error) {
+		calls++
+		return &scm.Response{Status: http.StatusNotFound}, scm.ErrNotFound
+	})
+	assert.Equal(t, scm.ErrNotFound, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoffPausesUntilRateLimitResets(t *testing.T) {
+	t.Parallel()
+	var waits []time.Duration
+	b := NewBackoff(time.Hour)
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	reset := time.Now().Add(10 * time.Minute).Unix()
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "5000")
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	err := b.Do(context.TODO(), func() (*scm.Response, error) {
+		return &scm.Response{Status: http.StatusOK, Header: header}, nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, waits)
+
+	rate, ok := b.Budget()
+	assert.True(t, ok)
+	assert.Equal(t, scm.Rate{Limit: 5000, Remaining: 0, Reset: reset}, rate)
+
+	err = b.Do(context.TODO(), func() (*scm.Response, error) {
+		return &scm.Response{Status: http.StatusOK}, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, waits, 1, "should pause the call until the rate limit resets")
+	assert.True(t, waits[0] > 9*time.Minute, "waited %s", waits[0])
+
+	b = NewBackoff(time.Minute)
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		return nil
+	}
+	_ = b.Do(context.TODO(), func() (*scm.Response, error) {
+		return &scm.Response{Status: http.StatusOK, Header: header}, nil
+	})
+	calls := 0
+	err = b.Do(context.TODO(), func() (*scm.Response, error) {
+		calls++
+		return &scm.Response{Status: http.StatusOK}, nil
+	})
+	assert.Equal(t, ErrRateLimited, errors.Cause(err), "should give up if the rate limit resets after the maximum wait")
+	assert.Equal(t, 0, calls)
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+	_, ok := RateLimit(nil)
+	assert.False(t, ok)
+	_, ok = RateLimit(&scm.Response{Header: http.Header{}})
+	assert.False(t, ok)
+
+	header := http.Header{}
+	header.Set("RateLimit-Limit", "600")
+	header.Set("RateLimit-Remaining", "42")
+	header.Set("RateLimit-Reset", "1700000000")
+	rate, ok := RateLimit(&scm.Response{Header: header})
+	assert.True(t, ok)
+	assert.Equal(t, scm.Rate{Limit: 600, Remaining: 42, Reset: 1700000000}, rate)
+
+	rate, ok = RateLimit(&scm.Response{Rate: scm.Rate{Limit: 5000, Remaining: 10}})
+	assert.True(t, ok)
+	assert.Equal(t, 10, rate.Remaining)
+}
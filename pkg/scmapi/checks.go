@@ -0,0 +1,55 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// CheckRun a completed check run of a commit which go-scm does not support
+type CheckRun struct {
+	// Name the name of the check run
+	Name string
+	// SHA the commit the check run is reported on
+	SHA string
+	// Conclusion the conclusion of the check run such as 'success' or 'failure'
+	Conclusion string
+	// Title the title of the output of the check run
+	Title string
+	// Summary the markdown summary of the output of the check run
+	Summary string
+	// DetailsURL the optional link to the details of the check run
+	DetailsURL string
+}
+
+// CreateCheckRun creates the completed check run on the commit. Requires the token of a GitHub App.
+// Supported on GitHub
+func CreateCheckRun(ctx context.Context, client *scm.Client, repo string, run *CheckRun) (*scm.Response, error) {
+	if client == nil {
+		return nil, errors.Errorf("no git provider client")
+	}
+	if client.Driver != scm.DriverGithub {
+		return nil, errors.Errorf("git provider %s does not support check runs", client.Driver.String())
+	}
+	body := map[string]interface{}{
+		"name":       run.Name,
+		"head_sha":   run.SHA,
+		"status":     "completed",
+		"conclusion": run.Conclusion,
+		"output": map[string]string{
+			"title":   run.Title,
+			"summary": run.Summary,
+		},
+	}
+	if run.DetailsURL != "" {
+		body["details_url"] = run.DetailsURL
+	}
+	res, err := DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("repos/%s/check-runs", repo), body, nil)
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to create the check run %s on commit %s", run.Name, run.SHA)
+	}
+	return res, nil
+}
@@ -0,0 +1,59 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCheckRun(t *testing.T) {
+	t.Parallel()
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/jstrachan/foo/check-runs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 4}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	_, err = scmapi.CreateCheckRun(ctx, client, "jstrachan/foo", &scmapi.CheckRun{
+		Name:       "changelog",
+		SHA:        "1111111111",
+		Conclusion: "success",
+		Title:      "published",
+		Summary:    "Published v1.2.3",
+		DetailsURL: "https://github.com/jstrachan/foo/releases/tag/v1.2.3",
+	})
+	require.NoError(t, err, "failed to create the check run")
+	assert.JSONEq(t, `{
+  "name": "changelog",
+  "head_sha": "1111111111",
+  "status": "completed",
+  "conclusion": "success",
+  "details_url": "https://github.com/jstrachan/foo/releases/tag/v1.2.3",
+  "output": {"title": "published", "summary": "Published v1.2.3"}
+}`, body)
+
+	client, err = gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+	_, err = scmapi.CreateCheckRun(ctx, client, "jstrachan/foo", &scmapi.CheckRun{Name: "changelog"})
+	assert.Error(t, err, "GitLab should not support check runs")
+}
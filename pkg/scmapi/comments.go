@@ -0,0 +1,62 @@
+package scmapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// commentsPageSize the number of comments listed per page when looking for an existing comment
+const commentsPageSize = 100
+
+// CreateCommentOnce adds the comment to the pull request or issue unless one of its comments already contains the
+// marker so that re-running a release does not notify the authors and reporters again.
+// Returns false if the comment already exists
+func CreateCommentOnce(ctx context.Context, client *scm.Client, repo string, number int, pullRequest bool, marker, body string) (bool, *scm.Response, error) {
+	if client == nil {
+		return false, nil, errors.Errorf("no git provider client")
+	}
+	kind := "issue"
+	if pullRequest {
+		kind = "pull request"
+	}
+	var res *scm.Response
+	for page := 1; ; page++ {
+		var comments []*scm.Comment
+		var err error
+		opts := scm.ListOptions{Page: page, Size: commentsPageSize}
+		if pullRequest {
+			comments, res, err = client.PullRequests.ListComments(ctx, repo, number, opts)
+		} else {
+			comments, res, err = client.Issues.ListComments(ctx, repo, number, opts)
+		}
+		if err == scm.ErrNotFound {
+			break
+		}
+		if err != nil {
+			return false, res, errors.Wrapf(err, "failed to list the comments of %s %d", kind, number)
+		}
+		for _, c := range comments {
+			if strings.Contains(c.Body, marker) {
+				return false, res, nil
+			}
+		}
+		if len(comments) < commentsPageSize {
+			break
+		}
+	}
+
+	input := &scm.CommentInput{Body: body + "\n\n" + marker}
+	var err error
+	if pullRequest {
+		_, res, err = client.PullRequests.CreateComment(ctx, repo, number, input)
+	} else {
+		_, res, err = client.Issues.CreateComment(ctx, repo, number, input)
+	}
+	if err != nil {
+		return false, res, errors.Wrapf(err, "failed to comment on %s %d", kind, number)
+	}
+	return true, res, nil
+}
@@ -0,0 +1,38 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCommentOnce(t *testing.T) {
+	t.Parallel()
+	client, data := scmfake.NewDefault()
+	data.IssueComments[7] = []*scm.Comment{{ID: 1, Body: "me too"}}
+	ctx := context.Background()
+	marker := "<!-- released v1.2.3 -->"
+
+	added, _, err := scmapi.CreateCommentOnce(ctx, client, "jstrachan/foo", 12, true, marker, "released in v1.2.3")
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, _, err = scmapi.CreateCommentOnce(ctx, client, "jstrachan/foo", 12, true, marker, "released in v1.2.3")
+	require.NoError(t, err)
+	assert.False(t, added, "should not comment twice")
+	assert.Equal(t, []string{"jstrachan/foo#12:released in v1.2.3\n\n" + marker}, data.PullRequestCommentsAdded)
+
+	added, _, err = scmapi.CreateCommentOnce(ctx, client, "jstrachan/foo", 7, false, marker, "released in v1.2.3")
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, _, err = scmapi.CreateCommentOnce(ctx, client, "jstrachan/foo", 7, false, "<!-- released v1.2.4 -->", "released in v1.2.4")
+	require.NoError(t, err)
+	assert.True(t, added, "should comment on the next release")
+	assert.Len(t, data.IssueComments[7], 3)
+}
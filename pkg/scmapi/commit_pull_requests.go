@@ -0,0 +1,266 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+type githubCommitPullRequest struct {
+	Number   int        `json:"number"`
+	Title    string     `json:"title"`
+	Body     string     `json:"body"`
+	State    string     `json:"state"`
+	HTMLURL  string     `json:"html_url"`
+	MergedAt *time.Time `json:"merged_at"`
+	Created  time.Time  `json:"created_at"`
+	User     struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type gitlabCommitMergeRequest struct {
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	WebURL      string    `json:"web_url"`
+	Created     time.Time `json:"created_at"`
+	Author      struct {
+		Username  string `json:"username"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"author"`
+	Labels []string `json:"labels"`
+}
+
+type bitbucketCommitPullRequests struct {
+	Values []struct {
+		ID          int       `json:"id"`
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		State       string    `json:"state"`
+		Created     time.Time `json:"created_on"`
+		Author      struct {
+			Nickname    string `json:"nickname"`
+			DisplayName string `json:"display_name"`
+			Links       struct {
+				Avatar struct {
+					Href string `json:"href"`
+				} `json:"avatar"`
+			} `json:"links"`
+		} `json:"author"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	} `json:"values"`
+}
+
+type stashCommitPullRequests struct {
+	Values []struct {
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		State       string `json:"state"`
+		CreatedDate int64  `json:"createdDate"`
+		Author      struct {
+			User struct {
+				Name         string `json:"name"`
+				DisplayName  string `json:"displayName"`
+				EmailAddress string `json:"emailAddress"`
+			} `json:"user"`
+		} `json:"author"`
+		Links struct {
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+	} `json:"values"`
+}
+
+type giteaCommitPullRequest struct {
+	Number  int       `json:"number"`
+	Title   string    `json:"title"`
+	Body    string    `json:"body"`
+	State   string    `json:"state"`
+	HTMLURL string    `json:"html_url"`
+	Merged  bool      `json:"merged"`
+	Created time.Time `json:"created_at"`
+	User    struct {
+		Login     string `json:"login"`
+		FullName  string `json:"full_name"`
+		Email     string `json:"email"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// ListCommitPullRequests lists the pull requests which contain the commit as go-scm does not yet expose them.
+// Returns an error if the git provider is not supported
+func ListCommitPullRequests(ctx context.Context, client *scm.Client, repo, sha string) ([]*scm.PullRequest, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	switch client.Driver {
+	case scm.DriverGithub:
+		var prs []githubCommitPullRequest
+		res, err := GetJSON(ctx, client, fmt.Sprintf("repos/%s/commits/%s/pulls", repo, sha), &prs)
+		if err != nil {
+			return nil, res, err
+		}
+		var answer []*scm.PullRequest
+		for i := range prs {
+			pr := &prs[i]
+			p := &scm.PullRequest{
+				Number:  pr.Number,
+				Title:   pr.Title,
+				Body:    pr.Body,
+				State:   pr.State,
+				Closed:  pr.State == "closed",
+				Merged:  pr.MergedAt != nil,
+				Link:    pr.HTMLURL,
+				Created: pr.Created,
+				Author: scm.User{
+					Login:  pr.User.Login,
+					Avatar: pr.User.AvatarURL,
+				},
+			}
+			for _, l := range pr.Labels {
+				p.Labels = append(p.Labels, &scm.Label{Name: l.Name})
+			}
+			answer = append(answer, p)
+		}
+		return answer, res, nil
+	case scm.DriverGitlab:
+		var mrs []gitlabCommitMergeRequest
+		res, err := GetJSON(ctx, client, fmt.Sprintf("api/v4/projects/%s/repository/commits/%s/merge_requests", url.PathEscape(repo), sha), &mrs)
+		if err != nil {
+			return nil, res, err
+		}
+		var answer []*scm.PullRequest
+		for i := range mrs {
+			mr := &mrs[i]
+			p := &scm.PullRequest{
+				Number:  mr.IID,
+				Title:   mr.Title,
+				Body:    mr.Description,
+				State:   mr.State,
+				Closed:  mr.State == "closed" || mr.State == "merged",
+				Merged:  mr.State == "merged",
+				Link:    mr.WebURL,
+				Created: mr.Created,
+				Author: scm.User{
+					Login:  mr.Author.Username,
+					Name:   mr.Author.Name,
+					Avatar: mr.Author.AvatarURL,
+				},
+			}
+			for _, l := range mr.Labels {
+				p.Labels = append(p.Labels, &scm.Label{Name: l})
+			}
+			answer = append(answer, p)
+		}
+		return answer, res, nil
+	case scm.DriverBitbucket:
+		var prs bitbucketCommitPullRequests
+		res, err := GetJSON(ctx, client, fmt.Sprintf("2.0/repositories/%s/commit/%s/pullrequests", repo, sha), &prs)
+		if err != nil {
+			return nil, res, err
+		}
+		var answer []*scm.PullRequest
+		for i := range prs.Values {
+			pr := &prs.Values[i]
+			answer = append(answer, &scm.PullRequest{
+				Number:  pr.ID,
+				Title:   pr.Title,
+				Body:    pr.Description,
+				State:   strings.ToLower(pr.State),
+				Closed:  pr.State != "OPEN",
+				Merged:  pr.State == "MERGED",
+				Link:    pr.Links.HTML.Href,
+				Created: pr.Created,
+				Author: scm.User{
+					Login:  pr.Author.Nickname,
+					Name:   pr.Author.DisplayName,
+					Avatar: pr.Author.Links.Avatar.Href,
+				},
+			})
+		}
+		return answer, res, nil
+	case scm.DriverStash:
+		namespace, name := scm.Split(repo)
+		var prs stashCommitPullRequests
+		res, err := GetJSON(ctx, client, fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s/commits/%s/pull-requests", namespace, name, sha), &prs)
+		if err != nil {
+			return nil, res, err
+		}
+		var answer []*scm.PullRequest
+		for i := range prs.Values {
+			pr := &prs.Values[i]
+			p := &scm.PullRequest{
+				Number:  pr.ID,
+				Title:   pr.Title,
+				Body:    pr.Description,
+				State:   strings.ToLower(pr.State),
+				Closed:  pr.State != "OPEN",
+				Merged:  pr.State == "MERGED",
+				Created: time.Unix(0, pr.CreatedDate*int64(time.Millisecond)),
+				Author: scm.User{
+					Login: pr.Author.User.Name,
+					Name:  pr.Author.User.DisplayName,
+					Email: pr.Author.User.EmailAddress,
+				},
+			}
+			if len(pr.Links.Self) > 0 {
+				p.Link = pr.Links.Self[0].Href
+			}
+			answer = append(answer, p)
+		}
+		return answer, res, nil
+	case scm.DriverGitea:
+		// gitea only returns the pull request which merged the commit
+		pr := &giteaCommitPullRequest{}
+		res, err := GetJSON(ctx, client, fmt.Sprintf("api/v1/repos/%s/commits/%s/pull", repo, sha), pr)
+		if err == scm.ErrNotFound {
+			return nil, res, nil
+		}
+		if err != nil {
+			return nil, res, err
+		}
+		p := &scm.PullRequest{
+			Number:  pr.Number,
+			Title:   pr.Title,
+			Body:    pr.Body,
+			State:   pr.State,
+			Closed:  pr.State == "closed",
+			Merged:  pr.Merged,
+			Link:    pr.HTMLURL,
+			Created: pr.Created,
+			Author: scm.User{
+				Login:  pr.User.Login,
+				Name:   pr.User.FullName,
+				Email:  pr.User.Email,
+				Avatar: pr.User.AvatarURL,
+			},
+		}
+		for _, l := range pr.Labels {
+			p.Labels = append(p.Labels, &scm.Label{Name: l.Name})
+		}
+		return []*scm.PullRequest{p}, res, nil
+	default:
+		return nil, nil, errors.Errorf("listing the pull requests of commits is not supported for git provider %s", client.Driver.String())
+	}
+}
@@ -0,0 +1,136 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm/driver/bitbucket"
+	"github.com/jenkins-x/go-scm/scm/driver/gitea"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/stash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCommitPullRequests(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/jstrachan/foo/commits/abc123/pulls" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+  {
+    "number": 12,
+    "title": "feat: cheese",
+    "state": "closed",
+    "html_url": "https://github.com/jstrachan/foo/pull/12",
+    "merged_at": "2021-03-01T10:00:00Z",
+    "user": {"login": "jstrachan"},
+    "labels": [{"name": "enhancement"}]
+  }
+]`))
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	prs, _, err := scmapi.ListCommitPullRequests(context.Background(), client, "jstrachan/foo", "abc123")
+	require.NoError(t, err, "failed to list the pull requests of the commit")
+	require.Len(t, prs, 1)
+	assert.Equal(t, 12, prs[0].Number)
+	assert.Equal(t, "feat: cheese", prs[0].Title)
+	assert.True(t, prs[0].Merged)
+	assert.Equal(t, "jstrachan", prs[0].Author.Login)
+	require.Len(t, prs[0].Labels, 1)
+	assert.Equal(t, "enhancement", prs[0].Labels[0].Name)
+
+	_, _, err = scmapi.ListCommitPullRequests(context.Background(), client, "jstrachan/foo", "def456")
+	assert.Error(t, err)
+}
+
+func TestListCommitPullRequestsBitbucket(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/jstrachan/foo/commit/abc123/pullrequests":
+			_, _ = w.Write([]byte(`{"values": [
+  {
+    "id": 7,
+    "title": "fix: the cork",
+    "state": "MERGED",
+    "created_on": "2021-03-01T10:00:00Z",
+    "author": {"nickname": "jstrachan", "display_name": "James Strachan"},
+    "links": {"html": {"href": "https://bitbucket.org/jstrachan/foo/pull-requests/7"}}
+  }
+]}`))
+		case "/rest/api/1.0/projects/JX/repos/foo/commits/abc123/pull-requests":
+			_, _ = w.Write([]byte(`{"values": [
+  {
+    "id": 8,
+    "title": "feat: wine",
+    "state": "OPEN",
+    "createdDate": 1614592800000,
+    "author": {"user": {"name": "jstrachan", "displayName": "James Strachan"}},
+    "links": {"self": [{"href": "https://bitbucket.example.com/projects/JX/repos/foo/pull-requests/8"}]}
+  }
+]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := bitbucket.New(server.URL)
+	require.NoError(t, err, "failed to create Bitbucket client")
+	prs, _, err := scmapi.ListCommitPullRequests(context.Background(), client, "jstrachan/foo", "abc123")
+	require.NoError(t, err, "failed to list the pull requests of the commit on Bitbucket Cloud")
+	require.Len(t, prs, 1)
+	assert.Equal(t, 7, prs[0].Number)
+	assert.True(t, prs[0].Merged)
+	assert.Equal(t, "jstrachan", prs[0].Author.Login)
+	assert.Equal(t, "https://bitbucket.org/jstrachan/foo/pull-requests/7", prs[0].Link)
+
+	client, err = stash.New(server.URL)
+	require.NoError(t, err, "failed to create Bitbucket Server client")
+	prs, _, err = scmapi.ListCommitPullRequests(context.Background(), client, "JX/foo", "abc123")
+	require.NoError(t, err, "failed to list the pull requests of the commit on Bitbucket Server")
+	require.Len(t, prs, 1)
+	assert.Equal(t, 8, prs[0].Number)
+	assert.False(t, prs[0].Merged)
+	assert.Equal(t, "James Strachan", prs[0].Author.Name)
+	assert.Equal(t, 2021, prs[0].Created.UTC().Year())
+}
+
+func TestListCommitPullRequestsGitea(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/version":
+			_, _ = w.Write([]byte(`{"version": "1.19.0"}`))
+		case "/api/v1/repos/jstrachan/foo/commits/abc123/pull":
+			_, _ = w.Write([]byte(`{"number": 3, "title": "fix: the cork", "state": "closed", "merged": true, "user": {"login": "jstrachan"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitea.New(server.URL)
+	require.NoError(t, err, "failed to create Gitea client")
+	prs, _, err := scmapi.ListCommitPullRequests(context.Background(), client, "jstrachan/foo", "abc123")
+	require.NoError(t, err, "failed to list the pull requests of the commit")
+	require.Len(t, prs, 1)
+	assert.Equal(t, 3, prs[0].Number)
+	assert.True(t, prs[0].Merged)
+
+	prs, _, err = scmapi.ListCommitPullRequests(context.Background(), client, "jstrachan/foo", "def456")
+	require.NoError(t, err, "a commit without a pull request is not an error")
+	assert.Empty(t, prs)
+}
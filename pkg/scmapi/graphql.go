@@ -0,0 +1,157 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// DefaultGraphQLBatchSize the default number of commits whose pull requests are queried in one GitHub GraphQL query
+const DefaultGraphQLBatchSize = 50
+
+// commitPullRequestsFragment the fields of the pull requests associated with a commit
+const commitPullRequestsFragment = `... on Commit {
+      associatedPullRequests(first: 5) {
+        nodes {
+          number
+          title
+          body
+          state
+          url
+          mergedAt
+          createdAt
+          author { login avatarUrl }
+          labels(first: 20) { nodes { name } }
+        }
+      }
+    }`
+
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+type githubGraphQLPullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"`
+	URL       string     `json:"url"`
+	MergedAt  *time.Time `json:"mergedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Author    *struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatarUrl"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+type githubGraphQLCommit struct {
+	AssociatedPullRequests struct {
+		Nodes []githubGraphQLPullRequest `json:"nodes"`
+	} `json:"associatedPullRequests"`
+}
+
+// ListCommitsPullRequests lists the pull requests which contain each of the commits using one GitHub GraphQL query
+// rather than a REST call per commit. The pull requests are indexed by the commit SHA. Commits unknown to GitHub have
+// no entry. Returns an error if the git provider is not GitHub
+func ListCommitsPullRequests(ctx context.Context, client *scm.Client, repo string, shas []string) (map[string][]*scm.PullRequest, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	if client.Driver != scm.DriverGithub {
+		return nil, nil, errors.Errorf("listing the pull requests of commits via GraphQL is not supported for git provider %s", client.Driver.String())
+	}
+	answer := map[string][]*scm.PullRequest{}
+	if len(shas) == 0 {
+		return answer, nil, nil
+	}
+	owner, name := scm.Split(repo)
+	params := []string{"$owner: String!", "$name: String!"}
+	fields := []string{}
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  name,
+	}
+	for i, sha := range shas {
+		alias := fmt.Sprintf("c%d", i)
+		params = append(params, fmt.Sprintf("$%s: GitObjectID!", alias))
+		fields = append(fields, fmt.Sprintf("%s: object(oid: $%s) {\n    %s\n  }", alias, alias, commitPullRequestsFragment))
+		variables[alias] = sha
+	}
+	query := fmt.Sprintf("query(%s) {\n  repository(owner: $owner, name: $name) {\n  %s\n  }\n}", strings.Join(params, ", "), strings.Join(fields, "\n  "))
+
+	result := struct {
+		Data struct {
+			Repository map[string]*githubGraphQLCommit `json:"repository"`
+		} `json:"data"`
+		Errors []graphqlError `json:"errors"`
+	}{}
+	path := "graphql"
+	if client.GraphQLURL != nil {
+		path = client.GraphQLURL.String()
+	}
+	res, err := DoJSON(ctx, client, http.MethodPost, path, &graphqlRequest{Query: query, Variables: variables}, &result)
+	if err != nil {
+		return nil, res, err
+	}
+	if result.Data.Repository == nil {
+		if len(result.Errors) > 0 {
+			return nil, res, errors.Errorf("GraphQL query of the pull requests of %d commits failed: %s", len(shas), result.Errors[0].Message)
+		}
+		return nil, res, errors.Errorf("no repository %s found", repo)
+	}
+	for i, sha := range shas {
+		commit := result.Data.Repository[fmt.Sprintf("c%d", i)]
+		if commit == nil {
+			continue
+		}
+		prs := []*scm.PullRequest{}
+		for j := range commit.AssociatedPullRequests.Nodes {
+			prs = append(prs, toGitHubGraphQLPullRequest(&commit.AssociatedPullRequests.Nodes[j]))
+		}
+		answer[sha] = prs
+	}
+	return answer, res, nil
+}
+
+func toGitHubGraphQLPullRequest(pr *githubGraphQLPullRequest) *scm.PullRequest {
+	// lets use the states of the REST API where merged pull requests are closed
+	state := strings.ToLower(pr.State)
+	if state == "merged" {
+		state = "closed"
+	}
+	p := &scm.PullRequest{
+		Number:  pr.Number,
+		Title:   pr.Title,
+		Body:    pr.Body,
+		State:   state,
+		Closed:  state != "open",
+		Merged:  pr.MergedAt != nil,
+		Link:    pr.URL,
+		Created: pr.CreatedAt,
+	}
+	if pr.Author != nil {
+		p.Author = scm.User{
+			Login:  pr.Author.Login,
+			Avatar: pr.Author.AvatarURL,
+		}
+	}
+	for _, l := range pr.Labels.Nodes {
+		p.Labels = append(p.Labels, &scm.Label{Name: l.Name})
+	}
+	return p
+}
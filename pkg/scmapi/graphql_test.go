@@ -0,0 +1,92 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCommitsPullRequests(t *testing.T) {
+	t.Parallel()
+	var variables map[string]interface{}
+	queries := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		queries++
+		req := struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || !strings.Contains(req.Query, "c1: object(oid: $c1)") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		variables = req.Variables
+		_, _ = w.Write([]byte(`{"data": {"repository": {
+  "c0": {"associatedPullRequests": {"nodes": [
+    {"number": 11, "title": "wip", "state": "CLOSED", "url": "https://github.com/jstrachan/foo/pull/11", "author": {"login": "bob"}, "labels": {"nodes": []}},
+    {"number": 12, "title": "feat: cheese", "state": "MERGED", "url": "https://github.com/jstrachan/foo/pull/12", "mergedAt": "2021-03-01T10:00:00Z", "author": {"login": "jstrachan", "avatarUrl": "https://avatars/jstrachan"}, "labels": {"nodes": [{"name": "enhancement"}]}}
+  ]}},
+  "c1": {"associatedPullRequests": {"nodes": []}},
+  "c2": null
+}}}`))
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	prs, _, err := scmapi.ListCommitsPullRequests(context.Background(), client, "jstrachan/foo", []string{"abc123", "def456", "unknown"})
+	require.NoError(t, err, "failed to list the pull requests of the commits")
+	assert.Equal(t, 1, queries, "should query all commits at once")
+	assert.Equal(t, map[string]interface{}{"owner": "jstrachan", "name": "foo", "c0": "abc123", "c1": "def456", "c2": "unknown"}, variables)
+
+	require.Len(t, prs["abc123"], 2)
+	pr := prs["abc123"][1]
+	assert.Equal(t, 12, pr.Number)
+	assert.Equal(t, "feat: cheese", pr.Title)
+	assert.Equal(t, "closed", pr.State)
+	assert.True(t, pr.Merged)
+	assert.Equal(t, "jstrachan", pr.Author.Login)
+	require.Len(t, pr.Labels, 1)
+	assert.Equal(t, "enhancement", pr.Labels[0].Name)
+	assert.False(t, prs["abc123"][0].Merged)
+
+	assert.Contains(t, prs, "def456")
+	assert.Empty(t, prs["def456"])
+	assert.NotContains(t, prs, "unknown", "should have no entry for commits unknown to GitHub")
+
+	glClient, err := gitlab.New(server.URL)
+	require.NoError(t, err)
+	_, _, err = scmapi.ListCommitsPullRequests(context.Background(), glClient, "jstrachan/foo", []string{"abc123"})
+	assert.Error(t, err, "should not support GitLab")
+}
+
+func TestListCommitsPullRequestsErrors(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"repository": null}, "errors": [{"message": "Could not resolve to a Repository"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	_, _, err = scmapi.ListCommitsPullRequests(context.Background(), client, "jstrachan/foo", []string{"abc123"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Could not resolve to a Repository")
+}
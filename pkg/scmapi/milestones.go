@@ -0,0 +1,180 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// Milestone a milestone of the git provider with the number of its open and closed issues which go-scm does not expose
+type Milestone struct {
+	// ID the ID of the milestone used by the GitLab API
+	ID int
+	// Number the number of the milestone used by the GitHub and Gitea APIs
+	Number int
+	// Title the title of the milestone
+	Title string
+	// State the state of the milestone such as 'open' or 'closed'
+	State string
+	// Link the link to the milestone
+	Link string
+	// OpenIssues the number of open issues of the milestone
+	OpenIssues int
+	// ClosedIssues the number of closed issues of the milestone
+	ClosedIssues int
+}
+
+// IsClosed returns true if the milestone is closed
+func (m *Milestone) IsClosed() bool {
+	return m.State == "closed"
+}
+
+type githubMilestone struct {
+	Number       int    `json:"number"`
+	Title        string `json:"title"`
+	State        string `json:"state"`
+	HTMLURL      string `json:"html_url"`
+	OpenIssues   int    `json:"open_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+}
+
+type gitlabMilestone struct {
+	ID     int    `json:"id"`
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	WebURL string `json:"web_url"`
+}
+
+type giteaMilestone struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	State        string `json:"state"`
+	OpenIssues   int    `json:"open_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+}
+
+// FindMilestone finds the open or closed milestone of the title including the number of its issues.
+// Returns scm.ErrNotFound if there is no such milestone. Supported on GitHub, GitLab and Gitea
+func FindMilestone(ctx context.Context, client *scm.Client, repo, title string) (*Milestone, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	switch client.Driver {
+	case scm.DriverGithub:
+		var milestones []githubMilestone
+		res, err := GetJSON(ctx, client, fmt.Sprintf("repos/%s/milestones?state=all&per_page=100", repo), &milestones)
+		if err != nil {
+			return nil, res, err
+		}
+		for _, m := range milestones {
+			if m.Title == title {
+				return &Milestone{
+					Number:       m.Number,
+					Title:        m.Title,
+					State:        m.State,
+					Link:         m.HTMLURL,
+					OpenIssues:   m.OpenIssues,
+					ClosedIssues: m.ClosedIssues,
+				}, res, nil
+			}
+		}
+		return nil, res, scm.ErrNotFound
+	case scm.DriverGitlab:
+		project := url.PathEscape(repo)
+		var milestones []gitlabMilestone
+		res, err := GetJSON(ctx, client, fmt.Sprintf("api/v4/projects/%s/milestones?title=%s", project, url.QueryEscape(title)), &milestones)
+		if err != nil {
+			return nil, res, err
+		}
+		if len(milestones) == 0 {
+			return nil, res, scm.ErrNotFound
+		}
+		m := milestones[0]
+		answer := &Milestone{
+			ID:     m.ID,
+			Number: m.IID,
+			Title:  m.Title,
+			State:  m.State,
+			Link:   m.WebURL,
+		}
+		if answer.State == "active" {
+			answer.State = "open"
+		}
+		// gitlab milestones do not include the number of their issues
+		stats := struct {
+			Statistics struct {
+				Counts struct {
+					Closed int `json:"closed"`
+					Opened int `json:"opened"`
+				} `json:"counts"`
+			} `json:"statistics"`
+		}{}
+		res, err = GetJSON(ctx, client, fmt.Sprintf("api/v4/projects/%s/issues_statistics?milestone=%s", project, url.QueryEscape(title)), &stats)
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to count the issues of milestone %s", title)
+		}
+		answer.OpenIssues = stats.Statistics.Counts.Opened
+		answer.ClosedIssues = stats.Statistics.Counts.Closed
+		return answer, res, nil
+	case scm.DriverGitea:
+		var milestones []giteaMilestone
+		res, err := GetJSON(ctx, client, fmt.Sprintf("api/v1/repos/%s/milestones?state=all&name=%s", repo, url.QueryEscape(title)), &milestones)
+		if err != nil {
+			return nil, res, err
+		}
+		for _, m := range milestones {
+			if m.Title == title {
+				return &Milestone{
+					ID:           m.ID,
+					Number:       m.ID,
+					Title:        m.Title,
+					State:        m.State,
+					Link:         fmt.Sprintf("%s/%s/milestone/%d", strings.TrimSuffix(serverURL(client), "/"), repo, m.ID),
+					OpenIssues:   m.OpenIssues,
+					ClosedIssues: m.ClosedIssues,
+				}, res, nil
+			}
+		}
+		return nil, res, scm.ErrNotFound
+	default:
+		return nil, nil, errors.Errorf("git provider %s does not support finding milestones", client.Driver.String())
+	}
+}
+
+// CloseMilestone closes the milestone. Supported on GitHub, GitLab and Gitea
+func CloseMilestone(ctx context.Context, client *scm.Client, repo string, m *Milestone) (*scm.Response, error) {
+	if client == nil {
+		return nil, errors.Errorf("no git provider client")
+	}
+	var res *scm.Response
+	var err error
+	switch client.Driver {
+	case scm.DriverGithub:
+		res, err = DoJSON(ctx, client, http.MethodPatch, fmt.Sprintf("repos/%s/milestones/%d", repo, m.Number), map[string]string{"state": "closed"}, nil)
+	case scm.DriverGitlab:
+		res, err = DoJSON(ctx, client, http.MethodPut, fmt.Sprintf("api/v4/projects/%s/milestones/%d", url.PathEscape(repo), m.ID), map[string]string{"state_event": "close"}, nil)
+	case scm.DriverGitea:
+		res, err = DoJSON(ctx, client, http.MethodPatch, fmt.Sprintf("api/v1/repos/%s/milestones/%d", repo, m.ID), map[string]string{"state": "closed"}, nil)
+	default:
+		return nil, errors.Errorf("git provider %s does not support closing milestones", client.Driver.String())
+	}
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to close milestone %s", m.Title)
+	}
+	return res, nil
+}
+
+// serverURL returns the URL of the git server of the client without the API path
+func serverURL(client *scm.Client) string {
+	u := *client.BaseURL
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.Path = strings.TrimSuffix(u.Path, "/api/v1")
+	u.RawQuery = ""
+	return u.String()
+}
@@ -0,0 +1,86 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/gitea"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAndCloseMilestone(t *testing.T) {
+	t.Parallel()
+	closed := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.EscapedPath()
+		switch key {
+		case "GET /api/v1/version":
+			_, _ = w.Write([]byte(`{"version": "1.17.0"}`))
+		case "GET /repos/jstrachan/foo/milestones":
+			_, _ = w.Write([]byte(`[
+  {"number": 1, "title": "1.1.0", "state": "closed", "open_issues": 0, "closed_issues": 4},
+  {"number": 2, "title": "1.2.0", "state": "open", "html_url": "https://github.com/jstrachan/foo/milestone/2", "open_issues": 1, "closed_issues": 7}
+]`))
+		case "GET /api/v4/projects/jstrachan%2Ffoo/milestones":
+			if r.URL.Query().Get("title") != "1.2.0" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"id": 42, "iid": 3, "title": "1.2.0", "state": "active", "web_url": "https://gitlab.com/jstrachan/foo/-/milestones/3"}]`))
+		case "GET /api/v4/projects/jstrachan%2Ffoo/issues_statistics":
+			_, _ = w.Write([]byte(`{"statistics": {"counts": {"all": 5, "closed": 3, "opened": 2}}}`))
+		case "GET /api/v1/repos/jstrachan/foo/milestones":
+			_, _ = w.Write([]byte(`[{"id": 9, "title": "1.2.0", "state": "open", "open_issues": 0, "closed_issues": 2}]`))
+		case "PATCH /repos/jstrachan/foo/milestones/2", "PUT /api/v4/projects/jstrachan%2Ffoo/milestones/42", "PATCH /api/v1/repos/jstrachan/foo/milestones/9":
+			data, _ := ioutil.ReadAll(r.Body)
+			closed[key] = string(data)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	m, _, err := scmapi.FindMilestone(ctx, client, "jstrachan/foo", "1.2.0")
+	require.NoError(t, err, "failed to find the GitHub milestone")
+	assert.Equal(t, &scmapi.Milestone{Number: 2, Title: "1.2.0", State: "open", Link: "https://github.com/jstrachan/foo/milestone/2", OpenIssues: 1, ClosedIssues: 7}, m)
+	_, err = scmapi.CloseMilestone(ctx, client, "jstrachan/foo", m)
+	require.NoError(t, err, "failed to close the GitHub milestone")
+	assert.JSONEq(t, `{"state": "closed"}`, closed["PATCH /repos/jstrachan/foo/milestones/2"])
+	_, _, err = scmapi.FindMilestone(ctx, client, "jstrachan/foo", "2.0.0")
+	assert.Equal(t, scm.ErrNotFound, err)
+
+	client, err = gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+	m, _, err = scmapi.FindMilestone(ctx, client, "jstrachan/foo", "1.2.0")
+	require.NoError(t, err, "failed to find the GitLab milestone")
+	assert.Equal(t, &scmapi.Milestone{ID: 42, Number: 3, Title: "1.2.0", State: "open", Link: "https://gitlab.com/jstrachan/foo/-/milestones/3", OpenIssues: 2, ClosedIssues: 3}, m)
+	_, err = scmapi.CloseMilestone(ctx, client, "jstrachan/foo", m)
+	require.NoError(t, err, "failed to close the GitLab milestone")
+	assert.JSONEq(t, `{"state_event": "close"}`, closed["PUT /api/v4/projects/jstrachan%2Ffoo/milestones/42"])
+	_, _, err = scmapi.FindMilestone(ctx, client, "jstrachan/foo", "2.0.0")
+	assert.Equal(t, scm.ErrNotFound, err)
+
+	client, err = gitea.New(server.URL)
+	require.NoError(t, err, "failed to create Gitea client")
+	m, _, err = scmapi.FindMilestone(ctx, client, "jstrachan/foo", "1.2.0")
+	require.NoError(t, err, "failed to find the Gitea milestone")
+	assert.Equal(t, server.URL+"/jstrachan/foo/milestone/9", m.Link)
+	assert.Equal(t, 2, m.ClosedIssues)
+	_, err = scmapi.CloseMilestone(ctx, client, "jstrachan/foo", m)
+	require.NoError(t, err, "failed to close the Gitea milestone")
+	assert.JSONEq(t, `{"state": "closed"}`, closed["PATCH /api/v1/repos/jstrachan/foo/milestones/9"])
+}
@@ -0,0 +1,58 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// GenerateReleaseNotes returns the release notes of the tag generated by the git provider from the pull requests or
+// commits since the previous tag. The previous tag and target commit are optional. Supported on GitHub and GitLab
+func GenerateReleaseNotes(ctx context.Context, client *scm.Client, repo, tag, previousTag, target string) (string, *scm.Response, error) {
+	if client == nil {
+		return "", nil, errors.Errorf("no git provider client")
+	}
+	switch client.Driver {
+	case scm.DriverGithub:
+		body := map[string]string{"tag_name": tag}
+		if previousTag != "" {
+			body["previous_tag_name"] = previousTag
+		}
+		if target != "" {
+			body["target_commitish"] = target
+		}
+		notes := struct {
+			Name string `json:"name"`
+			Body string `json:"body"`
+		}{}
+		res, err := DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("repos/%s/releases/generate-notes", repo), body, &notes)
+		if err != nil {
+			return "", res, errors.Wrapf(err, "failed to generate the release notes of tag %s", tag)
+		}
+		return notes.Body, res, nil
+	case scm.DriverGitlab:
+		// the changelog API uses the version without the tag prefix as the heading of the notes
+		query := url.Values{"version": []string{strings.TrimPrefix(tag, "v")}}
+		if previousTag != "" {
+			query.Set("from", previousTag)
+		}
+		if target != "" {
+			query.Set("to", target)
+		}
+		notes := struct {
+			Notes string `json:"notes"`
+		}{}
+		res, err := GetJSON(ctx, client, fmt.Sprintf("api/v4/projects/%s/repository/changelog?%s", url.PathEscape(repo), query.Encode()), &notes)
+		if err != nil {
+			return "", res, errors.Wrapf(err, "failed to generate the release notes of tag %s", tag)
+		}
+		return notes.Notes, res, nil
+	default:
+		return "", nil, errors.Errorf("git provider %s does not support generating release notes", client.Driver.String())
+	}
+}
@@ -0,0 +1,48 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateReleaseNotes(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "POST /repos/jstrachan/foo/releases/generate-notes":
+			body := map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"name": "` + body["tag_name"] + `", "body": "## What's Changed\n* fix: the cork since ` + body["previous_tag_name"] + `"}`))
+		case "GET /api/v4/projects/jstrachan%2Ffoo/repository/changelog":
+			q := r.URL.Query()
+			_, _ = w.Write([]byte(`{"notes": "## ` + q.Get("version") + `\n\n- fix: the cork since ` + q.Get("from") + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	notes, _, err := scmapi.GenerateReleaseNotes(ctx, client, "jstrachan/foo", "v1.1.0", "v1.0.0", "")
+	require.NoError(t, err, "failed to generate GitHub release notes")
+	assert.Equal(t, "## What's Changed\n* fix: the cork since v1.0.0", notes)
+
+	client, err = gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+	notes, _, err = scmapi.GenerateReleaseNotes(ctx, client, "jstrachan/foo", "v1.1.0", "v1.0.0", "abc123")
+	require.NoError(t, err, "failed to generate GitLab release notes")
+	assert.Equal(t, "## 1.1.0\n\n- fix: the cork since v1.0.0", notes)
+}
@@ -0,0 +1,101 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+type githubPullRequestCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string    `json:"name"`
+			Email string    `json:"email"`
+			Date  time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"author"`
+}
+
+type gitlabMergeRequestCommit struct {
+	ID          string    `json:"id"`
+	Message     string    `json:"message"`
+	AuthorName  string    `json:"author_name"`
+	AuthorEmail string    `json:"author_email"`
+	AuthoredAt  time.Time `json:"authored_date"`
+	WebURL      string    `json:"web_url"`
+}
+
+// ListPullRequestCommits lists the commits of the pull request as go-scm does not yet expose them.
+// Returns an error if the git provider is not supported
+func ListPullRequestCommits(ctx context.Context, client *scm.Client, repo string, number int) ([]*scm.Commit, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	switch client.Driver {
+	case scm.DriverGithub, scm.DriverGitea:
+		// Gitea returns the commits in the same format as GitHub
+		path := fmt.Sprintf("repos/%s/pulls/%d/commits?per_page=100", repo, number)
+		if client.Driver == scm.DriverGitea {
+			path = "api/v1/" + path
+		}
+		var commits []githubPullRequestCommit
+		res, err := GetJSON(ctx, client, path, &commits)
+		if err != nil {
+			return nil, res, err
+		}
+		var answer []*scm.Commit
+		for i := range commits {
+			c := &commits[i]
+			commit := &scm.Commit{
+				Sha:     c.SHA,
+				Message: c.Commit.Message,
+				Link:    c.HTMLURL,
+				Author: scm.Signature{
+					Name:  c.Commit.Author.Name,
+					Email: c.Commit.Author.Email,
+					Date:  c.Commit.Author.Date,
+				},
+			}
+			if c.Author != nil {
+				commit.Author.Login = c.Author.Login
+				commit.Author.Avatar = c.Author.AvatarURL
+			}
+			answer = append(answer, commit)
+		}
+		return answer, res, nil
+	case scm.DriverGitlab:
+		var commits []gitlabMergeRequestCommit
+		res, err := GetJSON(ctx, client, fmt.Sprintf("api/v4/projects/%s/merge_requests/%d/commits?per_page=100", url.PathEscape(repo), number), &commits)
+		if err != nil {
+			return nil, res, err
+		}
+		var answer []*scm.Commit
+		for i := range commits {
+			c := &commits[i]
+			answer = append(answer, &scm.Commit{
+				Sha:     c.ID,
+				Message: c.Message,
+				Link:    c.WebURL,
+				Author: scm.Signature{
+					Name:  c.AuthorName,
+					Email: c.AuthorEmail,
+					Date:  c.AuthoredAt,
+				},
+			})
+		}
+		return answer, res, nil
+	default:
+		return nil, nil, errors.Errorf("listing the commits of pull requests is not supported for git provider %s", client.Driver.String())
+	}
+}
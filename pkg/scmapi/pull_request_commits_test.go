@@ -0,0 +1,55 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPullRequestCommits(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/jstrachan/foo/pulls/12/commits" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+  {
+    "sha": "abc123",
+    "html_url": "https://github.com/jstrachan/foo/commit/abc123",
+    "commit": {"message": "feat: cheese", "author": {"name": "James Strachan", "email": "james@example.com"}},
+    "author": {"login": "jstrachan", "avatar_url": "https://avatars.example.com/jstrachan"}
+  },
+  {
+    "sha": "def456",
+    "commit": {"message": "fix: wine", "author": {"name": "Someone", "email": "someone@example.com"}},
+    "author": null
+  }
+]`))
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	commits, _, err := scmapi.ListPullRequestCommits(context.Background(), client, "jstrachan/foo", 12)
+	require.NoError(t, err, "failed to list pull request commits")
+	require.Len(t, commits, 2)
+	assert.Equal(t, "abc123", commits[0].Sha)
+	assert.Equal(t, "feat: cheese", commits[0].Message)
+	assert.Equal(t, "jstrachan", commits[0].Author.Login)
+	assert.Equal(t, "james@example.com", commits[0].Author.Email)
+	assert.Equal(t, "", commits[1].Author.Login)
+	assert.Equal(t, "Someone", commits[1].Author.Name)
+
+	_, _, err = scmapi.ListPullRequestCommits(context.Background(), client, "jstrachan/foo", 13)
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package scmapi
+
+import (
+	"context"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// pullRequestsPageSize the number of open pull requests listed per page when looking for an existing pull request
+const pullRequestsPageSize = 100
+
+// FindOpenPullRequest finds the open pull request from the head branch into the base branch.
+// Returns scm.ErrNotFound if there is no such pull request
+func FindOpenPullRequest(ctx context.Context, client *scm.Client, repo, head, base string) (*scm.PullRequest, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	var res *scm.Response
+	for page := 1; ; page++ {
+		var prs []*scm.PullRequest
+		var err error
+		prs, res, err = client.PullRequests.List(ctx, repo, scm.PullRequestListOptions{Page: page, Size: pullRequestsPageSize, Open: true})
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to list the open pull requests of %s", repo)
+		}
+		for _, pr := range prs {
+			if pr.Head.Ref == head && pr.Base.Ref == base && !pr.Closed && !pr.Merged {
+				return pr, res, nil
+			}
+		}
+		if len(prs) < pullRequestsPageSize {
+			return nil, res, scm.ErrNotFound
+		}
+	}
+}
+
+// CreatePullRequestOnce creates the pull request unless there is already an open pull request from its head branch
+// into its base branch so that re-running a release reuses the existing pull request.
+// Returns false if the pull request already exists
+func CreatePullRequestOnce(ctx context.Context, client *scm.Client, repo string, input *scm.PullRequestInput) (*scm.PullRequest, bool, *scm.Response, error) {
+	pr, res, err := FindOpenPullRequest(ctx, client, repo, input.Head, input.Base)
+	if err == nil {
+		return pr, false, res, nil
+	}
+	if err != scm.ErrNotFound {
+		return nil, false, res, err
+	}
+	pr, res, err = client.PullRequests.Create(ctx, repo, input)
+	if err != nil {
+		return nil, false, res, errors.Wrapf(err, "failed to create the pull request from %s into %s", input.Head, input.Base)
+	}
+	return pr, true, res, nil
+}
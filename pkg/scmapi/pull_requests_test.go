@@ -0,0 +1,35 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePullRequestOnce(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client, data := scmfake.NewDefault()
+	input := &scm.PullRequestInput{Title: "chore: changelog of 1.2.3", Head: "changelog-1.2.3", Base: "main"}
+
+	pr, created, _, err := scmapi.CreatePullRequestOnce(ctx, client, "jstrachan/foo", input)
+	require.NoError(t, err, "failed to create the pull request")
+	assert.True(t, created, "should have created the pull request")
+	assert.Len(t, data.PullRequestsCreated, 1)
+
+	existing, created, _, err := scmapi.CreatePullRequestOnce(ctx, client, "jstrachan/foo", input)
+	require.NoError(t, err, "failed to find the pull request")
+	assert.False(t, created, "should have reused the pull request")
+	assert.Equal(t, pr.Number, existing.Number)
+	assert.Len(t, data.PullRequestsCreated, 1)
+
+	_, _, err = scmapi.FindOpenPullRequest(ctx, client, "jstrachan/foo", "changelog-1.2.3", "release-1.x")
+	assert.Equal(t, scm.ErrNotFound, err)
+}
@@ -0,0 +1,359 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/pkg/errors"
+)
+
+// ReleaseAssetLink a link to a release asset such as a binary or package hosted outside of the git provider
+type ReleaseAssetLink struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	LinkType string `json:"link_type,omitempty"`
+}
+
+// ReleaseOptions the details of a release which are not yet exposed via go-scm
+type ReleaseOptions struct {
+	AssetLinks []ReleaseAssetLink
+	Milestones []string
+	// Assets the files to upload to the release
+	Assets []*ReleaseAsset
+}
+
+type gitlabReleaseLink struct {
+	ID int `json:"id"`
+	ReleaseAssetLink
+}
+
+type gitlabRelease struct {
+	Name        string `json:"name"`
+	TagName     string `json:"tag_name"`
+	Description string `json:"description"`
+	Assets      struct {
+		Links []gitlabReleaseLink `json:"links"`
+	} `json:"assets"`
+	Links struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+type gitlabReleaseAssets struct {
+	Links []ReleaseAssetLink `json:"links"`
+}
+
+type gitlabReleaseInput struct {
+	Name        string               `json:"name"`
+	TagName     string               `json:"tag_name,omitempty"`
+	Description string               `json:"description"`
+	Milestones  []string             `json:"milestones,omitempty"`
+	Assets      *gitlabReleaseAssets `json:"assets,omitempty"`
+}
+
+// PublishGitLabRelease creates or updates the GitLab release of the tag using the releases API so that asset links
+// and milestones can be associated with the release which go-scm does not yet support
+func PublishGitLabRelease(ctx context.Context, client *scm.Client, repo string, input *scm.ReleaseInput, opts *ReleaseOptions) (*scm.Release, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	if client.Driver != scm.DriverGitlab {
+		return nil, nil, errors.Errorf("git provider %s is not GitLab", client.Driver.String())
+	}
+	if opts == nil {
+		opts = &ReleaseOptions{}
+	}
+	releasesPath := fmt.Sprintf("api/v4/projects/%s/releases", url.PathEscape(repo))
+	tagPath := releasesPath + "/" + url.PathEscape(input.Tag)
+	body := &gitlabReleaseInput{
+		Name:        input.Title,
+		Description: input.Description,
+		Milestones:  opts.Milestones,
+	}
+
+	existing := &gitlabRelease{}
+	res, err := GetJSON(ctx, client, tagPath, existing)
+	if err == scm.ErrNotFound {
+		body.TagName = input.Tag
+		if len(opts.AssetLinks) > 0 {
+			body.Assets = &gitlabReleaseAssets{Links: opts.AssetLinks}
+		}
+		created := &gitlabRelease{}
+		res, err = DoJSON(ctx, client, http.MethodPost, releasesPath, body, created)
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to create the release for tag %s", input.Tag)
+		}
+		return toRelease(created, input), res, nil
+	}
+	if err != nil {
+		return nil, res, errors.Wrapf(err, "failed to find the release for tag %s", input.Tag)
+	}
+
+	updated := &gitlabRelease{}
+	res, err = DoJSON(ctx, client, http.MethodPut, tagPath, body, updated)
+	if err != nil {
+		return nil, res, errors.Wrapf(err, "failed to update the release for tag %s", input.Tag)
+	}
+
+	// the update API does not change the asset links so lets add or replace them individually
+	links := map[string]gitlabReleaseLink{}
+	for _, l := range existing.Assets.Links {
+		links[l.Name] = l
+	}
+	for _, l := range opts.AssetLinks {
+		current, ok := links[l.Name]
+		switch {
+		case !ok:
+			res, err = DoJSON(ctx, client, http.MethodPost, tagPath+"/assets/links", l, nil)
+		case current.URL != l.URL || (l.LinkType != "" && current.LinkType != l.LinkType):
+			res, err = DoJSON(ctx, client, http.MethodPut, fmt.Sprintf("%s/assets/links/%d", tagPath, current.ID), l, nil)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to add asset link %s to the release for tag %s", l.Name, input.Tag)
+		}
+	}
+	return toRelease(updated, input), res, nil
+}
+
+func toRelease(r *gitlabRelease, input *scm.ReleaseInput) *scm.Release {
+	tag := r.TagName
+	if tag == "" {
+		tag = input.Tag
+	}
+	return &scm.Release{
+		Title:       r.Name,
+		Description: r.Description,
+		Tag:         tag,
+		Link:        r.Links.Self,
+		Draft:       input.Draft,
+		Prerelease:  input.Prerelease,
+	}
+}
+
+// maxReleasePages the maximum number of pages of releases searched for a draft release
+const maxReleasePages = 10
+
+type giteaRelease struct {
+	ID         int       `json:"id"`
+	TagName    string    `json:"tag_name"`
+	Target     string    `json:"target_commitish"`
+	Name       string    `json:"name"`
+	Body       string    `json:"body"`
+	HTMLURL    string    `json:"html_url"`
+	Draft      bool      `json:"draft"`
+	Prerelease bool      `json:"prerelease"`
+	Created    time.Time `json:"created_at"`
+	Published  time.Time `json:"published_at"`
+}
+
+// MakeLatestValues the supported values of the GitHub make_latest release setting
+var MakeLatestValues = []string{"true", "false", "legacy"}
+
+// FindReleaseByTag finds the release of the tag including draft releases returning scm.ErrNotFound if there is none.
+// On Gitea the releases API is used directly as the go-scm driver fails if the release does not exist
+func FindReleaseByTag(ctx context.Context, client *scm.Client, repo, tag string) (*scm.Release, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	switch client.Driver {
+	case scm.DriverGitea:
+		r := &giteaRelease{}
+		res, err := GetJSON(ctx, client, fmt.Sprintf("api/v1/repos/%s/releases/tags/%s", repo, url.PathEscape(tag)), r)
+		if err != nil {
+			return nil, res, err
+		}
+		return r.toRelease(), res, nil
+	default:
+		if client.Releases == nil {
+			return nil, nil, errors.Errorf("git provider %s does not support releases", client.Driver.String())
+		}
+		rel, res, err := client.Releases.FindByTag(ctx, repo, tag)
+		if err == nil || !scmhelpers.IsScmNotFound(err) {
+			return rel, res, err
+		}
+		// draft releases are not found by their tag on GitHub so lets look for them in the list of releases
+		return findDraftRelease(ctx, client, repo, tag)
+	}
+}
+
+// findDraftRelease finds the draft release of the tag in the list of releases returning scm.ErrNotFound if there is none
+func findDraftRelease(ctx context.Context, client *scm.Client, repo, tag string) (*scm.Release, *scm.Response, error) {
+	opts := scm.ReleaseListOptions{Page: 1, Size: 100}
+	for ; opts.Page <= maxReleasePages; opts.Page++ {
+		releases, res, err := client.Releases.List(ctx, repo, opts)
+		if scmhelpers.IsScmNotFound(err) {
+			return nil, res, scm.ErrNotFound
+		}
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to list the releases of %s", repo)
+		}
+		for _, r := range releases {
+			if r.Draft && r.Tag == tag {
+				return r, res, nil
+			}
+		}
+		if len(releases) < opts.Size {
+			break
+		}
+	}
+	return nil, nil, scm.ErrNotFound
+}
+
+type giteaReleaseInput struct {
+	TagName    string `json:"tag_name"`
+	Target     string `json:"target_commitish,omitempty"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+// PublishGiteaRelease creates or updates the Gitea release of the tag using the releases API directly as the go-scm
+// driver fails on any error response
+func PublishGiteaRelease(ctx context.Context, client *scm.Client, repo string, input *scm.ReleaseInput) (*scm.Release, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	if client.Driver != scm.DriverGitea {
+		return nil, nil, errors.Errorf("git provider %s is not Gitea", client.Driver.String())
+	}
+	body := &giteaReleaseInput{
+		TagName:    input.Tag,
+		Target:     input.Commitish,
+		Name:       input.Title,
+		Body:       input.Description,
+		Draft:      input.Draft,
+		Prerelease: input.Prerelease,
+	}
+	rel, res, err := FindReleaseByTag(ctx, client, repo, input.Tag)
+	if err == scm.ErrNotFound {
+		r := &giteaRelease{}
+		res, err = DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("api/v1/repos/%s/releases", repo), body, r)
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to create the release for tag %s", input.Tag)
+		}
+		return r.toRelease(), res, nil
+	}
+	if err != nil {
+		return nil, res, errors.Wrapf(err, "failed to find the release for tag %s", input.Tag)
+	}
+	r := &giteaRelease{}
+	res, err = DoJSON(ctx, client, http.MethodPatch, fmt.Sprintf("api/v1/repos/%s/releases/%d", repo, rel.ID), body, r)
+	if err != nil {
+		return nil, res, errors.Wrapf(err, "failed to update the release %d for tag %s", rel.ID, input.Tag)
+	}
+	return r.toRelease(), res, nil
+}
+
+func (r *giteaRelease) toRelease() *scm.Release {
+	return &scm.Release{
+		ID:          r.ID,
+		Title:       r.Name,
+		Description: r.Body,
+		Link:        r.HTMLURL,
+		Tag:         r.TagName,
+		Commitish:   r.Target,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		Created:     r.Created,
+		Published:   r.Published,
+	}
+}
+
+// DeleteRelease deletes the release. On Gitea the releases API is used directly as the go-scm driver fails on any
+// error response and on GitLab the release is deleted by its tag as GitLab releases have no ID
+func DeleteRelease(ctx context.Context, client *scm.Client, repo string, rel *scm.Release) (*scm.Response, error) {
+	if client == nil {
+		return nil, errors.Errorf("no git provider client")
+	}
+	switch client.Driver {
+	case scm.DriverGitea:
+		return DoJSON(ctx, client, http.MethodDelete, fmt.Sprintf("api/v1/repos/%s/releases/%d", repo, rel.ID), nil, nil)
+	case scm.DriverGitlab:
+		return client.Releases.DeleteByTag(ctx, repo, rel.Tag)
+	default:
+		if client.Releases == nil {
+			return nil, errors.Errorf("git provider %s does not support releases", client.Driver.String())
+		}
+		return client.Releases.Delete(ctx, repo, rel.ID)
+	}
+}
+
+type githubRelease struct {
+	ID         int       `json:"id"`
+	TagName    string    `json:"tag_name"`
+	Target     string    `json:"target_commitish"`
+	Name       string    `json:"name"`
+	Body       string    `json:"body"`
+	HTMLURL    string    `json:"html_url"`
+	Draft      bool      `json:"draft"`
+	Prerelease bool      `json:"prerelease"`
+	Created    time.Time `json:"created_at"`
+	Published  time.Time `json:"published_at"`
+}
+
+type githubReleaseInput struct {
+	TagName    string `json:"tag_name"`
+	Target     string `json:"target_commitish,omitempty"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	MakeLatest string `json:"make_latest,omitempty"`
+}
+
+// PublishGitHubRelease creates the GitHub release or updates the existing release using the releases API directly so
+// that whether the release becomes the latest release of the repository can be specified which go-scm does not yet
+// support. The makeLatest value is 'true', 'false' or 'legacy' which uses the creation date and semantic version
+func PublishGitHubRelease(ctx context.Context, client *scm.Client, repo string, existing *scm.Release, input *scm.ReleaseInput, makeLatest string) (*scm.Release, *scm.Response, error) {
+	if client == nil {
+		return nil, nil, errors.Errorf("no git provider client")
+	}
+	if client.Driver != scm.DriverGithub {
+		return nil, nil, errors.Errorf("git provider %s is not GitHub", client.Driver.String())
+	}
+	body := &githubReleaseInput{
+		TagName:    input.Tag,
+		Target:     input.Commitish,
+		Name:       input.Title,
+		Body:       input.Description,
+		Draft:      input.Draft,
+		Prerelease: input.Prerelease,
+		MakeLatest: makeLatest,
+	}
+	r := &githubRelease{}
+	if existing == nil {
+		res, err := DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("repos/%s/releases", repo), body, r)
+		if err != nil {
+			return nil, res, errors.Wrapf(err, "failed to create the release for tag %s", input.Tag)
+		}
+		return r.toRelease(), res, nil
+	}
+	res, err := DoJSON(ctx, client, http.MethodPatch, fmt.Sprintf("repos/%s/releases/%d", repo, existing.ID), body, r)
+	if err != nil {
+		return nil, res, errors.Wrapf(err, "failed to update the release %d for tag %s", existing.ID, input.Tag)
+	}
+	return r.toRelease(), res, nil
+}
+
+func (r *githubRelease) toRelease() *scm.Release {
+	return &scm.Release{
+		ID:          r.ID,
+		Title:       r.Name,
+		Description: r.Body,
+		Link:        r.HTMLURL,
+		Tag:         r.TagName,
+		Commitish:   r.Target,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		Created:     r.Created,
+		Published:   r.Published,
+	}
+}
@@ -0,0 +1,155 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/gitea"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishGitLabRelease(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.EscapedPath()
+		requests = append(requests, key)
+		if r.Body != nil {
+			body := map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			bodies[key] = body
+		}
+		switch key {
+		case "GET /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0":
+			_, _ = w.Write([]byte(`{"tag_name": "v1.0.0", "assets": {"links": [{"id": 3, "name": "linux", "url": "https://example.com/old"}]}}`))
+			return
+		case "GET /api/v4/projects/jstrachan%2Ffoo/releases/v1.1.0":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case "POST /api/v4/projects/jstrachan%2Ffoo/releases",
+			"PUT /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0",
+			"PUT /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0/assets/links/3",
+			"POST /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0/assets/links":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		tag := "v1.0.0"
+		if bodies[key]["tag_name"] != nil {
+			tag = bodies[key]["tag_name"].(string)
+		}
+		_, _ = w.Write([]byte(`{"name": "` + tag + `", "tag_name": "` + tag + `", "_links": {"self": "https://gitlab.com/jstrachan/foo/-/releases/` + tag + `"}}`))
+	}))
+	defer server.Close()
+
+	client, err := gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+	opts := &scmapi.ReleaseOptions{
+		AssetLinks: []scmapi.ReleaseAssetLink{
+			{Name: "linux", URL: "https://example.com/linux", LinkType: "package"},
+			{Name: "docs", URL: "https://example.com/docs"},
+		},
+		Milestones: []string{"1.1"},
+	}
+
+	rel, _, err := scmapi.PublishGitLabRelease(context.Background(), client, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.1.0", Tag: "v1.1.0", Description: "cheese"}, opts)
+	require.NoError(t, err, "failed to create the release")
+	assert.Equal(t, "https://gitlab.com/jstrachan/foo/-/releases/v1.1.0", rel.Link)
+	created := bodies["POST /api/v4/projects/jstrachan%2Ffoo/releases"]
+	assert.Equal(t, "v1.1.0", created["tag_name"])
+	assert.Equal(t, []interface{}{"1.1"}, created["milestones"])
+	assert.Len(t, created["assets"].(map[string]interface{})["links"], 2)
+
+	requests = nil
+	_, _, err = scmapi.PublishGitLabRelease(context.Background(), client, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.0.0", Tag: "v1.0.0", Description: "wine"}, opts)
+	require.NoError(t, err, "failed to update the release")
+	assert.Equal(t, []string{
+		"GET /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0",
+		"PUT /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0",
+		"PUT /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0/assets/links/3",
+		"POST /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0/assets/links",
+	}, requests)
+	assert.Equal(t, "wine", bodies["PUT /api/v4/projects/jstrachan%2Ffoo/releases/v1.0.0"]["description"])
+}
+
+func TestPublishGiteaRelease(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.EscapedPath()
+		requests = append(requests, key)
+		switch key {
+		case "GET /gitea/api/v1/version":
+			_, _ = w.Write([]byte(`{"version": "1.15.0"}`))
+		case "GET /gitea/api/v1/repos/jstrachan/foo/releases/tags/v1.0.0":
+			_, _ = w.Write([]byte(`{"id": 5, "tag_name": "v1.0.0"}`))
+		case "PATCH /gitea/api/v1/repos/jstrachan/foo/releases/5", "POST /gitea/api/v1/repos/jstrachan/foo/releases":
+			input := map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			_, _ = w.Write([]byte(`{"id": 5, "tag_name": "` + input["tag_name"].(string) + `", "body": "` + input["body"].(string) + `", "html_url": "https://example.com/gitea/jstrachan/foo/releases/tag/` + input["tag_name"].(string) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitea.New(server.URL + "/gitea")
+	require.NoError(t, err, "failed to create Gitea client")
+
+	rel, _, err := scmapi.PublishGiteaRelease(context.Background(), client, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.1.0", Tag: "v1.1.0", Description: "cheese"})
+	require.NoError(t, err, "failed to create the release")
+	assert.Equal(t, "https://example.com/gitea/jstrachan/foo/releases/tag/v1.1.0", rel.Link)
+	assert.Equal(t, "cheese", rel.Description)
+	assert.Contains(t, requests, "POST /gitea/api/v1/repos/jstrachan/foo/releases")
+
+	rel, _, err = scmapi.PublishGiteaRelease(context.Background(), client, "jstrachan/foo", &scm.ReleaseInput{Title: "v1.0.0", Tag: "v1.0.0", Description: "wine"})
+	require.NoError(t, err, "failed to update the release")
+	assert.Equal(t, 5, rel.ID)
+	assert.Equal(t, "wine", rel.Description)
+	assert.Contains(t, requests, "PATCH /gitea/api/v1/repos/jstrachan/foo/releases/5")
+}
+
+func TestPublishGitHubRelease(t *testing.T) {
+	t.Parallel()
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		switch key {
+		case "POST /repos/jstrachan/foo/releases", "PATCH /repos/jstrachan/foo/releases/7":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies[key] = body
+		_, _ = w.Write([]byte(`{"id": 7, "tag_name": "` + body["tag_name"].(string) + `", "body": "` + body["body"].(string) + `", "html_url": "https://github.com/jstrachan/foo/releases/tag/v1.0.1"}`))
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	input := &scm.ReleaseInput{Title: "v1.0.1", Tag: "v1.0.1", Description: "cheese"}
+	rel, _, err := scmapi.PublishGitHubRelease(context.Background(), client, "jstrachan/foo", nil, input, "false")
+	require.NoError(t, err, "failed to create the release")
+	assert.Equal(t, 7, rel.ID)
+	assert.Equal(t, "https://github.com/jstrachan/foo/releases/tag/v1.0.1", rel.Link)
+	assert.Equal(t, "false", bodies["POST /repos/jstrachan/foo/releases"]["make_latest"])
+
+	input.Description = "wine"
+	rel, _, err = scmapi.PublishGitHubRelease(context.Background(), client, "jstrachan/foo", rel, input, "legacy")
+	require.NoError(t, err, "failed to update the release")
+	assert.Equal(t, "wine", rel.Description)
+	assert.Equal(t, "legacy", bodies["PATCH /repos/jstrachan/foo/releases/7"]["make_latest"])
+}
@@ -0,0 +1,63 @@
+package scmapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// GetJSON invokes a GET on the given REST path of the git provider and unmarshals the JSON response into the result.
+// This lets us use provider APIs which are not yet exposed via go-scm
+func GetJSON(ctx context.Context, client *scm.Client, path string, result interface{}) (*scm.Response, error) {
+	return DoJSON(ctx, client, http.MethodGet, path, nil, result)
+}
+
+// DoJSON invokes the given REST method and path on the git provider marshalling the body to JSON
+// and unmarshalling the JSON response into the result if it is not nil
+func DoJSON(ctx context.Context, client *scm.Client, method, path string, body, result interface{}) (*scm.Response, error) {
+	if client == nil {
+		return nil, errors.Errorf("no git provider client")
+	}
+	req := &scm.Request{
+		Method: method,
+		Path:   path,
+		Header: http.Header{
+			"Accept": []string{"application/json"},
+		},
+	}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal request body for %s %s", method, path)
+		}
+		req.Body = bytes.NewReader(data)
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := client.Do(ctx, req)
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to invoke %s %s", method, path)
+	}
+	defer res.Body.Close()
+
+	if res.Status == http.StatusNotFound {
+		return res, scm.ErrNotFound
+	}
+	if res.Status >= 300 {
+		data, _ := ioutil.ReadAll(io.LimitReader(res.Body, 4096))
+		return res, errors.Errorf("%s %s returned status %d: %s", method, path, res.Status, string(data))
+	}
+	if result == nil {
+		return res, nil
+	}
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil && err != io.EOF {
+		return res, errors.Wrapf(err, "failed to parse JSON response of %s %s", method, path)
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package scmapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+// CreateTag creates the annotated tag of the commit with the message via the git provider API which, unlike pushing
+// the tag, does not require push access to the repository. Supported on GitHub, GitLab and Gitea
+func CreateTag(ctx context.Context, client *scm.Client, repo, tag, sha, message string) (*scm.Response, error) {
+	if client == nil {
+		return nil, errors.Errorf("no git provider client")
+	}
+	var res *scm.Response
+	var err error
+	switch client.Driver {
+	case scm.DriverGithub:
+		// the tag object has to be referenced by the tag ref for it to be visible
+		object := struct {
+			SHA string `json:"sha"`
+		}{}
+		body := map[string]string{
+			"tag":     tag,
+			"message": message,
+			"object":  sha,
+			"type":    "commit",
+		}
+		res, err = DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("repos/%s/git/tags", repo), body, &object)
+		if err == nil {
+			ref := map[string]string{
+				"ref": "refs/tags/" + tag,
+				"sha": object.SHA,
+			}
+			res, err = DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("repos/%s/git/refs", repo), ref, nil)
+		}
+	case scm.DriverGitlab:
+		body := map[string]string{
+			"tag_name": tag,
+			"ref":      sha,
+			"message":  message,
+		}
+		res, err = DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("api/v4/projects/%s/repository/tags", url.PathEscape(repo)), body, nil)
+	case scm.DriverGitea:
+		body := map[string]string{
+			"tag_name": tag,
+			"target":   sha,
+			"message":  message,
+		}
+		res, err = DoJSON(ctx, client, http.MethodPost, fmt.Sprintf("api/v1/repos/%s/tags", repo), body, nil)
+	default:
+		return nil, errors.Errorf("git provider %s does not support creating tags", client.Driver.String())
+	}
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to create tag %s of commit %s", tag, sha)
+	}
+	return res, nil
+}
@@ -0,0 +1,44 @@
+// +build unit
+
+package scmapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTag(t *testing.T) {
+	t.Parallel()
+	bodies := map[string]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		body := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies[key] = body
+		switch key {
+		case "POST /repos/jstrachan/foo/git/tags":
+			_, _ = w.Write([]byte(`{"sha": "def456"}`))
+		case "POST /repos/jstrachan/foo/git/refs":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+	_, err = scmapi.CreateTag(context.Background(), client, "jstrachan/foo", "v1.2.0", "abc123", "the changelog")
+	require.NoError(t, err, "failed to create the tag")
+	assert.Equal(t, map[string]string{"tag": "v1.2.0", "message": "the changelog", "object": "abc123", "type": "commit"}, bodies["POST /repos/jstrachan/foo/git/tags"])
+	assert.Equal(t, map[string]string{"ref": "refs/tags/v1.2.0", "sha": "def456"}, bodies["POST /repos/jstrachan/foo/git/refs"])
+}
@@ -0,0 +1,78 @@
+package users
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+)
+
+// AliasesFileName the default name of the alias file relative to the root of the repository
+var AliasesFileName = filepath.Join(".jx", "changelog-aliases.yaml")
+
+// Aliases maps git emails and names to the logins of git provider accounts
+// for contributors whose git identities do not match their accounts
+type Aliases struct {
+	Aliases []Alias `json:"aliases,omitempty"`
+}
+
+// Alias the emails and names of a single git provider account
+type Alias struct {
+	// Login the login of the git provider account
+	Login string `json:"login"`
+	// Emails the git emails used by the account
+	Emails []string `json:"emails,omitempty"`
+	// Names the git names used by the account
+	Names []string `json:"names,omitempty"`
+}
+
+// LoadAliases loads the aliases from the given file if it exists
+func LoadAliases(path string) (*Aliases, error) {
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load file %s", path)
+	}
+	answer := &Aliases{}
+	err = yaml.Unmarshal(data, answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal YAML file %s", path)
+	}
+	return answer, nil
+}
+
+// Login returns the login for the given git name and email or an empty string if there is no alias.
+// Emails take precedence over names
+func (a *Aliases) Login(name, email string) string {
+	if a == nil {
+		return ""
+	}
+	if email != "" {
+		for _, alias := range a.Aliases {
+			for _, e := range alias.Emails {
+				if strings.EqualFold(e, email) {
+					return alias.Login
+				}
+			}
+		}
+	}
+	if name != "" {
+		for _, alias := range a.Aliases {
+			for _, n := range alias.Names {
+				if strings.EqualFold(n, name) {
+					return alias.Login
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+
+	path := filepath.Join(tmpDir, "aliases.yaml")
+	err = ioutil.WriteFile(path, []byte(`aliases:
+- login: jstrachan
+  emails:
+  - james@work.example.com
+  names:
+  - JS
+`), 0600)
+	require.NoError(t, err, "failed to save %s", path)
+
+	aliases, err := users.LoadAliases(path)
+	require.NoError(t, err, "failed to load %s", path)
+
+	assert.Equal(t, "jstrachan", aliases.Login("James", "JAMES@work.example.com"))
+	assert.Equal(t, "jstrachan", aliases.Login("js", "other@example.com"))
+	assert.Equal(t, "", aliases.Login("Someone", "someone@example.com"))
+
+	r := &users.GitUserResolver{Aliases: aliases, Offline: true}
+	u, err := r.Resolve(context.Background(), &scm.User{Name: "James", Email: "james@work.example.com"})
+	require.NoError(t, err, "failed to resolve user")
+	require.NotNil(t, u)
+	assert.Equal(t, "jstrachan", u.Login)
+
+	missing, err := users.LoadAliases(filepath.Join(tmpDir, "does-not-exist.yaml"))
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,134 @@
+package users
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+const (
+	// AnonymizeLogin removes emails and replaces names with the users login
+	AnonymizeLogin = "login"
+
+	// AnonymizeHash removes emails, names, logins and links replacing them with a keyed hash of the users identity
+	AnonymizeHash = "hash"
+
+	// AnonymizeSaltEnv the environment variable of the secret the identities are hashed with if no salt is specified
+	AnonymizeSaltEnv = "JX_CHANGELOG_ANONYMIZE_SALT"
+)
+
+// AnonymizeModes the supported anonymization modes
+var AnonymizeModes = []string{AnonymizeLogin, AnonymizeHash}
+
+var emailRegex = regexp.MustCompile(`<?[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}>?`)
+
+// personTrailerRegex matches the commit message trailers which identify a person such as Co-authored-by
+var personTrailerRegex = regexp.MustCompile(`(?im)^([ \t]*(?:co-authored-by|signed-off-by|reviewed-by|acked-by|tested-by|reported-by|helped-by|suggested-by):[ \t]*)(\S.*)$`)
+
+// Anonymizer removes personal data from user details so that changelogs can be generated for privacy sensitive repositories.
+// The same identity is always anonymized to the same value so that users are still deduplicated
+type Anonymizer struct {
+	// Mode the anonymization mode
+	Mode string
+	// Salt the secret the identities are hashed with so that the hashes cannot be reversed by hashing known emails
+	// or logins
+	Salt string
+}
+
+// NewAnonymizer creates an anonymizer for the given mode or returns nil if no mode is specified.
+// The hash mode requires a secret salt. Without a salt the login mode hashes the users without a login with a random
+// salt so their hashes are only stable within one changelog
+func NewAnonymizer(mode, salt string) (*Anonymizer, error) {
+	switch mode {
+	case "":
+		return nil, nil
+	case AnonymizeHash:
+		if salt == "" {
+			return nil, errors.Errorf("the %s anonymization mode requires a secret salt such as via $%s", mode, AnonymizeSaltEnv)
+		}
+		return &Anonymizer{Mode: mode, Salt: salt}, nil
+	case AnonymizeLogin:
+		if salt == "" {
+			data := make([]byte, 32)
+			_, err := rand.Read(data)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to generate a random salt")
+			}
+			salt = string(data)
+		}
+		return &Anonymizer{Mode: mode, Salt: salt}, nil
+	default:
+		return nil, errors.Errorf("unknown anonymization mode %s. Supported modes are: %s", mode, strings.Join(AnonymizeModes, ", "))
+	}
+}
+
+// Anonymize returns an anonymized copy of the user details. Hashed identities are only used as the name so that
+// they are never linked or mentioned as if they were logins on the git provider
+func (a *Anonymizer) Anonymize(u *jenkinsv1.UserDetails) *jenkinsv1.UserDetails {
+	if a == nil || u == nil {
+		return u
+	}
+	if a.Mode == AnonymizeHash || u.Login == "" {
+		return &jenkinsv1.UserDetails{
+			Name:         a.hash(u),
+			ExternalUser: u.ExternalUser,
+		}
+	}
+	return &jenkinsv1.UserDetails{
+		Login:        u.Login,
+		Name:         u.Login,
+		URL:          u.URL,
+		AvatarURL:    u.AvatarURL,
+		ExternalUser: u.ExternalUser,
+	}
+}
+
+// AnonymizeSlice returns anonymized copies of the user details
+func (a *Anonymizer) AnonymizeSlice(users []jenkinsv1.UserDetails) []jenkinsv1.UserDetails {
+	if a == nil {
+		return users
+	}
+	var answer []jenkinsv1.UserDetails
+	for i := range users {
+		answer = append(answer, *a.Anonymize(&users[i]))
+	}
+	return answer
+}
+
+// AnonymizeText replaces the people of trailers such as Co-authored-by with their hashed identities and removes any
+// other email addresses from the given text
+func (a *Anonymizer) AnonymizeText(text string) string {
+	if a == nil {
+		return text
+	}
+	text = personTrailerRegex.ReplaceAllStringFunc(text, func(line string) string {
+		groups := personTrailerRegex.FindStringSubmatch(line)
+		name, email, _, ok := parseMailmapNameAndEmail(groups[2])
+		if !ok {
+			name, email = strings.TrimSpace(groups[2]), ""
+		}
+		return groups[1] + a.hash(&jenkinsv1.UserDetails{Name: name, Email: email})
+	})
+	return emailRegex.ReplaceAllString(text, "")
+}
+
+// hash returns a short stable HMAC of the identity of the user keyed with the salt
+func (a *Anonymizer) hash(u *jenkinsv1.UserDetails) string {
+	identity := u.Login
+	if identity == "" {
+		identity = u.Email
+	}
+	if identity == "" {
+		identity = u.Name
+	}
+	mac := hmac.New(sha256.New, []byte(a.Salt))
+	_, _ = mac.Write([]byte(strings.ToLower(identity)))
+	return "user-" + hex.EncodeToString(mac.Sum(nil))[0:16]
+}
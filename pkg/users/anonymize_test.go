@@ -0,0 +1,75 @@
+// +build unit
+
+package users_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymizeLogin(t *testing.T) {
+	t.Parallel()
+	a, err := users.NewAnonymizer(users.AnonymizeLogin, "")
+	require.NoError(t, err)
+
+	u := a.Anonymize(&v1.UserDetails{Login: "jstrachan", Name: "James Strachan", Email: "james@example.com", URL: "https://github.com/jstrachan"})
+	assert.Equal(t, "jstrachan", u.Login)
+	assert.Equal(t, "jstrachan", u.Name)
+	assert.Empty(t, u.Email)
+	assert.Equal(t, "https://github.com/jstrachan", u.URL)
+
+	noLogin := a.Anonymize(&v1.UserDetails{Name: "Someone", Email: "someone@example.com"})
+	assert.Empty(t, noLogin.Email)
+	assert.NotEqual(t, "Someone", noLogin.Name)
+	assert.Equal(t, noLogin, a.Anonymize(&v1.UserDetails{Name: "Some One", Email: "Someone@example.com"}), "the same email should anonymize to the same user")
+}
+
+func TestAnonymizeHash(t *testing.T) {
+	t.Parallel()
+	a, err := users.NewAnonymizer(users.AnonymizeHash, "secret")
+	require.NoError(t, err)
+
+	u := a.Anonymize(&v1.UserDetails{Login: "jstrachan", Name: "James Strachan", Email: "james@example.com", URL: "https://github.com/jstrachan"})
+	assert.Empty(t, u.Login, "hashed identities should never be linked or mentioned as logins")
+	assert.True(t, strings.HasPrefix(u.Name, "user-"), "name %s should be hashed", u.Name)
+	assert.Empty(t, u.Email)
+	assert.Empty(t, u.URL)
+
+	text := a.AnonymizeText("fix things\n\nsee jane@example.com\n\nCo-authored-by: Jane Doe <jane@example.com>\nSigned-off-by: James Strachan <james@example.com>")
+	assert.NotContains(t, text, "Jane")
+	assert.NotContains(t, text, "James")
+	assert.NotContains(t, text, "@example.com")
+	assert.Regexp(t, `^fix things\n\nsee \n\nCo-authored-by: user-[0-9a-f]{16}\nSigned-off-by: user-[0-9a-f]{16}$`, text)
+	assert.Contains(t, text, a.Anonymize(&v1.UserDetails{Name: "Jane Doe", Email: "jane@example.com"}).Name, "trailers should use the same identity as the user")
+}
+
+func TestNewAnonymizer(t *testing.T) {
+	t.Parallel()
+	a, err := users.NewAnonymizer("", "")
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	_, err = users.NewAnonymizer("cheese", "secret")
+	assert.Error(t, err)
+	_, err = users.NewAnonymizer(users.AnonymizeHash, "")
+	assert.Error(t, err, "the hash mode should require a salt")
+}
+
+func TestAnonymizeHashIsKeyedWithTheSalt(t *testing.T) {
+	t.Parallel()
+	u := &v1.UserDetails{Login: "jstrachan", Email: "james@example.com"}
+	a, err := users.NewAnonymizer(users.AnonymizeHash, "secret")
+	require.NoError(t, err)
+	same, err := users.NewAnonymizer(users.AnonymizeHash, "secret")
+	require.NoError(t, err)
+	other, err := users.NewAnonymizer(users.AnonymizeHash, "another secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, a.Anonymize(u).Name, same.Anonymize(u).Name, "the same salt should hash to the same identity")
+	assert.NotEqual(t, a.Anonymize(u).Name, other.Anonymize(u).Name, "the hash should depend on the salt")
+}
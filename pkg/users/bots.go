@@ -0,0 +1,83 @@
+package users
+
+import (
+	"regexp"
+	"strings"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// DefaultBotSuffixes the default login suffixes used to detect bot accounts
+var DefaultBotSuffixes = []string{"[bot]", "-bot"}
+
+// gitHubAppBotSuffix the suffix of the logins of GitHub App accounts which GitHub reports with the 'Bot' type
+const gitHubAppBotSuffix = "[bot]"
+
+// gitLabBotLoginRegex matches the logins of the project and group access token bot users of GitLab
+var gitLabBotLoginRegex = regexp.MustCompile(`^(project|group)_\d+_bot(_[0-9a-f]+)?$`)
+
+// IsProviderBot returns true if the git provider marks the login as a bot account such as GitHub App accounts
+// like 'dependabot[bot]' or GitLab access token users like 'project_123_bot'
+func IsProviderBot(login string) bool {
+	return strings.HasSuffix(strings.ToLower(login), gitHubAppBotSuffix) || gitLabBotLoginRegex.MatchString(login)
+}
+
+// BotFilter detects bot accounts so they can be excluded from the changelog authors
+type BotFilter struct {
+	// Suffixes login or name suffixes which indicate a bot such as '[bot]'
+	Suffixes []string
+	// Users explicit logins, names or emails of bot accounts
+	Users []string
+}
+
+// NewBotFilter creates a bot filter using the default suffixes and the given explicit bot users
+func NewBotFilter(suffixes []string, users []string) *BotFilter {
+	if len(suffixes) == 0 {
+		suffixes = DefaultBotSuffixes
+	}
+	return &BotFilter{
+		Suffixes: suffixes,
+		Users:    users,
+	}
+}
+
+// IsBot returns true if the given login, name or email belongs to a bot account
+func (f *BotFilter) IsBot(login, name, email string) bool {
+	if f == nil {
+		return false
+	}
+	for _, u := range f.Users {
+		if u == "" {
+			continue
+		}
+		if strings.EqualFold(u, login) || strings.EqualFold(u, name) || strings.EqualFold(u, email) {
+			return true
+		}
+	}
+	emailUser := email
+	idx := strings.Index(emailUser, "@")
+	if idx >= 0 {
+		emailUser = emailUser[0:idx]
+	}
+	for _, suffix := range f.Suffixes {
+		if suffix == "" {
+			continue
+		}
+		suffix = strings.ToLower(suffix)
+		for _, text := range []string{login, name, emailUser} {
+			if text != "" && strings.HasSuffix(strings.ToLower(text), suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsBotUser returns true if the given user details belong to a bot account of the git provider, a user marked as a
+// service account in the user store or a user matching the filter
+func (f *BotFilter) IsBotUser(u *jenkinsv1.UserDetails) bool {
+	if f == nil || u == nil {
+		return false
+	}
+	return u.ServiceAccount != "" || IsProviderBot(u.Login) || f.IsBot(u.Login, u.Name, u.Email)
+}
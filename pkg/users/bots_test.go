@@ -0,0 +1,33 @@
+// +build unit
+
+package users_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotFilter(t *testing.T) {
+	t.Parallel()
+	f := users.NewBotFilter(nil, []string{"renovate@whitesourcesoftware.com"})
+
+	assert.True(t, f.IsBot("dependabot[bot]", "dependabot[bot]", ""), "dependabot login")
+	assert.True(t, f.IsBot("", "", "49699333+dependabot[bot]@users.noreply.github.com"), "dependabot email")
+	assert.True(t, f.IsBot("jenkins-x-bot", "", ""), "jenkins-x-bot login")
+	assert.True(t, f.IsBot("", "Renovate Bot", "renovate@whitesourcesoftware.com"), "deny listed email")
+	assert.False(t, f.IsBot("jstrachan", "James Strachan", "james@example.com"), "human")
+
+	assert.True(t, f.IsBotUser(&v1.UserDetails{Login: "someone", ServiceAccount: "tekton-bot"}), "service account")
+	assert.False(t, f.IsBotUser(nil), "nil user")
+
+	custom := users.NewBotFilter([]string{"-automation"}, nil)
+	assert.True(t, custom.IsBotUser(&v1.UserDetails{Login: "renovate[bot]"}), "GitHub App account with custom suffixes")
+	assert.True(t, custom.IsBotUser(&v1.UserDetails{Login: "project_42_bot_0a1b2c3d"}), "GitLab project access token user")
+	assert.False(t, custom.IsBotUser(&v1.UserDetails{Login: "project-bot-fan"}), "human")
+
+	var nilFilter *users.BotFilter
+	assert.False(t, nilFilter.IsBot("dependabot[bot]", "", ""), "nil filter")
+}
@@ -0,0 +1,55 @@
+package users
+
+import (
+	"bufio"
+	"context"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// CoAuthorTrailer the git trailer used to credit additional authors of a commit
+const CoAuthorTrailer = "Co-authored-by"
+
+// ParseCoAuthors parses the Co-authored-by trailers in the given commit message
+func ParseCoAuthors(message string) []scm.User {
+	var answer []scm.User
+	prefix := strings.ToLower(CoAuthorTrailer) + ":"
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(strings.ToLower(line), prefix) {
+			continue
+		}
+		name, email, _, ok := parseMailmapNameAndEmail(line[len(prefix):])
+		if !ok || (name == "" && email == "") {
+			continue
+		}
+		answer = append(answer, scm.User{
+			Name:  name,
+			Email: email,
+		})
+	}
+	return answer
+}
+
+// CoAuthorsAsUsers resolves the Co-authored-by trailers of the given commit message to Jenkins X User Details
+func (r *GitUserResolver) CoAuthorsAsUsers(ctx context.Context, message string) ([]jenkinsv1.UserDetails, error) {
+	var answer []jenkinsv1.UserDetails
+	for _, coAuthor := range ParseCoAuthors(message) {
+		name, email := r.Mailmap.Map(coAuthor.Name, coAuthor.Email)
+		u, err := r.Resolve(ctx, &scm.User{
+			Name:  name,
+			Email: email,
+		})
+		if err != nil {
+			return answer, err
+		}
+		if u != nil {
+			answer = append(answer, *u)
+		}
+	}
+	return answer, nil
+}
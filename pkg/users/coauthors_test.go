@@ -0,0 +1,30 @@
+// +build unit
+
+package users_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	t.Parallel()
+	message := `fix: something (#123)
+
+* first change
+* second change
+
+Co-authored-by: Jane Doe <jane@example.com>
+co-authored-by: joe <joe@example.com>
+Co-authored-by: invalid
+`
+	coAuthors := users.ParseCoAuthors(message)
+	expected := []scm.User{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Name: "joe", Email: "joe@example.com"},
+	}
+	assert.Equal(t, expected, coAuthors)
+}
@@ -0,0 +1,210 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+)
+
+type gitlabUser struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	PublicEmail string `json:"public_email"`
+	AvatarURL   string `json:"avatar_url"`
+	WebURL      string `json:"web_url"`
+}
+
+type giteaUser struct {
+	ID        int    `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type giteaUserSearch struct {
+	Data []giteaUser `json:"data"`
+}
+
+type githubCommitAuthor struct {
+	Author *githubUser `json:"author"`
+}
+
+type githubUser struct {
+	ID        int    `json:"id"`
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+}
+
+type githubUserSearch struct {
+	Items []githubUser `json:"items"`
+}
+
+type stashUser struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+type stashUserPage struct {
+	Values []stashUser `json:"values"`
+}
+
+// FindUserByEmail uses the git provider user search APIs to find the account for the given email.
+// Returns nil if the provider does not support searching by email or no user could be found
+func (r *GitUserResolver) FindUserByEmail(ctx context.Context, email string) (*scm.User, error) {
+	if r == nil || r.GitProvider == nil || email == "" {
+		return nil, nil
+	}
+	switch r.GitProvider.Driver {
+	case scm.DriverGithub:
+		return r.findGitHubUserByEmail(ctx, r.Repository, email)
+	case scm.DriverStash:
+		return r.findStashUserByEmail(ctx, email)
+	case scm.DriverGitlab:
+		return r.findGitLabUserByEmail(ctx, email)
+	case scm.DriverGitea:
+		return r.findGiteaUserByEmail(ctx, email)
+	default:
+		return nil, nil
+	}
+}
+
+func (r *GitUserResolver) findGitLabUserByEmail(ctx context.Context, email string) (*scm.User, error) {
+	var results []gitlabUser
+	path := fmt.Sprintf("api/v4/users?search=%s", url.QueryEscape(email))
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, r.GitProvider, path, &results)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to search GitLab users by email %s", email)
+	}
+	for _, u := range results {
+		if strings.EqualFold(u.Email, email) || strings.EqualFold(u.PublicEmail, email) {
+			return &scm.User{
+				ID:     u.ID,
+				Login:  u.Username,
+				Name:   u.Name,
+				Email:  email,
+				Avatar: u.AvatarURL,
+				Link:   u.WebURL,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *GitUserResolver) findGiteaUserByEmail(ctx context.Context, email string) (*scm.User, error) {
+	results := &giteaUserSearch{}
+	path := fmt.Sprintf("api/v1/users/search?q=%s", url.QueryEscape(email))
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, r.GitProvider, path, results)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to search Gitea users by email %s", email)
+	}
+	for _, u := range results.Data {
+		if strings.EqualFold(u.Email, email) {
+			return &scm.User{
+				ID:     u.ID,
+				Login:  u.Login,
+				Name:   u.FullName,
+				Email:  email,
+				Avatar: u.AvatarURL,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// findGitHubUserByEmail finds the account linked to the email of the commits in the repository
+// as GitHub links commit emails to accounts even if the email is private.
+// If there is no repository or commit the users with the email as their public email are searched
+func (r *GitUserResolver) findGitHubUserByEmail(ctx context.Context, repo, email string) (*scm.User, error) {
+	if repo != "" {
+		var commits []githubCommitAuthor
+		path := fmt.Sprintf("repos/%s/commits?author=%s&per_page=1", repo, url.QueryEscape(email))
+		err := r.callProvider(ctx, func() (*scm.Response, error) {
+			return scmapi.GetJSON(ctx, r.GitProvider, path, &commits)
+		})
+		if err != nil && !scm.IsScmNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to search GitHub commits by email %s", email)
+		}
+		for _, c := range commits {
+			if c.Author != nil && c.Author.Login != "" {
+				return c.Author.toScmUser(email), nil
+			}
+		}
+	}
+
+	results := &githubUserSearch{}
+	path := fmt.Sprintf("search/users?q=%s", url.QueryEscape(email+" in:email"))
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, r.GitProvider, path, results)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to search GitHub users by email %s", email)
+	}
+	if len(results.Items) == 1 {
+		return results.Items[0].toScmUser(email), nil
+	}
+	return nil, nil
+}
+
+func (u *githubUser) toScmUser(email string) *scm.User {
+	return &scm.User{
+		ID:     u.ID,
+		Login:  u.Login,
+		Email:  email,
+		Avatar: u.AvatarURL,
+		Link:   u.HTMLURL,
+	}
+}
+
+func (r *GitUserResolver) findStashUserByEmail(ctx context.Context, email string) (*scm.User, error) {
+	results := &stashUserPage{}
+	path := fmt.Sprintf("rest/api/1.0/users?filter=%s", url.QueryEscape(email))
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, r.GitProvider, path, results)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to search Bitbucket Server users by email %s", email)
+	}
+	for _, u := range results.Values {
+		if strings.EqualFold(u.EmailAddress, email) {
+			login := u.Slug
+			if login == "" {
+				login = u.Name
+			}
+			return &scm.User{
+				ID:    u.ID,
+				Login: login,
+				Name:  u.DisplayName,
+				Email: email,
+			}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/jenkins-x/go-scm/scm/driver/gitlab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindGitLabUserByEmail(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/users", r.URL.Path)
+		assert.Equal(t, "jane@example.com", r.URL.Query().Get("search"))
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{
+				"id":           123,
+				"username":     "jane",
+				"name":         "Jane Doe",
+				"public_email": "jane@example.com",
+				"avatar_url":   "https://gitlab.example.com/avatar.png",
+				"web_url":      "https://gitlab.example.com/jane",
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+
+	r := &users.GitUserResolver{GitProvider: client}
+	u, err := r.FindUserByEmail(context.TODO(), "jane@example.com")
+	require.NoError(t, err, "failed to find user")
+	require.NotNil(t, u, "should have found a user")
+	assert.Equal(t, "jane", u.Login)
+	assert.Equal(t, "https://gitlab.example.com/avatar.png", u.Avatar)
+
+	r.EmailLookup = true
+	resolved, err := r.Resolve(context.Background(), &scm.User{Name: "Jane Doe", Email: "jane@example.com"})
+	require.NoError(t, err, "failed to resolve user")
+	require.NotNil(t, resolved, "should have resolved a user")
+	assert.Equal(t, "jane", resolved.Login)
+}
+
+func TestFindGitLabUserByEmailRequiresMatch(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the search also matches names and usernames
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{
+				"id":       456,
+				"username": "jane-other",
+				"name":     "Jane Example",
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := gitlab.New(server.URL)
+	require.NoError(t, err, "failed to create GitLab client")
+
+	r := &users.GitUserResolver{GitProvider: client}
+	u, err := r.FindUserByEmail(context.TODO(), "jane@example.com")
+	require.NoError(t, err, "failed to find user")
+	assert.Nil(t, u, "should not attribute the email to a user without that email")
+}
+
+func TestFindGitHubUserByEmail(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/jenkins-x/jx-changelog/commits":
+			assert.Equal(t, "jane@example.com", r.URL.Query().Get("author"))
+			_, _ = w.Write([]byte(`[{"author": {"id": 123, "login": "jane", "avatar_url": "https://avatars.example.com/jane"}}]`))
+		case "/search/users":
+			assert.Equal(t, "bob@example.com in:email", r.URL.Query().Get("q"))
+			_, _ = w.Write([]byte(`{"items": [{"id": 456, "login": "bob"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{
+		GitProvider: client,
+		Repository:  "jenkins-x/jx-changelog",
+	}
+	u, err := r.FindUserByEmail(context.TODO(), "jane@example.com")
+	require.NoError(t, err, "failed to find user from commits")
+	require.NotNil(t, u, "should have found a user from the commits")
+	assert.Equal(t, "jane", u.Login)
+	assert.Equal(t, "https://avatars.example.com/jane", u.Avatar)
+
+	r.Repository = ""
+	u, err = r.FindUserByEmail(context.TODO(), "bob@example.com")
+	require.NoError(t, err, "failed to find user from search")
+	require.NotNil(t, u, "should have found a user from the search")
+	assert.Equal(t, "bob", u.Login)
+}
+
+func TestResolveSkipsEmailLookupByDefault(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{
+		GitProvider: client,
+		Repository:  "jenkins-x/jx-changelog",
+	}
+	resolved, err := r.Resolve(context.Background(), &scm.User{Name: "Jane Doe", Email: "jane@example.com"})
+	require.NoError(t, err, "failed to resolve user")
+	require.NotNil(t, resolved, "should have resolved the git user")
+	assert.Equal(t, "jane@example.com", resolved.Email)
+	assert.Equal(t, 0, calls, "should not search the git provider by email unless enabled")
+}
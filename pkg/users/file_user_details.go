@@ -0,0 +1,170 @@
+package users
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/naming"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultUserCacheTTL the default time a cached user is considered valid
+	DefaultUserCacheTTL = 24 * time.Hour
+
+	// DefaultNotFoundTTL the default time a login which could not be found is remembered
+	DefaultNotFoundTTL = time.Hour
+)
+
+// FileUserDetailService caches the user details in a JSON file so that they can be reused across runs
+type FileUserDetailService struct {
+	// Path the file to store the users in
+	Path string
+	// TTL how long a cached user is valid for. If zero cached users never expire
+	TTL time.Duration
+	// NotFoundTTL how long a login which could not be found is remembered. If zero it is remembered for the TTL
+	NotFoundTTL time.Duration
+
+	lock    sync.Mutex
+	entries map[string]*fileUserEntry
+	now     func() time.Time
+}
+
+type fileUserEntry struct {
+	User     *v1.UserDetails `json:"user,omitempty"`
+	NotFound bool            `json:"notFound,omitempty"`
+	Updated  time.Time       `json:"updated"`
+}
+
+// NewFileUserDetailService creates a new user detail service which caches users in the given file
+func NewFileUserDetailService(path string, ttl time.Duration) *FileUserDetailService {
+	return &FileUserDetailService{
+		Path:        path,
+		TTL:         ttl,
+		NotFoundTTL: DefaultNotFoundTTL,
+		now:         time.Now,
+	}
+}
+
+func (s *FileUserDetailService) GetUser(login string) *v1.UserDetails {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.load()
+	if err != nil {
+		log.Logger().Warnf("failed to load user cache %s: %s", s.Path, err.Error())
+	}
+	entry := s.entries[login]
+	if entry == nil || entry.NotFound || s.isExpired(entry) {
+		return nil
+	}
+	return entry.User
+}
+
+func (s *FileUserDetailService) IsUserNotFound(login string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.load()
+	if err != nil {
+		log.Logger().Warnf("failed to load user cache %s: %s", s.Path, err.Error())
+	}
+	entry := s.entries[login]
+	return entry != nil && entry.NotFound && !s.isExpired(entry)
+}
+
+func (s *FileUserDetailService) MarkUserNotFound(login string) error {
+	if login == "" {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.load()
+	if err != nil {
+		return err
+	}
+	s.entries[login] = &fileUserEntry{
+		NotFound: true,
+		Updated:  s.now(),
+	}
+	return s.save()
+}
+
+func (s *FileUserDetailService) CreateOrUpdateUser(u *v1.UserDetails) error {
+	if u == nil || u.Login == "" {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.load()
+	if err != nil {
+		return err
+	}
+
+	id := naming.ToValidName(u.Login)
+	entry := s.entries[id]
+	if entry == nil || entry.NotFound || s.isExpired(entry) {
+		entry = &fileUserEntry{User: u}
+		s.entries[id] = entry
+	} else {
+		mergeUserDetails(u, entry.User)
+	}
+	entry.Updated = s.now()
+	return s.save()
+}
+
+func (s *FileUserDetailService) isExpired(entry *fileUserEntry) bool {
+	ttl := s.TTL
+	if entry.NotFound && s.NotFoundTTL > 0 {
+		ttl = s.NotFoundTTL
+	}
+	return ttl > 0 && s.now().Sub(entry.Updated) > ttl
+}
+
+func (s *FileUserDetailService) load() error {
+	if s.entries != nil {
+		return nil
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	s.entries = map[string]*fileUserEntry{}
+	exists, err := files.FileExists(s.Path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", s.Path)
+	}
+	if !exists {
+		return nil
+	}
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load file %s", s.Path)
+	}
+	err = json.Unmarshal(data, &s.entries)
+	if err != nil {
+		return errors.Wrapf(err, "failed to unmarshal JSON file %s", s.Path)
+	}
+	return nil
+}
+
+func (s *FileUserDetailService) save() error {
+	dir := filepath.Dir(s.Path)
+	err := os.MkdirAll(dir, files.DefaultDirWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create directory %s", dir)
+	}
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal users to JSON")
+	}
+	err = ioutil.WriteFile(s.Path, data, files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", s.Path)
+	}
+	return nil
+}
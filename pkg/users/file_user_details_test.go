@@ -0,0 +1,60 @@
+// +build unit
+
+package users_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileUserDetailService(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+	path := filepath.Join(tmpDir, "cache", "users.json")
+
+	s := users.NewFileUserDetailService(path, time.Hour)
+	assert.Nil(t, s.GetUser("jstrachan"), "should not have a user before caching")
+
+	err = s.CreateOrUpdateUser(&v1.UserDetails{Login: "jstrachan", Name: "James Strachan"})
+	require.NoError(t, err, "failed to cache user")
+	err = s.CreateOrUpdateUser(&v1.UserDetails{Login: "jstrachan", Email: "james@example.com"})
+	require.NoError(t, err, "failed to update user")
+	require.FileExists(t, path, "should have saved the cache")
+
+	s2 := users.NewFileUserDetailService(path, time.Hour)
+	u := s2.GetUser("jstrachan")
+	require.NotNil(t, u, "should have loaded the user from %s", path)
+	assert.Equal(t, "James Strachan", u.Name)
+	assert.Equal(t, "james@example.com", u.Email)
+
+	expired := users.NewFileUserDetailService(path, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	assert.Nil(t, expired.GetUser("jstrachan"), "user should have expired")
+}
+
+func TestFileUserDetailServiceNotFound(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err, "could not create temp dir")
+	path := filepath.Join(tmpDir, "users.json")
+
+	s := users.NewFileUserDetailService(path, time.Hour)
+	assert.False(t, s.IsUserNotFound("ghost"))
+	err = s.MarkUserNotFound("ghost")
+	require.NoError(t, err, "failed to cache not found user")
+
+	s2 := users.NewFileUserDetailService(path, time.Hour)
+	assert.True(t, s2.IsUserNotFound("ghost"), "should have loaded the not found user from %s", path)
+	assert.Nil(t, s2.GetUser("ghost"), "not found users should not be returned")
+
+	s3 := users.NewFileUserDetailService(path, time.Hour)
+	s3.NotFoundTTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	assert.False(t, s3.IsUserNotFound("ghost"), "not found user should have expired")
+}
@@ -0,0 +1,118 @@
+package users
+
+import (
+	"bufio"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+)
+
+// MailmapFileName the name of the git mailmap file in the root of a repository
+const MailmapFileName = ".mailmap"
+
+// Mailmap maps commit names and emails to canonical identities using the same rules as git
+// see: https://git-scm.com/docs/gitmailmap
+type Mailmap struct {
+	entries map[string][]mailmapEntry
+}
+
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+}
+
+// LoadMailmap loads the .mailmap file in the given directory if it exists
+func LoadMailmap(dir string) (*Mailmap, error) {
+	path := filepath.Join(dir, MailmapFileName)
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load file %s", path)
+	}
+	return ParseMailmap(string(data)), nil
+}
+
+// ParseMailmap parses the text of a mailmap file
+func ParseMailmap(text string) *Mailmap {
+	m := &Mailmap{
+		entries: map[string][]mailmapEntry{},
+	}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name1, email1, rest, ok := parseMailmapNameAndEmail(line)
+		if !ok {
+			continue
+		}
+		entry := mailmapEntry{
+			properName: name1,
+		}
+		commitEmail := email1
+		name2, email2, _, ok := parseMailmapNameAndEmail(rest)
+		if ok {
+			entry.properEmail = email1
+			entry.commitName = name2
+			commitEmail = email2
+		}
+		key := strings.ToLower(commitEmail)
+		m.entries[key] = append(m.entries[key], entry)
+	}
+	return m
+}
+
+// Map returns the canonical name and email for the given commit name and email
+func (m *Mailmap) Map(name, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+	var found *mailmapEntry
+	for _, e := range m.entries[strings.ToLower(email)] {
+		entry := e
+		if entry.commitName == "" {
+			if found == nil || found.commitName == "" {
+				found = &entry
+			}
+		} else if strings.EqualFold(entry.commitName, name) {
+			found = &entry
+		}
+	}
+	if found == nil {
+		return name, email
+	}
+	if found.properName != "" {
+		name = found.properName
+	}
+	if found.properEmail != "" {
+		email = found.properEmail
+	}
+	return name, email
+}
+
+// parseMailmapNameAndEmail parses a leading `Name <email>` returning the remaining text
+func parseMailmapNameAndEmail(text string) (string, string, string, bool) {
+	start := strings.Index(text, "<")
+	if start < 0 {
+		return "", "", "", false
+	}
+	end := strings.Index(text[start:], ">")
+	if end < 0 {
+		return "", "", "", false
+	}
+	end += start
+	name := strings.TrimSpace(text[:start])
+	email := strings.TrimSpace(text[start+1 : end])
+	return name, email, text[end+1:], true
+}
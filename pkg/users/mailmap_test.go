@@ -0,0 +1,44 @@
+// +build unit
+
+package users_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailmap(t *testing.T) {
+	t.Parallel()
+	m := users.ParseMailmap(`# comment line
+Jane Doe <jane@example.com>
+<jane@example.com> <jane@old.example.com>
+Joe Bloggs <joe@example.com> <joe@laptop.local>
+Other Joe <other@example.com> joe <shared@example.com>
+`)
+
+	testCases := []struct {
+		name, email, expectedName, expectedEmail string
+	}{
+		{"jane", "jane@example.com", "Jane Doe", "jane@example.com"},
+		{"Jane", "JANE@old.example.com", "Jane", "jane@example.com"},
+		{"joe", "joe@laptop.local", "Joe Bloggs", "joe@example.com"},
+		{"Joe", "shared@example.com", "Other Joe", "other@example.com"},
+		{"somebody", "shared@example.com", "somebody", "shared@example.com"},
+		{"unknown", "unknown@example.com", "unknown", "unknown@example.com"},
+	}
+	for _, tc := range testCases {
+		name, email := m.Map(tc.name, tc.email)
+		assert.Equal(t, tc.expectedName, name, "name for %s <%s>", tc.name, tc.email)
+		assert.Equal(t, tc.expectedEmail, email, "email for %s <%s>", tc.name, tc.email)
+	}
+}
+
+func TestNilMailmap(t *testing.T) {
+	t.Parallel()
+	var m *users.Mailmap
+	name, email := m.Map("jane", "jane@example.com")
+	assert.Equal(t, "jane", name)
+	assert.Equal(t, "jane@example.com", email)
+}
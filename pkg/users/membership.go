@@ -0,0 +1,56 @@
+package users
+
+import (
+	"context"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// IsOrganizationMember returns true if the given login is a member of the resolvers organization.
+// The result for each login is remembered so that the git provider is only queried once per login
+func (r *GitUserResolver) IsOrganizationMember(ctx context.Context, login string) (bool, error) {
+	if r.Organization == "" || login == "" {
+		return false, nil
+	}
+	r.lock.Lock()
+	member, ok := r.members[login]
+	r.lock.Unlock()
+	if ok {
+		return member, nil
+	}
+
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		var res *scm.Response
+		var findErr error
+		member, res, findErr = r.GitProvider.Organizations.IsMember(ctx, r.Organization, login)
+		return res, findErr
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if %s is a member of organisation %s", login, r.Organization)
+	}
+
+	r.lock.Lock()
+	if r.members == nil {
+		r.members = map[string]bool{}
+	}
+	r.members[login] = member
+	r.lock.Unlock()
+	return member, nil
+}
+
+// markExternalUser marks the user as external if they are not a member of the resolvers organization
+func (r *GitUserResolver) markExternalUser(ctx context.Context, u *jenkinsv1.UserDetails) {
+	if u == nil || u.Login == "" || r.Organization == "" || r.Offline || r.GitProvider == nil {
+		return
+	}
+	member, err := r.IsOrganizationMember(ctx, u.Login)
+	if err != nil {
+		log.Logger().Warnf("%s", err.Error())
+		return
+	}
+	u.ExternalUser = !member
+}
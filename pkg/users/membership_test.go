@@ -0,0 +1,60 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveMarksExternalUsers(t *testing.T) {
+	t.Parallel()
+	memberChecks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/jstrachan", "/users/someone":
+			login := r.URL.Path[len("/users/"):]
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"login": login,
+				"name":  login,
+			})
+		case "/orgs/acme/members/jstrachan":
+			memberChecks++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{
+		GitProvider:  client,
+		Organization: "acme",
+	}
+
+	member, err := r.Resolve(context.Background(), &scm.User{Login: "jstrachan", Name: "James Strachan"})
+	require.NoError(t, err, "failed to resolve member")
+	require.NotNil(t, member)
+	assert.False(t, member.ExternalUser, "organisation member should not be external")
+
+	external, err := r.Resolve(context.Background(), &scm.User{Login: "someone", Name: "Someone"})
+	require.NoError(t, err, "failed to resolve external user")
+	require.NotNil(t, external)
+	assert.True(t, external.ExternalUser, "non member should be external")
+
+	_, err = r.Resolve(context.Background(), &scm.User{Login: "jstrachan", Name: "James Strachan"})
+	require.NoError(t, err, "failed to resolve member again")
+	assert.Equal(t, 1, memberChecks, "membership should only be checked once per login")
+}
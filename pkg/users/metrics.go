@@ -0,0 +1,87 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+)
+
+// Metrics receives the events of resolving users so that the cost of resolution can be reported
+type Metrics interface {
+	// CacheHit a user was found in the cache
+	CacheHit()
+	// CacheMiss a user was not found in the cache
+	CacheMiss()
+	// APICall a call was made to the git provider
+	APICall()
+	// UserNotFound a user could not be found on the git provider
+	UserNotFound()
+	// APIError a call to the git provider failed
+	APIError()
+}
+
+// ResolutionStats counts the events of resolving users
+type ResolutionStats struct {
+	CacheHits   int64 `json:"cacheHits"`
+	CacheMisses int64 `json:"cacheMisses"`
+	APICalls    int64 `json:"apiCalls"`
+	NotFound    int64 `json:"notFound"`
+	Errors      int64 `json:"errors"`
+}
+
+func (s *ResolutionStats) CacheHit() {
+	atomic.AddInt64(&s.CacheHits, 1)
+}
+
+func (s *ResolutionStats) CacheMiss() {
+	atomic.AddInt64(&s.CacheMisses, 1)
+}
+
+func (s *ResolutionStats) APICall() {
+	atomic.AddInt64(&s.APICalls, 1)
+}
+
+func (s *ResolutionStats) UserNotFound() {
+	atomic.AddInt64(&s.NotFound, 1)
+}
+
+func (s *ResolutionStats) APIError() {
+	atomic.AddInt64(&s.Errors, 1)
+}
+
+// String returns a summary of the statistics
+func (s *ResolutionStats) String() string {
+	return fmt.Sprintf("cache hits: %d, cache misses: %d, API calls: %d, not found: %d, errors: %d",
+		atomic.LoadInt64(&s.CacheHits), atomic.LoadInt64(&s.CacheMisses), atomic.LoadInt64(&s.APICalls),
+		atomic.LoadInt64(&s.NotFound), atomic.LoadInt64(&s.Errors))
+}
+
+type noMetrics struct{}
+
+func (noMetrics) CacheHit()     {}
+func (noMetrics) CacheMiss()    {}
+func (noMetrics) APICall()      {}
+func (noMetrics) UserNotFound() {}
+func (noMetrics) APIError()     {}
+
+// metrics returns the metrics of the resolver or a no-op implementation if there are none
+func (r *GitUserResolver) metrics() Metrics {
+	if r.Metrics == nil {
+		return noMetrics{}
+	}
+	return r.Metrics
+}
+
+// callProvider invokes the git provider retrying if the rate limit is exceeded and recording the call in the metrics
+func (r *GitUserResolver) callProvider(ctx context.Context, fn func() (*scm.Response, error)) error {
+	m := r.metrics()
+	m.APICall()
+	err := r.Backoff.Do(ctx, fn)
+	if err != nil && !scmhelpers.IsScmNotFound(err) {
+		m.APIError()
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package users
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+)
+
+var (
+	// githubNoReplyRegex matches GitHub noreply emails like '12345+alice@users.noreply.github.com' or 'alice@users.noreply.github.com'
+	githubNoReplyRegex = regexp.MustCompile(`^(?:\d+\+)?([^@+]+)@users\.noreply\.([^@]+)$`)
+
+	// gitlabNoReplyRegex matches GitLab noreply emails like '12345-alice@users.noreply.gitlab.com'
+	gitlabNoReplyRegex = regexp.MustCompile(`^(?:\d+-)?([^@]+)@users\.noreply\.([^@]+)$`)
+
+	// giteaNoReplyRegex matches Gitea noreply emails like 'alice@noreply.gitea.example.com'
+	giteaNoReplyRegex = regexp.MustCompile(`^([^@]+)@noreply\.([^@]+)$`)
+)
+
+// NoReplyEmailLogin returns the login of the git provider account for a noreply email address of the git server
+// such as '12345+alice@users.noreply.github.com' or an empty string if the email is not a noreply email of the
+// given driver and git server host
+func NoReplyEmailLogin(email string, driver scm.Driver, host string) string {
+	var re *regexp.Regexp
+	switch driver {
+	case scm.DriverGithub:
+		re = githubNoReplyRegex
+	case scm.DriverGitlab:
+		re = gitlabNoReplyRegex
+	case scm.DriverGitea:
+		re = giteaNoReplyRegex
+	default:
+		return ""
+	}
+	host = strings.ToLower(host)
+	email = strings.ToLower(strings.TrimSpace(email))
+	matches := re.FindStringSubmatch(email)
+	if len(matches) > 2 && matches[1] != "" && host != "" && matches[2] == host {
+		return matches[1]
+	}
+	return ""
+}
+
+// noReplyEmailLogin returns the login of the noreply email of the git server of the resolver
+func (r *GitUserResolver) noReplyEmailLogin(email string) string {
+	if r.GitProvider == nil {
+		return ""
+	}
+	return NoReplyEmailLogin(email, r.GitProvider.Driver, r.gitServerHost())
+}
+
+// gitServerHost returns the host of the git server defaulting to the host of the git provider API
+func (r *GitUserResolver) gitServerHost() string {
+	if r.GitServerURL != "" {
+		u, err := url.Parse(r.GitServerURL)
+		if err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	if r.GitProvider == nil || r.GitProvider.BaseURL == nil {
+		return ""
+	}
+	return strings.TrimPrefix(r.GitProvider.BaseURL.Host, "api.")
+}
@@ -0,0 +1,60 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoReplyEmailLogin(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		email    string
+		driver   scm.Driver
+		host     string
+		expected string
+	}{
+		{"12345+alice@users.noreply.github.com", scm.DriverGithub, "github.com", "alice"},
+		{"bob@users.noreply.github.com", scm.DriverGithub, "github.com", "bob"},
+		{"grace@users.noreply.github.example.com", scm.DriverGithub, "github.example.com", "grace"},
+		{"1234567-carol@users.noreply.gitlab.com", scm.DriverGitlab, "gitlab.com", "carol"},
+		{"dave@users.noreply.gitlab.example.com", scm.DriverGitlab, "gitlab.example.com", "dave"},
+		{"erin@noreply.gitea.example.com", scm.DriverGitea, "gitea.example.com", "erin"},
+		{"frank@example.com", scm.DriverGithub, "github.com", ""},
+		{"noreply@github.com", scm.DriverGithub, "github.com", ""},
+		{"", scm.DriverGithub, "github.com", ""},
+		{"mallory@users.noreply.evil.example.com", scm.DriverGithub, "github.com", ""},
+		{"mallory@noreply.evil.example.com", scm.DriverGitea, "gitea.example.com", ""},
+		{"bob@users.noreply.github.com", scm.DriverStash, "github.com", ""},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, users.NoReplyEmailLogin(tc.email, tc.driver, tc.host), "login for email %s on %s %s", tc.email, tc.driver.String(), tc.host)
+	}
+}
+
+func TestResolveNoReplyEmailOfGitServer(t *testing.T) {
+	t.Parallel()
+	client, err := github.New("https://github.example.com/api/v3")
+	require.NoError(t, err, "failed to create GitHub client")
+	r := &users.GitUserResolver{
+		GitProvider: client,
+		Offline:     true,
+	}
+
+	u, err := r.Resolve(context.Background(), &scm.User{Name: "Alice", Email: "12345+alice@users.noreply.github.example.com"})
+	require.NoError(t, err, "failed to resolve user")
+	require.NotNil(t, u)
+	assert.Equal(t, "alice", u.Login)
+
+	u, err = r.Resolve(context.Background(), &scm.User{Name: "Bob", Email: "bob@users.noreply.github.com"})
+	require.NoError(t, err, "failed to resolve user")
+	require.NotNil(t, u)
+	assert.Empty(t, u.Login, "should not trust the noreply emails of other git servers")
+}
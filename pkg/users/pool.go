@@ -0,0 +1,113 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// DefaultWorkers the default number of users resolved concurrently
+const DefaultWorkers = 4
+
+// ResolveAll resolves the given git users concurrently using a bounded pool of workers.
+// Each unique user is only resolved once and the results are returned in the same order as the given users
+// with nil entries for users which could not be resolved.
+// The failures to resolve a user are logged and the first one is returned along with the partial results.
+// Once the context is done any remaining users are not resolved and the context error is returned
+func (r *GitUserResolver) ResolveAll(ctx context.Context, users []scm.User) ([]*jenkinsv1.UserDetails, error) {
+	answer := make([]*jenkinsv1.UserDetails, len(users))
+	if r == nil || len(users) == 0 {
+		return answer, nil
+	}
+
+	// lets make sure the cache is created before we start the workers
+	r.userCache()
+
+	var keys []string
+	unique := map[string]*scm.User{}
+	indexes := make([]string, len(users))
+	for i := range users {
+		key := userKey(&users[i])
+		indexes[i] = key
+		if unique[key] == nil {
+			unique[key] = &users[i]
+			keys = append(keys, key)
+		}
+	}
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+
+	var lock sync.Mutex
+	var firstErr error
+	results := map[string]*jenkinsv1.UserDetails{}
+
+	ch := make(chan string)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for key := range ch {
+				u, err := r.Resolve(ctx, unique[key])
+				if err != nil {
+					log.Logger().Warnf("failed to resolve user %s: %s", userName(unique[key]), err.Error())
+				}
+				lock.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				results[key] = u
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		ch <- key
+	}
+	close(ch)
+	wg.Wait()
+
+	for i, key := range indexes {
+		answer[i] = results[key]
+	}
+	if ctx.Err() != nil {
+		return answer, ctx.Err()
+	}
+	return answer, firstErr
+}
+
+// userName returns the login or otherwise the name and email of the user for logging
+func userName(user *scm.User) string {
+	if user.Login != "" {
+		return user.Login
+	}
+	if user.Email != "" {
+		return user.Name + " <" + user.Email + ">"
+	}
+	return user.Name
+}
+
+// userKey returns the key used to detect duplicate users
+func userKey(user *scm.User) string {
+	if user.Login != "" {
+		return "login:" + strings.ToLower(user.Login)
+	}
+	if user.Email != "" {
+		return "email:" + strings.ToLower(user.Email) + ":" + user.Name
+	}
+	return "name:" + user.Name
+}
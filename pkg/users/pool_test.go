@@ -0,0 +1,91 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	scmfake "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAll(t *testing.T) {
+	t.Parallel()
+	scmClient, fakeData := scmfake.NewDefault()
+	fakeData.Users = append(fakeData.Users,
+		&scm.User{Login: "jstrachan", Name: "James Strachan", Email: "james@example.com"},
+		&scm.User{Login: "rawlingsj", Name: "James Rawlings", Email: "rawlingsj@example.com"},
+	)
+
+	r := &users.GitUserResolver{
+		GitProvider: scmClient,
+		Workers:     2,
+	}
+	input := []scm.User{
+		{Login: "jstrachan", Name: "James Strachan"},
+		{Login: "rawlingsj", Name: "James Rawlings"},
+		{Login: "jstrachan", Name: "James Strachan"},
+		{Name: "Someone Else", Email: "someone@example.com"},
+		{Login: "unknown", Name: "Unknown"},
+	}
+	resolved, err := r.ResolveAll(context.Background(), input)
+	require.NoError(t, err, "failed to resolve users")
+	require.Len(t, resolved, len(input))
+
+	require.NotNil(t, resolved[0])
+	assert.Equal(t, "jstrachan", resolved[0].Login)
+	assert.Equal(t, "james@example.com", resolved[0].Email)
+	require.NotNil(t, resolved[1])
+	assert.Equal(t, "rawlingsj", resolved[1].Login)
+	assert.Equal(t, resolved[0], resolved[2], "duplicate users should resolve to the same user")
+	require.NotNil(t, resolved[3])
+	assert.Equal(t, "someone@example.com", resolved[3].Email)
+	assert.Nil(t, resolved[4], "unknown login should not resolve")
+}
+
+func TestResolveAllCancelled(t *testing.T) {
+	t.Parallel()
+	scmClient, _ := scmfake.NewDefault()
+	r := &users.GitUserResolver{
+		GitProvider: scmClient,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := r.ResolveAll(ctx, []scm.User{{Login: "jstrachan", Name: "James Strachan"}})
+	require.Error(t, err, "should fail when the context is cancelled")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestResolveAllReturnsPartialResults(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/jstrachan" {
+			_, _ = w.Write([]byte(`{"login": "jstrachan", "name": "James Strachan", "email": "james@example.com"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{GitProvider: client}
+	resolved, err := r.ResolveAll(context.Background(), []scm.User{
+		{Login: "broken", Name: "Broken"},
+		{Login: "jstrachan", Name: "James Strachan"},
+	})
+	require.Error(t, err, "should return the failure to resolve a user")
+	require.Len(t, resolved, 2, "should return the partial results")
+	assert.Nil(t, resolved[0], "the failed user should not resolve")
+	require.NotNil(t, resolved[1], "the other users should still resolve")
+	assert.Equal(t, "james@example.com", resolved[1].Email)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
 	"github.com/jenkins-x/go-scm/scm"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/naming"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
 	"github.com/pkg/errors"
 
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
@@ -18,31 +21,59 @@ import (
 // GitUserResolver allows git users to be converted to Jenkins X users
 type GitUserResolver struct {
 	GitProvider *scm.Client
-	cache       UserDetailService
+	Mailmap     *Mailmap
+	Aliases     *Aliases
+	Bots        *BotFilter
+	Cache       UserDetailService
+	// Offline resolves users purely from their git signatures and the cache without calling the git provider
+	Offline bool
+	// Workers the number of users resolved concurrently when resolving many users
+	Workers int
+	// Backoff retries git provider calls which are rate limited
+	Backoff *scmapi.Backoff
+	// Organization if specified resolved users who are not members of this organization are marked as external users
+	Organization string
+	// GitServerURL the URL of the git server used to recognise the noreply emails of its users. Defaults to the host
+	// of the git provider API
+	GitServerURL string
+	// Repository the full name of the repository used to find the users of commit emails on providers which support it
+	Repository string
+	// EmailLookup searches the git provider for the users of commit emails without a login. The search APIs are
+	// heavily rate limited so it is disabled by default
+	EmailLookup bool
+	// Metrics receives the events of resolving users such as cache hits and git provider calls
+	Metrics Metrics
+	// SigningKeys resolves the authors of signed commits via the account which owns the signing key
+	SigningKeys bool
+
+	lock             sync.Mutex
+	emailUsers       map[string]*scm.User
+	members          map[string]bool
+	signingKeyLogins map[string]string
 }
 
 // GitSignatureAsUser resolves the signature to a Jenkins X User
-func (r *GitUserResolver) GitSignatureAsUser(signature *object.Signature) (*jenkinsv1.UserDetails, error) {
+func (r *GitUserResolver) GitSignatureAsUser(ctx context.Context, signature *object.Signature) (*jenkinsv1.UserDetails, error) {
 	// We can't resolve no info so shortcircuit
 	if signature.Name == "" && signature.Email == "" {
 		return nil, nil
 	}
+	name, email := r.Mailmap.Map(signature.Name, signature.Email)
 	gitUser := &scm.User{
-		Email: signature.Email,
-		Name:  signature.Name,
+		Email: email,
+		Name:  name,
 	}
-	return r.Resolve(gitUser)
+	return r.Resolve(ctx, gitUser)
 }
 
 // GitUserSliceAsUserDetailsSlice resolves a slice of git users to a slice of Jenkins X User Details
-func (r *GitUserResolver) GitUserSliceAsUserDetailsSlice(users []scm.User) ([]jenkinsv1.UserDetails, error) {
+func (r *GitUserResolver) GitUserSliceAsUserDetailsSlice(ctx context.Context, users []scm.User) ([]jenkinsv1.UserDetails, error) {
+	resolved, err := r.ResolveAll(ctx, users)
+	if err != nil {
+		return nil, err
+	}
 	var answer []jenkinsv1.UserDetails
-	for _, user := range users {
-		us := user
-		u, err := r.Resolve(&us)
-		if err != nil {
-			return nil, err
-		}
+	for _, u := range resolved {
 		if u != nil {
 			answer = append(answer, *u)
 		}
@@ -52,38 +83,114 @@ func (r *GitUserResolver) GitUserSliceAsUserDetailsSlice(users []scm.User) ([]je
 
 // Resolve will convert the GitUser to a Jenkins X user and attempt to complete the user info by:
 // * checking the user custom resources to see if the user is present there
-// * making a call to the gitProvider
-// as often user info is not complete in a git response
-func (r *GitUserResolver) Resolve(user *scm.User) (*jenkinsv1.UserDetails, error) {
+// * making a call to the gitProvider unless the resolver is offline
+// as often user info is not complete in a git response.
+// Any calls to the git provider are cancelled if the given context is done
+func (r *GitUserResolver) Resolve(ctx context.Context, user *scm.User) (*jenkinsv1.UserDetails, error) {
+	u, err := r.resolve(ctx, user)
+	if err != nil {
+		return u, err
+	}
+	r.markExternalUser(ctx, u)
+	return u, nil
+}
+
+func (r *GitUserResolver) resolve(ctx context.Context, user *scm.User) (*jenkinsv1.UserDetails, error) {
 	if r == nil || user == nil || user.Name == "" {
 		return nil, nil
 	}
+	if r.Bots.IsBot(user.Login, user.Name, user.Email) {
+		return nil, nil
+	}
+
+	// aliases override the login of the git user
+	if user.Login == "" {
+		aliasLogin := r.Aliases.Login(user.Name, user.Email)
+		if aliasLogin != "" {
+			aliasUser := *user
+			aliasUser.Login = aliasLogin
+			user = &aliasUser
+		}
+	}
+
+	// noreply emails identify the login so there is no need to query the git provider
+	noReplyLogin := ""
+	if user.Login == "" {
+		noReplyLogin = r.noReplyEmailLogin(user.Email)
+		if noReplyLogin != "" {
+			noReplyUser := *user
+			noReplyUser.Login = noReplyLogin
+			user = &noReplyUser
+		}
+	}
 
-	u := r.cache.GetUser(user.Name)
+	key := user.Login
+	if key == "" {
+		key = user.Name
+	}
+	u := r.userCache().GetUser(naming.ToValidName(key))
 	if u != nil {
+		r.metrics().CacheHit()
 		return u, nil
 	}
+	r.metrics().CacheMiss()
 
-	ctx := context.Background()
+	if r.Offline || r.GitProvider == nil || noReplyLogin != "" {
+		return r.cacheGitUser(user)
+	}
 
+	var scmUser *scm.User
+	var err error
 	if user.Login == "" {
-		u = r.GitUserToUser(user)
-		err := r.cache.CreateOrUpdateUser(u)
+		if !r.EmailLookup {
+			return r.cacheGitUser(user)
+		}
+		scmUser, err = r.findUserByEmail(ctx, user.Email)
 		if err != nil {
-			return u, errors.Wrapf(err, "failed to cache User")
+			// lets not cache the git user so that the email is looked up again such as once the rate limit resets
+			log.Logger().Warnf("failed to find user by email %s: %s", user.Email, err.Error())
+			return r.GitUserToUser(user), nil
+		}
+		if scmUser == nil {
+			r.metrics().UserNotFound()
+			return r.cacheGitUser(user)
+		}
+	} else {
+		if r.userCache().IsUserNotFound(naming.ToValidName(user.Login)) {
+			return nil, nil
+		}
+		err = r.callProvider(ctx, func() (*scm.Response, error) {
+			var res *scm.Response
+			var findErr error
+			scmUser, res, findErr = r.GitProvider.Users.FindLogin(ctx, user.Login)
+			return res, findErr
+		})
+		if errors.Cause(err) == scmapi.ErrRateLimited {
+			// lets not cache the git user so that the login is looked up again once the rate limit resets
+			log.Logger().Warnf("could not find user %s as the git provider rate limit was exceeded: %s", user.Login, err.Error())
+			return r.GitUserToUser(user), nil
+		}
+		if err != nil && !scmhelpers.IsScmNotFound(err) {
+			// lets not cache failures such as network or server errors as the user not being found
+			return nil, errors.Wrapf(err, "failed to find user %s", user.Login)
+		}
+		if scmhelpers.IsScmNotFound(err) {
+			r.metrics().UserNotFound()
+			err = r.userCache().MarkUserNotFound(naming.ToValidName(user.Login))
+			if err != nil {
+				log.Logger().Warnf("failed to cache that user %s was not found: %s", user.Login, err.Error())
+			}
+			return nil, nil
+		}
+		if scmUser == nil {
+			return nil, nil
 		}
-		return u, nil
 	}
 
-	scmUser, _, err := r.GitProvider.Users.FindLogin(ctx, user.Login)
-	if scmUser == nil || scmhelpers.IsScmNotFound(err) {
+	u = r.GitUserToUser(scmUser)
+	if r.Bots.IsBotUser(u) {
 		return nil, nil
 	}
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to find user %s", user.Login)
-	}
-
-	u = r.GitUserToUser(scmUser)
 	login := scmUser.Login
 	if login == "" {
 		login = strings.Replace(scmUser.Name, " ", "-", -1)
@@ -91,13 +198,48 @@ func (r *GitUserResolver) Resolve(user *scm.User) (*jenkinsv1.UserDetails, error
 	}
 	id := naming.ToValidName(login)
 	u.Name = naming.ToValidName(id)
-	err = r.cache.CreateOrUpdateUser(u)
+	err = r.userCache().CreateOrUpdateUser(u)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create User")
 	}
 	return u, nil
 }
 
+// cacheGitUser converts the git user to a Jenkins X user without looking it up on the git provider
+func (r *GitUserResolver) cacheGitUser(user *scm.User) (*jenkinsv1.UserDetails, error) {
+	u := r.GitUserToUser(user)
+	err := r.userCache().CreateOrUpdateUser(u)
+	if err != nil {
+		return u, errors.Wrapf(err, "failed to cache User")
+	}
+	return u, nil
+}
+
+// findUserByEmail finds the git provider user for the email remembering the result so each email is only searched once
+func (r *GitUserResolver) findUserByEmail(ctx context.Context, email string) (*scm.User, error) {
+	if email == "" {
+		return nil, nil
+	}
+	key := strings.ToLower(email)
+	r.lock.Lock()
+	found, ok := r.emailUsers[key]
+	r.lock.Unlock()
+	if ok {
+		return found, nil
+	}
+	found, err := r.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	if r.emailUsers == nil {
+		r.emailUsers = map[string]*scm.User{}
+	}
+	r.emailUsers[key] = found
+	r.lock.Unlock()
+	return found, nil
+}
+
 /* TODO
 // UpdateUserFromPRAuthor will attempt to use the
 func (r *GitUserResolver) UpdateUserFromPRAuthor(author *jenkinsv1.User, pullRequest *scm.PullRequest,
@@ -132,9 +274,11 @@ func (r *GitUserResolver) UpdateUserFromPRAuthor(author *jenkinsv1.User, pullReq
 // attaching the Git Provider account to Accounts
 func (r *GitUserResolver) GitUserToUser(gitUser *scm.User) *jenkinsv1.UserDetails {
 	return &jenkinsv1.UserDetails{
-		Login: gitUser.Login,
-		Name:  gitUser.Name,
-		Email: gitUser.Email,
+		Login:     gitUser.Login,
+		Name:      gitUser.Name,
+		Email:     gitUser.Email,
+		URL:       gitUser.Link,
+		AvatarURL: gitUser.Avatar,
 	}
 }
 
@@ -146,4 +290,10 @@ func (r *GitUserResolver) GitProviderKey() string {
 	return fmt.Sprintf("jenkins.io/git-%s-userid", r.GitProvider.Driver.String())
 }
 
-// mergeGitUsers merges user1 into user2, replacing any that do not have empty values on user2 with those from user1
+// userCache returns the cache of users lazily defaulting to an in memory cache
+func (r *GitUserResolver) userCache() UserDetailService {
+	if r.Cache == nil {
+		r.Cache = &InMemoryUserDetailService{}
+	}
+	return r.Cache
+}
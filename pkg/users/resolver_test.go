@@ -0,0 +1,132 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCachesNotFoundUsers(t *testing.T) {
+	t.Parallel()
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{GitProvider: client}
+	for i := 0; i < 3; i++ {
+		u, err := r.Resolve(context.Background(), &scm.User{Login: "ghost", Name: "Ghost"})
+		require.NoError(t, err, "failed to resolve user")
+		assert.Nil(t, u, "unknown login should not resolve")
+	}
+	assert.Equal(t, 1, lookups, "should only look up an unknown login once")
+}
+
+func TestResolveDoesNotCacheErrorsAsNotFound(t *testing.T) {
+	t.Parallel()
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{GitProvider: client}
+	for i := 0; i < 2; i++ {
+		_, err := r.Resolve(context.Background(), &scm.User{Login: "jstrachan", Name: "James Strachan"})
+		require.Error(t, err, "should fail to resolve user")
+	}
+	assert.Equal(t, 2, lookups, "should look up the login again after a server error")
+}
+
+func TestResolveDoesNotCacheRateLimitedUsers(t *testing.T) {
+	t.Parallel()
+	lookups := 0
+	limited := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if limited {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"login": "jstrachan", "name": "James Strachan", "email": "james@example.com"}`))
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{GitProvider: client, Backoff: scmapi.NewBackoff(time.Millisecond)}
+	u, err := r.Resolve(context.Background(), &scm.User{Login: "jstrachan", Name: "jstrachan"})
+	require.NoError(t, err, "should fall back to the git user when rate limited")
+	require.NotNil(t, u)
+	assert.Empty(t, u.Email, "should not have looked up the user")
+
+	limited = false
+	r.Backoff = nil
+	u, err = r.Resolve(context.Background(), &scm.User{Login: "jstrachan", Name: "jstrachan"})
+	require.NoError(t, err, "failed to resolve user")
+	require.NotNil(t, u)
+	assert.Equal(t, "james@example.com", u.Email, "should look up the user again once the rate limit resets")
+	assert.Equal(t, 2, lookups)
+}
+
+func TestResolveMetrics(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/jstrachan":
+			_, _ = w.Write([]byte(`{"login": "jstrachan", "name": "James Strachan"}`))
+		case "/users/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	stats := &users.ResolutionStats{}
+	r := &users.GitUserResolver{
+		GitProvider: client,
+		Metrics:     stats,
+	}
+	ctx := context.Background()
+	for _, login := range []string{"jstrachan", "jstrachan", "ghost"} {
+		_, err = r.Resolve(ctx, &scm.User{Login: login, Name: login})
+		require.NoError(t, err, "failed to resolve %s", login)
+	}
+	_, err = r.Resolve(ctx, &scm.User{Login: "broken", Name: "broken"})
+	require.Error(t, err, "should fail to resolve broken")
+
+	assert.Equal(t, int64(1), stats.CacheHits)
+	assert.Equal(t, int64(3), stats.CacheMisses)
+	assert.Equal(t, int64(3), stats.APICalls)
+	assert.Equal(t, int64(1), stats.NotFound)
+	assert.Equal(t, int64(1), stats.Errors)
+}
@@ -0,0 +1,103 @@
+package users
+
+import (
+	"context"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/pkg/errors"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// reviewsPageSize the number of reviews listed per page
+const reviewsPageSize = 100
+
+// Reviewers the resolved reviewers of the pull requests included in a release
+type Reviewers struct {
+	// Reviewers the users who submitted a review on any of the pull requests
+	Reviewers []jenkinsv1.UserDetails `json:"reviewers,omitempty"`
+	// Approvers the users who approved any of the pull requests
+	Approvers []jenkinsv1.UserDetails `json:"approvers,omitempty"`
+}
+
+// PullRequestReviewers fetches the reviews of the given pull requests and resolves the reviewers and approvers
+func (r *GitUserResolver) PullRequestReviewers(ctx context.Context, repo string, numbers []int) (*Reviewers, error) {
+	answer := &Reviewers{}
+	if r == nil || r.GitProvider == nil || r.GitProvider.Reviews == nil || r.Offline {
+		return answer, nil
+	}
+	var reviewers, approvers []scm.User
+	for _, n := range numbers {
+		reviews, err := r.listReviews(ctx, repo, n)
+		if err != nil {
+			return answer, err
+		}
+		for _, review := range reviews {
+			if review == nil || review.State == scm.ReviewStatePending {
+				continue
+			}
+			author := review.Author
+			if author.Name == "" {
+				// lets default the name as providers like GitHub only return the login of the reviewer
+				author.Name = author.Login
+			}
+			reviewers = append(reviewers, author)
+			if review.State == scm.ReviewStateApproved {
+				approvers = append(approvers, author)
+			}
+		}
+	}
+
+	var err error
+	answer.Reviewers, err = r.resolveUniqueUsers(ctx, reviewers)
+	if err != nil {
+		return answer, errors.Wrapf(err, "failed to resolve reviewers")
+	}
+	answer.Approvers, err = r.resolveUniqueUsers(ctx, approvers)
+	if err != nil {
+		return answer, errors.Wrapf(err, "failed to resolve approvers")
+	}
+	return answer, nil
+}
+
+// listReviews lists all the pages of reviews of the pull request
+func (r *GitUserResolver) listReviews(ctx context.Context, repo string, number int) ([]*scm.Review, error) {
+	var answer []*scm.Review
+	for page := 1; ; page++ {
+		var reviews []*scm.Review
+		err := r.callProvider(ctx, func() (*scm.Response, error) {
+			var res *scm.Response
+			var err error
+			reviews, res, err = r.GitProvider.Reviews.List(ctx, repo, number, scm.ListOptions{Page: page, Size: reviewsPageSize})
+			return res, err
+		})
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to list reviews of pull request %d in repository %s", number, repo)
+		}
+		answer = append(answer, reviews...)
+		if len(reviews) < reviewsPageSize {
+			return answer, nil
+		}
+	}
+}
+
+// resolveUniqueUsers resolves the users removing any duplicates
+func (r *GitUserResolver) resolveUniqueUsers(ctx context.Context, users []scm.User) ([]jenkinsv1.UserDetails, error) {
+	resolved, err := r.GitUserSliceAsUserDetailsSlice(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+	var answer []jenkinsv1.UserDetails
+	found := map[string]bool{}
+	for _, u := range resolved {
+		key := u.Login
+		if key == "" {
+			key = u.Name
+		}
+		if !found[key] {
+			found[key] = true
+			answer = append(answer, u)
+		}
+	}
+	return answer, nil
+}
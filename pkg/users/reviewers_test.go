@@ -0,0 +1,59 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestReviewersListsAllPages(t *testing.T) {
+	t.Parallel()
+	review := func(login, state string) map[string]interface{} {
+		return map[string]interface{}{"user": map[string]interface{}{"login": login}, "state": state}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/jstrachan/foo/pulls/1/reviews":
+			var reviews []map[string]interface{}
+			if r.URL.Query().Get("page") == "1" {
+				for i := 0; i < 100; i++ {
+					reviews = append(reviews, review("jane", "COMMENTED"))
+				}
+			} else {
+				reviews = append(reviews, review("bob", "APPROVED"))
+			}
+			_ = json.NewEncoder(w).Encode(reviews)
+		case strings.HasPrefix(r.URL.Path, "/users/"):
+			login := strings.TrimPrefix(r.URL.Path, "/users/")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"login": login, "name": login})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{GitProvider: client}
+	reviewers, err := r.PullRequestReviewers(context.Background(), "jstrachan/foo", []int{1})
+	require.NoError(t, err, "failed to find reviewers")
+
+	var logins []string
+	for _, u := range reviewers.Reviewers {
+		logins = append(logins, u.Login)
+	}
+	assert.Equal(t, []string{"jane", "bob"}, logins)
+	require.Len(t, reviewers.Approvers, 1)
+	assert.Equal(t, "bob", reviewers.Approvers[0].Login)
+}
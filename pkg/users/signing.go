@@ -0,0 +1,355 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/scmapi"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp/armor"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+const (
+	// SigningKeyGPG a commit signed with a GPG key
+	SigningKeyGPG = "gpg"
+
+	// SigningKeySSH a commit signed with an SSH key
+	SigningKeySSH = "ssh"
+
+	sshSignatureHeader = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureFooter = "-----END SSH SIGNATURE-----"
+	sshSignatureMagic  = "SSHSIG"
+)
+
+// SigningKey the key used to sign a commit
+type SigningKey struct {
+	// Type the kind of key: gpg or ssh
+	Type string
+	// ID the GPG key ID or the SHA256 fingerprint of the SSH public key
+	ID string
+}
+
+// CommitSigningKey returns the key used to sign the commit or nil if the commit is not signed or the signature cannot be parsed
+func CommitSigningKey(commit *object.Commit) *SigningKey {
+	if commit == nil {
+		return nil
+	}
+	signature := strings.TrimSpace(commit.PGPSignature)
+	if signature == "" {
+		return nil
+	}
+	if strings.HasPrefix(signature, sshSignatureHeader) {
+		id := sshSignatureFingerprint(signature)
+		if id == "" {
+			return nil
+		}
+		return &SigningKey{Type: SigningKeySSH, ID: id}
+	}
+	id := gpgSignatureKeyID(signature)
+	if id == "" {
+		return nil
+	}
+	return &SigningKey{Type: SigningKeyGPG, ID: id}
+}
+
+// gpgSignatureKeyID returns the issuer key ID of the armored GPG signature.
+// The signature packet is parsed directly so that the key ID can be found whatever the public key algorithm
+func gpgSignatureKeyID(signature string) string {
+	block, err := armor.Decode(strings.NewReader(signature))
+	if err != nil {
+		return ""
+	}
+	data, err := ioutil.ReadAll(block.Body)
+	if err != nil {
+		return ""
+	}
+	body := openpgpPacketBody(data)
+	// only version 4 signatures have subpackets
+	if len(body) < 6 || body[0] != 4 {
+		return ""
+	}
+	body = body[4:]
+	for i := 0; i < 2 && len(body) >= 2; i++ {
+		size := int(binary.BigEndian.Uint16(body))
+		body = body[2:]
+		if len(body) < size {
+			return ""
+		}
+		id := openpgpIssuerKeyID(body[0:size])
+		if id != "" {
+			return id
+		}
+		body = body[size:]
+	}
+	return ""
+}
+
+// openpgpPacketBody returns the body of the first OpenPGP packet
+func openpgpPacketBody(data []byte) []byte {
+	if len(data) < 2 || data[0]&0x80 == 0 {
+		return nil
+	}
+	var size, offset int
+	if data[0]&0x40 == 0 {
+		// old format packet
+		switch data[0] & 0x03 {
+		case 0:
+			size, offset = int(data[1]), 2
+		case 1:
+			if len(data) < 3 {
+				return nil
+			}
+			size, offset = int(binary.BigEndian.Uint16(data[1:])), 3
+		case 2:
+			if len(data) < 5 {
+				return nil
+			}
+			size, offset = int(binary.BigEndian.Uint32(data[1:])), 5
+		default:
+			size, offset = len(data)-1, 1
+		}
+	} else {
+		// new format packet
+		switch l := int(data[1]); {
+		case l < 192:
+			size, offset = l, 2
+		case l < 224:
+			if len(data) < 3 {
+				return nil
+			}
+			size, offset = (l-192)<<8+int(data[2])+192, 3
+		case l == 255:
+			if len(data) < 6 {
+				return nil
+			}
+			size, offset = int(binary.BigEndian.Uint32(data[2:])), 6
+		default:
+			return nil
+		}
+	}
+	if len(data) < offset+size {
+		return nil
+	}
+	return data[offset : offset+size]
+}
+
+// openpgpIssuerKeyID returns the key ID from the issuer or issuer fingerprint subpackets
+func openpgpIssuerKeyID(subpackets []byte) string {
+	for len(subpackets) > 0 {
+		size := int(subpackets[0])
+		offset := 1
+		switch {
+		case size >= 255:
+			if len(subpackets) < 5 {
+				return ""
+			}
+			size, offset = int(binary.BigEndian.Uint32(subpackets[1:])), 5
+		case size >= 192:
+			if len(subpackets) < 2 {
+				return ""
+			}
+			size, offset = (size-192)<<8+int(subpackets[1])+192, 2
+		}
+		if size == 0 || len(subpackets) < offset+size {
+			return ""
+		}
+		subpacket := subpackets[offset : offset+size]
+		subpackets = subpackets[offset+size:]
+
+		switch subpacket[0] & 0x7f {
+		case 16:
+			// issuer key ID
+			if len(subpacket) == 9 {
+				return fmt.Sprintf("%X", subpacket[1:])
+			}
+		case 33:
+			// issuer fingerprint whose last 8 bytes are the key ID
+			if len(subpacket) >= 10 {
+				return fmt.Sprintf("%X", subpacket[len(subpacket)-8:])
+			}
+		}
+	}
+	return ""
+}
+
+// sshSignatureFingerprint returns the SHA256 fingerprint of the public key in the armored SSH signature
+func sshSignatureFingerprint(signature string) string {
+	text := strings.TrimPrefix(signature, sshSignatureHeader)
+	idx := strings.Index(text, sshSignatureFooter)
+	if idx < 0 {
+		return ""
+	}
+	text = strings.Join(strings.Fields(text[0:idx]), "")
+	data, err := base64.StdEncoding.DecodeString(text)
+	if err != nil || !bytes.HasPrefix(data, []byte(sshSignatureMagic)) {
+		return ""
+	}
+	// the magic preamble is followed by a uint32 version then the length prefixed public key
+	data = data[len(sshSignatureMagic):]
+	if len(data) < 8 {
+		return ""
+	}
+	data = data[4:]
+	size := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint32(len(data)) < size {
+		return ""
+	}
+	sum := sha256.Sum256(data[0:size])
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// CommitAuthorAsUser resolves the author of the commit to a Jenkins X user.
+// If signing key resolution is enabled and the author signed the commit the git provider is used to find the account
+// of the signing key which attributes commits whose author email is private or not linked to an account
+func (r *GitUserResolver) CommitAuthorAsUser(ctx context.Context, repo string, commit *object.Commit) (*jenkinsv1.UserDetails, error) {
+	author := &commit.Author
+	if author.Name == "" && author.Email == "" {
+		return nil, nil
+	}
+	if r.SigningKeys && !r.Offline && r.GitProvider != nil && strings.EqualFold(author.Email, commit.Committer.Email) &&
+		r.Aliases.Login(author.Name, author.Email) == "" && r.noReplyEmailLogin(author.Email) == "" {
+		key := CommitSigningKey(commit)
+		if key != nil {
+			login, err := r.signingKeyLogin(ctx, repo, commit.Hash.String(), key)
+			if err != nil {
+				log.Logger().Warnf("failed to find the account of the signing key of commit %s: %s", commit.Hash.String(), err.Error())
+			}
+			if login != "" {
+				name, email := r.Mailmap.Map(author.Name, author.Email)
+				return r.Resolve(ctx, &scm.User{
+					Login: login,
+					Name:  name,
+					Email: email,
+				})
+			}
+		}
+	}
+	return r.GitSignatureAsUser(ctx, author)
+}
+
+// signingKeyLogin returns the login of the account owning the signing key remembering the result for each key
+func (r *GitUserResolver) signingKeyLogin(ctx context.Context, repo, sha string, key *SigningKey) (string, error) {
+	cacheKey := key.Type + ":" + key.ID
+	r.lock.Lock()
+	login, ok := r.signingKeyLogins[cacheKey]
+	r.lock.Unlock()
+	if ok {
+		return login, nil
+	}
+
+	var err error
+	switch r.GitProvider.Driver {
+	case scm.DriverGithub:
+		login, err = r.findGitHubCommitSigner(ctx, repo, sha)
+	case scm.DriverGitlab:
+		if key.Type == SigningKeySSH {
+			login, err = r.findGitLabSSHKeyUser(ctx, key.ID)
+		} else {
+			login, err = r.findGitLabCommitSigner(ctx, repo, sha)
+		}
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the user of %s signing key %s", key.Type, key.ID)
+	}
+
+	r.lock.Lock()
+	if r.signingKeyLogins == nil {
+		r.signingKeyLogins = map[string]string{}
+	}
+	r.signingKeyLogins[cacheKey] = login
+	r.lock.Unlock()
+	return login, nil
+}
+
+type githubCommit struct {
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Commit struct {
+		Verification struct {
+			Verified bool `json:"verified"`
+		} `json:"verification"`
+	} `json:"commit"`
+}
+
+// findGitHubCommitSigner uses the commit API as GitHub verifies the signature against the keys of the author account
+func (r *GitUserResolver) findGitHubCommitSigner(ctx context.Context, repo, sha string) (string, error) {
+	result := &githubCommit{}
+	path := fmt.Sprintf("repos/%s/commits/%s", repo, sha)
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, r.GitProvider, path, result)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !result.Commit.Verification.Verified || result.Author == nil {
+		return "", nil
+	}
+	return result.Author.Login, nil
+}
+
+type gitlabKey struct {
+	User *gitlabUser `json:"user"`
+}
+
+func (r *GitUserResolver) findGitLabSSHKeyUser(ctx context.Context, fingerprint string) (string, error) {
+	result := &gitlabKey{}
+	path := fmt.Sprintf("api/v4/keys?fingerprint=%s", url.QueryEscape(fingerprint))
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, r.GitProvider, path, result)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if result.User == nil {
+		return "", nil
+	}
+	return result.User.Username, nil
+}
+
+type gitlabCommitSignature struct {
+	VerificationStatus string `json:"verification_status"`
+	GPGKeyUserEmail    string `json:"gpg_key_user_email"`
+}
+
+// findGitLabCommitSigner finds the user of a verified GPG signature via the email of the GPG key
+func (r *GitUserResolver) findGitLabCommitSigner(ctx context.Context, repo, sha string) (string, error) {
+	result := &gitlabCommitSignature{}
+	path := fmt.Sprintf("api/v4/projects/%s/repository/commits/%s/signature", url.PathEscape(repo), sha)
+	err := r.callProvider(ctx, func() (*scm.Response, error) {
+		return scmapi.GetJSON(ctx, r.GitProvider, path, result)
+	})
+	if err != nil {
+		if scm.IsScmNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if result.VerificationStatus != "verified" || result.GPGKeyUserEmail == "" {
+		return "", nil
+	}
+	u, err := r.findUserByEmail(ctx, result.GPGKeyUserEmail)
+	if err != nil || u == nil {
+		return "", err
+	}
+	return u.Login, nil
+}
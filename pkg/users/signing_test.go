@@ -0,0 +1,113 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+const (
+	gpgSignature = `-----BEGIN PGP SIGNATURE-----
+
+iHUEABYIAB0WIQRbc+at94Y85zHNSxEjz1O9+LxgegUCatB9wQAKCRAjz1O9+Lxg
+ehwCAQDszynbF4MRjrzahoiuvFVlNJzUfmlRvyK4V7Wz+wsiYwEAgTB/ppkjz5bB
+L+v1ioG+XRPBvJzVMaUAB6omzJaLtwo=
+=eZkX
+-----END PGP SIGNATURE-----
+`
+
+	sshSignature = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgy9dTKox1RP6FQT93qY/GxK3PdT
+YQsJDNTwBSTEGGIjUAAAADZ2l0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1NTE5
+AAAAQPbLVhfZ6KljGPvqQg6S/TQ94glbaf6svnwLGh+Z4CwkxQzktIq9V8hilWD8ZZ2EdQ
+hARklcAyj4Ejx9s7l2GAU=
+-----END SSH SIGNATURE-----
+`
+)
+
+func TestCommitSigningKey(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, users.CommitSigningKey(&object.Commit{}), "unsigned commits should not have a key")
+
+	key := users.CommitSigningKey(&object.Commit{PGPSignature: gpgSignature})
+	require.NotNil(t, key, "should have found the GPG key")
+	assert.Equal(t, users.SigningKeyGPG, key.Type)
+	assert.Equal(t, "23CF53BDF8BC607A", key.ID)
+
+	key = users.CommitSigningKey(&object.Commit{PGPSignature: sshSignature})
+	require.NotNil(t, key, "should have found the SSH key")
+	assert.Equal(t, users.SigningKeySSH, key.Type)
+	assert.Equal(t, "SHA256:AQABgkBVXSDI+3QoX5Q8vtT+bv5sP5kd2LSH8ro2zuI", key.ID)
+}
+
+func TestCommitAuthorAsUserFromGitHubSignature(t *testing.T) {
+	t.Parallel()
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/jenkins-x/jx-changelog/commits/" + sha:
+			_, _ = w.Write([]byte(`{"author": {"login": "jane"}, "commit": {"verification": {"verified": true}}}`))
+		case "/users/jane":
+			_, _ = w.Write([]byte(`{"login": "jane", "name": "Jane Doe"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{
+		GitProvider: client,
+		SigningKeys: true,
+	}
+	signature := object.Signature{Name: "Jane Doe", Email: "jane@private.example.com"}
+	commit := &object.Commit{
+		Hash:         plumbing.NewHash(sha),
+		Author:       signature,
+		Committer:    signature,
+		PGPSignature: gpgSignature,
+	}
+	u, err := r.CommitAuthorAsUser(context.Background(), "jenkins-x/jx-changelog", commit)
+	require.NoError(t, err, "failed to resolve commit author")
+	require.NotNil(t, u, "should have resolved the author")
+	assert.Equal(t, "jane", u.Login)
+}
+
+func TestCommitAuthorAsUserKeepsAuthorOnSigningKeyError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := github.New(server.URL)
+	require.NoError(t, err, "failed to create GitHub client")
+
+	r := &users.GitUserResolver{
+		GitProvider: client,
+		SigningKeys: true,
+	}
+	signature := object.Signature{Name: "Jane Doe", Email: "jane@private.example.com"}
+	commit := &object.Commit{
+		Hash:         plumbing.NewHash("0123456789abcdef0123456789abcdef01234567"),
+		Author:       signature,
+		Committer:    signature,
+		PGPSignature: gpgSignature,
+	}
+	u, err := r.CommitAuthorAsUser(context.Background(), "jenkins-x/jx-changelog", commit)
+	require.NoError(t, err, "failed to resolve commit author")
+	require.NotNil(t, u, "should keep the author")
+	assert.Equal(t, "Jane Doe", u.Name)
+}
@@ -0,0 +1,40 @@
+package users
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// UserStoreMemory caches users in memory for the duration of a run
+	UserStoreMemory = "memory"
+
+	// UserStoreFile caches users in a JSON file so they can be reused across runs
+	UserStoreFile = "file"
+)
+
+// UserStoreKinds the kinds of user stores which do not require a kubernetes cluster
+var UserStoreKinds = []string{UserStoreMemory, UserStoreFile}
+
+// NewUserDetailService creates the user detail service of the given kind.
+// If no kind is specified a file store is used if a path is given otherwise an in memory store
+func NewUserDetailService(kind, path string, ttl time.Duration) (UserDetailService, error) {
+	if kind == "" {
+		kind = UserStoreMemory
+		if path != "" {
+			kind = UserStoreFile
+		}
+	}
+	switch kind {
+	case UserStoreMemory:
+		return &InMemoryUserDetailService{}, nil
+	case UserStoreFile:
+		if path == "" {
+			return nil, errors.Errorf("no file specified for the %s user store", kind)
+		}
+		return NewFileUserDetailService(path, ttl), nil
+	default:
+		return nil, errors.Errorf("unknown user store kind %s. Supported kinds are: %v", kind, UserStoreKinds)
+	}
+}
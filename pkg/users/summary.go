@@ -0,0 +1,71 @@
+package users
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+
+	jenkinsv1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+)
+
+// ContributorStats the contributions of a resolved user to a release
+type ContributorStats struct {
+	// User the resolved user
+	User jenkinsv1.UserDetails `json:"user"`
+	// Commits the number of commits authored by the user
+	Commits int `json:"commits"`
+	// FirstCommit the time of the first commit by the user
+	FirstCommit time.Time `json:"firstCommit"`
+	// LastCommit the time of the last commit by the user
+	LastCommit time.Time `json:"lastCommit"`
+}
+
+// ContributorSummary resolves the authors of the commits and returns the number of commits and the dates of the
+// first and last commit of each contributor ordered by the number of commits.
+// Authors which cannot be resolved such as bots are ignored
+func (r *GitUserResolver) ContributorSummary(ctx context.Context, commits []*object.Commit) ([]*ContributorStats, error) {
+	var gitUsers []scm.User
+	for _, commit := range commits {
+		name, email := r.Mailmap.Map(commit.Author.Name, commit.Author.Email)
+		gitUsers = append(gitUsers, scm.User{Name: name, Email: email})
+	}
+	resolved, err := r.ResolveAll(ctx, gitUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	var answer []*ContributorStats
+	stats := map[string]*ContributorStats{}
+	for i, u := range resolved {
+		if u == nil {
+			continue
+		}
+		key := u.Login
+		if key == "" {
+			key = u.Name
+		}
+		key = strings.ToLower(key)
+		s := stats[key]
+		if s == nil {
+			s = &ContributorStats{User: *u}
+			stats[key] = s
+			answer = append(answer, s)
+		}
+		when := commits[i].Author.When
+		s.Commits++
+		if s.FirstCommit.IsZero() || when.Before(s.FirstCommit) {
+			s.FirstCommit = when
+		}
+		if when.After(s.LastCommit) {
+			s.LastCommit = when
+		}
+	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		return answer[i].Commits > answer[j].Commits
+	})
+	return answer, nil
+}
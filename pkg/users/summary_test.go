@@ -0,0 +1,47 @@
+// +build unit
+
+package users_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-changelog/pkg/users"
+	"github.com/jenkins-x/go-scm/scm/driver/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestContributorSummary(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time {
+		return time.Date(2021, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+	commit := func(name, email string, when time.Time) *object.Commit {
+		return &object.Commit{Author: object.Signature{Name: name, Email: email, When: when}}
+	}
+	r := &users.GitUserResolver{
+		GitProvider: github.NewDefault(),
+		Offline:     true,
+		Bots:        users.NewBotFilter(nil, nil),
+	}
+	summary, err := r.ContributorSummary(context.Background(), []*object.Commit{
+		commit("Jane", "jane@users.noreply.github.com", day(3)),
+		commit("James", "1234+jstrachan@users.noreply.github.com", day(2)),
+		commit("dependabot[bot]", "support@dependabot.com", day(4)),
+		commit("James", "1234+jstrachan@users.noreply.github.com", day(5)),
+		commit("James", "1234+jstrachan@users.noreply.github.com", day(1)),
+	})
+	require.NoError(t, err, "failed to create summary")
+	require.Len(t, summary, 2)
+
+	assert.Equal(t, "jstrachan", summary[0].User.Login)
+	assert.Equal(t, 3, summary[0].Commits)
+	assert.Equal(t, day(1), summary[0].FirstCommit)
+	assert.Equal(t, day(5), summary[0].LastCommit)
+
+	assert.Equal(t, "jane", summary[1].User.Login)
+	assert.Equal(t, 1, summary[1].Commits)
+}
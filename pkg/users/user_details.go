@@ -1,23 +1,49 @@
 package users
 
 import (
+	"sync"
+
 	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/naming"
 	"github.com/jenkins-x/jx-logging/v3/pkg/log"
 )
 
-type UserDetailService struct {
-	cache map[string]*v1.UserDetails
+// UserDetailService caches the details of the users resolved from git
+type UserDetailService interface {
+	// GetUser returns the cached user for the given login or nil if it is not cached
+	GetUser(login string) *v1.UserDetails
+
+	// CreateOrUpdateUser caches the user merging it with any existing user of the same login
+	CreateOrUpdateUser(u *v1.UserDetails) error
+
+	// IsUserNotFound returns true if the login was recently not found on the git provider
+	IsUserNotFound(login string) bool
+
+	// MarkUserNotFound remembers that the login could not be found on the git provider so it is not looked up again
+	MarkUserNotFound(login string) error
+}
+
+// InMemoryUserDetailService caches the user details in memory for the duration of a run
+type InMemoryUserDetailService struct {
+	lock     sync.Mutex
+	cache    map[string]*v1.UserDetails
+	notFound map[string]bool
 }
 
-func (s *UserDetailService) GetUser(login string) *v1.UserDetails {
+func (s *InMemoryUserDetailService) GetUser(login string) *v1.UserDetails {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.getUser(login)
+}
+
+func (s *InMemoryUserDetailService) getUser(login string) *v1.UserDetails {
 	if s.cache == nil {
 		s.cache = map[string]*v1.UserDetails{}
 	}
 	return s.cache[login]
 }
 
-func (s *UserDetailService) CreateOrUpdateUser(u *v1.UserDetails) error {
+func (s *InMemoryUserDetailService) CreateOrUpdateUser(u *v1.UserDetails) error {
 	if u == nil || u.Login == "" {
 		return nil
 	}
@@ -26,26 +52,53 @@ func (s *UserDetailService) CreateOrUpdateUser(u *v1.UserDetails) error {
 
 	id := naming.ToValidName(u.Login)
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	// check for an existing user by email
-	existing := s.GetUser(id)
+	existing := s.getUser(id)
 	if existing == nil {
 		s.cache[id] = u
 		return nil
 	}
-	if u.Email != "" {
-		existing.Email = u.Email
+	mergeUserDetails(u, existing)
+	return nil
+}
+
+func (s *InMemoryUserDetailService) IsUserNotFound(login string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.notFound[login]
+}
+
+func (s *InMemoryUserDetailService) MarkUserNotFound(login string) error {
+	if login == "" {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.notFound == nil {
+		s.notFound = map[string]bool{}
 	}
-	if u.AvatarURL != "" {
-		existing.AvatarURL = u.AvatarURL
+	s.notFound[login] = true
+	return nil
+}
+
+// mergeUserDetails merges user1 into user2, replacing any values on user2 with the non empty values from user1
+func mergeUserDetails(user1, user2 *v1.UserDetails) {
+	if user1.Email != "" {
+		user2.Email = user1.Email
 	}
-	if u.URL != "" {
-		existing.URL = u.URL
+	if user1.AvatarURL != "" {
+		user2.AvatarURL = user1.AvatarURL
 	}
-	if u.Name != "" {
-		existing.Name = u.Name
+	if user1.URL != "" {
+		user2.URL = user1.URL
 	}
-	if u.Login != "" {
-		existing.Login = u.Login
+	if user1.Name != "" {
+		user2.Name = user1.Name
+	}
+	if user1.Login != "" {
+		user2.Login = user1.Login
 	}
-	return nil
 }